@@ -0,0 +1,44 @@
+// Copyright 2017 Orinoco Payments
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Interactive returns true if standard input is a terminal
+func Interactive() bool {
+	return isTerminal(int(os.Stdin.Fd()))
+}
+
+// Confirm asks a question on the terminal, returning true if the answer is
+// yes.  Anything other than "y" or "yes" is taken as no.  It returns an
+// error if standard input is not a terminal.
+func Confirm(question string) (bool, error) {
+	if !Interactive() {
+		return false, errors.New("cannot ask for confirmation when not running in a terminal")
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return false, fmt.Errorf("failed to read answer: %v", err)
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
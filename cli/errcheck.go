@@ -15,17 +15,50 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 )
 
-// ErrCheck checks for an error and quits if it is present
+// errorFormat is the format in which errors and warnings are written
+var errorFormat = "text"
+
+// jsonError is an error or warning as written in JSON format
+type jsonError struct {
+	Error   string `json:"error,omitempty"`
+	Warning string `json:"warning,omitempty"`
+	Code    int    `json:"code,omitempty"`
+}
+
+// SetErrorFormat sets the format in which errors and warnings are written to
+// stderr.  The format can be "text" or "json"; in JSON format each error is
+// written as a single object on its own line, containing the error and the
+// exit code.
+func SetErrorFormat(format string) error {
+	switch format {
+	case "", "text":
+		errorFormat = "text"
+	case "json":
+		errorFormat = "json"
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+	return nil
+}
+
+// ErrCheck checks for an error and quits if it is present.  The exit code
+// depends on the type of error; see ExitCode()
 func ErrCheck(err error, quiet bool, msg string) {
 	if err != nil {
-		if !quiet {
-			fmt.Fprintf(os.Stderr, "%s: %s\n", msg, err.Error())
-		}
-		os.Exit(1)
+		fail(quiet, fmt.Sprintf("%s: %s", msg, err.Error()), ExitCode(err))
+	}
+}
+
+// ErrCheckCode checks for an error and quits with the given exit code if it
+// is present
+func ErrCheckCode(err error, quiet bool, code int, msg string) {
+	if err != nil {
+		fail(quiet, fmt.Sprintf("%s: %s", msg, err.Error()), code)
 	}
 }
 
@@ -33,10 +66,7 @@ func ErrCheck(err error, quiet bool, msg string) {
 func ErrAssert(condition bool, err error, quiet bool, msg string) {
 	if !condition {
 		if err != nil {
-			if !quiet {
-				fmt.Fprintf(os.Stderr, "%s: %s\n", msg, err.Error())
-			}
-			os.Exit(1)
+			fail(quiet, fmt.Sprintf("%s: %s", msg, err.Error()), ExitCode(err))
 		}
 	}
 }
@@ -48,10 +78,54 @@ func Assert(condition bool, quiet bool, msg string) {
 	}
 }
 
-// Err prints an erro rand quits
+// AssertCode checks a condition and quits with the given exit code if it is
+// false
+func AssertCode(condition bool, quiet bool, code int, msg string) {
+	if !condition {
+		ErrCode(quiet, code, msg)
+	}
+}
+
+// Warn prints a warning but does not quit
+func Warn(quiet bool, msg string) {
+	if !quiet {
+		if errorFormat == "json" {
+			writeJSONError(&jsonError{Warning: msg})
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		}
+	}
+}
+
+// Err prints an error and quits
 func Err(quiet bool, msg string) {
+	fail(quiet, msg, ExitFalse)
+}
+
+// ErrCode prints an error and quits with the given exit code
+func ErrCode(quiet bool, code int, msg string) {
+	fail(quiet, msg, code)
+}
+
+// fail prints an error and quits with the given exit code
+func fail(quiet bool, msg string, code int) {
 	if !quiet {
-		fmt.Fprintf(os.Stderr, "%s\n", msg)
+		if errorFormat == "json" {
+			writeJSONError(&jsonError{Error: msg, Code: code})
+		} else {
+			fmt.Fprintf(os.Stderr, "%s\n", msg)
+		}
+	}
+	os.Exit(code)
+}
+
+// writeJSONError writes an error or warning to stderr as JSON
+func writeJSONError(e *jsonError) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		// Cannot happen with string and int fields
+		fmt.Fprintf(os.Stderr, "%s%s\n", e.Error, e.Warning)
+		return
 	}
-	os.Exit(1)
+	fmt.Fprintf(os.Stderr, "%s\n", data)
 }
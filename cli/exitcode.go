@@ -0,0 +1,110 @@
+// Copyright 2017 Orinoco Payments
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"regexp"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Exit codes.  ExitFalse is used both for commands that complete with a
+// negative result, such as in quiet mode when a name is not available, and
+// for failures that do not fall in to one of the other categories.
+const (
+	// ExitSuccess is returned when a command succeeds
+	ExitSuccess = 0
+	// ExitFalse is returned for a negative result, an item that is not found
+	// or an unclassified failure
+	ExitFalse = 1
+	// ExitUsage is returned for missing or invalid flags and arguments
+	ExitUsage = 2
+	// ExitNetwork is returned when the node cannot be reached or rejects a
+	// request
+	ExitNetwork = 3
+	// ExitExecution is returned when a transaction or call reverts or
+	// otherwise fails to execute
+	ExitExecution = 4
+)
+
+// ExitCode provides the exit code for an error, based on its type.  Errors
+// that have been wrapped with fmt.Errorf() and so lost their type are
+// classified by the words that they contain.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case errors.Is(err, ethereum.NotFound):
+		return ExitFalse
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ExitNetwork
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		// Includes HTTP errors, which are returned from the transport
+		return ExitNetwork
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Rate limiting and gateway errors can return non-JSON bodies
+		return ExitNetwork
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcExitCode(rpcErr)
+	}
+	return messageExitCode(err.Error())
+}
+
+// rpcExitCode provides the exit code for a JSON-RPC error returned by the
+// node
+func rpcExitCode(err rpc.Error) int {
+	switch err.ErrorCode() {
+	case 3, -32015:
+		// Execution reverted (geth) and VM execution error (parity)
+		return ExitExecution
+	case -32000:
+		// Generic server error, used for execution failures by older nodes
+		if executionMessage.MatchString(err.Error()) {
+			return ExitExecution
+		}
+	}
+	return ExitNetwork
+}
+
+// executionMessage matches the descriptions of execution failures
+var executionMessage = regexp.MustCompile(`(?i)\b(revert|reverted|invalid opcode|out of gas|gas required exceeds allowance|always failing transaction)\b`)
+
+// networkMessage matches the descriptions of network failures
+var networkMessage = regexp.MustCompile(`(?i)\b(connection refused|connection reset|no such host|network is unreachable|i/o timeout|deadline exceeded|unexpected EOF|EOF|too many requests|bad gateway|service unavailable|gateway timeout|no connection served the request)\b`)
+
+// messageExitCode provides the exit code for the message of an error whose
+// type has been lost.  Only whole words are matched, so that numbers such as
+// status codes do not match parts of hashes, addresses or block numbers.
+func messageExitCode(msg string) int {
+	if executionMessage.MatchString(msg) {
+		return ExitExecution
+	}
+	if networkMessage.MatchString(msg) {
+		return ExitNetwork
+	}
+	return ExitFalse
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 Orinoco Payments
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+)
+
+// DefaultLedgerPath is the default derivation path for Ledger accounts
+const DefaultLedgerPath = "m/44'/60'/0'/0/0"
+
+// ObtainLedgerAccount opens the first connected Ledger and derives the
+// account at the given path.  The Ethereum application must be open on the
+// Ledger.
+func ObtainLedgerAccount(path string) (accounts.Wallet, *accounts.Account, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid derivation path %s: %v", path, err)
+	}
+
+	hub, err := usbwallet.NewLedgerHub()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to access USB devices: %v", err)
+	}
+	wallets := hub.Wallets()
+	if len(wallets) == 0 {
+		return nil, nil, errors.New("no Ledger found")
+	}
+	wallet := wallets[0]
+	if err := wallet.Open(""); err != nil {
+		return nil, nil, fmt.Errorf("failed to open Ledger (is the Ethereum application running?): %v", err)
+	}
+	account, err := wallet.Derive(derivationPath, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to derive account %s: %v", path, err)
+	}
+	return wallet, &account, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 Orinoco Payments
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Out is the writer to which command output is written.  Errors and
+// warnings are written to stderr regardless.
+var Out io.Writer = os.Stdout
+
+// SetOutput directs command output to the named file.  An empty path or "-"
+// directs output to stdout.
+func SetOutput(path string) error {
+	if path == "" || path == "-" {
+		Out = os.Stdout
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	Out = file
+	return nil
+}
+
+// SecureOutput checks that output is suitable for secrets such as private
+// keys.  Output to stdout is only allowed if it is a terminal; output to a
+// file is allowed once the file has been restricted to its owner.
+func SecureOutput() error {
+	file, isFile := Out.(*os.File)
+	if !isFile {
+		return errors.New("output is not a file")
+	}
+	if file == os.Stdout {
+		if !isTerminal(int(file.Fd())) {
+			return errors.New("standard output is not a terminal; use --output to write to a file")
+		}
+		return nil
+	}
+	if err := file.Chmod(0600); err != nil {
+		return fmt.Errorf("failed to restrict permissions of output file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm() != 0600 {
+		return fmt.Errorf("output file has permissions %v rather than 0600", info.Mode().Perm())
+	}
+	return nil
+}
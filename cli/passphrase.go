@@ -0,0 +1,94 @@
+// Copyright 2017 Orinoco Payments
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// PassphraseEnvVar is the environment variable from which the passphrase is
+// read if it is not supplied on the command line
+const PassphraseEnvVar = "ETHEREAL_PASSPHRASE"
+
+// ObtainPassphrase obtains a passphrase without user interaction.  In order
+// of precedence the passphrase is taken from:
+//
+//   - the passphrase supplied directly
+//   - the first line of the passphrase file
+//   - the ETHEREAL_PASSPHRASE environment variable
+//
+// An empty string is returned if no passphrase is available.
+func ObtainPassphrase(passphrase string, file string) (string, error) {
+	if passphrase != "" {
+		return passphrase, nil
+	}
+	if file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %v", err)
+		}
+		line := strings.SplitN(string(data), "\n", 2)[0]
+		return strings.TrimRight(line, "\r"), nil
+	}
+	return os.Getenv(PassphraseEnvVar), nil
+}
+
+// PromptPassphrase prompts for a passphrase on the terminal with echo
+// disabled.  It returns an error if standard input is not a terminal.
+func PromptPassphrase() (string, error) {
+	return promptPassphrase("Passphrase: ")
+}
+
+// PromptNewPassphrase prompts for a new passphrase on the terminal, asking for
+// it twice to guard against typing errors.  It returns an error if the two
+// do not match or if standard input is not a terminal.
+func PromptNewPassphrase() (string, error) {
+	passphrase, err := promptPassphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	confirmation, err := promptPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirmation {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+func promptPassphrase(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !isTerminal(fd) {
+		return "", errors.New("no passphrase supplied and not running in a terminal")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+	restore, err := disableEcho(fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to disable terminal echo: %v", err)
+	}
+	defer restore()
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read passphrase: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
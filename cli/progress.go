@@ -0,0 +1,86 @@
+// Copyright 2017 Orinoco Payments
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	etherutils "github.com/orinocopay/go-etherutils"
+)
+
+// Progress reports the progress of a batch operation, and a summary of its
+// results, on stderr so that they do not mix with the operation's output.
+// Progress is only shown if stderr is a terminal.
+type Progress struct {
+	mutex     sync.Mutex
+	quiet     bool
+	terminal  bool
+	action    string
+	total     int
+	succeeded int
+	failed    int
+	value     *big.Int
+}
+
+// NewProgress creates a progress reporter for a batch of items.  The action
+// describes what happens to each item, for example "sent".
+func NewProgress(quiet bool, total int, action string) *Progress {
+	return &Progress{
+		quiet:    quiet,
+		terminal: isTerminal(int(os.Stderr.Fd())),
+		action:   action,
+		total:    total,
+		value:    big.NewInt(0),
+	}
+}
+
+// Done records the result of an item, along with its value if it succeeded.
+// It is safe to call from multiple goroutines.
+func (p *Progress) Done(err error, value *big.Int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err != nil {
+		p.failed++
+	} else {
+		p.succeeded++
+		if value != nil {
+			p.value.Add(p.value, value)
+		}
+	}
+	if !p.quiet && p.terminal {
+		fmt.Fprintf(os.Stderr, "\r\033[K%d/%d %s", p.succeeded+p.failed, p.total, p.action)
+	}
+}
+
+// Clear clears the progress indicator, and should be called before writing
+// output for an item so that the two do not overlap on the terminal
+func (p *Progress) Clear() {
+	if !p.quiet && p.terminal {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
+
+// Summary outputs the number of items that succeeded and failed, and their
+// total value
+func (p *Progress) Summary() {
+	if p.quiet {
+		return
+	}
+	p.Clear()
+	fmt.Fprintf(os.Stderr, "%d %s, %d failed, total value %s\n", p.succeeded, p.action, p.failed, etherutils.WeiToString(p.value, true))
+}
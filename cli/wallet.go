@@ -70,7 +70,9 @@ func ObtainWallet(chainID *big.Int, address common.Address) (accounts.Wallet, er
 	return wallet, fmt.Errorf("Failed to obtain wallet")
 }
 
-func obtainGethWallet(chainID *big.Int, address common.Address) (accounts.Wallet, error) {
+// KeystoreDir returns the directory holding the Geth keystore for the given
+// chain
+func KeystoreDir(chainID *big.Int) string {
 	keydir := node.DefaultDataDir()
 	if chainID.Cmp(params.MainnetChainConfig.ChainId) == 0 {
 		// Nothing to add for mainnet
@@ -79,8 +81,11 @@ func obtainGethWallet(chainID *big.Int, address common.Address) (accounts.Wallet
 	} else if chainID.Cmp(params.RinkebyChainConfig.ChainId) == 0 {
 		keydir = filepath.Join(keydir, "rinkeby")
 	}
-	keydir = filepath.Join(keydir, "keystore")
-	backends := []accounts.Backend{keystore.NewKeyStore(keydir, keystore.StandardScryptN, keystore.StandardScryptP)}
+	return filepath.Join(keydir, "keystore")
+}
+
+func obtainGethWallet(chainID *big.Int, address common.Address) (accounts.Wallet, error) {
+	backends := []accounts.Backend{keystore.NewKeyStore(KeystoreDir(chainID), keystore.StandardScryptN, keystore.StandardScryptP)}
 	accountManager := accounts.NewManager(backends...)
 	defer accountManager.Close()
 	account := accounts.Account{Address: address}
@@ -89,16 +94,7 @@ func obtainGethWallet(chainID *big.Int, address common.Address) (accounts.Wallet
 }
 
 func obtainGethWallets(chainID *big.Int) ([]accounts.Wallet, error) {
-	keydir := node.DefaultDataDir()
-	if chainID.Cmp(params.MainnetChainConfig.ChainId) == 0 {
-		// Nothing to add for mainnet
-	} else if chainID.Cmp(params.TestnetChainConfig.ChainId) == 0 {
-		keydir = filepath.Join(keydir, "testnet")
-	} else if chainID.Cmp(params.RinkebyChainConfig.ChainId) == 0 {
-		keydir = filepath.Join(keydir, "rinkeby")
-	}
-	keydir = filepath.Join(keydir, "keystore")
-	backends := []accounts.Backend{keystore.NewKeyStore(keydir, keystore.StandardScryptN, keystore.StandardScryptP)}
+	backends := []accounts.Backend{keystore.NewKeyStore(KeystoreDir(chainID), keystore.StandardScryptN, keystore.StandardScryptP)}
 	accountManager := accounts.NewManager(backends...)
 	defer accountManager.Close()
 	return accountManager.Wallets(), nil
@@ -14,7 +14,12 @@
 package cmd
 
 import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
 )
 
 // accountCmd represents the account command
@@ -25,6 +30,21 @@ var accountCmd = &cobra.Command{
 	Long:    `Obtain information about Ethereum accounts.`,
 }
 
+// accountKeystore returns the keystore for the chain.  Commands that manage
+// the keystore do not require a connection, so the chain ID is taken from
+// --chainid if not otherwise known and defaults to mainnet.
+func accountKeystore() (*keystore.KeyStore, string) {
+	chain := chainID
+	if chain == nil {
+		chain = big.NewInt(viper.GetInt64("chainid"))
+		if chain.Sign() == 0 {
+			chain = big.NewInt(1)
+		}
+	}
+	keydir := cli.KeystoreDir(chain)
+	return keystore.NewKeyStore(keydir, keystore.StandardScryptN, keystore.StandardScryptP), keydir
+}
+
 func init() {
 	RootCmd.AddCommand(accountCmd)
 }
@@ -0,0 +1,202 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util/etherscan"
+)
+
+var accountActivityAddress string
+var accountActivityFromBlock string
+var accountActivityToBlock string
+var accountActivityChunkSize uint64
+var accountActivityEtherscan bool
+var accountActivityOnChain bool
+
+// accountActivity is the summary of the transactions sent by an account
+type accountActivity struct {
+	sent    uint64
+	failed  uint64
+	gasUsed uint64
+	fees    *big.Int
+}
+
+// accountActivityCmd represents the account activity command
+var accountActivityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Summarise the transactions sent by an account",
+	Long: `Summarise the transactions sent by an account over a range of blocks: how many it sent, how many of those failed, the total gas they used and the total fees paid for them.  For example:
+
+    ethereal account activity --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --from-block=17000000
+
+--to-block defaults to the latest block.
+
+Nodes cannot look up transactions by sender, so by default every block in the range is obtained in full, in batches of --chunk-size blocks, along with the receipt of each transaction that the account sent.  This is slow: expect a range of a million blocks to take hours rather than minutes.  If the node holds historic state the account's nonce is used to skip the scan when nothing was sent, and to stop it once every transaction has been found.
+
+If a block explorer is configured with --etherscan-api-key, or in the config file, then the transactions are instead listed by the explorer, which is much faster.  --etherscan requires the explorer to be used, and --on-chain requires blocks to be scanned even if an explorer is configured.
+
+In quiet mode this will return 0 if the account sent any transactions in the range, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(accountActivityAddress != "", quiet, cli.ExitUsage, "--address is required")
+		cli.AssertCode(accountActivityFromBlock != "", quiet, cli.ExitUsage, "--from-block is required")
+		address, err := ens.Resolve(client, accountActivityAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain address of %s", accountActivityAddress))
+
+		fromBlock, pending, err := contractBlock(accountActivityFromBlock)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid from block")
+		cli.Assert(!pending, quiet, "Activity cannot be obtained from pending blocks")
+		toBlock, pending, err := contractBlock(accountActivityToBlock)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid to block")
+		cli.Assert(!pending, quiet, "Activity cannot be obtained from pending blocks")
+		from, to, err := logsRange(fromBlock, toBlock)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid block range")
+
+		cli.AssertCode(!accountActivityEtherscan || !accountActivityOnChain, quiet, cli.ExitUsage, "Cannot supply both --etherscan and --on-chain")
+		cli.AssertCode(!accountActivityEtherscan || explorerConfigured(), quiet, cli.ExitUsage, "--etherscan requires --etherscan-api-key or --etherscan-url")
+
+		var activity *accountActivity
+		if explorerConfigured() && !accountActivityOnChain {
+			activity, err = accountActivityExplorer(explorerClient(), address, from, to)
+			cli.ErrCheck(err, quiet, "Failed to obtain transactions from the explorer")
+		} else {
+			accountScanWarn(from, to)
+			activity, err = accountActivityScan(address, from, to)
+			cli.ErrCheck(err, quiet, "Failed to scan blocks")
+		}
+
+		if quiet {
+			if activity.sent > 0 {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(cli.Out, "Blocks:\t\t\t%d to %d\n", from, to)
+		fmt.Fprintf(cli.Out, "Transactions sent:\t%d\n", activity.sent)
+		fmt.Fprintf(cli.Out, "Failed transactions:\t%d\n", activity.failed)
+		fmt.Fprintf(cli.Out, "Gas used:\t\t%d\n", activity.gasUsed)
+		fmt.Fprintf(cli.Out, "Fees paid:\t\t%s%s\n", etherutils.WeiToString(activity.fees, true), etherFiatSuffix(activity.fees))
+		os.Exit(0)
+	},
+}
+
+// accountActivityExplorer summarises the transactions sent by an account
+// using a block explorer
+func accountActivityExplorer(explorer *etherscan.Client, address common.Address, from uint64, to uint64) (*accountActivity, error) {
+	txs, err := explorer.TransactionList(address.Hex(), from, to)
+	if err != nil {
+		return nil, err
+	}
+	activity := &accountActivity{fees: big.NewInt(0)}
+	for _, tx := range txs {
+		if !strings.EqualFold(tx.From, address.Hex()) {
+			continue
+		}
+		gasUsed, success := new(big.Int).SetString(tx.GasUsed, 10)
+		if !success || !gasUsed.IsUint64() {
+			return nil, fmt.Errorf("invalid gas used %q for transaction %s", tx.GasUsed, tx.Hash)
+		}
+		gasPrice, success := new(big.Int).SetString(tx.GasPrice, 10)
+		if !success {
+			return nil, fmt.Errorf("invalid gas price %q for transaction %s", tx.GasPrice, tx.Hash)
+		}
+		activity.sent++
+		if tx.IsError == "1" {
+			activity.failed++
+		}
+		activity.gasUsed += gasUsed.Uint64()
+		activity.fees.Add(activity.fees, gasPrice.Mul(gasPrice, gasUsed))
+	}
+	return activity, nil
+}
+
+// accountActivityScan summarises the transactions sent by an account by
+// obtaining each block in the range
+func accountActivityScan(address common.Address, from uint64, to uint64) (*accountActivity, error) {
+	activity := &accountActivity{fees: big.NewInt(0)}
+
+	// The change in the account's nonce over the range is the number of
+	// transactions that it sent, but only nodes with historic state can
+	// supply it
+	expected, known := accountActivityNonceChange(address, from, to)
+	if known {
+		diagnosticIf(verbose, fmt.Sprintf("Account sent %d transactions in the range", expected))
+		if expected == 0 {
+			return activity, nil
+		}
+	}
+
+	err := accountScan(address, from, to, accountActivityChunkSize, true, func(txs []*accountScanTx) bool {
+		for _, tx := range txs {
+			activity.sent++
+			if tx.Failed {
+				activity.failed++
+			}
+			activity.gasUsed += tx.GasUsed
+			activity.fees.Add(activity.fees, tx.Fee)
+		}
+		return known && activity.sent >= expected
+	})
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// accountActivityNonceChange obtains the number of transactions sent by an
+// account over a range of blocks from the change in its nonce, returning
+// false if the node cannot supply historic nonces
+func accountActivityNonceChange(address common.Address, from uint64, to uint64) (uint64, bool) {
+	ctx, cancel := localContext()
+	defer cancel()
+	startNonce := uint64(0)
+	if from > 0 {
+		var err error
+		startNonce, err = client.NonceAt(ctx, address, new(big.Int).SetUint64(from-1))
+		if err != nil {
+			diagnosticIf(verbose, fmt.Sprintf("Failed to obtain historic nonce: %v", err))
+			return 0, false
+		}
+	}
+	endNonce, err := client.NonceAt(ctx, address, new(big.Int).SetUint64(to))
+	if err != nil {
+		diagnosticIf(verbose, fmt.Sprintf("Failed to obtain historic nonce: %v", err))
+		return 0, false
+	}
+	if endNonce < startNonce {
+		return 0, false
+	}
+	return endNonce - startNonce, true
+}
+
+func init() {
+	accountCmd.AddCommand(accountActivityCmd)
+	accountActivityCmd.Flags().StringVar(&accountActivityAddress, "address", "", "Address of the account for which to summarise activity")
+	accountActivityCmd.Flags().StringVar(&accountActivityFromBlock, "from-block", "", "Block from which to summarise activity")
+	accountActivityCmd.Flags().StringVar(&accountActivityToBlock, "to-block", "", "Block up to which to summarise activity (defaults to the latest block)")
+	accountActivityCmd.Flags().Uint64Var(&accountActivityChunkSize, "chunk-size", 100, "Number of blocks to obtain in each batch when scanning on-chain")
+	accountActivityCmd.Flags().BoolVar(&accountActivityEtherscan, "etherscan", false, "Require the configured block explorer to be used rather than scanning blocks")
+	accountActivityCmd.Flags().BoolVar(&accountActivityOnChain, "on-chain", false, "Scan blocks even if a block explorer is configured")
+}
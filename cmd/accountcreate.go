@@ -0,0 +1,130 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/hd"
+)
+
+var accountCreateMnemonic string
+var accountCreateMnemonicFile string
+var accountCreateMnemonicPassphrase string
+var accountCreateHDPath string
+var accountCreateCount int
+
+// accountCreateCmd represents the account create command
+var accountCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create new accounts",
+	Long: `Create new accounts in the keystore.  For example:
+
+    ethereal account create --passphrase=secret
+
+This generates a new random key, stores it encrypted with the passphrase in the keystore for the chain, and outputs the address of the new account.  If no passphrase is supplied it is prompted for, twice.
+
+Accounts can instead be derived from a BIP-39 mnemonic, supplied with --mnemonic or on the first line of --mnemonic-file, so that the same accounts can be recreated later.  For example:
+
+    ethereal account create --mnemonic-file=mnemonic.txt --hd-path="m/44'/60'/0'/0/0" --count=5 --passphrase=secret
+
+--count creates that number of accounts.  When deriving from a mnemonic the last index of the path is incremented for each account, so the example above creates the accounts at m/44'/60'/0'/0/0 to m/44'/60'/0'/0/4.
+
+In quiet mode this will return 0 if the accounts are created, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(accountCreateCount > 0, quiet, cli.ExitUsage, "--count must be at least 1")
+		mnemonic, err := obtainMnemonic(accountCreateMnemonic, accountCreateMnemonicFile)
+		cli.ErrCheck(err, quiet, "Failed to obtain mnemonic")
+		cli.AssertCode(mnemonic != "" || !cmd.Flags().Changed("hd-path"), quiet, cli.ExitUsage, "--hd-path requires a mnemonic")
+
+		var seed []byte
+		var path []uint32
+		if mnemonic != "" {
+			seed, err = hd.SeedFromMnemonic(mnemonic, accountCreateMnemonicPassphrase)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid mnemonic")
+			path, err = hd.ParsePath(accountCreateHDPath)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid HD path")
+			cli.Assert(len(path) > 0, quiet, "HD path must contain at least one index")
+			cli.AssertCode(uint64(path[len(path)-1]&^hd.HardenedOffset)+uint64(accountCreateCount) <= uint64(hd.HardenedOffset), quiet, cli.ExitUsage, "--count too large for HD path")
+		}
+
+		passphrase := viper.GetString("passphrase")
+		if passphrase == "" {
+			passphrase, err = cli.PromptNewPassphrase()
+			cli.ErrCheck(err, quiet, "Failed to obtain passphrase")
+		}
+		cli.Assert(passphrase != "", quiet, "Passphrase cannot be empty")
+
+		ks, _ := accountKeystore()
+
+		for i := 0; i < accountCreateCount; i++ {
+			var key *ecdsa.PrivateKey
+			if seed == nil {
+				key, err = crypto.GenerateKey()
+				cli.ErrCheck(err, quiet, "Failed to generate key")
+			} else {
+				key, err = hd.DeriveKey(seed, path)
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to derive key at %s", hd.FormatPath(path)))
+			}
+			account, err := ks.ImportECDSA(key, passphrase)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to store key for %s", crypto.PubkeyToAddress(key.PublicKey).Hex()))
+
+			fields := log.Fields{
+				"group":   "account",
+				"command": "create",
+				"address": account.Address.Hex(),
+			}
+			if seed != nil {
+				fields["path"] = hd.FormatPath(path)
+			}
+			log.WithFields(fields).Info("success")
+
+			if !quiet {
+				if verbose {
+					if seed != nil {
+						fmt.Fprintf(cli.Out, "Path:\t\t%s\n", hd.FormatPath(path))
+					}
+					fmt.Fprintf(cli.Out, "Address:\t%s\n", account.Address.Hex())
+					fmt.Fprintf(cli.Out, "Location:\t%s\n", account.URL.Path)
+				} else {
+					fmt.Fprintln(cli.Out, account.Address.Hex())
+				}
+			}
+
+			if seed != nil {
+				path[len(path)-1]++
+			}
+		}
+		os.Exit(0)
+	},
+}
+
+func init() {
+	accountCmd.AddCommand(accountCreateCmd)
+	accountCreateCmd.Flags().String("passphrase", "", "Passphrase with which to encrypt the new accounts")
+	accountCreateCmd.Flags().String("passphrase-file", "", "File containing the passphrase with which to encrypt the new accounts on its first line")
+	accountCreateCmd.Flags().StringVar(&accountCreateMnemonic, "mnemonic", "", "BIP-39 mnemonic from which to derive the accounts")
+	accountCreateCmd.Flags().StringVar(&accountCreateMnemonicFile, "mnemonic-file", "", "File containing the BIP-39 mnemonic from which to derive the accounts on its first line")
+	accountCreateCmd.Flags().StringVar(&accountCreateMnemonicPassphrase, "mnemonic-passphrase", "", "Optional passphrase for the mnemonic")
+	accountCreateCmd.Flags().StringVar(&accountCreateHDPath, "hd-path", cli.DefaultLedgerPath, "Derivation path of the first account when deriving from a mnemonic")
+	accountCreateCmd.Flags().IntVar(&accountCreateCount, "count", 1, "Number of accounts to create")
+}
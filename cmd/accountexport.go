@@ -0,0 +1,105 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var accountExportAddress string
+var accountExportFormat string
+var accountExportConfirmed bool
+
+// accountExportCmd represents the account export command
+var accountExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the key of an account",
+	Long: `Export the key of an account from the keystore.  For example:
+
+    ethereal account export --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase=secret --i-know-what-im-doing --output=key.txt
+
+By default the key is decrypted with the passphrase and the raw private key is output.  Anyone with the private key has full control of the account, so exporting it requires --i-know-what-im-doing.  The private key is only written to stdout if it is a terminal; otherwise --output must be supplied, and the output file is restricted so that only its owner can read it.
+
+--format=keystore outputs the encrypted keystore JSON of the account instead, which can be imported in to other wallets with its passphrase.
+
+In quiet mode this will return 0 if the key is exported, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(accountExportAddress != "", quiet, cli.ExitUsage, "--address is required")
+		cli.AssertCode(common.IsHexAddress(accountExportAddress), quiet, cli.ExitUsage, fmt.Sprintf("Invalid address %s", accountExportAddress))
+		address := common.HexToAddress(accountExportAddress)
+		cli.Assert(accountExportFormat == "private-key" || accountExportFormat == "keystore", quiet, fmt.Sprintf("Unknown export format %s", accountExportFormat))
+
+		ks, keydir := accountKeystore()
+		var path string
+		for _, account := range ks.Accounts() {
+			if account.Address == address {
+				path = account.URL.Path
+				break
+			}
+		}
+		cli.Assert(path != "", quiet, fmt.Sprintf("Account %s not found in %s", address.Hex(), keydir))
+		keyJSON, err := ioutil.ReadFile(path)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read key file %s", path))
+
+		var output string
+		if accountExportFormat == "keystore" {
+			output = strings.TrimSpace(string(keyJSON))
+		} else {
+			cli.Assert(accountExportConfirmed, quiet, "Exporting a private key requires --i-know-what-im-doing")
+			cli.ErrCheck(cli.SecureOutput(), quiet, "Refusing to output private key")
+			passphrase := viper.GetString("passphrase")
+			if passphrase == "" {
+				passphrase, err = cli.PromptPassphrase()
+				cli.ErrCheck(err, quiet, "Failed to obtain passphrase")
+			}
+			key, err := keystore.DecryptKey(keyJSON, passphrase)
+			cli.ErrCheck(err, quiet, "Failed to decrypt key")
+			output = fmt.Sprintf("0x%s", hex.EncodeToString(crypto.FromECDSA(key.PrivateKey)))
+		}
+
+		log.WithFields(log.Fields{
+			"group":   "account",
+			"command": "export",
+			"address": address.Hex(),
+			"format":  accountExportFormat,
+		}).Info("success")
+
+		if !quiet {
+			fmt.Fprintln(cli.Out, output)
+		}
+		os.Exit(0)
+	},
+}
+
+func init() {
+	accountCmd.AddCommand(accountExportCmd)
+	accountExportCmd.Flags().StringVar(&accountExportAddress, "address", "", "Address of the account to export")
+	accountExportCmd.Flags().StringVar(&accountExportFormat, "format", "private-key", "What to export (private-key or keystore)")
+	accountExportCmd.Flags().BoolVar(&accountExportConfirmed, "i-know-what-im-doing", false, "Confirm that the private key should be exported")
+	accountExportCmd.Flags().String("passphrase", "", "Passphrase with which to decrypt the key")
+	accountExportCmd.Flags().String("passphrase-file", "", "File containing the passphrase with which to decrypt the key on its first line")
+}
@@ -0,0 +1,122 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var accountImportPrivateKey string
+var accountImportPrivateKeyFile string
+var accountImportForce bool
+
+// accountImportCmd represents the account import command
+var accountImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a private key",
+	Long: `Import a private key in to the keystore.  For example:
+
+    ethereal account import --private-key=0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318 --passphrase=secret
+
+The private key is supplied as hex, either with --private-key or on the first line of --private-key-file.  The key is stored encrypted with the passphrase in the keystore for the chain, and the address of the account is output.  If no passphrase is supplied it is prompted for, twice.
+
+If the account already exists in the keystore the key is not imported, unless --force is supplied in which case the existing key is replaced.
+
+In quiet mode this will return 0 if the key is imported, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(accountImportPrivateKey != "" || accountImportPrivateKeyFile != "", quiet, cli.ExitUsage, "--private-key or --private-key-file is required")
+		cli.AssertCode(accountImportPrivateKey == "" || accountImportPrivateKeyFile == "", quiet, cli.ExitUsage, "Cannot supply both --private-key and --private-key-file")
+
+		input := accountImportPrivateKey
+		if accountImportPrivateKeyFile != "" {
+			data, err := ioutil.ReadFile(accountImportPrivateKeyFile)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read %s", accountImportPrivateKeyFile))
+			input = strings.SplitN(string(data), "\n", 2)[0]
+		}
+		input = strings.TrimPrefix(strings.TrimSpace(input), "0x")
+		cli.Assert(len(input) == 64, quiet, "Private key must be 32 bytes")
+		key, err := crypto.HexToECDSA(input)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid private key")
+		address := crypto.PubkeyToAddress(key.PublicKey)
+
+		ks, _ := accountKeystore()
+		existing := make(map[string][]byte)
+		for _, account := range ks.Accounts() {
+			if account.Address == address {
+				data, err := ioutil.ReadFile(account.URL.Path)
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read existing key file %s", account.URL.Path))
+				existing[account.URL.Path] = data
+			}
+		}
+		cli.Assert(len(existing) == 0 || accountImportForce, quiet, fmt.Sprintf("Account %s already exists in the keystore; use --force to replace it", address.Hex()))
+
+		passphrase := viper.GetString("passphrase")
+		if passphrase == "" {
+			passphrase, err = cli.PromptNewPassphrase()
+			cli.ErrCheck(err, quiet, "Failed to obtain passphrase")
+		}
+		cli.Assert(passphrase != "", quiet, "Passphrase cannot be empty")
+
+		// Remove the existing key files, restoring them if the import fails
+		for path := range existing {
+			cli.ErrCheck(os.Remove(path), quiet, fmt.Sprintf("Failed to remove existing key file %s", path))
+		}
+		ks, _ = accountKeystore()
+		account, err := ks.ImportECDSA(key, passphrase)
+		if err != nil {
+			for path, data := range existing {
+				if restoreErr := ioutil.WriteFile(path, data, 0600); restoreErr != nil {
+					cli.Warn(quiet, fmt.Sprintf("Failed to restore key file %s: %v", path, restoreErr))
+				}
+			}
+		}
+		cli.ErrCheck(err, quiet, "Failed to import key")
+
+		log.WithFields(log.Fields{
+			"group":    "account",
+			"command":  "import",
+			"address":  account.Address.Hex(),
+			"replaced": len(existing) > 0,
+		}).Info("success")
+
+		if !quiet {
+			if verbose {
+				fmt.Fprintf(cli.Out, "Address:\t%s\n", account.Address.Hex())
+				fmt.Fprintf(cli.Out, "Location:\t%s\n", account.URL.Path)
+			} else {
+				fmt.Fprintln(cli.Out, account.Address.Hex())
+			}
+		}
+		os.Exit(0)
+	},
+}
+
+func init() {
+	accountCmd.AddCommand(accountImportCmd)
+	accountImportCmd.Flags().StringVar(&accountImportPrivateKey, "private-key", "", "Private key to import, in hex")
+	accountImportCmd.Flags().StringVar(&accountImportPrivateKeyFile, "private-key-file", "", "File containing the private key to import on its first line")
+	accountImportCmd.Flags().BoolVar(&accountImportForce, "force", false, "Replace the key if the account already exists in the keystore")
+	accountImportCmd.Flags().String("passphrase", "", "Passphrase with which to encrypt the imported key")
+	accountImportCmd.Flags().String("passphrase-file", "", "File containing the passphrase with which to encrypt the imported key on its first line")
+}
@@ -85,12 +85,12 @@ Note that this will only work for filesystem-based keystores.  Hardware wallets
 
 In quiet mode this will return 0 if the account was successfully decoded, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert((accountKeysAddress != "" && accountKeysPassphrase != "") || accountKeysPrivateKey != "", quiet, "--privatekey or both of --address and --passphrase are required")
+		cli.AssertCode((accountKeysAddress != "" && accountKeysPassphrase != "") || accountKeysPrivateKey != "", quiet, cli.ExitUsage, "--privatekey or both of --address and --passphrase are required")
 
 		var key *ecdsa.PrivateKey
 		if accountKeysPrivateKey != "" {
 			key, err = crypto.HexToECDSA(strings.TrimPrefix(accountKeysPrivateKey, "0x"))
-			cli.ErrCheck(err, quiet, "Invalid private key")
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid private key")
 			if quiet {
 				os.Exit(0)
 			}
@@ -124,9 +124,9 @@ In quiet mode this will return 0 if the account was successfully decoded, otherw
 				os.Exit(0)
 			}
 		}
-		fmt.Printf("Private key:\t\t0x%032x\n", key.D)
-		fmt.Printf("Public key:\t\t0x%s\n", hex.EncodeToString(crypto.FromECDSAPub(&key.PublicKey)))
-		fmt.Printf("Ethereum address:\t%s\n", crypto.PubkeyToAddress(key.PublicKey).Hex())
+		fmt.Fprintf(cli.Out, "Private key:\t\t0x%032x\n", key.D)
+		fmt.Fprintf(cli.Out, "Public key:\t\t0x%s\n", hex.EncodeToString(crypto.FromECDSAPub(&key.PublicKey)))
+		fmt.Fprintf(cli.Out, "Ethereum address:\t%s\n", crypto.PubkeyToAddress(key.PublicKey).Hex())
 	},
 }
 
@@ -15,14 +15,19 @@ package cmd
 
 import (
 	"fmt"
+	"math/big"
 	"os"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/orinocopay/go-etherutils"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
 )
 
+var accountListBalances bool
+var accountListNames bool
+
 // accountListCmd represents the account list command
 var accountListCmd = &cobra.Command{
 	Use:   "list",
@@ -31,51 +36,159 @@ var accountListCmd = &cobra.Command{
 
     ethereal account list
 
+This lists the accounts in the Geth and Parity keystores for the chain, along with those on connected hardware wallets.  --balances adds the balance of each account and --names adds its ENS name, if it has a reverse record.  Balances and names are obtained concurrently, and the number of concurrent lookups can be changed with --concurrency.  By default the command stops at the first failed lookup; --continue-on-error displays failures and continues.  Verbose output shows all of this along with the location of each account and its next nonce.
+
+Use --format=csv to output a row for each account in CSV format, or --format=json to output a single JSON array with an entry for each account.
+
 In quiet mode this will return 0 if any accounts are found, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		wallets, err := cli.ObtainWallets(chainID)
-		foundAccounts := false
-		if err == nil {
-			for _, wallet := range wallets {
-				for _, account := range wallet.Accounts() {
-					foundAccounts = true
-					if !quiet {
-						if !verbose {
-							fmt.Println(account.Address.Hex())
-						} else {
-							fmt.Printf("Location:\t%s\n", account.URL)
-							fmt.Printf("Address:\t%s\n", account.Address.Hex())
-							name, err := ens.ReverseResolve(client, &account.Address)
-							if err == nil {
-								fmt.Printf("Name:\t\t%s\n", name)
-							}
-							ctx, cancel := localContext()
-							defer cancel()
-							balance, err := client.BalanceAt(ctx, account.Address, nil)
-							if err == nil {
-								fmt.Printf("Balance:\t%s\n", etherutils.WeiToString(balance, true))
-							}
-							nonce, err := client.PendingNonceAt(ctx, account.Address)
-							if err == nil {
-								fmt.Printf("Next nonce:\t%v\n", nonce)
-							}
-							fmt.Println("")
-						}
-					}
-				}
+		cli.ErrCheck(err, quiet, "Failed to obtain wallets")
+		accounts := make([]*accountListResult, 0)
+		for _, wallet := range wallets {
+			for _, account := range wallet.Accounts() {
+				accounts = append(accounts, &accountListResult{address: account.Address, location: account.URL.String()})
 			}
 		}
 
 		if quiet {
-			if foundAccounts {
+			if len(accounts) > 0 {
 				os.Exit(0)
-			} else {
-				os.Exit(1)
 			}
+			os.Exit(1)
+		}
+
+		balances := accountListBalances || verbose
+		names := accountListNames || verbose
+		_, errs := concurrentLookups(len(accounts), func(i int) (interface{}, error) {
+			return nil, accountListLookup(accounts[i], balances, names)
+		})
+
+		jsonOutput := jsonFormat()
+		csv := !jsonOutput && csvFormat()
+		table := newCSVTable("address", "location", "name", "balance_wei", "balance", "nonce", "error")
+		jsonResults := make([]*accountListJSON, 0, len(accounts))
+		failed := false
+		for i, account := range accounts {
+			if errs[i] != nil {
+				failed = true
+				if !continueOnError {
+					cli.ErrCheck(errs[i], quiet, fmt.Sprintf("Failed to obtain information for %s", account.address.Hex()))
+				}
+			}
+			result := &accountListJSON{Address: account.address.Hex(), Location: account.location, Name: account.name}
+			if account.balance != nil {
+				result.Balance = account.balance.String()
+			}
+			if account.nonce != nil {
+				result.Nonce = fmt.Sprintf("%d", *account.nonce)
+			}
+			if errs[i] != nil {
+				result.Error = errs[i].Error()
+			}
+
+			switch {
+			case jsonOutput:
+				jsonResults = append(jsonResults, result)
+			case csv:
+				balance := ""
+				if account.balance != nil {
+					balance = etherutils.WeiToString(account.balance, true)
+				}
+				table.add(result.Address, result.Location, result.Name, result.Balance, balance, result.Nonce, result.Error)
+			case verbose:
+				fmt.Fprintf(cli.Out, "Location:\t%s\n", account.location)
+				fmt.Fprintf(cli.Out, "Address:\t%s\n", result.Address)
+				if account.name != "" {
+					fmt.Fprintf(cli.Out, "Name:\t\t%s\n", account.name)
+				}
+				if account.balance != nil {
+					fmt.Fprintf(cli.Out, "Balance:\t%s\n", etherutils.WeiToString(account.balance, true))
+				}
+				if account.nonce != nil {
+					fmt.Fprintf(cli.Out, "Next nonce:\t%v\n", *account.nonce)
+				}
+				if errs[i] != nil {
+					fmt.Fprintf(cli.Out, "Error:\t\t%v\n", errs[i])
+				}
+				fmt.Fprintln(cli.Out, "")
+			default:
+				line := result.Address
+				if names {
+					line = fmt.Sprintf("%s\t%s", line, account.name)
+				}
+				if errs[i] != nil {
+					line = fmt.Sprintf("%s\tError: %v", line, errs[i])
+				} else if balances {
+					line = fmt.Sprintf("%s\t%s", line, etherutils.WeiToString(account.balance, true))
+				}
+				fmt.Fprintln(cli.Out, line)
+			}
+		}
+		if jsonOutput {
+			writeJSONArray(jsonResults)
+		}
+		if csv {
+			table.write()
+		}
+		if failed {
+			os.Exit(1)
 		}
 	},
 }
 
+// accountListResult is an account along with the information obtained about
+// it
+type accountListResult struct {
+	address  common.Address
+	location string
+	name     string
+	balance  *big.Int
+	nonce    *uint64
+}
+
+// accountListJSON is the JSON representation of an account
+type accountListJSON struct {
+	Address  string `json:"address"`
+	Location string `json:"location"`
+	Name     string `json:"name,omitempty"`
+	Balance  string `json:"balance,omitempty"`
+	Nonce    string `json:"nonce,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// accountListLookup obtains the requested information about an account.  An
+// account without a reverse record is not an error.
+func accountListLookup(account *accountListResult, balances bool, names bool) error {
+	if names {
+		name, err := ens.ReverseResolve(client, &account.address)
+		if err == nil {
+			account.name = name
+		}
+	}
+	if balances {
+		ctx, cancel := localContext()
+		defer cancel()
+		balance, err := client.BalanceAt(ctx, account.address, nil)
+		if err != nil {
+			return fmt.Errorf("failed to obtain balance: %v", err)
+		}
+		account.balance = balance
+		if verbose {
+			nonce, err := client.PendingNonceAt(ctx, account.address)
+			if err != nil {
+				return fmt.Errorf("failed to obtain nonce: %v", err)
+			}
+			account.nonce = &nonce
+		}
+	}
+	return nil
+}
+
 func init() {
 	accountCmd.AddCommand(accountListCmd)
+	accountListCmd.Flags().BoolVar(&accountListBalances, "balances", false, "Show the balance of each account")
+	accountListCmd.Flags().BoolVar(&accountListNames, "names", false, "Show the ENS name of each account")
+	addFormatFlagWithJSON(accountListCmd)
+	addConcurrencyFlags(accountListCmd)
 }
@@ -14,11 +14,10 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
 )
@@ -28,26 +27,44 @@ var accountNonceAddress string
 // accountNonceCmd represents the account nonce command
 var accountNonceCmd = &cobra.Command{
 	Use:   "nonce",
-	Short: "Obtain the current nonce for an account",
-	Long: `Start the auction for a name with the Ethereum Name Service (ENS).  For example:
+	Short: "Obtain the current nonces for an account",
+	Long: `Obtain the latest and pending nonces for an account.  For example:
 
-    ethereal account nonce address=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+    ethereal account nonce --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
-In quiet mode this will return 0 if the nonce can be obtained, otherwise 1.`,
+The latest nonce is that of the most recently mined transaction, and the pending nonce includes transactions that have been sent but not yet mined.  The difference between the two is the number of transactions that are queued for the account.
+
+In quiet mode this will return 0 if there are no queued transactions for the account, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(accountNonceAddress != "", quiet, "--address is required")
+		cli.AssertCode(accountNonceAddress != "", quiet, cli.ExitUsage, "--address is required")
 		address, err := ens.Resolve(client, accountNonceAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain address of %s", accountNonceAddress))
 
-		ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+		ctx, cancel := localContext()
 		defer cancel()
 
-		nonce, err := client.PendingNonceAt(ctx, address)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain nonce for %s", accountNonceAddress))
+		latestNonce, err := client.NonceAt(ctx, address, nil)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain latest nonce for %s", accountNonceAddress))
+
+		pendingNonce, err := client.PendingNonceAt(ctx, address)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain pending nonce for %s", accountNonceAddress))
 
-		if !quiet {
-			fmt.Println(nonce)
+		// Nodes can briefly report a pending nonce behind the latest nonce
+		queued := uint64(0)
+		if pendingNonce > latestNonce {
+			queued = pendingNonce - latestNonce
 		}
+
+		if quiet {
+			if queued == 0 {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(cli.Out, "Latest nonce:\t\t%d\n", latestNonce)
+		fmt.Fprintf(cli.Out, "Pending nonce:\t\t%d\n", pendingNonce)
+		fmt.Fprintf(cli.Out, "Queued transactions:\t%d\n", queued)
 	},
 }
 
@@ -0,0 +1,192 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+// accountScanWarning is the number of blocks above which a scan warns that
+// it will be slow
+const accountScanWarning = 10000
+
+// accountScanBlock contains the fields of a block required to find the
+// transactions of an account
+type accountScanBlock struct {
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	Transactions []struct {
+		Hash     common.Hash     `json:"hash"`
+		Nonce    hexutil.Uint64  `json:"nonce"`
+		From     common.Address  `json:"from"`
+		To       *common.Address `json:"to"`
+		Value    *hexutil.Big    `json:"value"`
+		GasPrice *hexutil.Big    `json:"gasPrice"`
+		Input    hexutil.Bytes   `json:"input"`
+	} `json:"transactions"`
+}
+
+// accountScanReceipt contains the fields of a receipt required to summarise
+// a transaction
+type accountScanReceipt struct {
+	Status            *hexutil.Uint64 `json:"status"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+}
+
+// accountScanTx is a transaction found by scanning blocks
+type accountScanTx struct {
+	BlockNumber     uint64
+	Timestamp       uint64
+	Hash            common.Hash
+	Nonce           uint64
+	From            common.Address
+	To              *common.Address
+	Value           *big.Int
+	Input           []byte
+	ContractAddress *common.Address
+	Failed          bool
+	GasUsed         uint64
+	Fee             *big.Int
+}
+
+// accountScanWarn warns if a scan of a range of blocks will be slow
+func accountScanWarn(from uint64, to uint64) {
+	if to-from+1 > accountScanWarning {
+		cli.Warn(quiet, fmt.Sprintf("scanning %d blocks on-chain will take a long time; configuring a block explorer with --etherscan-api-key is much faster", to-from+1))
+	}
+}
+
+// accountScan obtains the transactions of an account by obtaining each block
+// in a range in full, in batches of chunkSize blocks.  If sentOnly is true
+// only the transactions that the account sent are returned, otherwise those
+// that it received are also returned.  done is called after each batch, and
+// if it returns true the scan stops.
+func accountScan(address common.Address, from uint64, to uint64, chunkSize uint64, sentOnly bool, done func([]*accountScanTx) bool) error {
+	if chunkSize == 0 {
+		chunkSize = 1
+	}
+	for start := from; start <= to; start += chunkSize {
+		end := start + chunkSize - 1
+		if end > to || end < start {
+			end = to
+		}
+		diagnosticIf(verbose, fmt.Sprintf("Scanning blocks %d to %d", start, end))
+		txs, err := accountScanChunk(address, start, end, sentOnly)
+		if err != nil {
+			return err
+		}
+		if done(txs) || end == to {
+			break
+		}
+	}
+	return nil
+}
+
+// accountScanChunk obtains the transactions of an account in a range of
+// blocks
+func accountScanChunk(address common.Address, start uint64, end uint64, sentOnly bool) ([]*accountScanTx, error) {
+	blocks := make([]*accountScanBlock, end-start+1)
+	batch := make([]rpc.BatchElem, len(blocks))
+	for i := range blocks {
+		blocks[i] = &accountScanBlock{}
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(start + uint64(i)), true},
+			Result: blocks[i],
+		}
+	}
+	if err := accountScanBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to obtain blocks %d to %d: %v", start, end, err)
+	}
+
+	txs := make([]*accountScanTx, 0)
+	gasPrices := make([]*big.Int, 0)
+	receipts := make([]*accountScanReceipt, 0)
+	batch = batch[:0]
+	for i, block := range blocks {
+		for _, tx := range block.Transactions {
+			if tx.From != address && (sentOnly || tx.To == nil || *tx.To != address) {
+				continue
+			}
+			value := tx.Value.ToInt()
+			if value == nil {
+				value = big.NewInt(0)
+			}
+			txs = append(txs, &accountScanTx{
+				BlockNumber: start + uint64(i),
+				Timestamp:   uint64(block.Timestamp),
+				Hash:        tx.Hash,
+				Nonce:       uint64(tx.Nonce),
+				From:        tx.From,
+				To:          tx.To,
+				Value:       value,
+				Input:       tx.Input,
+			})
+			gasPrices = append(gasPrices, tx.GasPrice.ToInt())
+			receipt := &accountScanReceipt{}
+			receipts = append(receipts, receipt)
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{tx.Hash},
+				Result: receipt,
+			})
+		}
+	}
+	if len(batch) == 0 {
+		return txs, nil
+	}
+	if err := accountScanBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to obtain receipts for blocks %d to %d: %v", start, end, err)
+	}
+
+	for i, receipt := range receipts {
+		tx := txs[i]
+		tx.Failed = receipt.Status != nil && uint64(*receipt.Status) == 0
+		tx.GasUsed = uint64(receipt.GasUsed)
+		tx.ContractAddress = receipt.ContractAddress
+		// Receipts from before London do not contain the effective gas
+		// price, which is then the gas price of the transaction
+		gasPrice := gasPrices[i]
+		if receipt.EffectiveGasPrice != nil {
+			gasPrice = receipt.EffectiveGasPrice.ToInt()
+		}
+		tx.Fee = big.NewInt(0)
+		if gasPrice != nil {
+			tx.Fee.Mul(gasPrice, new(big.Int).SetUint64(tx.GasUsed))
+		}
+	}
+	return txs, nil
+}
+
+// accountScanBatch sends a batch of calls, failing if any of them fail
+func accountScanBatch(batch []rpc.BatchElem) error {
+	ctx, cancel := localContext()
+	defer cancel()
+	if err := rpcClient.BatchCallContext(ctx, batch); err != nil {
+		return err
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			return elem.Error
+		}
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
+	"github.com/wealdtech/ethereal/util/etherscan"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+var accountTransactionsAddress string
+var accountTransactionsFromBlock string
+var accountTransactionsToBlock string
+var accountTransactionsChunkSize uint64
+var accountTransactionsOnChain bool
+var accountTransactionsJSON bool
+var accountTransactionsAbi string
+var accountTransactionsOnline bool
+
+// accountTransactionsTransferTopic is the topic of ERC-20 and ERC-721
+// Transfer events
+var accountTransactionsTransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// accountTransactionsTransferSingleTopic is the topic of ERC-1155
+// TransferSingle events
+var accountTransactionsTransferSingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+
+// accountTransaction is a transaction, internal transaction or token
+// transfer in the history of an account
+type accountTransaction struct {
+	// Type is one of "transaction", "internal", "erc20", "erc721" or
+	// "erc1155"
+	Type            string `json:"type"`
+	BlockNumber     uint64 `json:"blockNumber"`
+	Timestamp       uint64 `json:"timestamp,omitempty"`
+	Hash            string `json:"hash"`
+	From            string `json:"from"`
+	To              string `json:"to,omitempty"`
+	ContractAddress string `json:"contractAddress,omitempty"`
+	Value           string `json:"value,omitempty"`
+	Token           string `json:"token,omitempty"`
+	TokenSymbol     string `json:"tokenSymbol,omitempty"`
+	TokenDecimals   string `json:"tokenDecimals,omitempty"`
+	TokenID         string `json:"tokenId,omitempty"`
+	Input           string `json:"input,omitempty"`
+	Function        string `json:"function,omitempty"`
+	GasUsed         uint64 `json:"gasUsed,omitempty"`
+	Fee             string `json:"fee,omitempty"`
+	Failed          bool   `json:"failed,omitempty"`
+}
+
+// accountTransactionsCmd represents the account transactions command
+var accountTransactionsCmd = &cobra.Command{
+	Use:   "transactions",
+	Short: "List the historical transactions of an account",
+	Long: `List the transactions sent and received by an account, along with the internal transactions and token transfers in which it took part.  For example:
+
+    ethereal account transactions --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --from-block=17000000
+
+--to-block defaults to the latest block.  The data of each transaction is decoded where its function is known; further functions can be supplied with --abi, and --online looks up unknown functions with 4byte.directory.  Use --json to output a single JSON array with an entry for each item.
+
+The history is obtained from the block explorer configured with --etherscan-api-key and --etherscan-url, or in the config file.  If no explorer is configured, or --on-chain is supplied, the history is instead obtained from the node: each block in the range is obtained in full, in batches of --chunk-size blocks, and token transfers are found from their events.  This is slow, requires --from-block, and cannot find internal transactions or ERC-1155 batch transfers.
+
+In quiet mode this will return 0 if the account has any history in the range, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(accountTransactionsAddress != "", quiet, cli.ExitUsage, "--address is required")
+		address, err := ens.Resolve(client, accountTransactionsAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain address of %s", accountTransactionsAddress))
+
+		useExplorer := explorerConfigured() && !accountTransactionsOnChain
+		fromBlockStr := accountTransactionsFromBlock
+		if fromBlockStr == "" {
+			cli.AssertCode(useExplorer, quiet, cli.ExitUsage, "--from-block is required when scanning blocks on-chain")
+			fromBlockStr = "0"
+		}
+		fromBlock, pending, err := contractBlock(fromBlockStr)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid from block")
+		cli.Assert(!pending, quiet, "History cannot be obtained from pending blocks")
+		toBlock, pending, err := contractBlock(accountTransactionsToBlock)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid to block")
+		cli.Assert(!pending, quiet, "History cannot be obtained from pending blocks")
+		from, to, err := logsRange(fromBlock, toBlock)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid block range")
+
+		var txs []*accountTransaction
+		if useExplorer {
+			txs, err = accountTransactionsExplorer(explorerClient(), address, from, to)
+			cli.ErrCheck(err, quiet, "Failed to obtain history from the explorer")
+		} else {
+			accountScanWarn(from, to)
+			txs, err = accountTransactionsScan(address, from, to)
+			cli.ErrCheck(err, quiet, "Failed to obtain history")
+		}
+		sort.SliceStable(txs, func(i, j int) bool {
+			return txs[i].BlockNumber < txs[j].BlockNumber
+		})
+
+		if quiet {
+			if len(txs) > 0 {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+
+		txdata.InitFunctionMap()
+		if accountTransactionsOnline {
+			txdata.EnableOnlineLookup()
+		}
+		if accountTransactionsAbi != "" {
+			transactionAddABIFunctions(accountTransactionsAbi)
+		}
+		for _, tx := range txs {
+			if tx.To == "" {
+				// Contract creation
+				continue
+			}
+			if input, err := hexutil.Decode(tx.Input); err == nil && len(input) >= 4 {
+				if _, _, _, _, exists := txdata.DataToFunction(input); exists {
+					tx.Function = txdata.DataToString(input)
+				}
+			}
+		}
+
+		if accountTransactionsJSON {
+			writeJSONArray(txs)
+			os.Exit(0)
+		}
+		for _, tx := range txs {
+			fmt.Fprintf(cli.Out, "%d\t%s\t%s\n", tx.BlockNumber, tx.Hash, accountTransactionsDescription(tx, address))
+		}
+		os.Exit(0)
+	},
+}
+
+// accountTransactionsExplorer obtains the history of an account from a block
+// explorer
+func accountTransactionsExplorer(explorer *etherscan.Client, address common.Address, from uint64, to uint64) ([]*accountTransaction, error) {
+	lists := []struct {
+		txType string
+		list   func(string, uint64, uint64) ([]*etherscan.Transaction, error)
+	}{
+		{txType: "transaction", list: explorer.TransactionList},
+		{txType: "internal", list: explorer.InternalTransactionList},
+		{txType: "erc20", list: explorer.TokenTransferList},
+		{txType: "erc721", list: explorer.NFTTransferList},
+		{txType: "erc1155", list: explorer.MultiTokenTransferList},
+	}
+
+	txs := make([]*accountTransaction, 0)
+	for _, list := range lists {
+		diagnosticIf(verbose, fmt.Sprintf("Obtaining %s history", list.txType))
+		items, err := list.list(address.Hex(), from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain %s history: %v", list.txType, err)
+		}
+		for _, item := range items {
+			tx, err := accountTransactionFromExplorer(list.txType, item)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+// accountTransactionFromExplorer creates a history item from an item listed
+// by a block explorer
+func accountTransactionFromExplorer(txType string, item *etherscan.Transaction) (*accountTransaction, error) {
+	tx := &accountTransaction{
+		Type:  txType,
+		Hash:  item.Hash,
+		From:  accountTransactionsAddressHex(item.From),
+		To:    accountTransactionsAddressHex(item.To),
+		Value: item.Value,
+	}
+	if _, err := fmt.Sscan(item.BlockNumber, &tx.BlockNumber); err != nil {
+		return nil, fmt.Errorf("invalid block number %q for %s", item.BlockNumber, item.Hash)
+	}
+	fmt.Sscan(item.TimeStamp, &tx.Timestamp)
+
+	switch txType {
+	case "transaction", "internal":
+		tx.ContractAddress = accountTransactionsAddressHex(item.ContractAddress)
+		tx.Failed = item.IsError == "1"
+		if txType == "transaction" {
+			if item.Input != "0x" {
+				tx.Input = item.Input
+			}
+			fmt.Sscan(item.GasUsed, &tx.GasUsed)
+			if gasPrice, success := new(big.Int).SetString(item.GasPrice, 10); success {
+				tx.Fee = gasPrice.Mul(gasPrice, new(big.Int).SetUint64(tx.GasUsed)).String()
+			}
+		}
+	default:
+		tx.Token = accountTransactionsAddressHex(item.ContractAddress)
+		tx.TokenSymbol = item.TokenSymbol
+		tx.TokenDecimals = item.TokenDecimal
+		tx.TokenID = item.TokenID
+		if txType == "erc1155" {
+			tx.Value = item.TokenValue
+		}
+	}
+	return tx, nil
+}
+
+// accountTransactionsAddressHex provides an address listed by a block
+// explorer in checksummed form, or an empty string if there is no address
+func accountTransactionsAddressHex(input string) string {
+	if input == "" {
+		return ""
+	}
+	return common.HexToAddress(input).Hex()
+}
+
+// accountTransactionsScan obtains the history of an account from the node
+func accountTransactionsScan(address common.Address, from uint64, to uint64) ([]*accountTransaction, error) {
+	txs := make([]*accountTransaction, 0)
+	err := accountScan(address, from, to, accountTransactionsChunkSize, false, func(scanned []*accountScanTx) bool {
+		for _, scannedTx := range scanned {
+			tx := &accountTransaction{
+				Type:        "transaction",
+				BlockNumber: scannedTx.BlockNumber,
+				Timestamp:   scannedTx.Timestamp,
+				Hash:        scannedTx.Hash.Hex(),
+				From:        scannedTx.From.Hex(),
+				Value:       scannedTx.Value.String(),
+				GasUsed:     scannedTx.GasUsed,
+				Fee:         scannedTx.Fee.String(),
+				Failed:      scannedTx.Failed,
+			}
+			if scannedTx.To != nil {
+				tx.To = scannedTx.To.Hex()
+			}
+			if scannedTx.ContractAddress != nil && scannedTx.To == nil {
+				tx.ContractAddress = scannedTx.ContractAddress.Hex()
+			}
+			if len(scannedTx.Input) > 0 {
+				tx.Input = hexutil.Encode(scannedTx.Input)
+			}
+			txs = append(txs, tx)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := accountTransactionsTransfers(address, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(txs, transfers...), nil
+}
+
+// accountTransactionsTransfers obtains the token transfers of an account
+// from their events
+func accountTransactionsTransfers(address common.Address, from uint64, to uint64) ([]*accountTransaction, error) {
+	addressTopic := common.BytesToHash(address.Bytes())
+	queries := [][][]common.Hash{
+		{{accountTransactionsTransferTopic}, {addressTopic}},
+		{{accountTransactionsTransferTopic}, nil, {addressTopic}},
+		{{accountTransactionsTransferSingleTopic}, nil, {addressTopic}},
+		{{accountTransactionsTransferSingleTopic}, nil, nil, {addressTopic}},
+	}
+
+	// Transfers from an account to itself match more than one query
+	seen := make(map[string]bool)
+	tokens := make(map[common.Address]*accountTransactionsToken)
+	transfers := make([]*accountTransaction, 0)
+	for _, topics := range queries {
+		logs, err := filterLogs(ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Topics:    topics,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			key := fmt.Sprintf("%s:%d", log.TxHash.Hex(), log.Index)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			transfer := accountTransactionFromLog(log)
+			if transfer == nil {
+				continue
+			}
+			token, exists := tokens[log.Address]
+			if !exists {
+				token = accountTransactionsTokenDetails(log.Address, transfer.Type)
+				tokens[log.Address] = token
+			}
+			transfer.TokenSymbol = token.symbol
+			transfer.TokenDecimals = token.decimals
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
+
+// accountTransactionFromLog creates a history item from a token transfer
+// event, or returns nil if the event is not a transfer
+func accountTransactionFromLog(log types.Log) *accountTransaction {
+	transfer := &accountTransaction{
+		BlockNumber: log.BlockNumber,
+		Hash:        log.TxHash.Hex(),
+		Token:       log.Address.Hex(),
+	}
+	switch {
+	case log.Topics[0] == accountTransactionsTransferTopic && len(log.Topics) == 3 && len(log.Data) == 32:
+		transfer.Type = "erc20"
+		transfer.Value = new(big.Int).SetBytes(log.Data).String()
+	case log.Topics[0] == accountTransactionsTransferTopic && len(log.Topics) == 4:
+		transfer.Type = "erc721"
+		transfer.TokenID = log.Topics[3].Big().String()
+	case log.Topics[0] == accountTransactionsTransferSingleTopic && len(log.Topics) == 4 && len(log.Data) == 64:
+		transfer.Type = "erc1155"
+		transfer.TokenID = new(big.Int).SetBytes(log.Data[:32]).String()
+		transfer.Value = new(big.Int).SetBytes(log.Data[32:]).String()
+		transfer.From = common.BytesToAddress(log.Topics[2].Bytes()).Hex()
+		transfer.To = common.BytesToAddress(log.Topics[3].Bytes()).Hex()
+		return transfer
+	default:
+		return nil
+	}
+	transfer.From = common.BytesToAddress(log.Topics[1].Bytes()).Hex()
+	transfer.To = common.BytesToAddress(log.Topics[2].Bytes()).Hex()
+	return transfer
+}
+
+// accountTransactionsToken contains the details of a token
+type accountTransactionsToken struct {
+	symbol   string
+	decimals string
+}
+
+// accountTransactionsTokenDetails obtains the symbol of a token, and its
+// decimals if it is an ERC-20 token
+func accountTransactionsTokenDetails(address common.Address, txType string) *accountTransactionsToken {
+	token := &accountTransactionsToken{}
+	contract, err := contracts.NewERC20(address, client)
+	if err != nil {
+		return token
+	}
+	token.symbol = tokenSymbol(contract)
+	if txType == "erc20" {
+		if decimals, err := contract.Decimals(nil); err == nil {
+			token.decimals = fmt.Sprintf("%d", decimals)
+		}
+	}
+	return token
+}
+
+// accountTransactionsDescription describes a history item from the point of
+// view of an account
+func accountTransactionsDescription(tx *accountTransaction, address common.Address) string {
+	var value string
+	switch tx.Type {
+	case "transaction", "internal":
+		amount, _ := new(big.Int).SetString(tx.Value, 10)
+		if amount == nil {
+			amount = big.NewInt(0)
+		}
+		value = etherutils.WeiToString(amount, true)
+	case "erc20":
+		value = accountTransactionsTokenValue(tx)
+	case "erc721":
+		value = fmt.Sprintf("%s #%s", accountTransactionsTokenName(tx), tx.TokenID)
+	case "erc1155":
+		value = fmt.Sprintf("%s of %s #%s", tx.Value, accountTransactionsTokenName(tx), tx.TokenID)
+	}
+
+	var res string
+	switch {
+	case tx.To == "" && tx.ContractAddress != "":
+		res = fmt.Sprintf("Created contract %s", tx.ContractAddress)
+	case strings.EqualFold(tx.From, address.Hex()):
+		res = fmt.Sprintf("Sent %s to %s", value, tx.To)
+	default:
+		res = fmt.Sprintf("Received %s from %s", value, tx.From)
+	}
+	if tx.Type == "internal" {
+		res = "Internal: " + res
+	}
+	if tx.Failed {
+		res += " (failed)"
+	}
+	if tx.Function != "" {
+		res = fmt.Sprintf("%s\t%s", res, tx.Function)
+	}
+	return res
+}
+
+// accountTransactionsTokenValue provides the value of an ERC-20 transfer
+func accountTransactionsTokenValue(tx *accountTransaction) string {
+	amount, _ := new(big.Int).SetString(tx.Value, 10)
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+	var decimals uint8
+	if _, err := fmt.Sscan(tx.TokenDecimals, &decimals); err != nil {
+		return fmt.Sprintf("%v units of %s", amount, accountTransactionsTokenName(tx))
+	}
+	return fmt.Sprintf("%s %s", util.TokenValueToString(amount, decimals, false), accountTransactionsTokenName(tx))
+}
+
+// accountTransactionsTokenName provides the symbol of a token, or its
+// address if it does not have one
+func accountTransactionsTokenName(tx *accountTransaction) string {
+	if tx.TokenSymbol != "" {
+		return tx.TokenSymbol
+	}
+	return tx.Token
+}
+
+func init() {
+	accountCmd.AddCommand(accountTransactionsCmd)
+	accountTransactionsCmd.Flags().StringVar(&accountTransactionsAddress, "address", "", "Address of the account for which to list transactions")
+	accountTransactionsCmd.Flags().StringVar(&accountTransactionsFromBlock, "from-block", "", "Block from which to list transactions (defaults to the first block when using a block explorer)")
+	accountTransactionsCmd.Flags().StringVar(&accountTransactionsToBlock, "to-block", "", "Block up to which to list transactions (defaults to the latest block)")
+	accountTransactionsCmd.Flags().Uint64Var(&accountTransactionsChunkSize, "chunk-size", 100, "Number of blocks to obtain in each batch when scanning on-chain")
+	accountTransactionsCmd.Flags().Uint64Var(&logsChunkSize, "log-chunk-size", 10000, "Number of blocks to query for token transfers at a time when scanning on-chain")
+	accountTransactionsCmd.Flags().BoolVar(&accountTransactionsOnChain, "on-chain", false, "Scan blocks even if a block explorer is configured")
+	accountTransactionsCmd.Flags().BoolVar(&accountTransactionsJSON, "json", false, "Output the transactions as JSON")
+	accountTransactionsCmd.Flags().StringVar(&accountTransactionsAbi, "abi", "", "ABI, or path to ABI, used to decode transaction data")
+	accountTransactionsCmd.Flags().BoolVar(&accountTransactionsOnline, "online", false, "Look up unknown function signatures online")
+}
@@ -0,0 +1,139 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var accountUpdateAddress string
+var accountUpdateOldPassphrase string
+var accountUpdateNewPassphrase string
+
+// accountUpdateCmd represents the account update command
+var accountUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Change the passphrase of an account",
+	Long: `Change the passphrase with which the key of an account is encrypted.  For example:
+
+    ethereal account update --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --old-passphrase=secret --new-passphrase=newsecret
+
+The key file in the keystore for the chain is decrypted with the old passphrase and encrypted with the new one.  The new file is checked to decrypt with the new passphrase before it replaces the old file, and the replacement is atomic so the key file is never left partially written.  If either passphrase is not supplied it is prompted for; the new passphrase is prompted for twice.
+
+In quiet mode this will return 0 if the passphrase is changed, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(accountUpdateAddress != "", quiet, cli.ExitUsage, "--address is required")
+		cli.AssertCode(common.IsHexAddress(accountUpdateAddress), quiet, cli.ExitUsage, fmt.Sprintf("Invalid address %s", accountUpdateAddress))
+		address := common.HexToAddress(accountUpdateAddress)
+
+		ks, keydir := accountKeystore()
+		paths := make([]string, 0)
+		for _, account := range ks.Accounts() {
+			if account.Address == address {
+				paths = append(paths, account.URL.Path)
+			}
+		}
+		cli.Assert(len(paths) > 0, quiet, fmt.Sprintf("Account %s not found in %s", address.Hex(), keydir))
+		cli.Assert(len(paths) == 1, quiet, fmt.Sprintf("Account %s has multiple key files in %s", address.Hex(), keydir))
+
+		oldPassphrase := accountUpdateOldPassphrase
+		if oldPassphrase == "" {
+			oldPassphrase, err = cli.PromptPassphrase()
+			cli.ErrCheck(err, quiet, "Failed to obtain old passphrase")
+		}
+		newPassphrase := accountUpdateNewPassphrase
+		if newPassphrase == "" {
+			newPassphrase, err = cli.PromptNewPassphrase()
+			cli.ErrCheck(err, quiet, "Failed to obtain new passphrase")
+		}
+		cli.Assert(newPassphrase != "", quiet, "New passphrase cannot be empty")
+
+		err = accountUpdateKeyFile(paths[0], address, oldPassphrase, newPassphrase)
+		cli.ErrCheck(err, quiet, "Failed to change passphrase")
+
+		log.WithFields(log.Fields{
+			"group":   "account",
+			"command": "update",
+			"address": address.Hex(),
+		}).Info("success")
+
+		diagnosticIf(verbose, fmt.Sprintf("Updated %s", paths[0]))
+		os.Exit(0)
+	},
+}
+
+// accountUpdateKeyFile re-encrypts a key file with a new passphrase.  The new
+// file is written alongside the old and renamed over it once it has been
+// checked.
+func accountUpdateKeyFile(path string, address common.Address, oldPassphrase string, newPassphrase string) error {
+	keyJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	key, err := keystore.DecryptKey(keyJSON, oldPassphrase)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt key: %v", err)
+	}
+	if key.Address != address {
+		return fmt.Errorf("key file %s is for %s", path, key.Address.Hex())
+	}
+	newKeyJSON, err := keystore.EncryptKey(key, newPassphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %v", err)
+	}
+	check, err := keystore.DecryptKey(newKeyJSON, newPassphrase)
+	if err != nil || check.Address != address {
+		return errors.New("failed to decrypt key with new passphrase")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(newKeyJSON); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0600)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func init() {
+	accountCmd.AddCommand(accountUpdateCmd)
+	accountUpdateCmd.Flags().StringVar(&accountUpdateAddress, "address", "", "Address of the account")
+	accountUpdateCmd.Flags().StringVar(&accountUpdateOldPassphrase, "old-passphrase", "", "Current passphrase of the account")
+	accountUpdateCmd.Flags().StringVar(&accountUpdateNewPassphrase, "new-passphrase", "", "New passphrase for the account")
+}
@@ -14,6 +14,12 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cobra"
 )
 
@@ -31,5 +37,54 @@ func init() {
 	RootCmd.AddCommand(blockCmd)
 }
 func blockFlags(cmd *cobra.Command) {
-	cmd.Flags().StringVar(&blockStr, "block", "", "block hash or number")
+	cmd.Flags().StringVar(&blockStr, "block", "", "block hash or number, or \"latest\"")
+}
+
+// blockTransaction contains the information about a transaction in a block
+type blockTransaction struct {
+	Hash     common.Hash     `json:"hash"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+}
+
+// blockData contains the information about a block.  Blocks are fetched
+// directly as go-ethereum's Block cannot decode typed transactions.
+type blockData struct {
+	Number       *hexutil.Big       `json:"number"`
+	Hash         common.Hash        `json:"hash"`
+	Timestamp    hexutil.Uint64     `json:"timestamp"`
+	Miner        common.Address     `json:"miner"`
+	Extra        hexutil.Bytes      `json:"extraData"`
+	Difficulty   *hexutil.Big       `json:"difficulty"`
+	GasLimit     hexutil.Uint64     `json:"gasLimit"`
+	GasUsed      hexutil.Uint64     `json:"gasUsed"`
+	BaseFee      *hexutil.Big       `json:"baseFeePerGas"`
+	Uncles       []common.Hash      `json:"uncles"`
+	Transactions []blockTransaction `json:"transactions"`
+}
+
+// blockObtain obtains a block given its number, hash, "latest" or "pending"
+func blockObtain(ctx context.Context, input string) (*blockData, error) {
+	var block *blockData
+	var err error
+	switch {
+	case input == "latest" || input == "pending":
+		err = rpcClient.CallContext(ctx, &block, "eth_getBlockByNumber", input, true)
+	case blockInfoNumberRegexp.MatchString(input):
+		number, succeeded := new(big.Int).SetString(input, 10)
+		if !succeeded {
+			return nil, fmt.Errorf("failed to parse block number %s", input)
+		}
+		err = rpcClient.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeBig(number), true)
+	default:
+		err = rpcClient.CallContext(ctx, &block, "eth_getBlockByHash", common.HexToHash(input), true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %s not found", input)
+	}
+	return block, nil
 }
@@ -14,22 +14,47 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"math/big"
 	"os"
 	"regexp"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
 )
 
 var blockInfoTransactions bool
+var blockInfoJSON bool
 
-var blockInfoNumberRegexp = regexp.MustCompile("[0-9]+")
+var blockInfoNumberRegexp = regexp.MustCompile("^[0-9]+$")
+
+// blockInfoTransaction is the JSON representation of a block's transaction.
+type blockInfoTransaction struct {
+	Hash    string `json:"hash"`
+	From    string `json:"from"`
+	FromENS string `json:"fromENS,omitempty"`
+	To      string `json:"to,omitempty"`
+	ToENS   string `json:"toENS,omitempty"`
+}
+
+// blockInfo is the JSON representation of a block.
+type blockInfo struct {
+	Number       string                  `json:"number"`
+	Hash         string                  `json:"hash"`
+	Timestamp    int64                   `json:"timestamp"`
+	Miner        string                  `json:"miner"`
+	MinerENS     string                  `json:"minerENS,omitempty"`
+	Extra        string                  `json:"extraData"`
+	Difficulty   string                  `json:"difficulty"`
+	GasLimit     uint64                  `json:"gasLimit"`
+	GasUsed      uint64                  `json:"gasUsed"`
+	BaseFee      string                  `json:"baseFee,omitempty"`
+	Uncles       int                     `json:"uncles"`
+	Transactions []*blockInfoTransaction `json:"transactions"`
+}
 
 // blockInfoCmd represents the block info command
 var blockInfoCmd = &cobra.Command{
@@ -39,69 +64,128 @@ var blockInfoCmd = &cobra.Command{
 
     ethereal block info --block=0xfdf173c82f1e3e393166719ddc580c161b622fa504fa4b2ddd55f174af554fb7
 
+The block can be supplied as a number, a hash or "latest".  With --verbose each transaction in the block is listed along with its sender and recipient.  Use --json to output the information as JSON.
+
 In quiet mode this will return 0 if the block exists, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(blockStr != "", quiet, "--block is required")
-		var block *types.Block
+		cli.AssertCode(blockStr != "", quiet, cli.ExitUsage, "--block is required")
 		ctx, cancel := localContext()
 		defer cancel()
-		if blockInfoNumberRegexp.MatchString(blockStr) {
-			blockNum, succeeded := big.NewInt(0).SetString(blockStr, 10)
-			cli.Assert(succeeded, quiet, fmt.Sprintf("Failed to parse block number %s", blockStr))
-			block, err = client.BlockByNumber(ctx, blockNum)
-		} else {
-			blockHash := common.HexToHash(blockStr)
-			block, err = client.BlockByHash(ctx, blockHash)
-		}
+		block, err := blockObtain(ctx, blockStr)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain block %s", blockStr))
 
 		if quiet {
 			os.Exit(0)
 		}
 
-		fmt.Printf("Number:\t\t\t%v\n", block.Number())
-		fmt.Printf("Hash:\t\t\t%v\n", block.Hash().Hex())
-		fmt.Printf("Block time:\t\t%v (%v)\n", block.Time(), time.Unix(block.Time().Int64(), 0))
-		if verbose {
-			coinbase := block.Coinbase()
-			coinbaseName, err := ens.ReverseResolve(client, &coinbase)
-			if err == nil {
-				fmt.Printf("Mined by:\t\t%v (%s)\n", coinbaseName, block.Coinbase().Hex())
-			} else {
-				fmt.Printf("Mined by:\t\t%v\n", block.Coinbase().Hex())
-			}
+		info := &blockInfo{
+			Number:       block.Number.ToInt().String(),
+			Hash:         block.Hash.Hex(),
+			Timestamp:    int64(block.Timestamp),
+			Miner:        block.Miner.Hex(),
+			Extra:        string(block.Extra),
+			GasLimit:     uint64(block.GasLimit),
+			GasUsed:      uint64(block.GasUsed),
+			Uncles:       len(block.Uncles),
+			Transactions: make([]*blockInfoTransaction, len(block.Transactions)),
+		}
+		if block.Difficulty != nil {
+			info.Difficulty = block.Difficulty.ToInt().String()
 		}
-		outputIf(verbose, fmt.Sprintf("Extra:\t\t\t%s", block.Extra()))
-		outputIf(verbose, fmt.Sprintf("Difficulty:\t\t%v", block.Difficulty()))
-		fmt.Printf("Gas limit:\t\t%v\n", block.GasLimit())
-		gasPct := big.NewFloat(0).Quo(big.NewFloat(0).Mul(big.NewFloat(100), big.NewFloat(0).SetInt(big.NewInt(int64(block.GasUsed())))), big.NewFloat(0).SetInt(big.NewInt(int64(block.GasLimit()))))
-		fmt.Printf("Gas used:\t\t%v (%s%%)\n", block.GasUsed(), gasPct.Text('f', 2))
-		if verbose {
-			if len(block.Uncles()) > 0 {
-				fmt.Println("Uncles:")
-				for i, uncle := range block.Uncles() {
-					fmt.Printf("\t%d: block %v (%v)\n", i, uncle.Number, big.NewInt(0).Sub(uncle.Number, block.Number()))
+		if block.BaseFee != nil {
+			info.BaseFee = block.BaseFee.ToInt().String()
+		}
+		// Reverse resolution is only carried out when the names will be shown
+		resolve := verbose || blockInfoJSON
+		if resolve {
+			info.MinerENS = blockInfoName(block.Miner)
+		}
+		for i, tx := range block.Transactions {
+			info.Transactions[i] = &blockInfoTransaction{
+				Hash: tx.Hash.Hex(),
+				From: tx.From.Hex(),
+			}
+			if resolve {
+				info.Transactions[i].FromENS = blockInfoName(tx.From)
+			}
+			if tx.To != nil {
+				info.Transactions[i].To = tx.To.Hex()
+				if resolve {
+					info.Transactions[i].ToENS = blockInfoName(*tx.To)
 				}
 			}
+		}
+
+		if blockInfoJSON {
+			data, err := json.Marshal(info)
+			cli.ErrCheck(err, quiet, "Failed to generate JSON")
+			fmt.Fprintln(cli.Out, string(data))
+			os.Exit(0)
+		}
 
+		fmt.Fprintf(cli.Out, "Number:\t\t\t%v\n", info.Number)
+		fmt.Fprintf(cli.Out, "Hash:\t\t\t%v\n", info.Hash)
+		fmt.Fprintf(cli.Out, "Block time:\t\t%v (%v)\n", info.Timestamp, time.Unix(info.Timestamp, 0))
+		fmt.Fprintf(cli.Out, "Fee recipient:\t\t%s\n", blockInfoAddress(info.Miner, info.MinerENS))
+		diagnosticIf(verbose, fmt.Sprintf("Extra:\t\t\t%s", info.Extra))
+		diagnosticIf(verbose && info.Difficulty != "", fmt.Sprintf("Difficulty:\t\t%v", info.Difficulty))
+		fmt.Fprintf(cli.Out, "Gas limit:\t\t%v\n", info.GasLimit)
+		gasPct := 0.0
+		if info.GasLimit > 0 {
+			gasPct = float64(info.GasUsed) * 100 / float64(info.GasLimit)
+		}
+		fmt.Fprintf(cli.Out, "Gas used:\t\t%v (%.2f%%)\n", info.GasUsed, gasPct)
+		if block.BaseFee != nil {
+			fmt.Fprintf(cli.Out, "Base fee:\t\t%s\n", gasPriceString(block.BaseFee.ToInt()))
+		}
+		if verbose && len(block.Uncles) > 0 {
+			fmt.Fprintln(cli.Out, "Uncles:")
+			for i, uncle := range block.Uncles {
+				fmt.Fprintf(cli.Out, "\t%d: %v\n", i, uncle.Hex())
+			}
 		} else {
-			fmt.Printf("Uncles:\t\t\t%v\n", len(block.Uncles()))
+			fmt.Fprintf(cli.Out, "Uncles:\t\t\t%v\n", info.Uncles)
 		}
-		if blockInfoTransactions {
-			if block.Transactions().Len() > 0 {
-				fmt.Println("Transactions:")
-				for i, tx := range block.Transactions() {
-					fmt.Printf("\t%4d: %v\n", i, tx.Hash().Hex())
+		if (verbose || blockInfoTransactions) && len(info.Transactions) > 0 {
+			fmt.Fprintln(cli.Out, "Transactions:")
+			for i, tx := range info.Transactions {
+				if verbose {
+					to := "contract creation"
+					if tx.To != "" {
+						to = blockInfoAddress(tx.To, tx.ToENS)
+					}
+					fmt.Fprintf(cli.Out, "\t%4d: %v (%s -> %s)\n", i, tx.Hash, blockInfoAddress(tx.From, tx.FromENS), to)
+				} else {
+					fmt.Fprintf(cli.Out, "\t%4d: %v\n", i, tx.Hash)
 				}
 			}
 		} else {
-			fmt.Printf("Transactions:\t\t%v\n", block.Transactions().Len())
+			fmt.Fprintf(cli.Out, "Transactions:\t\t%v\n", len(info.Transactions))
 		}
 	},
 }
 
+// blockInfoName returns the reverse-resolved ENS name for an address, or an
+// empty string if it does not have one
+func blockInfoName(address common.Address) string {
+	name, err := ens.ReverseResolve(client, &address)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// blockInfoAddress formats an address along with its ENS name if present
+func blockInfoAddress(address string, name string) string {
+	if name == "" {
+		return address
+	}
+	return fmt.Sprintf("%s (%s)", name, address)
+}
+
 func init() {
 	blockCmd.AddCommand(blockInfoCmd)
 	blockInfoCmd.Flags().BoolVar(&blockInfoTransactions, "transactions", false, "Display hashes of all block transactions")
+	blockInfoCmd.Flags().BoolVar(&blockInfoJSON, "json", false, "Output the information as JSON")
 	blockFlags(blockInfoCmd)
 }
@@ -0,0 +1,160 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var blockOverviewBlocks int64
+var blockOverviewStallInterval time.Duration
+var blockOverviewJSON bool
+
+// blockOverviewBlock is the JSON representation of a block in the overview.
+type blockOverviewBlock struct {
+	Number       string  `json:"number"`
+	Timestamp    int64   `json:"timestamp"`
+	GasUsedPct   float64 `json:"gasUsedPercent"`
+	BaseFee      string  `json:"baseFee,omitempty"`
+	Transactions int     `json:"transactions"`
+}
+
+// blockOverview is the JSON representation of the overview.
+type blockOverview struct {
+	Blocks           []*blockOverviewBlock `json:"blocks"`
+	AverageBlockTime float64               `json:"averageBlockTime"`
+	SinceLastBlock   float64               `json:"sinceLastBlock"`
+	Stalled          bool                  `json:"stalled"`
+}
+
+// blockOverviewCmd represents the block overview command
+var blockOverviewCmd = &cobra.Command{
+	Use:   "overview",
+	Short: "Obtain an overview of recent blocks",
+	Long: `Obtain an overview of the most recent blocks on the chain, showing the gas used, base fee and number of transactions for each.  For example:
+
+    ethereal block overview --blocks=20
+
+The average time between the sampled blocks is displayed.  If no block has been produced for longer than --stall-interval the chain is reported as stalled.  Use --json to output the information as JSON, or --format=csv to output a row for each block in CSV format.
+
+In quiet mode this will return 0 if the chain is not stalled, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(blockOverviewBlocks > 0, quiet, cli.ExitUsage, "--blocks must be greater than 0")
+		ctx, cancel := localContext()
+		defer cancel()
+
+		var latest hexutil.Big
+		err := rpcClient.CallContext(ctx, &latest, "eth_blockNumber")
+		cli.ErrCheck(err, quiet, "Failed to obtain latest block number")
+
+		overview := &blockOverview{
+			Blocks: make([]*blockOverviewBlock, 0, blockOverviewBlocks),
+		}
+		var newest, oldest int64
+		for number := latest.ToInt().Int64(); number > latest.ToInt().Int64()-blockOverviewBlocks && number >= 0; number-- {
+			block, err := blockObtain(ctx, fmt.Sprintf("%d", number))
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain block %d", number))
+			entry := &blockOverviewBlock{
+				Number:       block.Number.ToInt().String(),
+				Timestamp:    int64(block.Timestamp),
+				Transactions: len(block.Transactions),
+			}
+			if block.GasLimit > 0 {
+				entry.GasUsedPct = float64(block.GasUsed) * 100 / float64(block.GasLimit)
+			}
+			if block.BaseFee != nil {
+				entry.BaseFee = block.BaseFee.ToInt().String()
+			}
+			overview.Blocks = append(overview.Blocks, entry)
+			if len(overview.Blocks) == 1 {
+				newest = entry.Timestamp
+			}
+			oldest = entry.Timestamp
+		}
+		if len(overview.Blocks) > 1 {
+			overview.AverageBlockTime = float64(newest-oldest) / float64(len(overview.Blocks)-1)
+		}
+		sinceLastBlock := time.Since(time.Unix(newest, 0))
+		overview.SinceLastBlock = sinceLastBlock.Seconds()
+		overview.Stalled = sinceLastBlock > blockOverviewStallInterval
+
+		if quiet {
+			if overview.Stalled {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		if csvFormat() {
+			table := newCSVTable("number", "timestamp", "time", "gas_used_percent", "base_fee_wei", "base_fee", "transactions")
+			for _, entry := range overview.Blocks {
+				baseFee := ""
+				if entry.BaseFee != "" {
+					baseFee = blockOverviewGWei(entry.BaseFee)
+				}
+				table.add(entry.Number, fmt.Sprintf("%d", entry.Timestamp), time.Unix(entry.Timestamp, 0).UTC().Format(time.RFC3339), fmt.Sprintf("%.2f", entry.GasUsedPct), entry.BaseFee, baseFee, fmt.Sprintf("%d", entry.Transactions))
+			}
+			table.write()
+			os.Exit(0)
+		}
+
+		if blockOverviewJSON {
+			data, err := json.Marshal(overview)
+			cli.ErrCheck(err, quiet, "Failed to generate JSON")
+			fmt.Fprintln(cli.Out, string(data))
+			os.Exit(0)
+		}
+
+		fmt.Fprintf(cli.Out, "%-10s  %-19s  %8s  %14s  %6s\n", "Block", "Time", "Gas used", "Base fee", "Txs")
+		for _, entry := range overview.Blocks {
+			baseFee := "-"
+			if entry.BaseFee != "" {
+				baseFee = blockOverviewGWei(entry.BaseFee)
+			}
+			fmt.Fprintf(cli.Out, "%-10s  %-19s  %7.2f%%  %14s  %6d\n", entry.Number, time.Unix(entry.Timestamp, 0).Format("2006-01-02 15:04:05"), entry.GasUsedPct, baseFee, entry.Transactions)
+		}
+		if len(overview.Blocks) > 1 {
+			fmt.Fprintf(cli.Out, "Average block time:\t%.2fs\n", overview.AverageBlockTime)
+		}
+		fmt.Fprintf(cli.Out, "Since last block:\t%v\n", sinceLastBlock.Round(time.Second))
+		if overview.Stalled {
+			fmt.Fprintf(cli.Out, "Chain appears to be stalled: no new block for more than %v\n", blockOverviewStallInterval)
+		}
+	},
+}
+
+// blockOverviewGWei formats a decimal Wei string as GWei
+func blockOverviewGWei(wei string) string {
+	value, succeeded := new(big.Float).SetString(wei)
+	if !succeeded {
+		return wei
+	}
+	return fmt.Sprintf("%s GWei", new(big.Float).Quo(value, big.NewFloat(1e9)).Text('f', 2))
+}
+
+func init() {
+	blockCmd.AddCommand(blockOverviewCmd)
+	blockOverviewCmd.Flags().Int64Var(&blockOverviewBlocks, "blocks", 10, "Number of recent blocks to show")
+	blockOverviewCmd.Flags().DurationVar(&blockOverviewStallInterval, "stall-interval", time.Minute, "Time without a new block after which the chain is considered stalled")
+	blockOverviewCmd.Flags().BoolVar(&blockOverviewJSON, "json", false, "Output the information as JSON")
+	addFormatFlag(blockOverviewCmd)
+}
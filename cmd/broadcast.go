@@ -0,0 +1,120 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+// broadcastResult is the outcome of sending a transaction to an endpoint
+type broadcastResult struct {
+	name string
+	err  error
+}
+
+// broadcastEndpoints provides the endpoints supplied with --broadcast-to
+func broadcastEndpoints() []string {
+	return splitEndpoints(viper.GetStringSlice("broadcast-to"))
+}
+
+// broadcastTransaction sends a signed transaction to the connection and to
+// each endpoint supplied with --broadcast-to at the same time.  It succeeds
+// if any of them accepts the transaction.
+func broadcastTransaction(ctx context.Context, signedTx *types.Transaction) error {
+	endpoints := broadcastEndpoints()
+	if len(endpoints) == 0 {
+		return client.SendTransaction(ctx, signedTx)
+	}
+
+	results := make([]*broadcastResult, len(endpoints)+1)
+	primaryName := "connection"
+	if connections := connectionEndpoints(); len(connections) == 1 {
+		if endpoint, err := parseConnection(connections[0]); err == nil {
+			primaryName = connectionName(endpoint)
+		}
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(results))
+	go func() {
+		defer wg.Done()
+		results[0] = &broadcastResult{
+			name: primaryName,
+			err:  client.SendTransaction(ctx, signedTx),
+		}
+	}()
+	for i, endpoint := range endpoints {
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			result := &broadcastResult{name: endpoint}
+			results[i] = result
+			if parsed, err := url.Parse(endpoint); err == nil {
+				result.name = connectionName(parsed)
+			}
+			// Headers from --rpc-header are for the connection, so are not
+			// sent to other endpoints
+			rpcClient, err := rpc.DialContext(ctx, endpoint)
+			if err != nil {
+				result.err = err
+				return
+			}
+			defer rpcClient.Close()
+			result.err = ethclient.NewClient(rpcClient).SendTransaction(ctx, signedTx)
+		}(i+1, endpoint)
+	}
+	wg.Wait()
+
+	var err error
+	accepted := 0
+	for _, result := range results {
+		switch {
+		case result.err == nil:
+			accepted++
+			diagnosticIf(!quiet, fmt.Sprintf("Accepted by %s", result.name))
+		case alreadyKnown(result.err):
+			accepted++
+			diagnosticIf(verbose, fmt.Sprintf("Already known by %s", result.name))
+		default:
+			cli.Warn(quiet, fmt.Sprintf("Not accepted by %s: %v", result.name, result.err))
+			if err == nil {
+				err = result.err
+			}
+		}
+	}
+	if accepted == 0 {
+		return fmt.Errorf("transaction not accepted by any endpoint: %v", err)
+	}
+	return nil
+}
+
+// alreadyKnown returns true if the error shows that the endpoint already has
+// the transaction
+func alreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, known := range []string{"already known", "known transaction", "already imported", "already exists"} {
+		if strings.Contains(msg, known) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+var concurrency int
+var continueOnError bool
+
+// addConcurrencyFlags adds flags for commands that carry out multiple lookups
+func addConcurrencyFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&concurrency, "concurrency", 5, "Number of lookups to carry out concurrently")
+	cmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "Display failed lookups and continue rather than stopping at the first failure")
+}
+
+// concurrentLookups carries out a lookup for each of count items, using a
+// bounded number of concurrent workers.  Results and errors are returned in
+// the same order as the items.
+func concurrentLookups(count int, lookup func(i int) (interface{}, error)) ([]interface{}, []error) {
+	results := make([]interface{}, count)
+	errs := make([]error, count)
+
+	workers := concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i], errs[i] = lookup(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results, errs
+}
@@ -0,0 +1,84 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+// confirmThreshold is the value or fee above which transactions must be
+// confirmed, if set
+var confirmThreshold *big.Int
+
+var confirmFunctionMapOnce sync.Once
+
+// confirmTransaction asks for confirmation of a transaction if its value or
+// maximum fee is above the threshold supplied with --confirm-threshold.
+// Confirmation is not requested if --yes is supplied, or if standard input is
+// not a terminal so that scripts are not interrupted.  It returns an error if
+// the transaction is not confirmed.
+func confirmTransaction(tx *types.Transaction) error {
+	if confirmThreshold == nil || offline || dryRun || viper.GetBool("yes") || !cli.Interactive() {
+		return nil
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
+	if tx.Value().Cmp(confirmThreshold) <= 0 && fee.Cmp(confirmThreshold) <= 0 {
+		return nil
+	}
+
+	recipient := "a new contract"
+	if tx.To() != nil {
+		recipient = tx.To().Hex()
+		if name, err := ens.ReverseResolve(client, tx.To()); err == nil {
+			recipient = fmt.Sprintf("%s (%s)", name, recipient)
+		}
+	}
+	if len(tx.Data()) > 0 {
+		confirmFunctionMapOnce.Do(txdata.InitFunctionMap)
+		fmt.Fprintf(os.Stderr, "Data:\t%s\n", txdata.DataToString(tx.Data()))
+	}
+	fmt.Fprintf(os.Stderr, "Fee:\tup to %s\n", etherutils.WeiToString(fee, true))
+	confirmed, err := cli.Confirm(fmt.Sprintf("Send %s to %s?", etherutils.WeiToString(tx.Value(), true), recipient))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return errors.New("transaction not confirmed")
+	}
+	return nil
+}
+
+// confirmSigner wraps a signer to ask for confirmation of transactions before
+// they are signed
+func confirmSigner(signer bind.SignerFn) bind.SignerFn {
+	return func(txSigner types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if err := confirmTransaction(tx); err != nil {
+			return nil, err
+		}
+		return signer(txSigner, address, tx)
+	}
+}
@@ -0,0 +1,566 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/viper"
+)
+
+var rpcHeaders []string
+
+// headerTransport adds fixed headers to each HTTP request
+type headerTransport struct {
+	headers http.Header
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests must not be modified by a RoundTripper so work on a copy
+	req = req.WithContext(req.Context())
+	req.Header = cloneHeader(req.Header)
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
+
+func cloneHeader(header http.Header) http.Header {
+	clone := make(http.Header, len(header))
+	for name, values := range header {
+		clone[name] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+// parseRPCHeaders parses headers of the form "Name: value"
+func parseRPCHeaders(input []string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, header := range input {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid header %q; must be of the form \"Name: value\"", header)
+		}
+		headers.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+	return headers, nil
+}
+
+// connectionEndpoints provides the endpoints supplied with --connection,
+// which can be repeated or separated by commas
+func connectionEndpoints() []string {
+	return splitEndpoints(viper.GetStringSlice("connection"))
+}
+
+// splitEndpoints splits endpoints that are separated by commas
+func splitEndpoints(input []string) []string {
+	endpoints := make([]string, 0)
+	for _, connection := range input {
+		for _, endpoint := range strings.Split(connection, ",") {
+			endpoint = strings.TrimSpace(endpoint)
+			if endpoint != "" {
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	return endpoints
+}
+
+// parseConnection parses the URL or path of a connection
+func parseConnection(connection string) (*url.URL, error) {
+	endpoint, err := url.Parse(connection)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection %s: %v", connection, err)
+	}
+	switch endpoint.Scheme {
+	case "http", "https", "ws", "wss", "":
+	default:
+		return nil, fmt.Errorf("unknown connection type %q; must be http, https, ws, wss or the path to an IPC socket", endpoint.Scheme)
+	}
+	return endpoint, nil
+}
+
+// connectionName provides the name of a connection for display, without any
+// path or credentials that it may contain
+func connectionName(endpoint *url.URL) string {
+	if endpoint.Host == "" {
+		return endpoint.Path
+	}
+	return fmt.Sprintf("%s://%s", endpoint.Scheme, endpoint.Host)
+}
+
+// connectionHeaders provides the headers supplied with --rpc-header, or in
+// the config file, or nil if there are none
+func connectionHeaders() (http.Header, error) {
+	input := rpcHeaders
+	if len(input) == 0 {
+		input = viper.GetStringSlice("rpc-header")
+	}
+	if len(input) == 0 {
+		return nil, nil
+	}
+	return parseRPCHeaders(input)
+}
+
+// dialConnections connects to the Ethereum nodes at the given URLs or paths.
+// If all of the connections are HTTP then each request is sent to the first
+// node that serves it, so that requests fail over to the next node if one is
+// unreachable or reports that it cannot serve the request.  Otherwise the
+// connection is made to the first node that responds.
+func dialConnections(connections []string) (*rpc.Client, error) {
+	if len(connections) == 0 {
+		return nil, errors.New("no connection supplied")
+	}
+	if len(connections) == 1 {
+		return dialConnection(connections[0])
+	}
+
+	// Headers commonly carry the credentials of a single provider so must
+	// not be sent to the others
+	headers, err := connectionHeaders()
+	if err != nil {
+		return nil, err
+	}
+	if headers != nil {
+		return nil, errors.New("headers cannot be supplied with more than one connection; supply any credentials in the URL of each connection instead")
+	}
+
+	endpoints := make([]*url.URL, len(connections))
+	allHTTP := true
+	for i, connection := range connections {
+		endpoint, err := parseConnection(connection)
+		if err != nil {
+			return nil, err
+		}
+		if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+			allHTTP = false
+		}
+		endpoints[i] = endpoint
+	}
+
+	if allHTTP {
+		transport := &retryTransport{
+			base: &failoverTransport{
+				endpoints: endpoints,
+				base:      http.DefaultTransport,
+			},
+		}
+		return rpc.DialHTTPWithClient(connections[0], &http.Client{Transport: transport})
+	}
+
+	// WebSocket and IPC connections persist, so fail over only when connecting
+	for i, connection := range connections {
+		var rpcClient *rpc.Client
+		rpcClient, err = dialConnection(connection)
+		if err == nil {
+			ctx, cancel := localContext()
+			var version string
+			err = rpcClient.CallContext(ctx, &version, "net_version")
+			cancel()
+			if err == nil {
+				diagnosticIf(verbose, fmt.Sprintf("Connected to %s", connectionName(endpoints[i])))
+				return rpcClient, nil
+			}
+			rpcClient.Close()
+		}
+		diagnosticIf(verbose, fmt.Sprintf("Failed to connect to %s: %v", connectionName(endpoints[i]), err))
+	}
+	return nil, err
+}
+
+// dialConnection connects to the Ethereum node at the given URL or path.
+// http:// and https:// URLs connect over HTTP, ws:// and wss:// URLs over
+// WebSocket and anything else is treated as the path to an IPC socket.
+// Headers supplied with --rpc-header, or in the config file, are added to
+// each request.
+func dialConnection(connection string) (*rpc.Client, error) {
+	endpoint, err := parseConnection(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := connectionHeaders()
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		if headers != nil {
+			return nil, fmt.Errorf("headers can only be supplied for HTTP connections")
+		}
+		ctx, cancel := localContext()
+		defer cancel()
+		return rpc.DialContext(ctx, connection)
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if headers != nil {
+		transport = &headerTransport{
+			headers: headers,
+			base:    transport,
+		}
+	}
+	return rpc.DialHTTPWithClient(connection, &http.Client{Transport: &retryTransport{base: transport}})
+}
+
+// failoverTransport sends each HTTP request to a number of endpoints in turn
+// until one of them serves it.  A request is not served if it fails to reach
+// the endpoint, if the endpoint returns an HTTP error, or if it returns a
+// JSON-RPC error showing that the node itself has a problem (see
+// nodeRPCError).  Other JSON-RPC errors, such as reverts and invalid
+// parameters, would be the same from any node so are returned as they are.
+// The endpoint that served the last request is the first to be tried for the
+// next.
+type failoverTransport struct {
+	endpoints []*url.URL
+	base      http.RoundTripper
+	mu        sync.Mutex
+	current   int
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The body is sent to each endpoint tried, so keep a copy
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t.mu.Lock()
+	first := t.current
+	t.mu.Unlock()
+	for i := range t.endpoints {
+		index := (first + i) % len(t.endpoints)
+		endpoint := t.endpoints[index]
+		attempt := req.WithContext(req.Context())
+		attemptURL := *endpoint
+		attempt.URL = &attemptURL
+		attempt.Host = endpoint.Host
+		attempt.Body = ioutil.NopCloser(bytes.NewReader(body))
+		attempt.ContentLength = int64(len(body))
+
+		resp, err := t.base.RoundTrip(attempt)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			var respBody []byte
+			respBody, err = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+			if err == nil {
+				err = nodeRPCError(respBody)
+			}
+			if err == nil {
+				t.mu.Lock()
+				t.current = index
+				t.mu.Unlock()
+				diagnosticIf(verbose, fmt.Sprintf("Request served by %s", connectionName(endpoint)))
+				return resp, nil
+			}
+		}
+		if i == len(t.endpoints)-1 || req.Context().Err() != nil {
+			// Nowhere left to try
+			if _, isNodeError := err.(*rpcNodeError); isNodeError {
+				// The response contains the error so pass it on
+				return resp, nil
+			}
+			if err != nil {
+				err = fmt.Errorf("no connection served the request; last error from %s: %w", connectionName(endpoint), err)
+			}
+			return resp, err
+		}
+		if resp != nil {
+			if err == nil {
+				err = errors.New(resp.Status)
+			}
+			resp.Body.Close()
+		}
+		diagnosticIf(verbose, fmt.Sprintf("Request to %s failed (%v); trying next connection", connectionName(endpoint), err))
+	}
+	return nil, errors.New("no connection available")
+}
+
+// retryTransport retries read-only HTTP requests that fail with a transient
+// error, with the delay between attempts doubling each time.  The number of
+// retries and the initial delay are set with --rpc-retries and
+// --rpc-retry-delay.  Requests that send or sign transactions are never
+// retried, as they may have taken effect even if their response was lost.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.retry(req)
+	if err != nil {
+		return nil, err
+	}
+	return statusCheck(resp)
+}
+
+// retry sends a request, retrying it if it is read-only and fails with a
+// transient error
+func (t *retryTransport) retry(req *http.Request) (*http.Response, error) {
+	retries := viper.GetInt("rpc-retries")
+	if retries <= 0 || req.Body == nil {
+		return t.base.RoundTrip(req)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if !readOnlyRequest(body) {
+		retries = 0
+	}
+
+	delay := viper.GetDuration("rpc-retry-delay")
+	for ; ; retries-- {
+		attempt := req.WithContext(req.Context())
+		attempt.Body = ioutil.NopCloser(bytes.NewReader(body))
+		attempt.ContentLength = int64(len(body))
+		resp, err := t.base.RoundTrip(attempt)
+		reason := err
+		if err == nil {
+			resp, reason = transientResponse(resp)
+		}
+		if reason == nil || retries <= 0 || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		diagnosticIf(verbose, fmt.Sprintf("Request failed with %v; retrying in %v", reason, delay))
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// httpStatusError is an HTTP response that does not contain a JSON-RPC
+// response, such as a rate limit or gateway error
+type httpStatusError struct {
+	statusCode int
+	status     string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP error %s", e.status)
+}
+
+// statusCheck turns an HTTP error response that does not contain JSON in to
+// an error.  Error responses that contain JSON are passed on, as they may be
+// JSON-RPC errors.
+func statusCheck(resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err == nil && json.Valid(body) {
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+	return nil, &httpStatusError{statusCode: resp.StatusCode, status: resp.Status}
+}
+
+// transientResponse returns an error if an HTTP response shows a failure
+// that might not occur if the request is retried, such as a rate limit or an
+// overloaded gateway.  The response is returned with its body intact.
+func transientResponse(resp *http.Response) (*http.Response, error) {
+	if transientStatus(resp.StatusCode) {
+		return resp, &httpStatusError{statusCode: resp.StatusCode, status: resp.Status}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	if nodeErr, isNodeError := nodeRPCError(body).(*rpcNodeError); isNodeError && transientRPCErrorCodes[nodeErr.code] {
+		return resp, nodeErr
+	}
+	return resp, nil
+}
+
+// transientStatus returns true if an HTTP status shows a failure that might
+// not occur if the request is retried
+func transientStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// transientRPCErrorCodes are the JSON-RPC error codes for failures that may
+// clear if a request is retried: resource unavailable and limit exceeded
+var transientRPCErrorCodes = map[int]bool{
+	-32002: true,
+	-32005: true,
+}
+
+// readOnlyRequest returns true if a JSON-RPC request, or every request in a
+// batch, leaves the state of the node and the chain unchanged
+func readOnlyRequest(body []byte) bool {
+	type request struct {
+		Method string `json:"method"`
+	}
+	var requests []*request
+	if err := json.Unmarshal(body, &requests); err != nil {
+		single := &request{}
+		if err := json.Unmarshal(body, single); err != nil {
+			return false
+		}
+		requests = []*request{single}
+	}
+	for _, request := range requests {
+		if request == nil || strings.HasPrefix(request.Method, "personal_") {
+			return false
+		}
+		switch request.Method {
+		case "eth_sendRawTransaction", "eth_sendTransaction", "eth_sign", "eth_signTransaction", "eth_submitWork", "eth_submitHashrate":
+			return false
+		}
+	}
+	return true
+}
+
+// rpcNodeError is a JSON-RPC error that shows that the node cannot serve
+// the request, rather than that the request itself is at fault
+type rpcNodeError struct {
+	code    int
+	message string
+}
+
+func (e *rpcNodeError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.message, e.code)
+}
+
+// nodeRPCErrorCodes are the JSON-RPC error codes that show that a node
+// cannot serve a request: internal error, resource unavailable and limit
+// exceeded
+var nodeRPCErrorCodes = map[int]bool{
+	-32603: true,
+	-32002: true,
+	-32005: true,
+}
+
+// nodeRPCError returns an error if a JSON-RPC response, or any response in a
+// batch, is an error showing that the node cannot serve the request
+func nodeRPCError(body []byte) error {
+	type response struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	var responses []*response
+	if err := json.Unmarshal(body, &responses); err != nil {
+		single := &response{}
+		if err := json.Unmarshal(body, single); err != nil {
+			// Not for us to judge; leave it to the RPC client
+			return nil
+		}
+		responses = []*response{single}
+	}
+	for _, response := range responses {
+		if response != nil && response.Error != nil && nodeRPCErrorCodes[response.Error.Code] {
+			return &rpcNodeError{code: response.Error.Code, message: response.Error.Message}
+		}
+	}
+	return nil
+}
+
+// blockWaiter waits for new blocks.  If the connection supports
+// subscriptions (WebSocket and IPC) then it is notified of new blocks as they
+// arrive, otherwise it polls.
+type blockWaiter struct {
+	heads    chan *types.Header
+	sub      ethereum.Subscription
+	interval time.Duration
+}
+
+// blockWaiterSubscribedInterval is the time after which a subscribed waiter
+// returns even if it has not been notified of a new block
+const blockWaiterSubscribedInterval = time.Minute
+
+// newBlockWaiter creates a block waiter, subscribing to new blocks if
+// possible and otherwise polling at the given interval
+func newBlockWaiter(ctx context.Context, pollInterval time.Duration) *blockWaiter {
+	waiter := &blockWaiter{
+		heads:    make(chan *types.Header, 16),
+		interval: pollInterval,
+	}
+	sub, err := client.SubscribeNewHead(ctx, waiter.heads)
+	if err != nil {
+		// HTTP connections cannot subscribe, so poll
+		return waiter
+	}
+	diagnosticIf(verbose, "Subscribed to new blocks")
+	waiter.sub = sub
+	waiter.interval = blockWaiterSubscribedInterval
+	return waiter
+}
+
+// wait waits until there may be a new block.  It returns false if the
+// context is done first.
+func (w *blockWaiter) wait(ctx context.Context) bool {
+	var subErr <-chan error
+	if w.sub != nil {
+		subErr = w.sub.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-w.heads:
+	case err := <-subErr:
+		// Subscription has failed; fall back to polling
+		diagnosticIf(verbose, fmt.Sprintf("Subscription to new blocks failed: %v", err))
+		w.sub = nil
+		w.interval = transactionWaitPollInterval
+	case <-time.After(w.interval):
+	}
+	return true
+}
+
+// close releases the subscription, if any
+func (w *blockWaiter) close() {
+	if w.sub != nil {
+		w.sub.Unsubscribe()
+	}
+}
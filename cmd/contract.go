@@ -14,6 +14,7 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -26,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/spf13/cobra"
 )
 
@@ -62,6 +64,137 @@ func contractParseAbi(input string) (output abi.ABI, err error) {
 	return abi.JSON(reader)
 }
 
+// contractBlock parses a block specifier.  This can be a block number in
+// decimal or hex, "latest" or "pending".  A nil number refers to the latest
+// block.
+func contractBlock(input string) (number *big.Int, pending bool, err error) {
+	switch input {
+	case "", "latest":
+		return nil, false, nil
+	case "pending":
+		return nil, true, nil
+	default:
+		var success bool
+		number, success = new(big.Int).SetString(input, 0)
+		if !success || number.Sign() < 0 {
+			return nil, false, fmt.Errorf("invalid block %s", input)
+		}
+		return number, false, nil
+	}
+}
+
+// contractRevertSelector is the selector for Error(string), used to return
+// revert reasons
+var contractRevertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// contractPanicSelector is the selector for Panic(uint256), used to return
+// the codes of failed assertions and other runtime errors
+var contractPanicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+// contractPanicReasons are the descriptions of the panic codes raised by
+// Solidity
+var contractPanicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop from empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// contractRevertReason obtains the revert reason from the data returned by a
+// call, if present.  Both Error(string) and Panic(uint256) are understood.
+func contractRevertReason(data []byte) (reason string, present bool) {
+	if len(data) == 36 && bytes.Equal(data[:4], contractPanicSelector) {
+		code := new(big.Int).SetBytes(data[4:])
+		description, known := contractPanicReasons[code.Uint64()]
+		if !code.IsUint64() || !known {
+			description = "unknown panic"
+		}
+		return fmt.Sprintf("%s (panic 0x%x)", description, code), true
+	}
+	if len(data) < 4 || (len(data)-4)%32 != 0 || !bytes.Equal(data[:4], contractRevertSelector) {
+		return "", false
+	}
+	stringType, err := abi.NewType("string")
+	if err != nil {
+		return "", false
+	}
+	values, err := abi.Arguments{{Type: stringType}}.UnpackValues(data[4:])
+	if err != nil || len(values) != 1 {
+		return "", false
+	}
+	reason, present = values[0].(string)
+	return
+}
+
+// contractLogArgument is an argument of an event decoded from a log
+type contractLogArgument struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed"`
+	Value   string `json:"value"`
+}
+
+// contractDecodeLog decodes the arguments of a log given the event that
+// generated it.  The first topic of the log is the signature of the event
+// unless the event is anonymous, and indexed arguments of dynamic types are
+// only available as the hash of their value.  Unnamed arguments are named by
+// their position.
+func contractDecodeLog(event abi.Event, log *types.Log) ([]*contractLogArgument, error) {
+	nonIndexed, err := event.Inputs.UnpackValues(log.Data)
+	if err != nil {
+		return nil, err
+	}
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("log does not have a topic")
+		}
+		topics = topics[1:]
+	}
+
+	results := make([]*contractLogArgument, 0, len(event.Inputs))
+	for i, input := range event.Inputs {
+		result := &contractLogArgument{Name: input.Name, Type: input.Type.String(), Indexed: input.Indexed}
+		if result.Name == "" {
+			result.Name = fmt.Sprintf("%d", i)
+		}
+		var value interface{}
+		if input.Indexed {
+			if len(topics) == 0 {
+				return nil, fmt.Errorf("log does not have a topic for %s", result.Name)
+			}
+			topic := topics[0]
+			topics = topics[1:]
+			switch input.Type.T {
+			case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+				result.Value = topic.Hex()
+				results = append(results, result)
+				continue
+			}
+			values, err := abi.Arguments{{Type: input.Type}}.UnpackValues(topic.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			value = values[0]
+		} else {
+			value = nonIndexed[0]
+			nonIndexed = nonIndexed[1:]
+		}
+		result.Value, err = contractValueToString(input.Type, value)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 func contractUnpack(abi abi.ABI, name string, data []byte) (result *[]*interface{}, err error) {
 	method, exists := abi.Methods[name]
 	if !exists {
@@ -228,10 +361,12 @@ func contractStringToValue(argType abi.Type, val string) (interface{}, error) {
 
 func contractValueToString(argType abi.Type, val interface{}) (string, error) {
 	switch argType.T {
-	case abi.IntTy:
-		return val.(*big.Int).String(), nil
-	case abi.UintTy:
-		return val.(*big.Int).String(), nil
+	case abi.IntTy, abi.UintTy:
+		if bigVal, isBig := val.(*big.Int); isBig {
+			return bigVal.String(), nil
+		}
+		// Smaller integers are unpacked in to native types
+		return fmt.Sprintf("%v", val), nil
 	case abi.BoolTy:
 		if val.(bool) == true {
 			return "true", nil
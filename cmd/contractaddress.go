@@ -0,0 +1,101 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var contractAddressDeployer string
+var contractAddressNonce int64
+var contractAddressSalt string
+var contractAddressInitCode string
+var contractAddressInitCodeHash string
+
+// contractAddressCmd represents the contract address command
+var contractAddressCmd = &cobra.Command{
+	Use:   "address",
+	Short: "Calculate the address of a contract before it is deployed",
+	Long: `Calculate the address at which a contract will be deployed.  For a contract deployed by a transaction, or with CREATE, supply the deployer and the nonce of the deployment.  For example:
+
+    ethereal contract address --deployer=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --nonce=7
+
+For a contract deployed with CREATE2 supply the deployer, which is the contract that carries out the deployment, along with the salt and the init code of the contract.  For example:
+
+    ethereal contract address --deployer=0x4e59b44847b379578588920cA78FbF26c0B4956C --salt=0x01 --init-code=0x6080...
+
+The hash of the init code can be supplied with --init-code-hash in place of the init code itself.  The salt can be supplied in decimal or hex.
+
+In quiet mode this will return 0 if the address is calculated, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(contractAddressDeployer != "", quiet, cli.ExitUsage, "--deployer is required")
+		deployer, err := ens.Resolve(client, contractAddressDeployer)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve deployer %s", contractAddressDeployer))
+
+		var address common.Address
+		if contractAddressSalt == "" {
+			cli.AssertCode(contractAddressInitCode == "" && contractAddressInitCodeHash == "", quiet, cli.ExitUsage, "--salt is required with --init-code or --init-code-hash")
+			cli.AssertCode(contractAddressNonce >= 0, quiet, cli.ExitUsage, "--nonce or --salt is required")
+			address = crypto.CreateAddress(deployer, uint64(contractAddressNonce))
+		} else {
+			cli.AssertCode(contractAddressNonce < 0, quiet, cli.ExitUsage, "only one of --nonce and --salt can be supplied")
+			saltBytes, err := contractStorageWord(contractAddressSalt)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid salt")
+			var salt [32]byte
+			copy(salt[:], saltBytes)
+
+			var initCodeHash []byte
+			switch {
+			case contractAddressInitCode != "" && contractAddressInitCodeHash != "":
+				cli.ErrCode(quiet, cli.ExitUsage, "only one of --init-code and --init-code-hash can be supplied")
+			case contractAddressInitCode != "":
+				initCode, err := hex.DecodeString(strings.TrimPrefix(contractAddressInitCode, "0x"))
+				cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid init code")
+				initCodeHash = crypto.Keccak256(initCode)
+				diagnosticIf(verbose, fmt.Sprintf("Init code hash is 0x%x", initCodeHash))
+			case contractAddressInitCodeHash != "":
+				initCodeHash, err = hex.DecodeString(strings.TrimPrefix(contractAddressInitCodeHash, "0x"))
+				cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid init code hash")
+				cli.Assert(len(initCodeHash) == 32, quiet, "Init code hash must be 32 bytes")
+			default:
+				cli.ErrCode(quiet, cli.ExitUsage, "--init-code or --init-code-hash is required with --salt")
+			}
+			address = util.CreateAddress2(deployer, salt, initCodeHash)
+		}
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintln(cli.Out, address.Hex())
+	},
+}
+
+func init() {
+	contractCmd.AddCommand(contractAddressCmd)
+	contractAddressCmd.Flags().StringVar(&contractAddressDeployer, "deployer", "", "Address of the account or contract that deploys the contract")
+	contractAddressCmd.Flags().Int64Var(&contractAddressNonce, "nonce", -1, "Nonce of the deployment (CREATE)")
+	contractAddressCmd.Flags().StringVar(&contractAddressSalt, "salt", "", "Salt of the deployment (CREATE2)")
+	contractAddressCmd.Flags().StringVar(&contractAddressInitCode, "init-code", "", "Init code of the contract, as hex (CREATE2)")
+	contractAddressCmd.Flags().StringVar(&contractAddressInitCodeHash, "init-code-hash", "", "Hash of the init code of the contract, as hex (CREATE2)")
+}
@@ -22,6 +22,7 @@ import (
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
@@ -30,6 +31,7 @@ import (
 var contractCallFromAddress string
 var contractCallCall string
 var contractCallReturns string
+var contractCallBlock string
 
 // contractCallCmd represents the contract call command
 var contractCallCmd = &cobra.Command{
@@ -39,22 +41,36 @@ var contractCallCmd = &cobra.Command{
 
    ethereal contract call --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --abi="./erc20.abi" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="totalSupply()"
 
+--from is optional, and only required if the result of the call depends on the sender.  The call can be made against a historical block by supplying its number with --block, which also accepts "latest" and "pending".
+
+If the call reverts with a reason then the reason is displayed.
+
+The call can be made as if accounts had a different state, for example to see if it would succeed if the caller held more Ether or a contract had different code.  The balance of an account can be overridden with --override-balance and its code with --override-code, each of which can be supplied multiple times.  For example:
+
+   ethereal contract call --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --abi="./erc20.abi" --call="balanceOf(0x5FfC014343cd971B7eb70732021E26C35B744cc4)" --override-code=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07=0x6080...
+
+Further overrides, such as the nonce or storage of an account, can be supplied with --state-override as a JSON object in the format used by eth_call, or in a file.  Not all nodes support state overrides.
+
 In quiet mode this will return 0 if the contract is successfully called, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		var fromAddress common.Address
+		if contractCallFromAddress != "" {
+			fromAddress, err = ens.Resolve(client, contractCallFromAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", contractCallFromAddress))
+		}
 
-		cli.Assert(contractCallFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, contractCallFromAddress)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", contractCallFromAddress))
+		blockNumber, pending, err := contractBlock(contractCallBlock)
+		cli.ErrCheck(err, quiet, "Failed to parse block")
 
 		// We need to have 'call' and 'abi'
-		cli.Assert(contractCallCall != "", quiet, "--call is required")
+		cli.AssertCode(contractCallCall != "", quiet, cli.ExitUsage, "--call is required")
 
 		var abi abi.ABI
 		if contractAbi == "" {
 			// TODO See if we can fetch the ABI from ENS
-			cli.Err(quiet, "--abi is required")
+			cli.ErrCode(quiet, cli.ExitUsage, "--abi is required")
 		} else {
-			cli.Assert(contractAbi != "", quiet, "--abi is required (if not present in ENS)")
+			cli.AssertCode(contractAbi != "", quiet, cli.ExitUsage, "--abi is required (if not present in ENS)")
 			abi, err = contractParseAbi(contractAbi)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse ABI %s", contractAbi))
 		}
@@ -76,18 +92,19 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 		method, exists := abi.Methods[methodName]
 		cli.Assert(exists, quiet, fmt.Sprintf("Method %s is unknown", methodName))
 
+		cli.Assert(len(contractCallArgs) == len(method.Inputs), quiet, fmt.Sprintf("Method %s requires %d arguments but %d were supplied", methodName, len(method.Inputs), len(contractCallArgs)))
 		var methodArgs []interface{}
 		for i, input := range method.Inputs {
 			val, err := contractStringToValue(input.Type, contractCallArgs[i])
 			cli.ErrCheck(err, quiet, "Failed to decode argument")
-			outputIf(verbose, fmt.Sprintf("input %d is %v (%v)", i, val, reflect.TypeOf(val)))
+			diagnosticIf(verbose, fmt.Sprintf("input %d is %v (%v)", i, val, reflect.TypeOf(val)))
 			methodArgs = append(methodArgs, val)
 		}
 
 		data, err := abi.Pack(methodName, methodArgs...)
 		cli.ErrCheck(err, quiet, "Failed to convert arguments")
 
-		cli.Assert(contractStr != "", quiet, "--contract is required")
+		cli.AssertCode(contractStr != "", quiet, cli.ExitUsage, "--contract is required")
 		contractAddress, err := ens.Resolve(client, contractStr)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
 
@@ -99,7 +116,22 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 		}
 		ctx, cancel := localContext()
 		defer cancel()
-		result, err := client.CallContract(ctx, msg, nil)
+		var result []byte
+		if stateOverridesSupplied() {
+			var overrides map[common.Address]*stateOverride
+			overrides, err = stateOverrides()
+			cli.ErrCheck(err, quiet, "Failed to obtain state overrides")
+			result, err = stateOverrideCall(ctx, msg, blockNumber, pending, overrides)
+		} else if pending {
+			result, err = client.PendingCallContract(ctx, msg)
+		} else {
+			result, err = client.CallContract(ctx, msg, blockNumber)
+		}
+		// Nodes return the revert reason either as the result or as the
+		// data of the error, so check for it before the error itself
+		if reason, reverted := callRevertReason(result, err); reverted {
+			cli.ErrCode(quiet, cli.ExitExecution, fmt.Sprintf("Call to %s reverted: %s", methodName, reason))
+		}
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to call contract %s", methodName))
 		cli.Assert(len(result) > 0, quiet, fmt.Sprintf("Call to %s did not return any data", methodName))
 
@@ -108,7 +140,7 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 		}
 
 		abiOutput, err := contractUnpack(abi, methodName, []byte(result))
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid ABI for %s in ABI", methodName))
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid ABI for %s in ABI", methodName))
 		results := []string{}
 		for i, _ := range *abiOutput {
 			val, err := contractValueToString(method.Outputs[i].Type, *((*abiOutput)[i]))
@@ -117,7 +149,7 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 		}
 
 		// Output the result
-		fmt.Printf("%s\n", strings.Join(results, ","))
+		fmt.Fprintf(cli.Out, "%s\n", strings.Join(results, ","))
 	},
 }
 
@@ -126,5 +158,7 @@ func init() {
 	contractFlags(contractCallCmd)
 	contractCallCmd.Flags().StringVar(&contractCallFromAddress, "from", "", "Address from which to call the contract method")
 	contractCallCmd.Flags().StringVar(&contractCallCall, "call", "", "Contract method to call")
+	contractCallCmd.Flags().StringVar(&contractCallBlock, "block", "", "Block against which to make the call (number, \"latest\" or \"pending\")")
 	contractCallCmd.Flags().StringVar(&contractCallReturns, "returns", "", "Comma-separated return types")
+	addStateOverrideFlags(contractCallCmd)
 }
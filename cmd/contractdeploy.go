@@ -22,6 +22,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	etherutils "github.com/orinocopay/go-etherutils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -33,6 +34,8 @@ var contractDeployFromAddress string
 var contractDeployConstructor string
 var contractDeployData string
 var contractDeployAmount string
+var contractDeployDataFile string
+var contractDeployWait bool
 
 // contractDeployCmd represents the contract deploy command
 var contractDeployCmd = &cobra.Command{
@@ -46,17 +49,27 @@ where data is the hex string of the contract binary.  If the contract constructo
 
    ethereal contract deploy --data=0x606060...430029 --abi='./MyContract.abi' --constructor='constructor(1,2,3)' --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase=secret
 
-In quiet mode this will return 0 if the contract creation transaction is successfully sent, otherwise 1.`,
+Large contract binaries can be supplied in a file with --data-file.  Gas is estimated automatically unless --gaslimit is supplied.
+
+If --wait is supplied then the command waits for the deployment transaction to be mined, for at most the duration given by --wait-timeout, and displays the address of the deployed contract rather than the transaction hash.
+
+In quiet mode this will return 0 if the contract creation transaction is successfully sent (and, with --wait, successfully mined), otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(contractDeployFromAddress != "", quiet, "--from is required")
+		cli.AssertCode(contractDeployFromAddress != "", quiet, cli.ExitUsage, "--from is required")
 		fromAddress, err := ens.Resolve(client, contractDeployFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", contractDeployFromAddress))
 
-		cli.Assert(contractDeployData != "", quiet, "--data is required")
+		cli.AssertCode(contractDeployData != "" || contractDeployDataFile != "", quiet, cli.ExitUsage, "--data or --data-file is required")
+		cli.AssertCode(contractDeployData == "" || contractDeployDataFile == "", quiet, cli.ExitUsage, "only one of --data and --data-file can be supplied")
 
 		var data []byte
-		data, err = hex.DecodeString(strings.TrimPrefix(contractDeployData, "0x"))
-		cli.ErrCheck(err, quiet, "Failed to decode data")
+		if contractDeployDataFile != "" {
+			data, err = transactionDataFromFile(contractDeployDataFile)
+			cli.ErrCheck(err, quiet, "Failed to read data file")
+		} else {
+			data, err = hex.DecodeString(strings.TrimPrefix(contractDeployData, "0x"))
+			cli.ErrCheck(err, quiet, "Failed to decode data")
+		}
 		// Add construcor arguments if present
 		if contractAbi != "" {
 			cli.Assert(contractDeployConstructor != "", quiet, "Constructor required if ABI is present")
@@ -93,7 +106,7 @@ In quiet mode this will return 0 if the contract creation transaction is success
 		amount := big.NewInt(0)
 		if contractDeployAmount != "" {
 			amount, err = etherutils.StringToWei(contractDeployAmount)
-			cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid amount %s", contractDeployAmount))
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid amount %s", contractDeployAmount))
 		}
 
 		// Create and sign the transaction
@@ -104,12 +117,12 @@ In quiet mode this will return 0 if the contract creation transaction is success
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			ctx, cancel := localContext()
 			defer cancel()
-			err = client.SendTransaction(ctx, signedTx)
+			err = sendTransaction(ctx, signedTx)
 			cli.ErrCheck(err, quiet, "Failed to send contract deployment transaction")
 
 			log.WithFields(log.Fields{
@@ -124,10 +137,29 @@ In quiet mode this will return 0 if the contract creation transaction is success
 				"transactionid": signedTx.Hash().Hex(),
 			}).Info("success")
 
+			if contractDeployWait {
+				diagnosticIf(verbose, fmt.Sprintf("Waiting for transaction %s to be mined", signedTx.Hash().Hex()))
+				waitCtx, waitCancel := waitContext()
+				defer waitCancel()
+				receipt, err := transactionWaitForReceipt(waitCtx, signedTx.Hash())
+				cli.ErrCheck(err, quiet, "Failed to obtain contract deployment receipt")
+				cli.AssertCode(receipt.Status == types.ReceiptStatusSuccessful, quiet, cli.ExitExecution, fmt.Sprintf("Contract deployment transaction %s reverted", signedTx.Hash().Hex()))
+				if quiet {
+					os.Exit(0)
+				}
+				name, err := ens.ReverseResolve(client, &receipt.ContractAddress)
+				if err == nil && name != "" {
+					fmt.Fprintf(cli.Out, "%s (%s)\n", receipt.ContractAddress.Hex(), name)
+				} else {
+					fmt.Fprintln(cli.Out, receipt.ContractAddress.Hex())
+				}
+				os.Exit(0)
+			}
+
 			if quiet {
 				os.Exit(0)
 			}
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 
 		//		cli.Assert(contractStr != "", quiet, "--contract is required")
@@ -160,7 +192,7 @@ In quiet mode this will return 0 if the contract creation transaction is success
 		//		}
 
 		// Output the result
-		//		fmt.Printf("%s\n", strings.Join(results, ","))
+		//		fmt.Fprintf(cli.Out, "%s\n", strings.Join(results, ","))
 	},
 }
 
@@ -170,6 +202,8 @@ func init() {
 	contractDeployCmd.Flags().StringVar(&contractDeployAmount, "amount", "", "Amount of Ether to send with the contract deployment")
 	contractDeployCmd.Flags().StringVar(&contractDeployConstructor, "constructor", "", "Constructor invocation (if required)")
 	contractDeployCmd.Flags().StringVar(&contractDeployData, "data", "", "Contract data (as a hex string)")
+	contractDeployCmd.Flags().StringVar(&contractDeployDataFile, "data-file", "", "File containing contract data (as a hex string)")
+	contractDeployCmd.Flags().BoolVar(&contractDeployWait, "wait", false, "Wait for the contract to be deployed and display its address")
 	contractDeployCmd.Flags().StringVar(&contractDeployFromAddress, "from", "", "Address from which to deploy the contract")
 	addTransactionFlags(contractDeployCmd, "Passphrase for the address from which to deploy the conract")
 }
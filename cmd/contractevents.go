@@ -0,0 +1,206 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var contractEventsEvent string
+var contractEventsFromBlock string
+var contractEventsToBlock string
+var contractEventsArgs []string
+var contractEventsJSON bool
+
+// contractEventsCmd represents the contract events command
+var contractEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Obtain events emitted by a contract",
+	Long: `Obtain and decode the events emitted by a contract.  For example:
+
+    ethereal contract events --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --abi="./erc20.abi" --event=Transfer --from-block=5000000 --to-block=5001000 --arg=_from=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+
+--event is the name of the event in the ABI.  --from-block and --to-block are block numbers, or "latest"; if not supplied the latest block is used.  Indexed arguments can be filtered with --arg=name=value, which can be supplied multiple times; multiple values for the same argument match any of them.  Indexed strings and bytes are stored as their hash, so are matched by hashing the value supplied.
+
+The block range is queried in windows of --chunk-size blocks, as many nodes refuse queries over wide ranges.  If a node refuses a window because it is too large or returns too many results, the window is halved and the query retried.
+
+Each event is output with its block, transaction and arguments.  Use --json to output a single JSON array with an entry for each event.
+
+In quiet mode this will return 0 if any events are found, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(contractStr != "", quiet, cli.ExitUsage, "--contract is required")
+		cli.AssertCode(contractAbi != "", quiet, cli.ExitUsage, "--abi is required")
+		cli.AssertCode(contractEventsEvent != "", quiet, cli.ExitUsage, "--event is required")
+
+		contractAddress, err := ens.Resolve(client, contractStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
+		contractABI, err := contractParseAbi(contractAbi)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse ABI %s", contractAbi))
+		event, exists := contractABI.Events[contractEventsEvent]
+		cli.Assert(exists, quiet, fmt.Sprintf("Event %s is unknown", contractEventsEvent))
+
+		fromBlock, pending, err := contractBlock(contractEventsFromBlock)
+		cli.ErrCheck(err, quiet, "Failed to parse from block")
+		cli.Assert(!pending, quiet, "Events cannot be obtained from pending blocks")
+		toBlock, pending, err := contractBlock(contractEventsToBlock)
+		cli.ErrCheck(err, quiet, "Failed to parse to block")
+		cli.Assert(!pending, quiet, "Events cannot be obtained from pending blocks")
+
+		topics, err := contractEventsTopics(event, contractEventsArgs)
+		cli.ErrCheck(err, quiet, "Failed to create filter")
+
+		logs, err := filterLogs(ethereum.FilterQuery{
+			FromBlock: fromBlock,
+			ToBlock:   toBlock,
+			Addresses: []common.Address{contractAddress},
+			Topics:    topics,
+		})
+		cli.ErrCheck(err, quiet, "Failed to obtain events")
+
+		if quiet {
+			if len(logs) > 0 {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+
+		results := make([]*contractEventJSON, 0, len(logs))
+		for _, log := range logs {
+			values, err := contractDecodeLog(event, &log)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to decode event in transaction %s", log.TxHash.Hex()))
+			if contractEventsJSON {
+				results = append(results, &contractEventJSON{
+					BlockNumber:     log.BlockNumber,
+					TransactionHash: log.TxHash.Hex(),
+					LogIndex:        log.Index,
+					Event:           event.Name,
+					Args:            values,
+				})
+				continue
+			}
+			argStrs := make([]string, len(values))
+			for i, value := range values {
+				argStrs[i] = fmt.Sprintf("%s=%s", value.Name, value.Value)
+			}
+			fmt.Fprintf(cli.Out, "%d\t%s\t%s(%s)\n", log.BlockNumber, log.TxHash.Hex(), event.Name, strings.Join(argStrs, ", "))
+		}
+		if contractEventsJSON {
+			writeJSONArray(results)
+		}
+	},
+}
+
+// contractEventJSON is the JSON representation of an event
+type contractEventJSON struct {
+	BlockNumber     uint64                 `json:"blockNumber"`
+	TransactionHash string                 `json:"transactionHash"`
+	LogIndex        uint                   `json:"logIndex"`
+	Event           string                 `json:"event"`
+	Args            []*contractLogArgument `json:"args"`
+}
+
+// contractEventsTopics creates the topic filter for an event from arguments
+// of the form name=value
+func contractEventsTopics(event abi.Event, args []string) ([][]common.Hash, error) {
+	topics := make([][]common.Hash, 0)
+	if !event.Anonymous {
+		topics = append(topics, []common.Hash{event.Id()})
+	}
+	offset := len(topics)
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("argument %s is not of the form name=value", arg)
+		}
+		position := -1
+		var input abi.Argument
+		indexed := 0
+		for _, candidate := range event.Inputs {
+			if !candidate.Indexed {
+				continue
+			}
+			if candidate.Name == parts[0] {
+				position = offset + indexed
+				input = candidate
+				break
+			}
+			indexed++
+		}
+		if position == -1 {
+			names := make([]string, 0)
+			for _, candidate := range event.Inputs {
+				if candidate.Indexed {
+					names = append(names, candidate.Name)
+				}
+			}
+			return nil, fmt.Errorf("%s is not an indexed argument of %s (indexed arguments are %s)", parts[0], event.Name, strings.Join(names, ", "))
+		}
+		topic, err := contractEventsTopic(input.Type, parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", parts[0], err)
+		}
+		for len(topics) <= position {
+			topics = append(topics, nil)
+		}
+		topics[position] = append(topics[position], topic)
+	}
+	return topics, nil
+}
+
+// contractEventsTopic encodes a value as a topic.  Dynamic values are stored
+// in topics as their hash.
+func contractEventsTopic(argType abi.Type, input string) (common.Hash, error) {
+	switch argType.T {
+	case abi.StringTy:
+		return crypto.Keccak256Hash([]byte(input)), nil
+	case abi.BytesTy:
+		value, err := contractStringToValue(argType, input)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return crypto.Keccak256Hash(value.([]byte)), nil
+	case abi.SliceTy, abi.ArrayTy:
+		return common.Hash{}, fmt.Errorf("filtering on %s is not supported", argType)
+	}
+	value, err := contractStringToValue(argType, input)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	encoded, err := abi.Arguments{{Type: argType}}.Pack(value)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(encoded), nil
+}
+
+func init() {
+	contractCmd.AddCommand(contractEventsCmd)
+	contractFlags(contractEventsCmd)
+	contractEventsCmd.Flags().StringVar(&contractEventsEvent, "event", "", "Name of the event")
+	contractEventsCmd.Flags().StringVar(&contractEventsFromBlock, "from-block", "", "Block from which to obtain events (number or \"latest\")")
+	contractEventsCmd.Flags().StringVar(&contractEventsToBlock, "to-block", "", "Block to which to obtain events (number or \"latest\")")
+	contractEventsCmd.Flags().StringArrayVar(&contractEventsArgs, "arg", nil, "Filter on an indexed argument, as name=value (can be supplied multiple times)")
+	contractEventsCmd.Flags().BoolVar(&contractEventsJSON, "json", false, "Output the events as JSON")
+	addLogsFlags(contractEventsCmd)
+}
@@ -0,0 +1,94 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var contractImplementationBlock string
+
+// contractImplementationCmd represents the contract implementation command
+var contractImplementationCmd = &cobra.Command{
+	Use:   "implementation",
+	Short: "Obtain the implementation of a proxy contract",
+	Long: `Obtain the implementation of a proxy contract from its storage.  For example:
+
+    ethereal contract implementation --contract=0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48
+
+Proxies that follow EIP-1967 are supported, including beacon proxies for which the implementation is obtained from the beacon.  UUPS proxies that use the EIP-1822 slot and OpenZeppelin proxies that use the ZeppelinOS slots are also supported.  The admin of the proxy and its beacon are shown if present, along with the ENS names of all addresses.  A historical value can be obtained by supplying --block.
+
+In quiet mode this will return 0 if the contract has an implementation, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(contractStr != "", quiet, cli.ExitUsage, "--contract is required")
+		contractAddress, err := ens.Resolve(client, contractStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
+
+		blockNumber, pending, err := contractBlock(contractImplementationBlock)
+		cli.ErrCheck(err, quiet, "Failed to parse block")
+		cli.Assert(!pending, quiet, "Pending block not supported with this command")
+
+		ctx, cancel := localContext()
+		defer cancel()
+		proxy, err := util.ProxyDetails(ctx, client, contractAddress, blockNumber)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain proxy details for %s", contractStr))
+		if proxy == nil {
+			outputIf(!quiet, fmt.Sprintf("%s does not have an implementation slot set", contractStr))
+			os.Exit(1)
+		}
+		if quiet {
+			if proxy.Implementation == (common.Address{}) {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		fmt.Fprintf(cli.Out, "Proxy type:\t\t%s\n", proxy.Type)
+		if proxy.Beacon != (common.Address{}) {
+			fmt.Fprintf(cli.Out, "Beacon:\t\t\t%s\n", contractImplementationAddress(proxy.Beacon))
+		}
+		if proxy.Implementation == (common.Address{}) {
+			fmt.Fprintf(cli.Out, "Implementation:\t\tnot available from beacon\n")
+		} else {
+			fmt.Fprintf(cli.Out, "Implementation:\t\t%s\n", contractImplementationAddress(proxy.Implementation))
+		}
+		if proxy.Admin != (common.Address{}) {
+			fmt.Fprintf(cli.Out, "Admin:\t\t\t%s\n", contractImplementationAddress(proxy.Admin))
+		}
+		if proxy.Implementation == (common.Address{}) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+// contractImplementationAddress formats an address along with its ENS name
+// if present
+func contractImplementationAddress(address common.Address) string {
+	return blockInfoAddress(address.Hex(), blockInfoName(address))
+}
+
+func init() {
+	contractCmd.AddCommand(contractImplementationCmd)
+	contractFlags(contractImplementationCmd)
+	contractImplementationCmd.Flags().StringVar(&contractImplementationBlock, "block", "", "Block at which to obtain the implementation (number or \"latest\")")
+}
@@ -34,6 +34,8 @@ var contractSendAmount string
 var contractSendFromAddress string
 var contractSendCall string
 var contractSendReturns string
+var contractSendData string
+var contractSendDataFile string
 
 // contractSendCmd represents the contract call command
 var contractSendCmd = &cobra.Command{
@@ -43,61 +45,80 @@ var contractSendCmd = &cobra.Command{
 
    ethereal contract send --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --abi="./erc20.abi" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="transfer(0x5FfC014343cd971B7eb70732021E26C35B744cc4, 10)" --passphrase=secret
 
+Pre-encoded data can be supplied instead of a call with --data, or for large amounts of data in a file with --data-file.  The ABI is not required in either case.
+
 In quiet mode this will return 0 if the transaction is successfully sent, otherwise 1.`,
 	Aliases: []string{"transaction", "transmit"},
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(contractSendFromAddress != "", quiet, "--from is required")
+		cli.AssertCode(contractSendFromAddress != "", quiet, cli.ExitUsage, "--from is required")
 		fromAddress, err := ens.Resolve(client, contractSendFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", contractSendFromAddress))
 
-		// We need to have 'call' and 'abi'
-		cli.Assert(contractSendCall != "", quiet, "--call is required")
-
-		var abi abi.ABI
-		if contractAbi == "" {
-			// TODO See if we can fetch the ABI from ENS
-			cli.Err(quiet, "--abi is required")
-		} else {
-			cli.Assert(contractAbi != "", quiet, "--abi is required (if not present in ENS)")
-			abi, err = contractParseAbi(contractAbi)
-			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse ABI %s", contractAbi))
+		inputs := 0
+		for _, input := range []string{contractSendCall, contractSendData, contractSendDataFile} {
+			if input != "" {
+				inputs++
+			}
 		}
+		cli.AssertCode(inputs != 0, quiet, cli.ExitUsage, "one of --call, --data or --data-file is required")
+		cli.AssertCode(inputs == 1, quiet, cli.ExitUsage, "only one of --call, --data and --data-file can be supplied")
+
+		var data []byte
+		switch {
+		case contractSendDataFile != "":
+			data, err = transactionDataFromFile(contractSendDataFile)
+			cli.ErrCheck(err, quiet, "Failed to read data file")
+		case contractSendData != "":
+			data, err = hex.DecodeString(strings.TrimPrefix(contractSendData, "0x"))
+			cli.ErrCheck(err, quiet, "Failed to parse data")
+		default:
+			// We need to have 'abi' to encode 'call'
+			var abi abi.ABI
+			if contractAbi == "" {
+				// TODO See if we can fetch the ABI from ENS
+				cli.ErrCode(quiet, cli.ExitUsage, "--abi is required")
+			} else {
+				cli.AssertCode(contractAbi != "", quiet, cli.ExitUsage, "--abi is required (if not present in ENS)")
+				abi, err = contractParseAbi(contractAbi)
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse ABI %s", contractAbi))
+			}
 
-		openBracketPos := strings.Index(contractSendCall, "(")
-		cli.Assert(openBracketPos != -1, quiet, fmt.Sprintf("Missing open bracket in call %s", contractSendCall))
-		closeBracketPos := strings.LastIndex(contractSendCall, ")")
-		cli.Assert(closeBracketPos != -1, quiet, fmt.Sprintf("Missing close bracket in call %s", contractSendCall))
+			openBracketPos := strings.Index(contractSendCall, "(")
+			cli.Assert(openBracketPos != -1, quiet, fmt.Sprintf("Missing open bracket in call %s", contractSendCall))
+			closeBracketPos := strings.LastIndex(contractSendCall, ")")
+			cli.Assert(closeBracketPos != -1, quiet, fmt.Sprintf("Missing close bracket in call %s", contractSendCall))
 
-		methodName := contractSendCall[0:openBracketPos]
+			methodName := contractSendCall[0:openBracketPos]
 
-		var contractSendArgs []string
-		if openBracketPos+1 != closeBracketPos {
-			parser := csv.NewReader(strings.NewReader(contractSendCall[openBracketPos+1 : closeBracketPos]))
-			contractSendArgs, err = parser.Read()
-			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse arguments for %s", contractSendCall))
-		}
+			var contractSendArgs []string
+			if openBracketPos+1 != closeBracketPos {
+				parser := csv.NewReader(strings.NewReader(contractSendCall[openBracketPos+1 : closeBracketPos]))
+				contractSendArgs, err = parser.Read()
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse arguments for %s", contractSendCall))
+			}
 
-		method, exists := abi.Methods[methodName]
-		cli.Assert(exists, quiet, fmt.Sprintf("Method %s is unknown", methodName))
+			method, exists := abi.Methods[methodName]
+			cli.Assert(exists, quiet, fmt.Sprintf("Method %s is unknown", methodName))
 
-		var methodArgs []interface{}
-		for i, input := range method.Inputs {
-			val, err := contractStringToValue(input.Type, contractSendArgs[i])
-			cli.ErrCheck(err, quiet, "Failed to decode argument")
-			methodArgs = append(methodArgs, val)
-		}
+			var methodArgs []interface{}
+			for i, input := range method.Inputs {
+				val, err := contractStringToValue(input.Type, contractSendArgs[i])
+				cli.ErrCheck(err, quiet, "Failed to decode argument")
+				methodArgs = append(methodArgs, val)
+			}
 
-		data, err := abi.Pack(methodName, methodArgs...)
-		cli.ErrCheck(err, quiet, "Failed to convert arguments")
+			data, err = abi.Pack(methodName, methodArgs...)
+			cli.ErrCheck(err, quiet, "Failed to convert arguments")
+		}
 
-		cli.Assert(contractStr != "", quiet, "--contract is required")
+		cli.AssertCode(contractStr != "", quiet, cli.ExitUsage, "--contract is required")
 		contractAddress, err := ens.Resolve(client, contractStr)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
 
 		amount := big.NewInt(0)
 		if contractDeployAmount != "" {
 			amount, err = etherutils.StringToWei(contractSendAmount)
-			cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid amount %s", contractSendAmount))
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid amount %s", contractSendAmount))
 		}
 
 		// Create and sign the transaction
@@ -108,12 +129,12 @@ In quiet mode this will return 0 if the transaction is successfully sent, otherw
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			ctx, cancel := localContext()
 			defer cancel()
-			err = client.SendTransaction(ctx, signedTx)
+			err = sendTransaction(ctx, signedTx)
 			cli.ErrCheck(err, quiet, "Failed to send contract method transaction")
 
 			log.WithFields(log.Fields{
@@ -132,7 +153,7 @@ In quiet mode this will return 0 if the transaction is successfully sent, otherw
 			if quiet {
 				os.Exit(0)
 			}
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
@@ -143,6 +164,8 @@ func init() {
 	contractSendCmd.Flags().StringVar(&contractSendAmount, "amount", "", "Amount of Ether to send with the contract method")
 	contractSendCmd.Flags().StringVar(&contractSendFromAddress, "from", "", "Address from which to call the contract function")
 	contractSendCmd.Flags().StringVar(&contractSendCall, "call", "", "Contract function to call")
+	contractSendCmd.Flags().StringVar(&contractSendData, "data", "", "Encoded data to send to the contract (as a hex string)")
+	contractSendCmd.Flags().StringVar(&contractSendDataFile, "data-file", "", "File containing encoded data to send to the contract (as a hex string)")
 	contractSendCmd.Flags().StringVar(&contractSendReturns, "returns", "", "Comma-separated return types")
 	addTransactionFlags(contractSendCmd, "Passphrase for the address from which to send the contract transaction")
 }
@@ -15,10 +15,12 @@ package cmd
 
 import (
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
@@ -28,6 +30,11 @@ var contractStorageFromAddress string
 var contractStorageCall string
 var contractStorageReturns string
 var contractStorageKey string
+var contractStorageSlot string
+var contractStorageMappingSlot string
+var contractStorageBlock string
+var contractStorageInt bool
+var contractStorageAddress bool
 
 // contractStorageCmd represents the contract storage command
 var contractStorageCmd = &cobra.Command{
@@ -37,18 +44,56 @@ var contractStorageCmd = &cobra.Command{
 
    ethereal contract storage --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --key=0x01
 
+The storage can also be referenced by slot number, in decimal or hex:
+
+   ethereal contract storage --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --slot=1
+
+The storage for a value in a mapping can be obtained by supplying the slot of the mapping and the key of the value in the mapping:
+
+   ethereal contract storage --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --mapping-slot=1 --key=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+
+The value is displayed as hex, or as an integer with --int or an address with --address.  A historical value can be obtained by supplying --block.
+
 In quiet mode this will return 0 if the storage contains a non-zero value, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(contractStr != "", quiet, "--contract is required")
+		cli.AssertCode(contractStr != "", quiet, cli.ExitUsage, "--contract is required")
 		contractAddress, err := ens.Resolve(client, contractStr)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
 
-		cli.Assert(contractStorageKey != "", quiet, "--key is required")
+		cli.AssertCode(!(contractStorageInt && contractStorageAddress), quiet, cli.ExitUsage, "only one of --int and --address can be supplied")
+
 		var hash common.Hash
-		hash = common.HexToHash(strings.TrimPrefix(contractStorageKey, "0x"))
+		switch {
+		case contractStorageMappingSlot != "":
+			cli.AssertCode(contractStorageSlot == "", quiet, cli.ExitUsage, "only one of --slot and --mapping-slot can be supplied")
+			cli.AssertCode(contractStorageKey != "", quiet, cli.ExitUsage, "--key is required with --mapping-slot")
+			key, err := contractStorageWord(contractStorageKey)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid key")
+			slot, err := contractStorageWord(contractStorageMappingSlot)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid mapping slot")
+			hash = crypto.Keccak256Hash(key, slot)
+			diagnosticIf(verbose, fmt.Sprintf("Storage key is %s", hash.Hex()))
+		case contractStorageSlot != "":
+			cli.AssertCode(contractStorageKey == "", quiet, cli.ExitUsage, "only one of --slot and --key can be supplied")
+			slot, err := contractStorageWord(contractStorageSlot)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid slot")
+			hash = common.BytesToHash(slot)
+		default:
+			cli.AssertCode(contractStorageKey != "", quiet, cli.ExitUsage, "--key or --slot is required")
+			hash = common.HexToHash(strings.TrimPrefix(contractStorageKey, "0x"))
+		}
+
+		blockNumber, pending, err := contractBlock(contractStorageBlock)
+		cli.ErrCheck(err, quiet, "Failed to parse block")
+
 		ctx, cancel := localContext()
 		defer cancel()
-		value, err := client.StorageAt(ctx, contractAddress, hash, nil)
+		var value []byte
+		if pending {
+			value, err = client.PendingStorageAt(ctx, contractAddress, hash)
+		} else {
+			value, err = client.StorageAt(ctx, contractAddress, hash, blockNumber)
+		}
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain storage for contract %s", contractStr))
 
 		if quiet {
@@ -61,12 +106,33 @@ In quiet mode this will return 0 if the storage contains a non-zero value, other
 		}
 
 		// Output the result
-		fmt.Printf("0x%x\n", value)
+		switch {
+		case contractStorageInt:
+			fmt.Fprintf(cli.Out, "%v\n", new(big.Int).SetBytes(value))
+		case contractStorageAddress:
+			fmt.Fprintf(cli.Out, "%s\n", common.BytesToAddress(value).Hex())
+		default:
+			fmt.Fprintf(cli.Out, "0x%x\n", value)
+		}
 	},
 }
 
+// contractStorageWord turns a decimal or hex value in to a 32-byte word
+func contractStorageWord(input string) ([]byte, error) {
+	value, success := new(big.Int).SetString(input, 0)
+	if !success || value.Sign() < 0 || value.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid value %s", input)
+	}
+	return common.LeftPadBytes(value.Bytes(), 32), nil
+}
+
 func init() {
 	contractCmd.AddCommand(contractStorageCmd)
 	contractFlags(contractStorageCmd)
-	contractStorageCmd.Flags().StringVar(&contractStorageKey, "key", "", "Storage key")
+	contractStorageCmd.Flags().StringVar(&contractStorageKey, "key", "", "Storage key, or key in the mapping if --mapping-slot is supplied")
+	contractStorageCmd.Flags().StringVar(&contractStorageSlot, "slot", "", "Storage slot, in decimal or hex")
+	contractStorageCmd.Flags().StringVar(&contractStorageMappingSlot, "mapping-slot", "", "Storage slot of a mapping, in decimal or hex")
+	contractStorageCmd.Flags().StringVar(&contractStorageBlock, "block", "", "Block at which to obtain the storage (number, \"latest\" or \"pending\")")
+	contractStorageCmd.Flags().BoolVar(&contractStorageInt, "int", false, "Display the value as an integer")
+	contractStorageCmd.Flags().BoolVar(&contractStorageAddress, "address", false, "Display the value as an address")
 }
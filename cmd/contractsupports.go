@@ -0,0 +1,119 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var contractSupportsInterfaces []string
+var contractSupportsAll bool
+
+// contractSupportsCmd represents the contract supports command
+var contractSupportsCmd = &cobra.Command{
+	Use:   "supports",
+	Short: "Find the interfaces that a contract supports",
+	Long: `Find if a contract claims to support interfaces, using ERC-165.  For example:
+
+    ethereal contract supports --contract=0x06012c8cf97BEaD5deAe237070F9587f8E7A266d --interface=erc721
+
+Interfaces can be supplied as 4 bytes of hex, such as 0x80ac58cd, or as one of the following aliases: ` + strings.Join(util.InterfaceAliases(), ", ") + `.  --interface can be supplied multiple times.  Alternatively, supply --all to check all of the interfaces with aliases and list those that are supported.
+
+Contracts that do not implement ERC-165 cannot be checked, and are reported as such.
+
+In quiet mode this will return 0 if the contract supports all of the interfaces, or with --all if it implements ERC-165, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(contractStr != "", quiet, cli.ExitUsage, "--contract is required")
+		cli.AssertCode(len(contractSupportsInterfaces) > 0 || contractSupportsAll, quiet, cli.ExitUsage, "--interface or --all is required")
+		cli.AssertCode(len(contractSupportsInterfaces) == 0 || !contractSupportsAll, quiet, cli.ExitUsage, "only one of --interface and --all can be supplied")
+
+		aliases := make(map[[4]byte]string)
+		for _, alias := range util.InterfaceAliases() {
+			aliases[util.InterfaceIDs[alias]] = alias
+		}
+		ids := make([][4]byte, 0)
+		if contractSupportsAll {
+			for _, alias := range util.InterfaceAliases() {
+				if alias != "erc165" {
+					ids = append(ids, util.InterfaceIDs[alias])
+				}
+			}
+		} else {
+			for _, input := range contractSupportsInterfaces {
+				id, err := util.InterfaceID(input)
+				cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid interface")
+				ids = append(ids, id)
+			}
+		}
+
+		address, err := ens.Resolve(client, contractStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
+		ctx, cancel := localContext()
+		defer cancel()
+		implemented, err := util.ImplementsERC165(ctx, client, address)
+		cli.ErrCheck(err, quiet, "Failed to check for ERC-165")
+		cli.Assert(implemented, quiet, fmt.Sprintf("%s does not implement ERC-165", contractStr))
+
+		allSupported := true
+		supportedCount := 0
+		for _, id := range ids {
+			supported, err := util.SupportsInterface(ctx, client, address, id)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to check interface 0x%x", id))
+			if !supported {
+				allSupported = false
+			} else {
+				supportedCount++
+			}
+			if quiet || (contractSupportsAll && !supported) {
+				continue
+			}
+			name := fmt.Sprintf("0x%x", id)
+			if alias, exists := aliases[id]; exists {
+				name = fmt.Sprintf("%s (%s)", alias, name)
+			}
+			if contractSupportsAll {
+				fmt.Fprintln(cli.Out, name)
+			} else if supported {
+				fmt.Fprintf(cli.Out, "%s: supported\n", name)
+			} else {
+				fmt.Fprintf(cli.Out, "%s: not supported\n", name)
+			}
+		}
+		if contractSupportsAll {
+			if supportedCount == 0 {
+				outputIf(!quiet, "No known interfaces are supported")
+			}
+			os.Exit(0)
+		}
+		if !allSupported {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+func init() {
+	contractCmd.AddCommand(contractSupportsCmd)
+	contractFlags(contractSupportsCmd)
+	contractSupportsCmd.Flags().StringSliceVar(&contractSupportsInterfaces, "interface", nil, "Interface to check, as 4 bytes of hex or an alias such as erc721")
+	contractSupportsCmd.Flags().BoolVar(&contractSupportsAll, "all", false, "Check all interfaces with aliases")
+}
@@ -0,0 +1,176 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util/etherscan"
+	"github.com/wealdtech/ethereal/util/solidity"
+)
+
+var contractVerifyContract string
+var contractVerifySources string
+var contractVerifyMain string
+var contractVerifyName string
+var contractVerifyCompiler string
+var contractVerifyOptimizer bool
+var contractVerifyOptimizerRuns uint64
+var contractVerifyEVMVersion string
+var contractVerifyRemappings []string
+var contractVerifyConstructorArgs string
+var contractVerifyAPIKey string
+var contractVerifyAPIURL string
+var contractVerifyGUID string
+
+// contractVerifyPollInterval is the time between checks of the status of a
+// verification
+var contractVerifyPollInterval = 5 * time.Second
+
+// contractVerifyCmd represents the contract verify command
+var contractVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the source of a contract with a block explorer",
+	Long: `Submit the source of a deployed contract to Etherscan, or a block explorer with a compatible API, for verification.  For example:
+
+    ethereal contract verify --contract=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --sources=contracts --main=Token.sol --compiler=v0.8.19 --optimizer-runs=200 --api-key=ABCDEFGH --timeout=2m
+
+The sources and compiler settings are supplied as for "ethereal contract verify-input", and must match those with which the contract was compiled.  The contract is named after its main source file unless --name is supplied.  If the contract's constructor takes arguments then they must be supplied, ABI-encoded as a hex string, with --constructor-args.  --compiler can be a release such as v0.8.19 or a full version such as v0.8.19+commit.7dd6d404.
+
+If --api-key or --api-url are not supplied then the explorer configured with --etherscan-api-key and --etherscan-url, or in the config file, is used; by default this is the Etherscan API for the chain of the connected node.
+
+Once the source is submitted the command waits for the result of the verification for at most the duration given by --timeout, displaying the reason if verification fails.  The result of an earlier submission can be checked by supplying its GUID with --guid.
+
+In quiet mode this will return 0 if the contract is verified, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		apiKey := contractVerifyAPIKey
+		if apiKey == "" {
+			apiKey = explorerAPIKey()
+		}
+		apiURL := contractVerifyAPIURL
+		if apiURL == "" {
+			apiURL = explorerURL()
+		}
+		explorer := etherscan.New(apiURL, apiKey, chainID, viper.GetDuration("timeout"))
+
+		guid := contractVerifyGUID
+		if guid == "" {
+			guid = contractVerifySubmit(explorer)
+			if guid == "" {
+				// Already verified
+				os.Exit(0)
+			}
+			diagnosticIf(verbose, fmt.Sprintf("Submitted for verification with GUID %s", guid))
+		}
+
+		ctx, cancel := localContext()
+		defer cancel()
+		// Explorers take a few seconds to accept checks of new submissions
+		wait := contractVerifyGUID == ""
+		for {
+			if wait {
+				select {
+				case <-ctx.Done():
+					cli.Err(quiet, fmt.Sprintf("Timed out waiting for verification; check its result later with --guid=%s", guid))
+				case <-time.After(contractVerifyPollInterval):
+				}
+			}
+			wait = true
+			status, err := explorer.VerifyStatus(guid)
+			cli.ErrCheck(err, quiet, "Failed to obtain status of verification")
+			if status.Verified {
+				outputIf(!quiet, fmt.Sprintf("Verified: %s", status.Message))
+				os.Exit(0)
+			}
+			cli.Assert(status.Pending, quiet, fmt.Sprintf("Verification failed: %s", status.Message))
+			diagnosticIf(verbose, status.Message)
+		}
+	},
+}
+
+// contractVerifySubmit submits the contract for verification, returning the
+// GUID of the verification or an empty string if it is already verified
+func contractVerifySubmit(explorer *etherscan.Client) string {
+	cli.AssertCode(contractVerifyContract != "", quiet, cli.ExitUsage, "--contract is required")
+	cli.AssertCode(contractVerifyMain != "", quiet, cli.ExitUsage, "--main is required")
+	cli.AssertCode(contractVerifyCompiler != "", quiet, cli.ExitUsage, "--compiler is required")
+	address, err := ens.Resolve(client, contractVerifyContract)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractVerifyContract))
+	ctx, cancel := localContext()
+	defer cancel()
+	code, err := client.CodeAt(ctx, address, nil)
+	cli.ErrCheck(err, quiet, "Failed to obtain contract code")
+	cli.Assert(len(code) > 0, quiet, fmt.Sprintf("There is no contract at %s", address.Hex()))
+
+	input, err := contractStandardInput(contractVerifySources, contractVerifyMain, contractVerifyOptimizer, contractVerifyOptimizerRuns, contractVerifyEVMVersion, contractVerifyRemappings)
+	cli.ErrCheck(err, quiet, "Failed to read sources")
+	data, err := json.Marshal(input)
+	cli.ErrCheck(err, quiet, "Failed to generate JSON")
+
+	compilerVersion, err := solidity.LongVersion(solidity.CompilerListURL, contractVerifyCompiler)
+	cli.ErrCheck(err, quiet, "Failed to obtain compiler version")
+	diagnosticIf(verbose, fmt.Sprintf("Compiler version is %s", compilerVersion))
+
+	mainName := path.Clean(filepath.ToSlash(contractVerifyMain))
+	name := contractVerifyName
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(mainName), ".sol")
+	}
+
+	constructorArgs, err := hex.DecodeString(strings.TrimPrefix(contractVerifyConstructorArgs, "0x"))
+	cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid constructor arguments")
+
+	guid, err := explorer.VerifySource(&etherscan.VerifyRequest{
+		Address:              address.Hex(),
+		Input:                string(data),
+		ContractName:         fmt.Sprintf("%s:%s", mainName, name),
+		CompilerVersion:      compilerVersion,
+		ConstructorArguments: constructorArgs,
+	})
+	if err == etherscan.ErrAlreadyVerified {
+		outputIf(!quiet, "Contract source is already verified")
+		return ""
+	}
+	cli.ErrCheck(err, quiet, "Failed to submit contract for verification")
+	return guid
+}
+
+func init() {
+	contractCmd.AddCommand(contractVerifyCmd)
+	contractVerifyCmd.Flags().StringVar(&contractVerifyContract, "contract", "", "Address of the contract")
+	contractVerifyCmd.Flags().StringVar(&contractVerifySources, "sources", ".", "Directory containing the source files")
+	contractVerifyCmd.Flags().StringVar(&contractVerifyMain, "main", "", "Source file of the contract, relative to --sources")
+	contractVerifyCmd.Flags().StringVar(&contractVerifyName, "name", "", "Name of the contract (defaults to the name of the main source file)")
+	contractVerifyCmd.Flags().StringVar(&contractVerifyCompiler, "compiler", "", "Version of the compiler, for example v0.8.19")
+	contractVerifyCmd.Flags().BoolVar(&contractVerifyOptimizer, "optimizer", true, "Enable the optimizer")
+	contractVerifyCmd.Flags().Uint64Var(&contractVerifyOptimizerRuns, "optimizer-runs", 200, "Number of runs for the optimizer")
+	contractVerifyCmd.Flags().StringVar(&contractVerifyEVMVersion, "evm-version", "", "EVM version targeted by the compiler, for example \"paris\" (defaults to that of the compiler)")
+	contractVerifyCmd.Flags().StringSliceVar(&contractVerifyRemappings, "remapping", nil, "Remapping of imports, of the form prefix=target.  Can be supplied multiple times")
+	contractVerifyCmd.Flags().StringVar(&contractVerifyConstructorArgs, "constructor-args", "", "ABI-encoded arguments of the constructor (as a hex string)")
+	contractVerifyCmd.Flags().StringVar(&contractVerifyAPIKey, "api-key", "", "API key for the block explorer")
+	contractVerifyCmd.Flags().StringVar(&contractVerifyAPIURL, "api-url", "", "URL of the block explorer's API")
+	contractVerifyCmd.Flags().StringVar(&contractVerifyGUID, "guid", "", "GUID of an earlier submission for which to check the result")
+}
@@ -0,0 +1,90 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/solidity"
+)
+
+var contractVerifyInputSources string
+var contractVerifyInputMain string
+var contractVerifyInputOptimizer bool
+var contractVerifyInputOptimizerRuns uint64
+var contractVerifyInputEVMVersion string
+var contractVerifyInputRemappings []string
+
+// contractVerifyInputCmd represents the contract verify-input command
+var contractVerifyInputCmd = &cobra.Command{
+	Use:   "verify-input",
+	Short: "Generate the input to verify the source of a contract",
+	Long: `Generate the Solidity standard JSON input for a contract, as accepted by block explorers to verify its source.  For example:
+
+    ethereal contract verify-input --sources=contracts --main=Token.sol --optimizer-runs=200 --output=Token.json
+
+The input contains the main source file and all of the source files that it imports, directly or indirectly, from the directory given by --sources.  Imports that do not start with "./" or "../" are found relative to the directory, after any remappings supplied with --remapping, for example "@openzeppelin/=node_modules/@openzeppelin/".
+
+The compiler settings must match those with which the contract was compiled.  The optimizer is enabled with --optimizer-runs unless --optimizer=false is supplied, and the EVM version can be set with --evm-version.  The sources are not compiled.  The input is written to standard output, or to the file given by --output.
+
+In quiet mode this will return 0 if the input is generated, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(contractVerifyInputMain != "", quiet, cli.ExitUsage, "--main is required")
+
+		input, err := contractStandardInput(contractVerifyInputSources, contractVerifyInputMain, contractVerifyInputOptimizer, contractVerifyInputOptimizerRuns, contractVerifyInputEVMVersion, contractVerifyInputRemappings)
+		cli.ErrCheck(err, quiet, "Failed to read sources")
+		data, err := json.MarshalIndent(input, "", "  ")
+		cli.ErrCheck(err, quiet, "Failed to generate JSON")
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintln(cli.Out, string(data))
+	},
+}
+
+// contractStandardInput creates the standard JSON input for the main source
+// file in a directory
+func contractStandardInput(dir string, main string, optimizer bool, optimizerRuns uint64, evmVersion string, remappings []string) (*solidity.StandardInput, error) {
+	sources, err := solidity.Sources(dir, main, remappings)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range solidity.SourceNames(sources) {
+		diagnosticIf(verbose, fmt.Sprintf("Including %s", name))
+	}
+	return solidity.NewStandardInput(sources, &solidity.Settings{
+		Remappings: remappings,
+		Optimizer: &solidity.Optimizer{
+			Enabled: optimizer,
+			Runs:    optimizerRuns,
+		},
+		EVMVersion: evmVersion,
+	}), nil
+}
+
+func init() {
+	contractCmd.AddCommand(contractVerifyInputCmd)
+	contractVerifyInputCmd.Flags().StringVar(&contractVerifyInputSources, "sources", ".", "Directory containing the source files")
+	contractVerifyInputCmd.Flags().StringVar(&contractVerifyInputMain, "main", "", "Source file of the contract, relative to --sources")
+	contractVerifyInputCmd.Flags().BoolVar(&contractVerifyInputOptimizer, "optimizer", true, "Enable the optimizer")
+	contractVerifyInputCmd.Flags().Uint64Var(&contractVerifyInputOptimizerRuns, "optimizer-runs", 200, "Number of runs for the optimizer")
+	contractVerifyInputCmd.Flags().StringVar(&contractVerifyInputEVMVersion, "evm-version", "", "EVM version targeted by the compiler, for example \"paris\" (defaults to that of the compiler)")
+	contractVerifyInputCmd.Flags().StringSliceVar(&contractVerifyInputRemappings, "remapping", nil, "Remapping of imports, of the form prefix=target.  Can be supplied multiple times")
+}
@@ -44,14 +44,14 @@ In quiet mode this will return 0 if the resource exists, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
-		cli.Assert(dnsDomain != "", quiet, "--domain is required")
+		cli.AssertCode(dnsDomain != "", quiet, cli.ExitUsage, "--domain is required")
 		if !strings.HasSuffix(dnsDomain, ".") {
 			dnsDomain = dnsDomain + "."
 		}
 		dnsDomain = ens.NormaliseDomain(dnsDomain)
-		outputIf(verbose, fmt.Sprintf("DNS domain is %s", dnsDomain))
+		diagnosticIf(verbose, fmt.Sprintf("DNS domain is %s", dnsDomain))
 		ensDomain := strings.TrimSuffix(dnsDomain, ".")
-		outputIf(verbose, fmt.Sprintf("ENS domain is %s", ensDomain))
+		diagnosticIf(verbose, fmt.Sprintf("ENS domain is %s", ensDomain))
 		domainHash := ens.NameHash(ensDomain)
 
 		dnsName = strings.ToLower(dnsName)
@@ -62,7 +62,7 @@ In quiet mode this will return 0 if the resource exists, otherwise 1.`,
 				dnsName = dnsName + "." + dnsDomain
 			}
 		}
-		outputIf(verbose, fmt.Sprintf("DNS name is %s", dnsName))
+		diagnosticIf(verbose, fmt.Sprintf("DNS name is %s", dnsName))
 		nameHash := util.DnsDomainHash(dnsName)
 
 		// Obtain the registry contract
@@ -74,7 +74,7 @@ In quiet mode this will return 0 if the resource exists, otherwise 1.`,
 		cli.ErrCheck(err, quiet, fmt.Sprintf("No resolver registered for %s", dnsDomain))
 		resolverContract, err := ens.DnsResolverContractByAddress(client, resolverAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver contract for %s", dnsDomain))
-		outputIf(verbose, fmt.Sprintf("Resolver contract is at %s", resolverAddress.Hex()))
+		diagnosticIf(verbose, fmt.Sprintf("Resolver contract is at %s", resolverAddress.Hex()))
 
 		var data []byte
 		if dnsResource == "" {
@@ -95,7 +95,7 @@ In quiet mode this will return 0 if the resource exists, otherwise 1.`,
 			dnsResource := strings.ToUpper(dnsResource)
 			resourceNum, exists := stringToType[dnsResource]
 			cli.Assert(exists, quiet, fmt.Sprintf("Unknown resource %s", dnsResource))
-			outputIf(verbose, fmt.Sprintf("Resource record is %s (%d)", dnsResource, resourceNum))
+			diagnosticIf(verbose, fmt.Sprintf("Resource record is %s (%d)", dnsResource, resourceNum))
 			data, err = resolverContract.DnsRecord(nil, domainHash, nameHash, resourceNum)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain %s resource %s for %s", dnsResource, dnsName, dnsDomain))
 			cli.Assert(len(data) > 0, quiet, fmt.Sprintf("No value of %s resource %s for %s", dnsResource, dnsName, dnsDomain))
@@ -106,7 +106,7 @@ In quiet mode this will return 0 if the resource exists, otherwise 1.`,
 		}
 
 		if dnsGetWire {
-			fmt.Println(hex.EncodeToString(data))
+			fmt.Fprintln(cli.Out, hex.EncodeToString(data))
 		} else {
 			// Decode the data resource record(s)
 			offset := 0
@@ -114,7 +114,7 @@ In quiet mode this will return 0 if the resource exists, otherwise 1.`,
 			for offset < len(data) {
 				result, offset, err = dns.UnpackRR(data, offset)
 				if err == nil {
-					fmt.Println(result)
+					fmt.Fprintln(cli.Out, result)
 				}
 			}
 		}
@@ -45,14 +45,14 @@ var dnsSetCmd = &cobra.Command{
 
 In quiet mode this will return 0 if the set transaction is successfully sent, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(dnsDomain != "", quiet, "--domain is required")
+		cli.AssertCode(dnsDomain != "", quiet, cli.ExitUsage, "--domain is required")
 		if !strings.HasSuffix(dnsDomain, ".") {
 			dnsDomain = dnsDomain + "."
 		}
 		dnsDomain = ens.NormaliseDomain(dnsDomain)
-		outputIf(verbose, fmt.Sprintf("DNS domain is %s", dnsDomain))
+		diagnosticIf(verbose, fmt.Sprintf("DNS domain is %s", dnsDomain))
 		ensDomain := strings.TrimSuffix(dnsDomain, ".")
-		outputIf(verbose, fmt.Sprintf("ENS domain is %s", ensDomain))
+		diagnosticIf(verbose, fmt.Sprintf("ENS domain is %s", ensDomain))
 		domainHash := ens.NameHash(ensDomain)
 
 		// Obtain the registry contract
@@ -63,14 +63,14 @@ In quiet mode this will return 0 if the set transaction is successfully sent, ot
 		domainOwner, err := registryContract.Owner(nil, domainHash)
 		cli.ErrCheck(err, quiet, "Cannot obtain owner")
 		cli.Assert(bytes.Compare(domainOwner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
-		outputIf(verbose, fmt.Sprintf("Domain owner is %s", domainOwner.Hex()))
+		diagnosticIf(verbose, fmt.Sprintf("Domain owner is %s", domainOwner.Hex()))
 
 		// Obtain resolver for the domain
 		resolverAddress, err := ens.Resolver(registryContract, ensDomain)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("No resolver registered for %s", dnsDomain))
 		resolverContract, err := ens.DnsResolverContractByAddress(client, resolverAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver contract for %s", dnsDomain))
-		outputIf(verbose, fmt.Sprintf("Resolver contract is at %s", resolverAddress.Hex()))
+		diagnosticIf(verbose, fmt.Sprintf("Resolver contract is at %s", resolverAddress.Hex()))
 
 		var signedTx *types.Transaction
 		data := make([]byte, 16384)
@@ -89,7 +89,7 @@ In quiet mode this will return 0 if the set transaction is successfully sent, ot
 			w.Write(data[0:offset])
 			w.Close()
 			data = b.Bytes()
-			fmt.Printf("Data size is %d\n", len(data))
+			fmt.Fprintf(cli.Out, "Data size is %d\n", len(data))
 
 			// Build the transaction
 			opts, err := generateTxOpts(domainOwner)
@@ -100,7 +100,7 @@ In quiet mode this will return 0 if the set transaction is successfully sent, ot
 				if !quiet {
 					buf := new(bytes.Buffer)
 					signedTx.EncodeRLP(buf)
-					fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+					fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 				}
 			} else {
 				log.WithFields(log.Fields{
@@ -117,7 +117,7 @@ In quiet mode this will return 0 if the set transaction is successfully sent, ot
 				if quiet {
 					os.Exit(0)
 				}
-				fmt.Println(signedTx.Hash().Hex())
+				fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 			}
 
 		} else {
@@ -130,18 +130,18 @@ In quiet mode this will return 0 if the set transaction is successfully sent, ot
 					dnsName = dnsName + "." + dnsDomain
 				}
 			}
-			outputIf(verbose, fmt.Sprintf("DNS name is %s", dnsName))
+			diagnosticIf(verbose, fmt.Sprintf("DNS name is %s", dnsName))
 			nameHash := util.DnsDomainHash(dnsName)
 
-			cli.Assert(dnsSetTtl != time.Duration(0), quiet, "--ttl is required")
+			cli.AssertCode(dnsSetTtl != time.Duration(0), quiet, cli.ExitUsage, "--ttl is required")
 
-			cli.Assert(dnsResource != "", quiet, "--resource is required")
+			cli.AssertCode(dnsResource != "", quiet, cli.ExitUsage, "--resource is required")
 			dnsResource := strings.ToUpper(dnsResource)
 			resourceNum, exists := stringToType[dnsResource]
 			cli.Assert(exists, quiet, fmt.Sprintf("Unknown resource %s", dnsResource))
-			outputIf(verbose, fmt.Sprintf("Resource record is %s (%d)", dnsResource, resourceNum))
+			diagnosticIf(verbose, fmt.Sprintf("Resource record is %s (%d)", dnsResource, resourceNum))
 
-			cli.Assert(dnsSetValue != "", quiet, "--value is required")
+			cli.AssertCode(dnsSetValue != "", quiet, cli.ExitUsage, "--value is required")
 
 			// Create the data resource record(s)
 			offset := 0
@@ -163,9 +163,9 @@ In quiet mode this will return 0 if the set transaction is successfully sent, ot
 					// We have an SOA so increment the serial
 					soaRr, _, err := dns.UnpackRR(curSoaData, 0)
 					cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to unpack SOA resource for %s", dnsDomain))
-					outputIf(verbose, fmt.Sprintf("Current SOA record is %v", soaRr))
+					diagnosticIf(verbose, fmt.Sprintf("Current SOA record is %v", soaRr))
 					soaRr.(*dns.SOA).Serial += 1
-					outputIf(verbose, fmt.Sprintf("New SOA record is %v", soaRr))
+					diagnosticIf(verbose, fmt.Sprintf("New SOA record is %v", soaRr))
 					soaData = make([]byte, 16384)
 					offset, err := dns.PackRR(soaRr, soaData, 0, nil, false)
 					soaData = soaData[0:offset]
@@ -181,7 +181,7 @@ In quiet mode this will return 0 if the set transaction is successfully sent, ot
 				if !quiet {
 					buf := new(bytes.Buffer)
 					signedTx.EncodeRLP(buf)
-					fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+					fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 				}
 			} else {
 				log.WithFields(log.Fields{
@@ -203,7 +203,7 @@ In quiet mode this will return 0 if the set transaction is successfully sent, ot
 					os.Exit(0)
 				}
 
-				fmt.Println(signedTx.Hash().Hex())
+				fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 			}
 		}
 	},
@@ -0,0 +1,97 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+// dryRun is set if transactions should be displayed rather than sent
+var dryRun bool
+
+// dryRunSigner wraps a signer so that the signed transaction is displayed
+// rather than sent
+func dryRunSigner(signer bind.SignerFn) bind.SignerFn {
+	return func(txSigner types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		signedTx, err := signer(txSigner, address, tx)
+		if err != nil {
+			return nil, err
+		}
+		dryRunTransaction(address, signedTx)
+		return signedTx, nil
+	}
+}
+
+// dryRunTransaction displays the details of a signed transaction, along with
+// the result of simulating it if online, and exits without sending it.  In
+// quiet mode this exits with 0 if the simulation succeeds, otherwise 1.
+func dryRunTransaction(from common.Address, signedTx *types.Transaction) {
+	var simulationErr error
+	if !offline {
+		simulationErr = simulateTransaction(from, signedTx)
+	}
+
+	if quiet {
+		if simulationErr != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	txdata.InitFunctionMap()
+	fmt.Fprintln(cli.Out, "Dry run; transaction not sent")
+	fmt.Fprintf(cli.Out, "From:\t\t\t%s\n", from.Hex())
+	if signedTx.To() == nil {
+		fmt.Fprintln(cli.Out, "To:\t\t\tNew contract")
+	} else {
+		fmt.Fprintf(cli.Out, "To:\t\t\t%s\n", signedTx.To().Hex())
+	}
+	fmt.Fprintf(cli.Out, "Value:\t\t\t%s\n", etherutils.WeiToString(signedTx.Value(), true))
+	if len(signedTx.Data()) > 0 {
+		fmt.Fprintf(cli.Out, "Data:\t\t\t%s\n", txdata.DataToString(signedTx.Data()))
+	}
+	fmt.Fprintf(cli.Out, "Nonce:\t\t\t%d\n", signedTx.Nonce())
+	fmt.Fprintf(cli.Out, "Gas limit:\t\t%d\n", signedTx.Gas())
+	fmt.Fprintf(cli.Out, "Gas price:\t\t%s\n", etherutils.WeiToString(signedTx.GasPrice(), true))
+	maxFee := new(big.Int).Mul(signedTx.GasPrice(), new(big.Int).SetUint64(signedTx.Gas()))
+	fmt.Fprintf(cli.Out, "Maximum fee:\t\t%s\n", etherutils.WeiToString(maxFee, true))
+	if v, _, _ := signedTx.RawSignatureValues(); v.Sign() != 0 {
+		// Transactions to be signed by the node have no hash yet
+		fmt.Fprintf(cli.Out, "Hash:\t\t\t%s\n", signedTx.Hash().Hex())
+		buf := new(bytes.Buffer)
+		signedTx.EncodeRLP(buf)
+		fmt.Fprintf(cli.Out, "Raw:\t\t\t0x%s\n", hex.EncodeToString(buf.Bytes()))
+	}
+	switch {
+	case offline:
+		fmt.Fprintln(cli.Out, "Simulation:\t\tNot available when offline")
+	case simulationErr != nil:
+		fmt.Fprintf(cli.Out, "Simulation:\t\tFailed: %v\n", simulationErr)
+		os.Exit(1)
+	default:
+		fmt.Fprintln(cli.Out, "Simulation:\t\tSucceeded")
+	}
+	os.Exit(0)
+}
@@ -14,7 +14,11 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
 )
 
@@ -39,10 +43,61 @@ func inState(domain string, state string) (inState bool) {
 	return
 }
 
+// ensController returns true if the address is able to manage the name,
+// either by owning it or by being an approved operator for its owner
+func ensController(name string, address common.Address) (bool, error) {
+	registry, err := ens.RegistryContract(client)
+	if err != nil {
+		return false, err
+	}
+	owner, err := registry.Owner(nil, ens.NameHash(name))
+	if err != nil {
+		return false, err
+	}
+	if owner == address {
+		return true, nil
+	}
+	if owner == ens.UnknownAddress {
+		return false, nil
+	}
+	// Older registries do not support operators, so treat failure as
+	// not approved
+	approved, err := ens.IsApprovedForAll(client, owner, address)
+	if err != nil {
+		return false, nil
+	}
+	return approved, nil
+}
+
+// ensSender obtains the address from which to send a transaction that
+// manages a name.  If from is not supplied this is the owner of the name,
+// otherwise unless force is set it is checked to be able to manage the name
+func ensSender(name string, from string, force bool) common.Address {
+	var fromAddress common.Address
+	if from == "" {
+		registry, err := ens.RegistryContract(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+		fromAddress, err = registry.Owner(nil, ens.NameHash(name))
+		cli.ErrCheck(err, quiet, "Failed to obtain owner")
+		cli.Assert(fromAddress != ens.UnknownAddress, quiet, fmt.Sprintf("Owner of %s is not set", name))
+		return fromAddress
+	}
+
+	fromAddress, err := ens.Resolve(client, from)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", from))
+	if !force {
+		controller, err := ensController(name, fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to obtain owner")
+		cli.Assert(controller, quiet, fmt.Sprintf("%s is not the owner or an approved operator of %s", fromAddress.Hex(), name))
+	}
+	return fromAddress
+}
+
 func init() {
 	RootCmd.AddCommand(ensCmd)
 }
 
 func ensFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&ensDomain, "domain", "", "Domain against which to operate (e.g. wealdtech.eth)")
+	cmd.Flags().StringVar(&ensDomain, "name", "", "Alternative for --domain")
 }
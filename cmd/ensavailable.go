@@ -0,0 +1,194 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var ensAvailableDuration string
+var ensAvailableController string
+var ensAvailableJSON bool
+
+// ensAvailableResult is the availability of a name
+type ensAvailableResult struct {
+	Name        string `json:"name"`
+	Available   bool   `json:"available"`
+	Price       string `json:"price,omitempty"`
+	Premium     string `json:"premium,omitempty"`
+	Expiry      int64  `json:"expiry,omitempty"`
+	GracePeriod bool   `json:"gracePeriod,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ensAvailableCmd represents the ens available command
+var ensAvailableCmd = &cobra.Command{
+	Use:   "available [name...]",
+	Short: "Check if .eth names are available",
+	Long: `Check if .eth names are available for registration, and their price.  For example:
+
+    ethereal ens available --domain=enstest.eth --duration=1y
+
+Further names can be supplied as arguments.  For each name that is available the price of registering it for --duration is shown.  Names that expired recently are subject to a premium that falls to nothing over 21 days; the premium is included in the price and also shown separately.  For each name that is not available its expiry is shown, along with whether it is in the 90-day grace period after expiry during which only its owner can renew it.  If --currency is supplied then prices are also displayed in that currency.  Use --json to output a single JSON array with an entry for each name.
+
+In quiet mode this will return 0 if all names are available, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		names := make([]string, 0, len(args)+1)
+		if ensDomain != "" {
+			names = append(names, ensDomain)
+		}
+		names = append(names, args...)
+		cli.AssertCode(len(names) > 0, quiet, cli.ExitUsage, "--domain is required")
+		duration, err := util.StringToDuration(ensAvailableDuration)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid duration")
+		cli.Assert(duration >= ens.MinRegistrationDuration, quiet, "Duration must be at least 28 days")
+
+		controller := ensRegistrarController(ensAvailableController)
+		registrar, err := ens.BaseRegistrarAddress(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registrar")
+
+		allAvailable := true
+		results := make([]*ensAvailableResult, 0, len(names))
+		for _, name := range names {
+			result := ensAvailableCheck(controller, registrar, name, duration)
+			if !result.Available {
+				allAvailable = false
+			}
+			results = append(results, result)
+		}
+
+		if quiet {
+			if allAvailable {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+		if ensAvailableJSON {
+			writeJSONArray(results)
+			os.Exit(0)
+		}
+		for _, result := range results {
+			fmt.Fprintln(cli.Out, ensAvailableString(result, duration))
+		}
+		os.Exit(0)
+	},
+}
+
+// ensAvailableCheck obtains the availability and price of a name
+func ensAvailableCheck(controller common.Address, registrar common.Address, name string, duration time.Duration) *ensAvailableResult {
+	result := &ensAvailableResult{Name: name}
+	domain, err := ens.NormaliseDomainStrict(name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Name = domain
+	if ens.DomainLevel(domain) != 1 || ens.Tld(domain) != "eth" {
+		result.Error = "only second-level .eth names can be registered"
+		return result
+	}
+	label, err := ens.DomainPart(domain, 1)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	expiry, err := ens.NameExpires(client, registrar, domain)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to obtain expiry: %v", err)
+		return result
+	}
+	if !expiry.IsZero() {
+		result.Expiry = expiry.Unix()
+	}
+	result.Available, err = ens.Available(client, controller, label)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to obtain availability: %v", err)
+		return result
+	}
+
+	if !result.Available {
+		result.GracePeriod = !expiry.IsZero() && time.Now().After(expiry)
+		return result
+	}
+
+	price, err := ens.RentPrice(client, controller, label, duration)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to obtain price: %v", err)
+		return result
+	}
+	result.Price = price.String()
+	if !expiry.IsZero() && time.Now().Before(expiry.Add(ens.GracePeriod+ens.PremiumPeriod)) {
+		premium, err := ens.Premium(client, controller, label, expiry, duration)
+		if err != nil {
+			diagnosticIf(verbose, fmt.Sprintf("Failed to obtain premium for %s: %v", domain, err))
+		} else if premium.Sign() > 0 {
+			result.Premium = premium.String()
+		}
+	}
+	return result
+}
+
+// ensAvailableString provides a line of text describing the availability of
+// a name
+func ensAvailableString(result *ensAvailableResult, duration time.Duration) string {
+	if result.Error != "" {
+		return fmt.Sprintf("%s\tError: %s", result.Name, result.Error)
+	}
+	if result.Available {
+		price, _ := new(big.Int).SetString(result.Price, 10)
+		res := fmt.Sprintf("%s is available; %s costs %s%s", result.Name, ensAvailableDurationString(duration), etherutils.WeiToString(price, true), etherFiatSuffix(price))
+		if result.Premium != "" {
+			premium, _ := new(big.Int).SetString(result.Premium, 10)
+			res = fmt.Sprintf("%s including a premium of %s%s", res, etherutils.WeiToString(premium, true), etherFiatSuffix(premium))
+		}
+		return res
+	}
+	if result.Expiry == 0 {
+		return fmt.Sprintf("%s is not available", result.Name)
+	}
+	expiry := time.Unix(result.Expiry, 0).UTC()
+	if result.GracePeriod {
+		return fmt.Sprintf("%s expired %s and is in its grace period until %s", result.Name, expiry.Format("2006-01-02 15:04:05"), expiry.Add(ens.GracePeriod).Format("2006-01-02 15:04:05"))
+	}
+	return fmt.Sprintf("%s is registered until %s", result.Name, expiry.Format("2006-01-02 15:04:05"))
+}
+
+// ensAvailableDurationString provides a duration in days
+func ensAvailableDurationString(duration time.Duration) string {
+	days := int64(duration / (24 * time.Hour))
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+func init() {
+	ensCmd.AddCommand(ensAvailableCmd)
+	ensFlags(ensAvailableCmd)
+	ensAvailableCmd.Flags().StringVar(&ensAvailableDuration, "duration", "1y", "The period of registration to price, for example 1y or 90d")
+	ensAvailableCmd.Flags().StringVar(&ensAvailableController, "controller", "", "Address of the registrar controller (defaults to that published in ENS)")
+	ensAvailableCmd.Flags().BoolVar(&ensAvailableJSON, "json", false, "Output the results as JSON")
+}
@@ -0,0 +1,130 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var ensAvatarIPFSGateway string
+
+// ensAvatarMaxMetadata is the maximum size of NFT metadata that will be read
+const ensAvatarMaxMetadata = 1024 * 1024
+
+// ensAvatarCmd represents the ens avatar command
+var ensAvatarCmd = &cobra.Command{
+	Use:   "avatar",
+	Short: "Obtain the avatar of an ENS domain",
+	Long: `Obtain the URL of the avatar image of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ethereal ens avatar --name=enstest.eth
+
+The avatar is taken from the name's avatar text record.  If the record refers to an NFT, in the form eip155:1/erc721:0x.../1234 or eip155:1/erc1155:0x.../1234, then the NFT's metadata is fetched from the URI provided by its contract and the image in the metadata is displayed; a warning is given if the address of the name does not hold the NFT.  Otherwise the record is the URI of the image itself.  IPFS and IPNS URIs are converted to URLs with the gateway supplied in --ipfs-gateway.
+
+In verbose mode the avatar record and the metadata URI are also displayed.
+
+In quiet mode this will return 0 if the name has an avatar whose image URL can be obtained, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+
+		resolver, err := ens.ResolverContract(client, name)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver for %s", name))
+		record, err := resolver.Text(nil, ens.NameHash(name), "avatar")
+		cli.ErrCheck(err, quiet, "Failed to obtain avatar record")
+		cli.Assert(record != "", quiet, fmt.Sprintf("%s has no avatar", name))
+		diagnosticIf(verbose, fmt.Sprintf("Avatar record: %s", record))
+
+		avatar, err := ens.ParseAvatar(record)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid avatar record")
+
+		image := avatar.URI
+		if avatar.IsNFT() {
+			cli.Assert(avatar.ChainID.Cmp(chainID) == 0, quiet, fmt.Sprintf("Avatar NFT is on chain %v but connected to chain %v", avatar.ChainID, chainID))
+			address, err := ens.Resolve(client, name)
+			if err != nil {
+				diagnosticIf(verbose, fmt.Sprintf("Cannot check ownership of avatar NFT: %v", err))
+			} else {
+				owned, err := ens.AvatarOwned(client, avatar, address)
+				cli.ErrCheck(err, quiet, "Failed to obtain owner of avatar NFT")
+				if !owned {
+					cli.Warn(quiet, fmt.Sprintf("Avatar NFT is not held by %s, the address of %s", address.Hex(), name))
+				}
+			}
+
+			metadataURI, err := ens.AvatarMetadataURI(client, avatar)
+			cli.ErrCheck(err, quiet, "Failed to obtain metadata URI of avatar NFT")
+			cli.Assert(metadataURI != "", quiet, "Avatar NFT has no metadata URI")
+			metadataURI = ens.GatewayURL(metadataURI, ensAvatarIPFSGateway)
+			if !strings.HasPrefix(metadataURI, "data:") {
+				diagnosticIf(verbose, fmt.Sprintf("Metadata URI: %s", metadataURI))
+			}
+			metadata, err := ensAvatarFetch(metadataURI)
+			cli.ErrCheck(err, quiet, "Failed to obtain metadata of avatar NFT")
+			image, err = ens.AvatarImage(metadata)
+			cli.ErrCheck(err, quiet, "Failed to obtain image of avatar NFT")
+		}
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintln(cli.Out, ens.GatewayURL(image, ensAvatarIPFSGateway))
+	},
+}
+
+// ensAvatarFetch fetches the content of a URI
+func ensAvatarFetch(uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		return ens.DecodeDataURI(uri)
+	}
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		return nil, fmt.Errorf("unsupported URI %s", uri)
+	}
+	httpClient := &http.Client{Timeout: viper.GetDuration("timeout")}
+	resp, err := httpClient.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %s returned %s", uri, resp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, ensAvatarMaxMetadata+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > ensAvatarMaxMetadata {
+		return nil, errors.New("metadata is too large")
+	}
+	return body, nil
+}
+
+func init() {
+	ensCmd.AddCommand(ensAvatarCmd)
+	ensFlags(ensAvatarCmd)
+	ensAvatarCmd.Flags().StringVar(&ensAvatarIPFSGateway, "ipfs-gateway", "https://ipfs.io", "Gateway used to convert IPFS and IPNS URIs to URLs")
+}
@@ -0,0 +1,64 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+// ensContenthashGetCmd represents the ens contenthash get command
+var ensContenthashGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Obtain the content hash of an ENS domain",
+	Long: `Obtain the content hash of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ethereal ens contenthash get --name=enstest.eth
+
+The content hash is displayed as a URI, for example ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4, ipns://k51... or bzz://d1de9994....  In verbose mode the encoded content hash is also displayed.
+
+In quiet mode this will return 0 if the name has a content hash that can be decoded, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+
+		registry, err := ens.RegistryContract(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+		resolverAddress, err := ens.Resolver(registry, name)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver for %s", name))
+		hash, err := ens.Contenthash(client, resolverAddress, name)
+		cli.ErrCheck(err, quiet, "Failed to obtain content hash")
+		cli.Assert(len(hash) > 0, quiet, fmt.Sprintf("%s has no content hash", name))
+		diagnosticIf(verbose, fmt.Sprintf("Content hash: %#x", hash))
+
+		uri, err := ens.ContenthashToURI(hash)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to decode content hash %#x", hash))
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintln(cli.Out, uri)
+	},
+}
+
+func init() {
+	ensContenthashCmd.AddCommand(ensContenthashGetCmd)
+	ensFlags(ensContenthashGetCmd)
+}
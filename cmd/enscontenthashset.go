@@ -0,0 +1,98 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var ensContenthashSetURI string
+var ensContenthashSetFromAddress string
+var ensContenthashSetForce bool
+
+// ensContenthashSetCmd represents the ens contenthash set command
+var ensContenthashSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the content hash of an ENS domain",
+	Long: `Set the content hash of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ethereal ens contenthash set --name=enstest.eth --uri=ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4 --passphrase="my secret passphrase"
+
+The URI can refer to IPFS content with ipfs://, an IPNS key with ipns:// or Swarm content with bzz://, and is encoded as an EIP-1577 content hash.  IPFS and IPNS values can be version 0 or version 1 CIDs.
+
+The content hash is set on the name's current resolver, which must support content hashes.  If --from is not supplied the transaction is sent from the owner of the name.  The command checks that the sender owns the name or is an approved operator for its owner before sending the transaction; use --force to skip this check.
+
+The keystore for the sending account must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to set the content hash is sent successfully, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+		cli.AssertCode(ensContenthashSetURI != "", quiet, cli.ExitUsage, "--uri is required")
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+		hash, err := ens.URIToContenthash(ensContenthashSetURI)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid URI")
+		diagnosticIf(verbose, fmt.Sprintf("Content hash: %#x", hash))
+
+		fromAddress := ensSender(name, ensContenthashSetFromAddress, ensContenthashSetForce)
+
+		registry, err := ens.RegistryContract(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+		resolverAddress, err := ens.Resolver(registry, name)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver for %s", name))
+		resolver, err := ens.ResolverContractByAddress(client, resolverAddress)
+		cli.ErrCheck(err, quiet, "Failed to obtain resolver contract")
+		supported, err := resolver.SupportsInterface(nil, ens.ContenthashInterfaceID)
+		cli.Assert(err == nil && supported, quiet, "Resolver does not support content hashes")
+
+		opts, err := generateTxOpts(fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+		signedTx, err := ens.SetContenthash(opts, client, resolverAddress, name, hash)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+
+		log.WithFields(log.Fields{
+			"group":         "ens",
+			"command":       "contenthash set",
+			"name":          name,
+			"uri":           ensContenthashSetURI,
+			"contenthash":   fmt.Sprintf("%#x", hash),
+			"from":          fromAddress.Hex(),
+			"networkid":     chainID,
+			"gas":           signedTx.Gas(),
+			"gasprice":      signedTx.GasPrice().String(),
+			"transactionid": signedTx.Hash().Hex(),
+		}).Info("success")
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+	},
+}
+
+func init() {
+	ensContenthashCmd.AddCommand(ensContenthashSetCmd)
+	ensFlags(ensContenthashSetCmd)
+	ensContenthashSetCmd.Flags().StringVar(&ensContenthashSetURI, "uri", "", "The URI of the content, for example ipfs://Qm...")
+	ensContenthashSetCmd.Flags().StringVar(&ensContenthashSetFromAddress, "from", "", "Address from which to send the transaction (defaults to the owner of the name)")
+	ensContenthashSetCmd.Flags().BoolVar(&ensContenthashSetForce, "force", false, "Send without checking that the sender is able to manage the name")
+	addTransactionFlags(ensContenthashSetCmd, "Passphrase for the account that sends the transaction")
+}
@@ -15,9 +15,11 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/orinocopay/go-etherutils"
@@ -28,23 +30,55 @@ import (
 
 var zero = big.NewInt(0)
 
+var ensInfoJSON bool
+
+// ensInfoRecords contains the registry and resolver records for a name.
+type ensInfoRecords struct {
+	Name          string            `json:"name"`
+	Owner         string            `json:"owner,omitempty"`
+	OwnerName     string            `json:"ownerName,omitempty"`
+	TTL           uint64            `json:"ttl"`
+	Resolver      string            `json:"resolver,omitempty"`
+	Address       string            `json:"address,omitempty"`
+	ReverseName   string            `json:"reverseName,omitempty"`
+	Contenthash   string            `json:"contenthash,omitempty"`
+	CoinAddresses map[string]string `json:"coinAddresses,omitempty"`
+	Text          map[string]string `json:"text,omitempty"`
+}
+
 // ensInfoCmd represents the ens info command
 var ensInfoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Obtain information about an ENS domain",
 	Long: `Obtain information about a domain registered with the Ethereum Name Service (ENS).  For example:
 
-    ens info enstest.eth
+    ethereal ens info --name=enstest.eth
+
+This shows the owner, resolver and TTL of the name, the address to which it resolves and that address's reverse record, and any common text records.  For resolvers that support them the content hash and addresses for other coins are also shown.  Use --json to output the records as JSON.
 
 In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--domain is required")
 
 		ensDomain = ens.NormaliseDomain(ensDomain)
-		outputIf(verbose, fmt.Sprintf("Normalised domain is %s", ensDomain))
+		diagnosticIf(verbose, fmt.Sprintf("Normalised domain is %s", ensDomain))
+
+		if ensInfoJSON {
+			info := ensInfoObtain(ensDomain)
+			if quiet {
+				if info.Owner == "" {
+					os.Exit(1)
+				}
+				os.Exit(0)
+			}
+			data, err := json.Marshal(info)
+			cli.ErrCheck(err, quiet, "Failed to generate JSON")
+			fmt.Fprintln(cli.Out, string(data))
+			os.Exit(0)
+		}
 
-		outputIf(verbose, fmt.Sprintf("Top-level domain is %s", ens.Tld(ensDomain)))
+		diagnosticIf(verbose, fmt.Sprintf("Top-level domain is %s", ens.Tld(ensDomain)))
 		registrarContract, err := ens.RegistrarContract(client, ens.Tld(ensDomain))
 		cli.ErrCheck(err, quiet, "Failed to obtain ENS registrar contract")
 
@@ -52,7 +86,7 @@ In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
 		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
 		domainOwnerAddress, err := registry.Owner(nil, ens.NameHash(ensDomain))
 		cli.ErrCheck(err, quiet, "Failed to obtain domain owner")
-		fmt.Printf("Domain owner is %s\n", domainOwnerAddress.Hex())
+		fmt.Fprintf(cli.Out, "Domain owner is %s\n", domainOwnerAddress.Hex())
 
 		if ens.DomainLevel(ensDomain) == 1 {
 			state, err := ens.State(registrarContract, client, ensDomain)
@@ -76,7 +110,7 @@ In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
 					case "Owned":
 						ownedInfo(ensDomain)
 					default:
-						fmt.Println(state)
+						fmt.Fprintln(cli.Out, state)
 					}
 				}
 			} else {
@@ -91,13 +125,14 @@ In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
 func init() {
 	ensCmd.AddCommand(ensInfoCmd)
 	ensFlags(ensInfoCmd)
+	ensInfoCmd.Flags().BoolVar(&ensInfoJSON, "json", false, "Output the records as JSON")
 }
 
 func availableInfo(name string) {
 	if len(name) < 11 { // 7 + 4 for '.eth'
-		fmt.Println("Unavailable due to name length restrictions")
+		fmt.Fprintln(cli.Out, "Unavailable due to name length restrictions")
 	} else {
-		fmt.Println("Available")
+		fmt.Fprintln(cli.Out, "Available")
 	}
 }
 
@@ -107,7 +142,7 @@ func biddingInfo(name string) {
 	_, _, registrationDate, _, _, err := ens.Entry(registrarContract, client, name)
 	cli.ErrCheck(err, quiet, "Cannot obtain auction status")
 	twoDaysAgo := time.Duration(-48) * time.Hour
-	fmt.Println("Bidding until", registrationDate.Add(twoDaysAgo))
+	fmt.Fprintln(cli.Out, "Bidding until", registrationDate.Add(twoDaysAgo))
 }
 
 func revealingInfo(name string) {
@@ -115,13 +150,13 @@ func revealingInfo(name string) {
 	cli.ErrCheck(err, quiet, "Failed to obtain ENS registrar contract")
 	_, _, registrationDate, value, highestBid, err := ens.Entry(registrarContract, client, name)
 	cli.ErrCheck(err, quiet, "Cannot obtain information for that name")
-	fmt.Println("Revealing until", registrationDate)
+	fmt.Fprintln(cli.Out, "Revealing until", registrationDate)
 	// If the value is 0 then it is is minvalue instead
 	if value.Cmp(zero) == 0 {
 		value, _ = etherutils.StringToWei("0.01 ether")
 	}
-	fmt.Println("Locked value is", etherutils.WeiToString(value, true))
-	fmt.Println("Highest bid is", etherutils.WeiToString(highestBid, true))
+	fmt.Fprintln(cli.Out, "Locked value is", etherutils.WeiToString(value, true))
+	fmt.Fprintln(cli.Out, "Highest bid is", etherutils.WeiToString(highestBid, true))
 	// TODO number of bids revealed?
 }
 
@@ -130,12 +165,12 @@ func wonInfo(name string) {
 	cli.ErrCheck(err, quiet, "Failed to obtain ENS registrar contract")
 	_, deedAddress, registrationDate, value, highestBid, err := ens.Entry(registrarContract, client, name)
 	cli.ErrCheck(err, quiet, "Cannot obtain information for that name")
-	fmt.Println("Won since", registrationDate)
+	fmt.Fprintln(cli.Out, "Won since", registrationDate)
 	if value.Cmp(zero) == 0 {
 		value, _ = etherutils.StringToWei("0.01 ether")
 	}
-	fmt.Println("Locked value is", etherutils.WeiToString(value, true))
-	fmt.Println("Highest bid was", etherutils.WeiToString(highestBid, true))
+	fmt.Fprintln(cli.Out, "Locked value is", etherutils.WeiToString(value, true))
+	fmt.Fprintln(cli.Out, "Highest bid was", etherutils.WeiToString(highestBid, true))
 
 	// Deed
 	deedContract, err := ens.DeedContract(client, &deedAddress)
@@ -145,9 +180,9 @@ func wonInfo(name string) {
 	cli.ErrCheck(err, quiet, "Failed to obtain deed owner")
 	deedOwnerName, _ := ens.ReverseResolve(client, &deedOwner)
 	if deedOwnerName == "" {
-		fmt.Println("Deed owner is", deedOwner.Hex())
+		fmt.Fprintln(cli.Out, "Deed owner is", deedOwner.Hex())
 	} else {
-		fmt.Printf("Deed owner is %s (%s)\n", deedOwnerName, deedOwner.Hex())
+		fmt.Fprintf(cli.Out, "Deed owner is %s (%s)\n", deedOwnerName, deedOwner.Hex())
 	}
 }
 
@@ -156,9 +191,9 @@ func ownedInfo(name string) {
 	cli.ErrCheck(err, quiet, "Failed to obtain ENS registrar contract")
 	_, deedAddress, registrationDate, value, highestBid, err := ens.Entry(registrarContract, client, name)
 	if err == nil {
-		fmt.Println("Owned since", registrationDate)
-		fmt.Println("Locked value is", etherutils.WeiToString(value, true))
-		fmt.Println("Highest bid was", etherutils.WeiToString(highestBid, true))
+		fmt.Fprintln(cli.Out, "Owned since", registrationDate)
+		fmt.Fprintln(cli.Out, "Locked value is", etherutils.WeiToString(value, true))
+		fmt.Fprintln(cli.Out, "Highest bid was", etherutils.WeiToString(highestBid, true))
 
 		// Deed
 		deedContract, err := ens.DeedContract(client, &deedAddress)
@@ -168,9 +203,9 @@ func ownedInfo(name string) {
 		cli.ErrCheck(err, quiet, "Failed to obtain deed owner")
 		deedOwnerName, _ := ens.ReverseResolve(client, &deedOwner)
 		if deedOwnerName == "" {
-			fmt.Println("Deed owner is", deedOwner.Hex())
+			fmt.Fprintln(cli.Out, "Deed owner is", deedOwner.Hex())
 		} else {
-			fmt.Printf("Deed owner is %s (%s)\n", deedOwnerName, deedOwner.Hex())
+			fmt.Fprintf(cli.Out, "Deed owner is %s (%s)\n", deedOwnerName, deedOwner.Hex())
 		}
 
 		previousDeedOwner, err := deedContract.PreviousOwner(nil)
@@ -178,106 +213,145 @@ func ownedInfo(name string) {
 		if bytes.Compare(previousDeedOwner.Bytes(), ens.UnknownAddress.Bytes()) != 0 {
 			previousDeedOwnerName, _ := ens.ReverseResolve(client, &previousDeedOwner)
 			if previousDeedOwnerName == "" {
-				fmt.Println("Previous deed owner is", previousDeedOwner.Hex())
+				fmt.Fprintln(cli.Out, "Previous deed owner is", previousDeedOwner.Hex())
 			} else {
-				fmt.Printf("Previous deed owner is %s (%s)\n", previousDeedOwnerName, previousDeedOwner.Hex())
+				fmt.Fprintf(cli.Out, "Previous deed owner is %s (%s)\n", previousDeedOwnerName, previousDeedOwner.Hex())
 			}
 		}
 	}
 
-	// Address owner
+	ensInfoPrint(ensInfoObtain(name))
+}
+
+func subdomainInfo(name string) {
+	ensInfoPrint(ensInfoObtain(name))
+}
+
+// ensInfoObtain obtains the registry and resolver records for a name
+func ensInfoObtain(name string) *ensInfoRecords {
+	info := &ensInfoRecords{
+		Name: name,
+	}
+
 	registry, err := ens.RegistryContract(client)
 	cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
-	domainOwnerAddress, err := registry.Owner(nil, ens.NameHash(name))
+	nameHash := ens.NameHash(name)
+	owner, err := registry.Owner(nil, nameHash)
 	cli.ErrCheck(err, quiet, "Failed to obtain domain owner")
-	if domainOwnerAddress == ens.UnknownAddress {
-		fmt.Println("Address owner not set")
-		return
-	}
-	domainOwnerName, _ := ens.ReverseResolve(client, &domainOwnerAddress)
-	if domainOwnerName == "" {
-		fmt.Println("Address owner is", domainOwnerAddress.Hex())
-	} else {
-		fmt.Printf("Address owner is %s (%s)\n", domainOwnerName, domainOwnerAddress.Hex())
+	if owner == ens.UnknownAddress {
+		return info
 	}
+	info.Owner = owner.Hex()
+	info.OwnerName, _ = ens.ReverseResolve(client, &owner)
+	info.TTL, err = registry.Ttl(nil, nameHash)
+	cli.ErrCheck(err, quiet, "Failed to obtain TTL")
 
-	// Resolver
 	resolverAddress, err := ens.Resolver(registry, name)
 	if err != nil {
-		fmt.Println("Resolver not configured")
-		return
-	}
-	resolverName, _ := ens.ReverseResolve(client, &resolverAddress)
-	if resolverName == "" {
-		fmt.Println("Resolver is", resolverAddress.Hex())
-	} else {
-		fmt.Printf("Resolver is %s (%s)\n", resolverName, resolverAddress.Hex())
+		return info
 	}
+	info.Resolver = resolverAddress.Hex()
+	resolver, err := ens.ResolverContractByAddress(client, resolverAddress)
+	cli.ErrCheck(err, quiet, "Failed to obtain resolver contract")
 
-	// Address
-	address, err := ens.Resolve(client, name)
-	if err != nil || address == ens.UnknownAddress {
-		fmt.Println("Name does not resolve to an address")
-		return
+	address, err := resolver.Addr(nil, nameHash)
+	if err == nil && address != ens.UnknownAddress {
+		info.Address = address.Hex()
+		info.ReverseName, _ = ens.ReverseResolve(client, &address)
 	}
-	fmt.Println("Domain resolves to", address.Hex())
 
-	// Reverse resolution
-	reverseDomain, err := ens.ReverseResolve(client, &address)
-	if err != nil || reverseDomain == "" {
-		fmt.Println("Address does not resolve to a domain")
-		return
+	// Older resolvers do not support all record types, so check before
+	// attempting to obtain them
+	if supported, err := resolver.SupportsInterface(nil, ens.TextInterfaceID); err == nil && supported {
+		for _, key := range ens.TextKeys {
+			text, err := resolver.Text(nil, nameHash, key)
+			if err == nil && text != "" {
+				if info.Text == nil {
+					info.Text = make(map[string]string)
+				}
+				info.Text[key] = text
+			}
+		}
+	}
+	if supported, err := resolver.SupportsInterface(nil, ens.ContenthashInterfaceID); err == nil && supported {
+		contenthash, err := ens.Contenthash(client, resolverAddress, name)
+		if err == nil && len(contenthash) > 0 {
+			info.Contenthash = fmt.Sprintf("%#x", contenthash)
+		}
+	}
+	if supported, err := resolver.SupportsInterface(nil, ens.MultiCoinInterfaceID); err == nil && supported {
+		for coin, coinType := range ens.CoinTypes {
+			if coin == "ETH" {
+				continue
+			}
+			coinAddress, err := ens.CoinAddress(client, resolverAddress, name, coinType)
+			if err == nil && len(coinAddress) > 0 {
+				if info.CoinAddresses == nil {
+					info.CoinAddresses = make(map[string]string)
+				}
+				info.CoinAddresses[coin] = fmt.Sprintf("%#x", coinAddress)
+			}
+		}
 	}
-	fmt.Println("Address resolves to", reverseDomain)
 
-	// TODO Other common fields (addr, abi, etc.) (if configured)
+	return info
 }
 
-func subdomainInfo(name string) {
-	// Address owner
-	registry, err := ens.RegistryContract(client)
-	cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
-	domainOwnerAddress, err := registry.Owner(nil, ens.NameHash(name))
-	cli.ErrCheck(err, quiet, "Failed to obtain domain owner")
-	if domainOwnerAddress == ens.UnknownAddress {
-		fmt.Println("Address owner not set")
+// ensInfoPrint prints the records for a name
+func ensInfoPrint(info *ensInfoRecords) {
+	if info.Owner == "" {
+		fmt.Fprintln(cli.Out, "Address owner not set")
 		return
 	}
-	domainOwnerName, _ := ens.ReverseResolve(client, &domainOwnerAddress)
-	if domainOwnerName == "" {
-		fmt.Println("Address owner is", domainOwnerAddress.Hex())
-	} else {
-		fmt.Printf("Address owner is %s (%s)\n", domainOwnerName, domainOwnerAddress.Hex())
-	}
+	fmt.Fprintln(cli.Out, "Address owner is", ensInfoAddress(info.Owner, info.OwnerName))
+	fmt.Fprintln(cli.Out, "TTL is", info.TTL)
 
-	// Resolver
-	resolverAddress, err := ens.Resolver(registry, name)
-	if err != nil {
-		fmt.Println("Resolver not configured")
+	if info.Resolver == "" {
+		fmt.Fprintln(cli.Out, "Resolver not configured")
 		return
 	}
-	resolverName, _ := ens.ReverseResolve(client, &resolverAddress)
-	if resolverName == "" {
-		fmt.Println("Resolver is", resolverAddress.Hex())
+	fmt.Fprintln(cli.Out, "Resolver is", info.Resolver)
+
+	if info.Address == "" {
+		fmt.Fprintln(cli.Out, "Name does not resolve to an address")
 	} else {
-		fmt.Printf("Resolver is %s (%s)\n", resolverName, resolverAddress.Hex())
+		fmt.Fprintln(cli.Out, "Domain resolves to", info.Address)
+		if info.ReverseName == "" {
+			fmt.Fprintln(cli.Out, "Address does not resolve to a domain")
+		} else {
+			fmt.Fprintln(cli.Out, "Address resolves to", info.ReverseName)
+		}
 	}
 
-	// Address
-	address, err := ens.Resolve(client, name)
-	if err != nil || address == ens.UnknownAddress {
-		fmt.Println("Name does not resolve to an address")
-		return
+	if info.Contenthash != "" {
+		fmt.Fprintln(cli.Out, "Content hash is", info.Contenthash)
+	}
+	if len(info.CoinAddresses) > 0 {
+		fmt.Fprintln(cli.Out, "Coin addresses:")
+		for _, coin := range ensInfoSortedKeys(info.CoinAddresses) {
+			fmt.Fprintf(cli.Out, "\t%s: %s\n", coin, info.CoinAddresses[coin])
+		}
 	}
-	fmt.Println("Domain resolves to", address.Hex())
+	if len(info.Text) > 0 {
+		fmt.Fprintln(cli.Out, "Text records:")
+		for _, key := range ensInfoSortedKeys(info.Text) {
+			fmt.Fprintf(cli.Out, "\t%s: %s\n", key, info.Text[key])
+		}
+	}
+}
 
-	// Reverse resolution
-	reverseDomain, err := ens.ReverseResolve(client, &address)
-	if err != nil || reverseDomain == "" {
-		fmt.Println("Address does not resolve to a domain")
-		return
+func ensInfoAddress(address string, name string) string {
+	if name == "" {
+		return address
 	}
-	fmt.Println("Address resolves to", reverseDomain)
+	return fmt.Sprintf("%s (%s)", name, address)
+}
 
-	// TODO Other common fields (addr, abi, etc.) (if configured)
+func ensInfoSortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
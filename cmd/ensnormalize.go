@@ -0,0 +1,57 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+// ensNormalizeCmd represents the ens normalize command
+var ensNormalizeCmd = &cobra.Command{
+	Use:     "normalize",
+	Aliases: []string{"normalise"},
+	Short:   "Normalize an ENS name",
+	Long: `Normalize a name as per the Ethereum Name Service (ENS), displaying the normalized name and its namehash.  For example:
+
+    ethereal ens normalize --name=WealdTech.eth
+
+This uses the same normalization as the commands that resolve names, so can be used to find out why a name does not resolve as expected.  If the name is invalid the reason is displayed.  This command does not need a connection to an Ethereum node.
+
+In quiet mode this will return 0 if the name is valid, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+
+		if quiet {
+			os.Exit(0)
+		}
+
+		fmt.Fprintf(cli.Out, "Name:\t\t%s\n", name)
+		diagnosticIf(verbose && name != ensDomain, fmt.Sprintf("Original name:\t%s", ensDomain))
+		fmt.Fprintf(cli.Out, "Namehash:\t0x%x\n", ens.NameHash(name))
+	},
+}
+
+func init() {
+	ensCmd.AddCommand(ensNormalizeCmd)
+	ensFlags(ensNormalizeCmd)
+}
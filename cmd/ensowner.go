@@ -0,0 +1,82 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+// Mechanisms by which a name can be held
+const (
+	ensOwnershipRegistry  = "registry"
+	ensOwnershipRegistrar = "registrar"
+	ensOwnershipWrapped   = "name wrapper"
+)
+
+// ensOwnerCmd represents the ens owner command
+var ensOwnerCmd = &cobra.Command{
+	Use:   "owner",
+	Short: "Manage ENS ownership",
+	Long:  `Set Ethereum Name Service ownership information`,
+}
+
+// ensOwnership obtains the mechanism by which a name is held, the contract
+// that holds it and its owner.  Wrapped names are held by the name wrapper,
+// unwrapped .eth names by the registrar and all other names directly by the
+// registry.
+func ensOwnership(name string) (mechanism string, contract common.Address, owner common.Address, err error) {
+	registry, err := ens.RegistryContract(client)
+	if err != nil {
+		return
+	}
+	contract, err = ens.RegistryContractAddress(client)
+	if err != nil {
+		return
+	}
+	owner, err = registry.Owner(nil, ens.NameHash(name))
+	if err != nil {
+		return
+	}
+	mechanism = ensOwnershipRegistry
+	if owner == ens.UnknownAddress {
+		return
+	}
+
+	if ens.IsNameWrapper(client, owner) {
+		wrapper := owner
+		owner, err = ens.WrappedOwner(client, wrapper, name)
+		if err != nil {
+			return
+		}
+		return ensOwnershipWrapped, wrapper, owner, nil
+	}
+
+	if ens.Tld(name) == "eth" && ens.DomainLevel(name) == 1 {
+		registrar, err := ens.BaseRegistrarAddress(client)
+		if err == nil {
+			// Expired names do not have a registrant
+			registrant, err := ens.Registrant(client, registrar, name)
+			if err == nil && registrant != ens.UnknownAddress {
+				return ensOwnershipRegistrar, registrar, registrant, nil
+			}
+		}
+	}
+	return mechanism, contract, owner, nil
+}
+
+func init() {
+	ensCmd.AddCommand(ensOwnerCmd)
+}
@@ -0,0 +1,123 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/ens/registrycontract"
+)
+
+var ensOwnerSetToAddress string
+var ensOwnerSetFromAddress string
+var ensOwnerSetForce bool
+
+// ensOwnerSetCmd represents the ens owner set command
+var ensOwnerSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Transfer ownership of an ENS domain",
+	Long: `Transfer ownership of a name registered with the Ethereum Name Service (ENS) to another address.  For example:
+
+    ethereal ens owner set --name=enstest.eth --to=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase"
+
+The mechanism used to transfer the name depends on how it is held:
+
+  - a name held by the name wrapper is transferred by transferring its name wrapper token
+  - an unwrapped .eth name is transferred by transferring its registrar token
+  - any other name is transferred by setting its owner in the registry
+
+If --from is not supplied the transaction is sent from the current owner of the name.  The command checks that the sender owns the name or is an approved operator for its owner before sending the transaction; use --force to skip this check.
+
+Transferring a name cannot be undone by the sender.
+
+In quiet mode this will return 0 if the transaction to transfer the name is sent successfully, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+		cli.AssertCode(ensOwnerSetToAddress != "", quiet, cli.ExitUsage, "--to is required")
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+
+		toAddress, err := ens.Resolve(client, ensOwnerSetToAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", ensOwnerSetToAddress))
+		cli.Assert(toAddress != ens.UnknownAddress, quiet, "Cannot transfer to the zero address")
+
+		mechanism, contract, owner, err := ensOwnership(name)
+		cli.ErrCheck(err, quiet, "Failed to obtain owner")
+		cli.Assert(owner != ens.UnknownAddress, quiet, fmt.Sprintf("Owner of %s is not set", name))
+		diagnosticIf(verbose, fmt.Sprintf("%s is held by the %s; current owner is %s", name, mechanism, owner.Hex()))
+		cli.Assert(owner != toAddress, quiet, fmt.Sprintf("%s is already owned by %s", name, toAddress.Hex()))
+
+		fromAddress := owner
+		if ensOwnerSetFromAddress != "" {
+			fromAddress, err = ens.Resolve(client, ensOwnerSetFromAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", ensOwnerSetFromAddress))
+			if !ensOwnerSetForce && fromAddress != owner {
+				approved, _ := ens.OperatorApproved(client, contract, owner, fromAddress)
+				cli.Assert(approved, quiet, fmt.Sprintf("%s is not the owner or an approved operator of %s", fromAddress.Hex(), name))
+			}
+		}
+
+		cli.Warn(quiet, fmt.Sprintf("transferring %s from %s to %s; this cannot be undone", name, owner.Hex(), toAddress.Hex()))
+
+		opts, err := generateTxOpts(fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+		var signedTx *types.Transaction
+		switch mechanism {
+		case ensOwnershipWrapped:
+			signedTx, err = ens.TransferWrappedName(opts, client, contract, owner, toAddress, name)
+		case ensOwnershipRegistrar:
+			signedTx, err = ens.TransferRegistration(opts, client, contract, owner, toAddress, name)
+		default:
+			var registry *registrycontract.RegistryContract
+			registry, err = ens.RegistryContract(client)
+			cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+			signedTx, err = registry.SetOwner(opts, ens.NameHash(name), toAddress)
+		}
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+
+		log.WithFields(log.Fields{
+			"group":         "ens",
+			"command":       "owner set",
+			"name":          name,
+			"mechanism":     mechanism,
+			"from":          fromAddress.Hex(),
+			"to":            toAddress.Hex(),
+			"networkid":     chainID,
+			"gas":           signedTx.Gas(),
+			"gasprice":      signedTx.GasPrice().String(),
+			"transactionid": signedTx.Hash().Hex(),
+		}).Info("success")
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+	},
+}
+
+func init() {
+	ensOwnerCmd.AddCommand(ensOwnerSetCmd)
+	ensFlags(ensOwnerSetCmd)
+	ensOwnerSetCmd.Flags().StringVar(&ensOwnerSetToAddress, "to", "", "The new owner of the name")
+	ensOwnerSetCmd.Flags().StringVar(&ensOwnerSetFromAddress, "from", "", "Address from which to send the transaction (defaults to the owner of the name)")
+	ensOwnerSetCmd.Flags().BoolVar(&ensOwnerSetForce, "force", false, "Send without checking that the sender is able to manage the name")
+	addTransactionFlags(ensOwnerSetCmd, "Passphrase for the account that sends the transaction")
+}
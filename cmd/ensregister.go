@@ -0,0 +1,261 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var ensRegisterOwner string
+var ensRegisterFromAddress string
+var ensRegisterDuration string
+var ensRegisterResolver string
+var ensRegisterAddress string
+var ensRegisterSecret string
+var ensRegisterController string
+var ensRegisterPriceBuffer uint64
+
+// ensRegisterCmd represents the ens register command
+var ensRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register a .eth name",
+	Long: `Register a .eth name with the Ethereum Name Service (ENS).  For example:
+
+    ethereal ens register --domain=enstest.eth --owner=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --duration=1y --passphrase="my secret passphrase"
+
+Registration takes place in two steps to stop others from front-running it.  First a commitment to the registration is sent, and then after the commitment has aged for the period required by the registrar (usually a minute) the registration itself is sent.  The command sends the commitment, waits for it to age by watching the timestamps of new blocks, and then sends the registration.  The secret used in the commitment is displayed, and if the command is interrupted it can be resumed by running it again with the same arguments and --secret.  Each transaction is waited for until it is mined, for at most the duration given by --wait-timeout.
+
+The name is checked to be available before the commitment is sent.  The registration is sent with the rent price for the duration plus a buffer of --price-buffer percent, to allow for changes in the price of Ether between the two steps; any excess is refunded by the registrar.  --duration accepts values such as "1y" or "90d", and must be at least 28 days.
+
+The resolver of the name can be set at registration with --resolver, and its address with --address.  If --address is supplied without --resolver then the public resolver is used.
+
+If --from is not supplied the transactions are sent from the owner.  The registrar controller is found through ENS, and can be overridden with --controller.  Both the current and the legacy registrar controllers are supported; any other contract is refused before the commitment is sent.
+
+In quiet mode this will return 0 if the name is registered, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--domain is required")
+		cli.AssertCode(ensRegisterOwner != "", quiet, cli.ExitUsage, "--owner is required")
+		domain, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid domain %s", ensDomain))
+		cli.Assert(ens.DomainLevel(domain) == 1 && ens.Tld(domain) == "eth", quiet, "Only second-level .eth names can be registered")
+		label, err := ens.DomainPart(domain, 1)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid domain %s", domain))
+		cli.Assert(len([]rune(label)) >= 3, quiet, ".eth names must be at least 3 characters long")
+
+		duration, err := util.StringToDuration(ensRegisterDuration)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid duration")
+		cli.Assert(duration >= ens.MinRegistrationDuration, quiet, "Duration must be at least 28 days")
+
+		ownerAddress, err := ens.Resolve(client, ensRegisterOwner)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve owner %s", ensRegisterOwner))
+		fromAddress := ownerAddress
+		if ensRegisterFromAddress != "" {
+			fromAddress, err = ens.Resolve(client, ensRegisterFromAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", ensRegisterFromAddress))
+		}
+		var resolverAddress common.Address
+		if ensRegisterResolver != "" {
+			resolverAddress, err = ens.Resolve(client, ensRegisterResolver)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve resolver %s", ensRegisterResolver))
+		} else if ensRegisterAddress != "" {
+			resolverAddress, err = ens.PublicResolver(client)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("No public resolver for network id %v", chainID))
+		}
+		var address common.Address
+		if ensRegisterAddress != "" {
+			address, err = ens.Resolve(client, ensRegisterAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve address %s", ensRegisterAddress))
+		}
+
+		controller := ensRegistrarController(ensRegisterController)
+		available, err := ens.Available(client, controller, label)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain availability of %s", domain))
+		cli.Assert(available, quiet, fmt.Sprintf("%s is not available", domain))
+		price, err := ens.RentPrice(client, controller, label, duration)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain price of %s", domain))
+		value := new(big.Int).Mul(price, new(big.Int).SetUint64(100+ensRegisterPriceBuffer))
+		value.Div(value, big.NewInt(100))
+		minAge, maxAge, err := ens.CommitmentAges(client, controller)
+		cli.ErrCheck(err, quiet, "Failed to obtain commitment ages")
+
+		var secret [32]byte
+		if ensRegisterSecret != "" {
+			secretBytes, err := hex.DecodeString(strings.TrimPrefix(ensRegisterSecret, "0x"))
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid secret")
+			cli.Assert(len(secretBytes) == 32, quiet, "Secret must be 32 bytes")
+			copy(secret[:], secretBytes)
+		} else {
+			_, err = rand.Read(secret[:])
+			cli.ErrCheck(err, quiet, "Failed to generate secret")
+		}
+		commitment, err := ens.MakeCommitment(client, controller, label, ownerAddress, duration, secret, resolverAddress, address)
+		cli.ErrCheck(err, quiet, "Failed to create commitment")
+		committed, err := ens.CommitmentTime(client, controller, commitment)
+		cli.ErrCheck(err, quiet, "Failed to obtain commitment")
+
+		fees := big.NewInt(0)
+		if committed.IsZero() {
+			cli.Assert(ensRegisterSecret == "", quiet, "No commitment found for the supplied secret")
+			outputIf(!quiet, fmt.Sprintf("Secret is 0x%s; supply it with --secret to resume if interrupted", hex.EncodeToString(secret[:])))
+			fee, err := ensControllerSend("register", domain, "commit", fromAddress, big.NewInt(0), func() (*types.Transaction, error) {
+				opts, err := generateTxOpts(fromAddress)
+				if err != nil {
+					return nil, err
+				}
+				return ens.Commit(opts, client, controller, commitment)
+			})
+			cli.ErrCheck(err, quiet, "Failed to commit")
+			fees.Add(fees, fee)
+			committed, err = ens.CommitmentTime(client, controller, commitment)
+			cli.ErrCheck(err, quiet, "Failed to obtain commitment")
+			cli.Assert(!committed.IsZero(), quiet, "Commitment not found after it was mined")
+		} else {
+			diagnosticIf(verbose, fmt.Sprintf("Commitment made at %v", committed))
+		}
+
+		// Wait for the commitment to age
+		now, err := ensRegisterWaitUntil(committed.Add(minAge))
+		cli.ErrCheck(err, quiet, "Failed to wait for commitment to age")
+		cli.Assert(now.Sub(committed) < maxAge, quiet, "Commitment has expired; run again without --secret to make a new one")
+
+		fee, err := ensControllerSend("register", domain, "register", fromAddress, value, func() (*types.Transaction, error) {
+			opts, err := generateTxOpts(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			opts.Value = value
+			return ens.Register(opts, client, controller, label, ownerAddress, duration, secret, resolverAddress, address)
+		})
+		cli.ErrCheck(err, quiet, "Failed to register")
+		fees.Add(fees, fee)
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintf(cli.Out, "%s registered to %s\n", domain, ownerAddress.Hex())
+		total := new(big.Int).Add(price, fees)
+		fmt.Fprintf(cli.Out, "Cost: %s (registration %s, fees %s)%s\n", etherutils.WeiToString(total, true), etherutils.WeiToString(price, true), etherutils.WeiToString(fees, true), etherFiatSuffix(total))
+	},
+}
+
+// ensRegistrarController obtains the address of the .eth registrar controller,
+// either as supplied or from ENS
+func ensRegistrarController(input string) common.Address {
+	if input != "" {
+		controller, err := ens.Resolve(client, input)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve controller %s", input))
+		return controller
+	}
+	controller, err := ens.RegistrarControllerAddress(client)
+	cli.ErrCheck(err, quiet, "Failed to obtain registrar controller")
+	return controller
+}
+
+// ensControllerSend sends a transaction to the registrar controller and waits
+// for it to be mined, returning the fee paid for it
+func ensControllerSend(command string, domain string, step string, fromAddress common.Address, value *big.Int, send func() (*types.Transaction, error)) (*big.Int, error) {
+	signedTx, err := send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transaction to %s %s: %v", step, domain, err)
+	}
+	log.WithFields(log.Fields{
+		"group":         "ens",
+		"command":       command,
+		"step":          step,
+		"domain":        domain,
+		"from":          fromAddress.Hex(),
+		"value":         value.String(),
+		"networkid":     chainID,
+		"gas":           signedTx.Gas(),
+		"gasprice":      signedTx.GasPrice().String(),
+		"transactionid": signedTx.Hash().Hex(),
+	}).Info("success")
+
+	outputIf(!quiet, fmt.Sprintf("Transaction to %s %s is %s", step, domain, signedTx.Hash().Hex()))
+	// The next transaction uses the following nonce
+	nonce++
+	ctx, cancel := waitContext()
+	defer cancel()
+	receipt, err := transactionWaitForReceipt(ctx, signedTx.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain receipt for transaction to %s %s: %v", step, domain, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction to %s %s reverted", step, domain)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), signedTx.GasPrice()), nil
+}
+
+// ensRegisterWaitUntil waits until the timestamp of the latest block has
+// reached the given time, returning the timestamp
+func ensRegisterWaitUntil(target time.Time) (time.Time, error) {
+	// Block timestamps can lag the local clock, so allow for the wait timeout
+	// on top of the time remaining
+	timeout := viper.GetDuration("wait-timeout")
+	if remaining := time.Until(target); remaining > 0 {
+		timeout += remaining
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	waiter := newBlockWaiter(ctx, transactionWaitPollInterval)
+	defer waiter.close()
+	for {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil && !transientError(err) {
+			return time.Time{}, err
+		}
+		if err == nil {
+			timestamp := time.Unix(header.Time.Int64(), 0)
+			if !timestamp.Before(target) {
+				return timestamp, nil
+			}
+			diagnosticIf(!quiet, fmt.Sprintf("Waiting %v for commitment to age", target.Sub(timestamp).Round(time.Second)))
+		}
+		if !waiter.wait(ctx) {
+			return time.Time{}, fmt.Errorf("timed out waiting for block at %v", target)
+		}
+	}
+}
+
+func init() {
+	ensCmd.AddCommand(ensRegisterCmd)
+	ensFlags(ensRegisterCmd)
+	ensRegisterCmd.Flags().StringVar(&ensRegisterOwner, "owner", "", "The owner of the name")
+	ensRegisterCmd.Flags().StringVar(&ensRegisterFromAddress, "from", "", "Address from which to send the transactions (defaults to the owner)")
+	ensRegisterCmd.Flags().StringVar(&ensRegisterDuration, "duration", "1y", "The period for which to register the name, for example 1y or 90d")
+	ensRegisterCmd.Flags().StringVar(&ensRegisterResolver, "resolver", "", "The resolver for the name")
+	ensRegisterCmd.Flags().StringVar(&ensRegisterAddress, "address", "", "The address to which the name resolves")
+	ensRegisterCmd.Flags().StringVar(&ensRegisterSecret, "secret", "", "The secret of an existing commitment, to resume a registration")
+	ensRegisterCmd.Flags().StringVar(&ensRegisterController, "controller", "", "Address of the registrar controller (defaults to that published in ENS)")
+	ensRegisterCmd.Flags().Uint64Var(&ensRegisterPriceBuffer, "price-buffer", 10, "Percentage added to the rent price to allow for price changes")
+	addTransactionFlags(ensRegisterCmd, "Passphrase for the account that sends the transactions")
+}
@@ -0,0 +1,185 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var ensRenewFromAddress string
+var ensRenewDuration string
+var ensRenewFile string
+var ensRenewController string
+var ensRenewPriceBuffer uint64
+
+// ensRenewCmd represents the ens renew command
+var ensRenewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Renew .eth names",
+	Long: `Extend the registration of one or more .eth names.  For example:
+
+    ethereal ens renew --domain=enstest.eth --duration=1y --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase"
+
+Multiple names can be renewed together by supplying --file, which contains one name per line; blank lines and lines starting with '#' are ignored.  Names are renewed in turn, and if a name cannot be renewed the remaining names are still attempted.
+
+Each renewal is sent with the rent price for the duration plus a buffer of --price-buffer percent, to allow for changes in the price of Ether; any excess is refunded by the registrar.  --duration accepts values such as "1y" or "90d".  Anyone can renew a name, so --from does not need to be the owner.
+
+The expiry of each name is displayed before and after it is renewed.
+
+In quiet mode this will return 0 if all names are renewed, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "" || ensRenewFile != "", quiet, cli.ExitUsage, "--domain or --file is required")
+		cli.AssertCode(ensRenewFromAddress != "", quiet, cli.ExitUsage, "--from is required")
+		duration, err := util.StringToDuration(ensRenewDuration)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid duration")
+		cli.Assert(duration > 0, quiet, "Duration must be positive")
+		fromAddress, err := ens.Resolve(client, ensRenewFromAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", ensRenewFromAddress))
+
+		names := make([]string, 0)
+		if ensDomain != "" {
+			names = append(names, ensDomain)
+		}
+		if ensRenewFile != "" {
+			file, err := os.Open(ensRenewFile)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to open %s", ensRenewFile))
+			fileNames, err := ensRenewParse(file)
+			file.Close()
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read %s", ensRenewFile))
+			names = append(names, fileNames...)
+		}
+		cli.Assert(len(names) > 0, quiet, "No names to renew")
+
+		controller := ensRegistrarController(ensRenewController)
+		registrar, err := ens.BaseRegistrarAddress(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registrar")
+
+		total := big.NewInt(0)
+		failed := false
+		for _, name := range names {
+			cost, err := ensRenewName(controller, registrar, fromAddress, name, duration)
+			if err != nil {
+				cli.Warn(quiet, fmt.Sprintf("Failed to renew %s: %v", name, err))
+				failed = true
+				continue
+			}
+			total.Add(total, cost)
+		}
+
+		if !quiet && len(names) > 1 {
+			fmt.Fprintf(cli.Out, "Total cost: %s%s\n", etherutils.WeiToString(total, true), etherFiatSuffix(total))
+		}
+		if failed {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+// ensRenewName renews a single name, returning the total cost of the renewal
+func ensRenewName(controller common.Address, registrar common.Address, fromAddress common.Address, name string, duration time.Duration) (*big.Int, error) {
+	domain, err := ens.NormaliseDomainStrict(name)
+	if err != nil {
+		return nil, err
+	}
+	if ens.DomainLevel(domain) != 1 || ens.Tld(domain) != "eth" {
+		return nil, fmt.Errorf("only second-level .eth names can be renewed")
+	}
+	label, err := ens.DomainPart(domain, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry, err := ens.NameExpires(client, registrar, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain expiry: %v", err)
+	}
+	if expiry.IsZero() {
+		return nil, fmt.Errorf("not registered")
+	}
+	outputIf(!quiet, fmt.Sprintf("%s expires %s", domain, expiry.UTC().Format("2006-01-02 15:04:05")))
+
+	price, err := ens.RentPrice(client, controller, label, duration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain price: %v", err)
+	}
+	value := new(big.Int).Mul(price, new(big.Int).SetUint64(100+ensRenewPriceBuffer))
+	value.Div(value, big.NewInt(100))
+
+	fee, err := ensControllerSend("renew", domain, "renew", fromAddress, value, func() (*types.Transaction, error) {
+		opts, err := generateTxOpts(fromAddress)
+		if err != nil {
+			return nil, err
+		}
+		opts.Value = value
+		return ens.Renew(opts, client, controller, label, duration)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expiry, err = ens.NameExpires(client, registrar, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain new expiry: %v", err)
+	}
+	total := new(big.Int).Add(price, fee)
+	if !quiet {
+		fmt.Fprintf(cli.Out, "%s now expires %s\n", domain, expiry.UTC().Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(cli.Out, "Cost: %s (renewal %s, fee %s)%s\n", etherutils.WeiToString(total, true), etherutils.WeiToString(price, true), etherutils.WeiToString(fee, true), etherFiatSuffix(total))
+	}
+	return total, nil
+}
+
+// ensRenewParse reads the names in a renewal file
+func ensRenewParse(input io.Reader) ([]string, error) {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		names = append(names, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func init() {
+	ensCmd.AddCommand(ensRenewCmd)
+	ensFlags(ensRenewCmd)
+	ensRenewCmd.Flags().StringVar(&ensRenewFromAddress, "from", "", "Address from which to send the transactions")
+	ensRenewCmd.Flags().StringVar(&ensRenewDuration, "duration", "1y", "The period by which to extend the registration, for example 1y or 90d")
+	ensRenewCmd.Flags().StringVar(&ensRenewFile, "file", "", "File containing names to renew, one per line")
+	ensRenewCmd.Flags().StringVar(&ensRenewController, "controller", "", "Address of the registrar controller (defaults to that published in ENS)")
+	ensRenewCmd.Flags().Uint64Var(&ensRenewPriceBuffer, "price-buffer", 10, "Percentage added to the rent price to allow for price changes")
+	addTransactionFlags(ensRenewCmd, "Passphrase for the account that sends the transactions")
+}
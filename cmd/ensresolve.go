@@ -0,0 +1,65 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+// ensResolveCmd represents the ens resolve command
+var ensResolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Resolve an ENS name to an address",
+	Long: `Resolve a name registered with the Ethereum Name Service (ENS) to the address to which it points.  For example:
+
+    ethereal ens resolve --name=enstest.eth
+
+The name is normalised before it is resolved.  In verbose mode the address of the name's resolver is also displayed.
+
+In quiet mode this will return 0 if the name resolves to an address, otherwise 1.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+		diagnosticIf(verbose && name != ensDomain, fmt.Sprintf("Normalised name is %s", name))
+
+		registryContract, err := ens.RegistryContract(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+		resolverAddress, err := registryContract.Resolver(nil, ens.NameHash(name))
+		cli.ErrCheck(err, quiet, "Failed to obtain resolver")
+		cli.Assert(resolverAddress != ens.UnknownAddress, quiet, fmt.Sprintf("%s does not have a resolver", name))
+		diagnosticIf(verbose, fmt.Sprintf("Resolver is %s", resolverAddress.Hex()))
+
+		resolverContract, err := ens.ResolverContractByAddress(client, resolverAddress)
+		cli.ErrCheck(err, quiet, "Failed to obtain resolver contract")
+		address, err := resolverContract.Addr(nil, ens.NameHash(name))
+		cli.ErrCheck(err, quiet, "Failed to obtain address")
+		cli.Assert(address != ens.UnknownAddress, quiet, fmt.Sprintf("%s does not have an address record", name))
+
+		if !quiet {
+			fmt.Fprintln(cli.Out, address.Hex())
+		}
+	},
+}
+
+func init() {
+	ensCmd.AddCommand(ensResolveCmd)
+	ensFlags(ensResolveCmd)
+}
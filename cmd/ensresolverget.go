@@ -32,7 +32,7 @@ var ensResolverGetCmd = &cobra.Command{
 In quiet mode this will return 0 if the name has a resolver, otherwise 1.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--domain is required")
 
 		// registrarContract, err := ens.RegistrarContract(client, ensDomain)
 		// inState, err := ens.NameInState(registrarContract, client, ensDomain, "Owned")
@@ -43,7 +43,7 @@ In quiet mode this will return 0 if the name has a resolver, otherwise 1.`,
 		resolver, err := ens.Resolver(registryContract, ensDomain)
 		cli.ErrCheck(err, quiet, "No resolver for that name")
 		if !quiet {
-			fmt.Println(resolver.Hex())
+			fmt.Fprintln(cli.Out, resolver.Hex())
 		}
 	},
 }
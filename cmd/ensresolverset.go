@@ -39,7 +39,7 @@ The keystore for the account that owns the name must be local (i.e. listed with
 In quiet mode this will return 0 if the transaction to set the resolver is sent successfully, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
-		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--domain is required")
 
 		// Ensure that the name is in a suitable state
 		if ens.Tld(ensDomain) == "eth" && ens.DomainLevel(ensDomain) == 1 {
@@ -66,7 +66,7 @@ In quiet mode this will return 0 if the transaction to set the resolver is sent
 		tx, err := registryContract.SetResolver(opts, ens.NameHash(ensDomain), resolverAddress)
 		cli.ErrCheck(err, quiet, "Failed to send transaction")
 		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
+			fmt.Fprintln(cli.Out, "Transaction ID is", tx.Hash().Hex())
 		}
 	},
 }
@@ -0,0 +1,124 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var ensSetAddressAddress string
+var ensSetAddressCoinType uint64
+var ensSetAddressFromAddress string
+var ensSetAddressForce bool
+var ensSetAddressSetResolver bool
+
+// ensSetAddressCmd represents the ens set address command
+var ensSetAddressCmd = &cobra.Command{
+	Use:   "address",
+	Short: "Set the address of an ENS domain",
+	Long: `Set the address to which a name registered with the Ethereum Name Service (ENS) resolves.  For example:
+
+    ethereal ens set address --name=enstest.eth --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase"
+
+The address is set on the name's current resolver.  If the name does not have a resolver then --set-resolver will point it at the public resolver before setting the address.
+
+By default the Ethereum address of the name is set, and the address can be an ENS name.  Addresses for other chains can be set by supplying the SLIP-44 coin type with --coin-type, in which case the address must be supplied as hex-encoded bytes and the resolver must support multi-coin addresses.
+
+If --from is not supplied the transaction is sent from the owner of the name.  The command checks that the sender owns the name or is an approved operator for its owner before sending the transaction; use --force to skip this check.
+
+In quiet mode this will return 0 if the transaction to set the address is sent successfully, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+		cli.AssertCode(ensSetAddressAddress != "", quiet, cli.ExitUsage, "--address is required")
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+
+		fromAddress := ensSender(name, ensSetAddressFromAddress, ensSetAddressForce)
+
+		registry, err := ens.RegistryContract(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+		resolverAddress, err := registry.Resolver(nil, ens.NameHash(name))
+		cli.ErrCheck(err, quiet, "Failed to obtain resolver")
+		if resolverAddress == ens.UnknownAddress {
+			cli.Assert(ensSetAddressSetResolver, quiet, fmt.Sprintf("%s does not have a resolver; use --set-resolver to set the public resolver", name))
+			resolverAddress, err = ens.PublicResolver(client)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("No public resolver for network id %v", chainID))
+			opts, err := generateTxOpts(fromAddress)
+			cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+			signedTx, err := registry.SetResolver(opts, ens.NameHash(name), resolverAddress)
+			cli.ErrCheck(err, quiet, "Failed to send transaction to set resolver")
+			outputIf(!quiet, fmt.Sprintf("Resolver set to public resolver %s with transaction %s", resolverAddress.Hex(), signedTx.Hash().Hex()))
+			// The following transaction requires the next nonce
+			nonce++
+		}
+		resolver, err := ens.ResolverContractByAddress(client, resolverAddress)
+		cli.ErrCheck(err, quiet, "Failed to obtain resolver contract")
+
+		opts, err := generateTxOpts(fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+		var signedTx *types.Transaction
+		if ensSetAddressCoinType == ens.CoinTypes["ETH"] {
+			address, err := ens.Resolve(client, ensSetAddressAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve address %s", ensSetAddressAddress))
+			signedTx, err = resolver.SetAddr(opts, ens.NameHash(name), address)
+			cli.ErrCheck(err, quiet, "Failed to send transaction")
+		} else {
+			supported, err := resolver.SupportsInterface(nil, ens.MultiCoinInterfaceID)
+			cli.Assert(err == nil && supported, quiet, "Resolver does not support multi-coin addresses")
+			address := common.FromHex(ensSetAddressAddress)
+			cli.AssertCode(len(address) > 0 && strings.HasPrefix(ensSetAddressAddress, "0x"), quiet, cli.ExitUsage, "--address must be hex-encoded bytes for coin types other than Ethereum")
+			signedTx, err = ens.SetCoinAddress(opts, client, resolverAddress, name, ensSetAddressCoinType, address)
+			cli.ErrCheck(err, quiet, "Failed to send transaction")
+		}
+
+		log.WithFields(log.Fields{
+			"group":         "ens",
+			"command":       "set address",
+			"name":          name,
+			"address":       ensSetAddressAddress,
+			"cointype":      ensSetAddressCoinType,
+			"from":          fromAddress.Hex(),
+			"networkid":     chainID,
+			"gas":           signedTx.Gas(),
+			"gasprice":      signedTx.GasPrice().String(),
+			"transactionid": signedTx.Hash().Hex(),
+		}).Info("success")
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+	},
+}
+
+func init() {
+	ensSetCmd.AddCommand(ensSetAddressCmd)
+	ensFlags(ensSetAddressCmd)
+	ensSetAddressCmd.Flags().StringVar(&ensSetAddressAddress, "address", "", "The address to which the name resolves")
+	ensSetAddressCmd.Flags().Uint64Var(&ensSetAddressCoinType, "coin-type", 60, "The SLIP-44 coin type of the address")
+	ensSetAddressCmd.Flags().StringVar(&ensSetAddressFromAddress, "from", "", "Address from which to send the transaction (defaults to the owner of the name)")
+	ensSetAddressCmd.Flags().BoolVar(&ensSetAddressForce, "force", false, "Send without checking that the sender is able to manage the name")
+	ensSetAddressCmd.Flags().BoolVar(&ensSetAddressSetResolver, "set-resolver", false, "Set the public resolver if the name does not have a resolver")
+	addTransactionFlags(ensSetAddressCmd, "Passphrase for the account that sends the transaction")
+}
@@ -0,0 +1,220 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var ensSubdomainCreateLabel string
+var ensSubdomainCreateOwner string
+var ensSubdomainCreateFromAddress string
+var ensSubdomainCreateResolver string
+var ensSubdomainCreateAddress string
+var ensSubdomainCreateFuses uint32
+var ensSubdomainCreateExpiry uint64
+var ensSubdomainCreateForce bool
+
+// ensSubdomainCreateCmd represents the ens subdomain create command
+var ensSubdomainCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a subdomain of an ENS domain",
+	Long: `Create a subdomain of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ethereal ens subdomain create --domain=enstest.eth --label=bar --owner=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase"
+
+will create bar.enstest.eth owned by the given address.  If the domain is held by the name wrapper then the subdomain is created through the name wrapper, burning any fuses supplied with --fuses.
+
+The resolver and address of the subdomain can be set at the same time with --resolver and --address; if --address is supplied without --resolver then the public resolver is used.  Doing so requires the subdomain to be created with the sender as its owner and transferred to the final owner afterwards, so the command sends multiple transactions and waits for each to be mined before sending the next.
+
+If --from is not supplied the transactions are sent from the owner of the domain.  The command checks that the sender owns the domain or is an approved operator for its owner before sending any transactions; use --force to skip this check.
+
+In quiet mode this will return 0 if the transactions to create the subdomain are sent successfully, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--domain is required")
+		cli.AssertCode(ensSubdomainCreateLabel != "", quiet, cli.ExitUsage, "--label is required")
+		cli.AssertCode(!strings.Contains(ensSubdomainCreateLabel, "."), quiet, cli.ExitUsage, "--label must not contain '.'")
+		cli.AssertCode(ensSubdomainCreateOwner != "", quiet, cli.ExitUsage, "--owner is required")
+		domain, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid domain %s", ensDomain))
+		label, err := ens.NormaliseDomainStrict(ensSubdomainCreateLabel)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid label %s", ensSubdomainCreateLabel))
+		subdomain := fmt.Sprintf("%s.%s", label, domain)
+
+		ownerAddress, err := ens.Resolve(client, ensSubdomainCreateOwner)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve owner %s", ensSubdomainCreateOwner))
+
+		// Work out how the domain is held.  Subdomains are controlled by the
+		// owner in the registry, or the name wrapper if it holds the domain
+		registry, err := ens.RegistryContract(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+		contract, err := ens.RegistryContractAddress(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain registry contract address")
+		domainOwner, err := registry.Owner(nil, ens.NameHash(domain))
+		cli.ErrCheck(err, quiet, "Failed to obtain owner")
+		cli.Assert(domainOwner != ens.UnknownAddress, quiet, fmt.Sprintf("Owner of %s is not set", domain))
+		wrapped := ens.IsNameWrapper(client, domainOwner)
+		if wrapped {
+			contract = domainOwner
+			domainOwner, err = ens.WrappedOwner(client, contract, domain)
+			cli.ErrCheck(err, quiet, "Failed to obtain owner of wrapped domain")
+			diagnosticIf(verbose, fmt.Sprintf("%s is held by the name wrapper", domain))
+		} else {
+			cli.AssertCode(ensSubdomainCreateFuses == 0, quiet, cli.ExitUsage, "--fuses can only be used with domains held by the name wrapper")
+		}
+
+		fromAddress := domainOwner
+		if ensSubdomainCreateFromAddress != "" {
+			fromAddress, err = ens.Resolve(client, ensSubdomainCreateFromAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", ensSubdomainCreateFromAddress))
+			if !ensSubdomainCreateForce && fromAddress != domainOwner {
+				approved, _ := ens.OperatorApproved(client, contract, domainOwner, fromAddress)
+				cli.Assert(approved, quiet, fmt.Sprintf("%s is not the owner or an approved operator of %s", fromAddress.Hex(), domain))
+			}
+		}
+
+		var resolverAddress common.Address
+		if ensSubdomainCreateResolver != "" {
+			resolverAddress, err = ens.Resolve(client, ensSubdomainCreateResolver)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve resolver %s", ensSubdomainCreateResolver))
+		} else if ensSubdomainCreateAddress != "" {
+			resolverAddress, err = ens.PublicResolver(client)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("No public resolver for network id %v", chainID))
+		}
+		var address common.Address
+		if ensSubdomainCreateAddress != "" {
+			address, err = ens.Resolve(client, ensSubdomainCreateAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve address %s", ensSubdomainCreateAddress))
+		}
+
+		// If further records are to be set the sender needs to own the
+		// subdomain until they have been set
+		interimOwner := ownerAddress
+		if resolverAddress != ens.UnknownAddress {
+			interimOwner = fromAddress
+		}
+
+		signedTx := ensSubdomainCreateSend(subdomain, "create", fromAddress, func() (*types.Transaction, error) {
+			opts, err := generateTxOpts(fromAddress)
+			if err != nil {
+				return nil, err
+			}
+			if wrapped {
+				return ens.SetWrappedSubdomainOwner(opts, client, contract, domain, label, interimOwner, ensSubdomainCreateFuses, ensSubdomainCreateExpiry)
+			}
+			return registry.SetSubnodeOwner(opts, ens.NameHash(domain), ens.LabelHash(label), interimOwner)
+		})
+		if resolverAddress != ens.UnknownAddress {
+			ensSubdomainCreateSend(subdomain, "set resolver", fromAddress, func() (*types.Transaction, error) {
+				opts, err := generateTxOpts(fromAddress)
+				if err != nil {
+					return nil, err
+				}
+				if wrapped {
+					return ens.SetWrappedResolver(opts, client, contract, subdomain, resolverAddress)
+				}
+				return registry.SetResolver(opts, ens.NameHash(subdomain), resolverAddress)
+			})
+		}
+		if address != ens.UnknownAddress {
+			ensSubdomainCreateSend(subdomain, "set address", fromAddress, func() (*types.Transaction, error) {
+				resolver, err := ens.ResolverContractByAddress(client, resolverAddress)
+				if err != nil {
+					return nil, err
+				}
+				opts, err := generateTxOpts(fromAddress)
+				if err != nil {
+					return nil, err
+				}
+				return resolver.SetAddr(opts, ens.NameHash(subdomain), address)
+			})
+		}
+		if interimOwner != ownerAddress {
+			ensSubdomainCreateSend(subdomain, "transfer", fromAddress, func() (*types.Transaction, error) {
+				opts, err := generateTxOpts(fromAddress)
+				if err != nil {
+					return nil, err
+				}
+				if wrapped {
+					return ens.TransferWrappedName(opts, client, contract, interimOwner, ownerAddress, subdomain)
+				}
+				return registry.SetOwner(opts, ens.NameHash(subdomain), ownerAddress)
+			})
+		}
+
+		if quiet {
+			os.Exit(0)
+		}
+		if interimOwner == ownerAddress {
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+		} else {
+			fmt.Fprintf(cli.Out, "%s created and owned by %s\n", subdomain, ownerAddress.Hex())
+		}
+	},
+}
+
+// ensSubdomainCreateSend sends one of the transactions required to create a
+// subdomain.  If further transactions may follow it waits for the
+// transaction to be mined, as later transactions depend on its effects.
+func ensSubdomainCreateSend(subdomain string, step string, fromAddress common.Address, send func() (*types.Transaction, error)) *types.Transaction {
+	signedTx, err := send()
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to send transaction to %s %s", step, subdomain))
+	log.WithFields(log.Fields{
+		"group":         "ens",
+		"command":       "subdomain create",
+		"step":          step,
+		"subdomain":     subdomain,
+		"from":          fromAddress.Hex(),
+		"networkid":     chainID,
+		"gas":           signedTx.Gas(),
+		"gasprice":      signedTx.GasPrice().String(),
+		"transactionid": signedTx.Hash().Hex(),
+	}).Info("success")
+
+	if ensSubdomainCreateResolver != "" || ensSubdomainCreateAddress != "" {
+		outputIf(!quiet, fmt.Sprintf("Transaction to %s %s is %s", step, subdomain, signedTx.Hash().Hex()))
+		ctx, cancel := waitContext()
+		defer cancel()
+		receipt, err := transactionWaitForReceipt(ctx, signedTx.Hash())
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain receipt for transaction to %s %s", step, subdomain))
+		cli.AssertCode(receipt.Status == types.ReceiptStatusSuccessful, quiet, cli.ExitExecution, fmt.Sprintf("Transaction to %s %s reverted", step, subdomain))
+		// The next transaction uses the following nonce
+		nonce++
+	}
+	return signedTx
+}
+
+func init() {
+	ensSubdomainCmd.AddCommand(ensSubdomainCreateCmd)
+	ensFlags(ensSubdomainCreateCmd)
+	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateLabel, "label", "", "The label of the subdomain (e.g. 'bar' to create bar.enstest.eth)")
+	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateOwner, "owner", "", "The owner of the subdomain")
+	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateFromAddress, "from", "", "Address from which to send the transactions (defaults to the owner of the domain)")
+	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateResolver, "resolver", "", "The resolver for the subdomain")
+	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateAddress, "address", "", "The address to which the subdomain resolves")
+	ensSubdomainCreateCmd.Flags().Uint32Var(&ensSubdomainCreateFuses, "fuses", 0, "Fuses to burn on the subdomain (name wrapper only)")
+	ensSubdomainCreateCmd.Flags().Uint64Var(&ensSubdomainCreateExpiry, "expiry", 0, "Expiry of the subdomain as a Unix timestamp; limited to the expiry of the domain (name wrapper only)")
+	ensSubdomainCreateCmd.Flags().BoolVar(&ensSubdomainCreateForce, "force", false, "Send without checking that the sender is able to manage the domain")
+	addTransactionFlags(ensSubdomainCreateCmd, "Passphrase for the account that sends the transactions")
+}
@@ -0,0 +1,55 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+// ensTextGetCmd represents the ens text get command
+var ensTextGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Obtain a text record of an ENS domain",
+	Long: `Obtain a text record of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ethereal ens text get --name=enstest.eth --key=com.twitter
+
+In quiet mode this will return 0 if the name has a value for the key, otherwise 1.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+		cli.AssertCode(ensTextKey != "", quiet, cli.ExitUsage, "--key is required")
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+
+		resolver, err := ens.ResolverContract(client, name)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver for %s", name))
+		text, err := resolver.Text(nil, ens.NameHash(name), ensTextKey)
+		cli.ErrCheck(err, quiet, "Failed to obtain text record")
+		cli.Assert(text != "", quiet, fmt.Sprintf("%s has no text record for %s", name, ensTextKey))
+
+		if !quiet {
+			fmt.Fprintln(cli.Out, text)
+		}
+	},
+}
+
+func init() {
+	ensTextCmd.AddCommand(ensTextGetCmd)
+	ensTextFlags(ensTextGetCmd)
+}
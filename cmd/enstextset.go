@@ -0,0 +1,87 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var ensTextSetValue string
+var ensTextSetFromAddress string
+var ensTextSetForce bool
+
+// ensTextSetCmd represents the ens text set command
+var ensTextSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a text record of an ENS domain",
+	Long: `Set a text record of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ethereal ens text set --name=enstest.eth --key=url --value=https://www.example.com/ --passphrase="my secret passphrase"
+
+The record is set on the name's current resolver.  If --from is not supplied the transaction is sent from the owner of the name.  The command checks that the sender owns the name or is an approved operator for its owner before sending the transaction; use --force to skip this check.
+
+The keystore for the sending account must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to set the text record is sent successfully, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--name is required")
+		cli.AssertCode(ensTextKey != "", quiet, cli.ExitUsage, "--key is required")
+		cli.AssertCode(cmd.Flags().Changed("value"), quiet, cli.ExitUsage, "--value is required")
+		name, err := ens.NormaliseDomainStrict(ensDomain)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", ensDomain))
+
+		fromAddress := ensSender(name, ensTextSetFromAddress, ensTextSetForce)
+		resolver, err := ens.ResolverContract(client, name)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver for %s", name))
+
+		opts, err := generateTxOpts(fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+		signedTx, err := resolver.SetText(opts, ens.NameHash(name), ensTextKey, ensTextSetValue)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+
+		log.WithFields(log.Fields{
+			"group":         "ens",
+			"command":       "text set",
+			"name":          name,
+			"key":           ensTextKey,
+			"value":         ensTextSetValue,
+			"from":          fromAddress.Hex(),
+			"networkid":     chainID,
+			"gas":           signedTx.Gas(),
+			"gasprice":      signedTx.GasPrice().String(),
+			"transactionid": signedTx.Hash().Hex(),
+		}).Info("success")
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+	},
+}
+
+func init() {
+	ensTextCmd.AddCommand(ensTextSetCmd)
+	ensTextFlags(ensTextSetCmd)
+	ensTextSetCmd.Flags().StringVar(&ensTextSetValue, "value", "", "The value of the text record")
+	ensTextSetCmd.Flags().StringVar(&ensTextSetFromAddress, "from", "", "Address from which to send the transaction (defaults to the owner of the name)")
+	ensTextSetCmd.Flags().BoolVar(&ensTextSetForce, "force", false, "Send without checking that the sender is able to manage the name")
+	addTransactionFlags(ensTextSetCmd, "Passphrase for the account that sends the transaction")
+}
@@ -39,8 +39,8 @@ The keystore for the address must be local (i.e. listed with 'get accounts list'
 In quiet mode this will return 0 if the transaction to transfer the name is sent successfully, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
-		cli.Assert(ensDomain != "", quiet, "--domain is required")
-		cli.Assert(ensTransferNewOwnerStr != "", quiet, "--address is required")
+		cli.AssertCode(ensDomain != "", quiet, cli.ExitUsage, "--domain is required")
+		cli.AssertCode(ensTransferNewOwnerStr != "", quiet, cli.ExitUsage, "--address is required")
 		cli.Assert(len(ensDomain) > 10, quiet, "Domain must be at least 7 characters long")
 		cli.Assert(len(strings.Split(ensDomain, ".")) == 2, quiet, "Name must not contain . (except for ending in .eth)")
 
@@ -57,7 +57,7 @@ In quiet mode this will return 0 if the transaction to transfer the name is sent
 		owner, err := registryContract.Owner(nil, ens.NameHash(ensDomain))
 		cli.ErrCheck(err, quiet, "Cannot obtain owner")
 		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, fmt.Sprintf("Owner of %s is not set", ensDomain))
-		outputIf(verbose, fmt.Sprintf("Current owner of %s is %s", ensDomain, owner.Hex()))
+		diagnosticIf(verbose, fmt.Sprintf("Current owner of %s is %s", ensDomain, owner.Hex()))
 
 		// Transfer the deed
 		newOwnerAddress, err := ens.Resolve(client, ensTransferNewOwnerStr)
@@ -69,7 +69,7 @@ In quiet mode this will return 0 if the transaction to transfer the name is sent
 		tx, err := registrarContract.Transfer(opts, ens.LabelHash(domain), newOwnerAddress)
 		cli.ErrCheck(err, quiet, "Failed to send transaction")
 		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
+			fmt.Fprintln(cli.Out, "Transaction ID is", tx.Hash().Hex())
 		}
 		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
 			"domain":    ensDomain,
@@ -14,6 +14,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
@@ -24,11 +25,13 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util/multicall"
 )
 
-var etherBalanceAddress string
+var etherBalanceAddresses []string
 var etherBalanceBlock string
 var etherBalanceWei bool
+var etherBalanceTotal bool
 
 // etherBalanceCmd represents the ether balance command
 var etherBalanceCmd = &cobra.Command{
@@ -38,11 +41,11 @@ var etherBalanceCmd = &cobra.Command{
 
     ethereal ether balance --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
-In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.`,
+Multiple addresses can be supplied, in which case the balance for each is printed on its own line.  Use --format=csv to output a row for each address in CSV format, or --format=json to output a single JSON array with an entry for each address.  The total of the balances can be printed with --total.  Balances are obtained concurrently, and the number of concurrent lookups can be changed with --concurrency.  When multiple addresses are supplied and the chain has a Multicall3 contract the balances are obtained in batches through it, which is much faster against remote nodes; --multicall-address changes the address of the contract and --multicall=false disables it.  By default the command stops at the first failed lookup; --continue-on-error displays failures and continues.  When multiple addresses are supplied progress is shown on stderr while the balances are obtained, followed by a summary of the number of lookups that succeeded and failed and the total balance.  If --currency is supplied then balances are also displayed in that currency.
+
+In quiet mode this will return 0 if the balance of each address is greater than 0, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(etherBalanceAddress != "", quiet, "--address is required")
-		address, err := ens.Resolve(client, etherBalanceAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain address")
+		cli.AssertCode(len(etherBalanceAddresses) > 0, quiet, cli.ExitUsage, "--address is required")
 
 		var blockNumber *big.Int
 		if etherBalanceBlock != "" {
@@ -60,31 +63,217 @@ In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.`
 			}
 		}
 
-		ctx, cancel := localContext()
-		defer cancel()
-		balance, err := client.BalanceAt(ctx, address, blockNumber)
-		cli.Assert(err == nil || !strings.HasPrefix(err.Error(), "missing trie node"), quiet, "Connection does not have information on that block, please change the connection parameter to point to a full node")
-		cli.ErrCheck(err, quiet, "Failed to obtain balance")
+		jsonOutput := jsonFormat()
+		csv := !jsonOutput && csvFormat()
+		table := newCSVTable("address", "name", "balance_wei", "balance", "error")
+		jsonResults := make([]*etherBalanceJSON, 0, len(etherBalanceAddresses))
+		// Progress is only reported when checking multiple addresses
+		progress := cli.NewProgress(quiet || len(etherBalanceAddresses) == 1, len(etherBalanceAddresses), "checked")
+		results, errs := concurrentLookups(len(etherBalanceAddresses), func(i int) (interface{}, error) {
+			result, err := etherBalanceResolve(etherBalanceAddresses[i], csv || jsonOutput)
+			if err != nil {
+				progress.Done(err, nil)
+				return nil, err
+			}
+			return result, nil
+		})
+		if len(etherBalanceAddresses) > 1 {
+			etherBalanceMulticall(results, blockNumber, progress)
+		}
+		results, errs = concurrentLookups(len(etherBalanceAddresses), func(i int) (interface{}, error) {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			result := results[i].(*etherBalanceResult)
+			if result.balance != nil {
+				return result, nil
+			}
+			if err := etherBalanceLookup(result, blockNumber); err != nil {
+				progress.Done(err, nil)
+				return nil, err
+			}
+			progress.Done(nil, result.balance)
+			return result, nil
+		})
+		progress.Clear()
+
+		failed := false
+		allNonZero := true
+		total := big.NewInt(0)
+		for i, input := range etherBalanceAddresses {
+			if errs[i] != nil {
+				failed = true
+				allNonZero = false
+				if !continueOnError {
+					cli.ErrCheck(errs[i], quiet, fmt.Sprintf("Failed to obtain balance for %s", input))
+				}
+				if jsonOutput {
+					jsonResults = append(jsonResults, &etherBalanceJSON{Address: input, Error: errs[i].Error()})
+				} else if csv {
+					table.add(input, "", "", "", errs[i].Error())
+				} else if !quiet {
+					fmt.Fprintf(cli.Out, "%s\tError: %v\n", input, errs[i])
+				}
+				continue
+			}
+			result := results[i].(*etherBalanceResult)
+
+			if result.balance.Cmp(big.NewInt(0)) == 0 {
+				allNonZero = false
+			}
+			total.Add(total, result.balance)
+			if quiet {
+				continue
+			}
+
+			switch {
+			case jsonOutput:
+				jsonResults = append(jsonResults, &etherBalanceJSON{Address: result.address.Hex(), Name: result.name, Balance: result.balance.String()})
+			case csv:
+				table.add(result.address.Hex(), result.name, result.balance.String(), etherutils.WeiToString(result.balance, true))
+			case len(etherBalanceAddresses) == 1:
+				fmt.Fprintf(cli.Out, "%s\n", etherBalanceString(result.balance))
+			default:
+				fmt.Fprintf(cli.Out, "%s\t%s\n", etherBalanceLabel(result), etherBalanceString(result.balance))
+			}
+		}
 
 		if quiet {
-			if balance.Cmp(big.NewInt(0)) == 0 {
-				os.Exit(1)
-			} else {
+			if allNonZero {
 				os.Exit(0)
 			}
+			os.Exit(1)
 		}
 
-		if etherBalanceWei {
-			fmt.Printf("%s\n", balance.String())
-		} else {
-			fmt.Printf("%s\n", etherutils.WeiToString(balance, true))
+		if jsonOutput {
+			writeJSONArray(jsonResults)
+		} else if etherBalanceTotal && len(etherBalanceAddresses) > 1 {
+			if csv {
+				table.add("Total", "", total.String(), etherutils.WeiToString(total, true))
+			} else {
+				fmt.Fprintf(cli.Out, "Total\t%s\n", etherBalanceString(total))
+			}
+		}
+		if csv {
+			table.write()
+		}
+		progress.Summary()
+		if failed {
+			os.Exit(1)
 		}
 	},
 }
 
+// etherBalanceResolve obtains the address, and if required the name, for
+// which to obtain a balance
+func etherBalanceResolve(input string, named bool) (*etherBalanceResult, error) {
+	address, err := ens.Resolve(client, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain address: %v", err)
+	}
+	result := &etherBalanceResult{address: address}
+	if len(etherBalanceAddresses) > 1 || named {
+		result.name = etherBalanceName(input, address)
+	}
+	return result, nil
+}
+
+// etherBalanceMulticall obtains the balances of resolved addresses through
+// the multicall contract, if it is available.  Balances that cannot be
+// obtained this way are left for individual lookups.
+func etherBalanceMulticall(results []interface{}, blockNumber *big.Int, progress *cli.Progress) {
+	address := multicallAddress(blockNumber)
+	if address == nil {
+		return
+	}
+	pending := make([]*etherBalanceResult, 0, len(results))
+	calls := make([]multicall.Call, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			pending = append(pending, result.(*etherBalanceResult))
+			calls = append(calls, multicall.EthBalanceCall(*address, result.(*etherBalanceResult).address))
+		}
+	}
+	balances, err := multicallUint256s(*address, calls, blockNumber)
+	if err != nil {
+		cli.Warn(quiet || !verbose, fmt.Sprintf("Multicall failed: %v; using individual calls", err))
+		return
+	}
+	for i, balance := range balances {
+		if balance != nil {
+			pending[i].balance = balance
+			progress.Done(nil, balance)
+		}
+	}
+}
+
+// etherBalanceLookup obtains the balance of a single address
+func etherBalanceLookup(result *etherBalanceResult, blockNumber *big.Int) error {
+	ctx, cancel := localContext()
+	defer cancel()
+	balance, err := client.BalanceAt(ctx, result.address, blockNumber)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "missing trie node") {
+			return errors.New("connection does not have information on that block, please change the connection parameter to point to a full node")
+		}
+		return fmt.Errorf("failed to obtain balance: %v", err)
+	}
+	result.balance = balance
+	return nil
+}
+
+// etherBalanceJSON is the JSON representation of the balance of an address
+type etherBalanceJSON struct {
+	Address string `json:"address"`
+	Name    string `json:"name,omitempty"`
+	Balance string `json:"balance,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// etherBalanceResult is the result of a balance lookup
+type etherBalanceResult struct {
+	address common.Address
+	name    string
+	balance *big.Int
+}
+
+// etherBalanceString formats a balance according to the output options
+func etherBalanceString(balance *big.Int) string {
+	if etherBalanceWei {
+		return balance.String()
+	}
+	return etherutils.WeiToString(balance, true) + etherFiatSuffix(balance)
+}
+
+// etherBalanceName provides the ENS name for an address, either as supplied
+// or by reverse resolution
+func etherBalanceName(input string, address common.Address) string {
+	if strings.HasPrefix(input, "0x") {
+		name, err := ens.ReverseResolve(client, &address)
+		if err == nil {
+			return name
+		}
+		return ""
+	}
+	return input
+}
+
+// etherBalanceLabel provides a label for the address of a balance, including
+// its ENS name if it has one
+func etherBalanceLabel(result *etherBalanceResult) string {
+	if result.name == "" {
+		return result.address.Hex()
+	}
+	return fmt.Sprintf("%s (%s)", result.address.Hex(), result.name)
+}
+
 func init() {
 	etherCmd.AddCommand(etherBalanceCmd)
 	etherBalanceCmd.Flags().BoolVar(&etherBalanceWei, "wei", false, "Display output in number of Wei")
-	etherBalanceCmd.Flags().StringVar(&etherBalanceAddress, "address", "", "Address to show Ether balance")
+	etherBalanceCmd.Flags().StringSliceVar(&etherBalanceAddresses, "address", nil, "Address to show Ether balance (can be supplied multiple times)")
 	etherBalanceCmd.Flags().StringVar(&etherBalanceBlock, "block", "", "block hash or number at which to show Ether balance (must be run against an archive node)")
+	addConcurrencyFlags(etherBalanceCmd)
+	addMulticallFlags(etherBalanceCmd)
+	etherBalanceCmd.Flags().BoolVar(&etherBalanceTotal, "total", false, "Display the total balance of all addresses")
+	addFormatFlagWithJSON(etherBalanceCmd)
 }
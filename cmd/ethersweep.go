@@ -38,70 +38,83 @@ var etherSweepCmd = &cobra.Command{
 
     etherereal ether sweep --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --passphrase=secret
 
+If --dry-run is supplied then the sweep transaction, including the amount that would be swept, is displayed but not sent.
+
 In quiet mode this will return 0 if the sweep transaction is successfully sent, otherwise 1.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(etherSweepFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, etherSweepFromAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain from address for sweep")
+	Run: etherSweep,
+}
+
+// etherSweep sweeps all funds from one address to another
+func etherSweep(cmd *cobra.Command, args []string) {
+	cli.AssertCode(etherSweepFromAddress != "", quiet, cli.ExitUsage, "--from is required")
+	fromAddress, err := ens.Resolve(client, etherSweepFromAddress)
+	cli.ErrCheck(err, quiet, "Failed to obtain from address for sweep")
 
-		cli.Assert(etherSweepToAddress != "", quiet, "--to is required")
-		toAddress, err := ens.Resolve(client, etherSweepToAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain to address for sweep")
+	cli.AssertCode(etherSweepToAddress != "", quiet, cli.ExitUsage, "--to is required")
+	toAddress, err := ens.Resolve(client, etherSweepToAddress)
+	cli.ErrCheck(err, quiet, "Failed to obtain to address for sweep")
+
+	// Obtain the balance of the address
+	ctx, cancel := localContext()
+	defer cancel()
+	balance, err := client.BalanceAt(ctx, fromAddress, nil)
+	cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
+	cli.Assert(balance.Cmp(big.NewInt(0)) > 0, quiet, fmt.Sprintf("Balance of %s is 0; nothing to sweep", fromAddress.Hex()))
+
+	// Obtain the amount of gas required to send the transaction, and calculate the amount to send
+	gas := gasLimit
+	if gas == 0 {
+		gas, err = estimateGas(fromAddress, &toAddress, big.NewInt(0), nil)
+		cli.ErrCheck(err, quiet, "Failed to estimate gas required to sweep funds")
+	}
+	diagnosticIf(verbose, fmt.Sprintf("Gas estimation is %v", gas))
+	gasCost := big.NewInt(0).Mul(big.NewInt(int64(gas)), gasPrice)
+	diagnosticIf(verbose, fmt.Sprintf("Gas cost is %v", etherutils.WeiToString(gasCost, true)))
+	cli.Assert(balance.Cmp(gasCost) > 0, quiet, fmt.Sprintf("Balance of %s is insufficient to pay gas cost of %s", etherutils.WeiToString(balance, true), etherutils.WeiToString(gasCost, true)))
+	amount := balance.Sub(balance, gasCost)
+	diagnosticIf(verbose, fmt.Sprintf("Sweeping %s", etherutils.WeiToString(amount, true)))
 
-		// Obtain the balance of the address
+	// Create and sign the transaction
+	signedTx, err := createSignedTransaction(fromAddress, &toAddress, amount, gas, nil)
+	cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+	if offline {
+		if !quiet {
+			buf := new(bytes.Buffer)
+			signedTx.EncodeRLP(buf)
+			fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
+		}
+	} else {
 		ctx, cancel := localContext()
 		defer cancel()
-		balance, err := client.BalanceAt(ctx, fromAddress, nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
-		cli.Assert(balance.Cmp(big.NewInt(0)) > 0, quiet, fmt.Sprintf("Balance of %s is 0; nothing to sweep", fromAddress.Hex()))
+		err = sendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
 
-		// Obtain the amount of gas required to send the transaction, and calculate the amount to send
-		gas, err := estimateGas(fromAddress, &toAddress, balance, nil)
-		cli.ErrCheck(err, quiet, "Failed to estimate gas required to sweep funds")
-		outputIf(verbose, fmt.Sprintf("Gas estimation is %v", gas))
-		gasCost := big.NewInt(0).Mul(big.NewInt(int64(gas)), gasPrice)
-		outputIf(verbose, fmt.Sprintf("Gas cost is %v", etherutils.WeiToString(gasCost, true)))
-		amount := balance.Sub(balance, gasCost)
-		outputIf(verbose, fmt.Sprintf("Sweeping %s", etherutils.WeiToString(amount, true)))
-
-		// Create and sign the transaction
-		signedTx, err := createSignedTransaction(fromAddress, &toAddress, amount, gasLimit, nil)
-		cli.ErrCheck(err, quiet, "Failed to create transaction")
-
-		if offline {
-			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
-			}
-		} else {
-			ctx, cancel := localContext()
-			defer cancel()
-			err = client.SendTransaction(ctx, signedTx)
-			cli.ErrCheck(err, quiet, "Failed to send transaction")
-
-			log.WithFields(log.Fields{
-				"group":         "ether",
-				"command":       "sweep",
-				"from":          fromAddress.Hex(),
-				"to":            toAddress.Hex(),
-				"amount":        amount.String(),
-				"networkid":     chainID,
-				"gas":           signedTx.Gas(),
-				"gasprice":      signedTx.GasPrice().String(),
-				"transactionid": signedTx.Hash().Hex(),
-			}).Info("success")
-			if quiet {
-				os.Exit(0)
-			}
-			fmt.Println(signedTx.Hash().Hex())
+		log.WithFields(log.Fields{
+			"group":         cmd.Parent().Name(),
+			"command":       "sweep",
+			"from":          fromAddress.Hex(),
+			"to":            toAddress.Hex(),
+			"amount":        amount.String(),
+			"networkid":     chainID,
+			"gas":           signedTx.Gas(),
+			"gasprice":      signedTx.GasPrice().String(),
+			"transactionid": signedTx.Hash().Hex(),
+		}).Info("success")
+		if quiet {
+			os.Exit(0)
 		}
-	},
+		fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+	}
 }
 
 func init() {
 	etherCmd.AddCommand(etherSweepCmd)
-	etherSweepCmd.Flags().StringVar(&etherSweepFromAddress, "from", "", "Address from which to sweep Ether")
-	etherSweepCmd.Flags().StringVar(&etherSweepToAddress, "to", "", "Address to which to sweep Ether")
+	etherSweepFlags(etherSweepCmd)
 	addTransactionFlags(etherSweepCmd, "the address that holds the funds")
 }
+
+func etherSweepFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&etherSweepFromAddress, "from", "", "Address from which to sweep Ether")
+	cmd.Flags().StringVar(&etherSweepToAddress, "to", "", "Address to which to sweep Ether")
+}
@@ -40,27 +40,34 @@ var etherTransferCmd = &cobra.Command{
 
     ethereal ether transfer --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --amount=1.5ether --passphrase=secret
 
+The transaction can be signed with a connected Ledger by supplying --ledger, with the account selected by --hd-path.  In this case the from address is taken from the Ledger if not supplied.
+
 In quiet mode this will return 0 if the transfer transaction is successfully sent, otherwise 1.`,
 	Aliases: []string{"send"},
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(etherTransferFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, etherTransferFromAddress)
+		cli.AssertCode(etherTransferFromAddress != "" || senderImplicit(), quiet, cli.ExitUsage, "--from is required")
+		fromAddress, err := senderAddress(etherTransferFromAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain from address for transfer")
 
-		cli.Assert(etherTransferToAddress != "", quiet, "--to is required")
+		cli.AssertCode(etherTransferToAddress != "", quiet, cli.ExitUsage, "--to is required")
+		if offline {
+			cli.Assert(!strings.HasSuffix(etherTransferToAddress, ".eth"), quiet, "ENS names cannot be resolved when offline")
+		}
 		toAddress, err := ens.Resolve(client, etherTransferToAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain to address for transfer")
 
-		cli.Assert(etherTransferAmount != "", quiet, "--amount is required")
+		cli.AssertCode(etherTransferAmount != "", quiet, cli.ExitUsage, "--amount is required")
 		amount, err := etherutils.StringToWei(etherTransferAmount)
-		cli.ErrCheck(err, quiet, "Invalid amount")
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid amount")
 
-		// Obtain the balance of the address
-		ctx, cancel := localContext()
-		defer cancel()
-		balance, err := client.BalanceAt(ctx, fromAddress, nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
-		cli.Assert(balance.Cmp(amount) > 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", etherutils.WeiToString(balance, true)))
+		if !offline {
+			// Obtain the balance of the address
+			ctx, cancel := localContext()
+			defer cancel()
+			balance, err := client.BalanceAt(ctx, fromAddress, nil)
+			cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
+			cli.Assert(balance.Cmp(amount) > 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", etherutils.WeiToString(balance, true)))
+		}
 
 		// Turn the data string in to hex
 		etherTransferData = strings.TrimPrefix(etherTransferData, "0x")
@@ -79,12 +86,12 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			ctx, cancel := localContext()
 			defer cancel()
-			err = client.SendTransaction(ctx, signedTx)
+			err = sendTransaction(ctx, signedTx)
 			cli.ErrCheck(err, quiet, "Failed to send transaction")
 
 			log.WithFields(log.Fields{
@@ -102,7 +109,7 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 			if quiet {
 				os.Exit(0)
 			}
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
@@ -0,0 +1,62 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/util/etherscan"
+)
+
+// explorerSetting obtains a setting for the block explorer.  A value on the
+// command line takes precedence, followed by the value for the chain in the
+// config file, for example etherscan.137.api-key, and then the top-level value
+// in the config file.
+func explorerSetting(flag string, chainKey string) string {
+	if RootCmd.PersistentFlags().Changed(flag) {
+		return viper.GetString(flag)
+	}
+	if chainID != nil {
+		if value := viper.GetString(fmt.Sprintf("etherscan.%v.%s", chainID, chainKey)); value != "" {
+			return value
+		}
+	}
+	return viper.GetString(flag)
+}
+
+// explorerAPIKey obtains the API key for the block explorer
+func explorerAPIKey() string {
+	return explorerSetting("etherscan-api-key", "api-key")
+}
+
+// explorerURL obtains the URL of the block explorer's API
+func explorerURL() string {
+	if url := explorerSetting("etherscan-url", "url"); url != "" {
+		return url
+	}
+	return etherscan.DefaultURL
+}
+
+// explorerConfigured returns true if a block explorer has been configured,
+// either with an API key or with the URL of an explorer that does not need
+// one
+func explorerConfigured() bool {
+	return explorerAPIKey() != "" || explorerSetting("etherscan-url", "url") != ""
+}
+
+// explorerClient creates a client for the configured block explorer
+func explorerClient() *etherscan.Client {
+	return etherscan.New(explorerURL(), explorerAPIKey(), chainID, viper.GetDuration("timeout"))
+}
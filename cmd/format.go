@@ -0,0 +1,88 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var outputFormat string
+
+// addFormatFlag adds the output format flag for commands that output rows
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&outputFormat, "format", "text", "Output format (text or csv)")
+}
+
+// addFormatFlagWithJSON adds the output format flag for commands that output
+// rows and can also output them as a single JSON array
+func addFormatFlagWithJSON(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&outputFormat, "format", "text", "Output format (text, csv or json)")
+}
+
+// jsonFormat returns true if output should be in JSON format.  This must be
+// checked before csvFormat, which does not accept JSON.
+func jsonFormat() bool {
+	return outputFormat == "json"
+}
+
+// csvFormat returns true if output should be in CSV format
+func csvFormat() bool {
+	switch outputFormat {
+	case "", "text":
+		return false
+	case "csv":
+		return true
+	default:
+		cli.ErrCode(quiet, cli.ExitUsage, fmt.Sprintf("Unknown output format %s", outputFormat))
+		return false
+	}
+}
+
+// csvTable accumulates rows for CSV output
+type csvTable struct {
+	header []string
+	rows   [][]string
+}
+
+// newCSVTable creates a CSV table with the given columns
+func newCSVTable(columns ...string) *csvTable {
+	return &csvTable{header: columns}
+}
+
+// add adds a row to the table.  Missing trailing values are left empty.
+func (t *csvTable) add(values ...string) {
+	row := make([]string, len(t.header))
+	copy(row, values)
+	t.rows = append(t.rows, row)
+}
+
+// write writes the header and rows of the table
+func (t *csvTable) write() {
+	writer := csv.NewWriter(cli.Out)
+	writer.Write(t.header)
+	writer.WriteAll(t.rows)
+	cli.ErrCheck(writer.Error(), quiet, "Failed to write CSV")
+}
+
+// writeJSONArray writes items as a single JSON array
+func writeJSONArray(items interface{}) {
+	data, err := json.Marshal(items)
+	cli.ErrCheck(err, quiet, "Failed to generate JSON")
+	fmt.Fprintln(cli.Out, string(data))
+}
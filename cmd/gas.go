@@ -0,0 +1,180 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+)
+
+// gasPriceCap is the maximum gas price for a transaction, if set
+var gasPriceCap *big.Int
+
+// gasPriceCapForced is set if transactions should be sent regardless of
+// gasPriceCap
+var gasPriceCapForced bool
+
+// gasCmd represents the gas command
+var gasCmd = &cobra.Command{
+	Use:   "gas",
+	Short: "Obtain information about gas",
+	Long:  `Obtain information about gas prices.`,
+}
+
+func init() {
+	RootCmd.AddCommand(gasCmd)
+}
+
+// checkGasPriceCap returns an error if the gas price of a transaction is
+// above the cap supplied with --gas-price-cap, unless --ignore-gas-price-cap is supplied
+func checkGasPriceCap(tx *types.Transaction) error {
+	if gasPriceCap == nil || gasPriceCapForced || tx.GasPrice().Cmp(gasPriceCap) <= 0 {
+		return nil
+	}
+	return fmt.Errorf("gas price of %s is above the cap of %s (use --ignore-gas-price-cap to send regardless)", etherutils.WeiToString(tx.GasPrice(), true), etherutils.WeiToString(gasPriceCap, true))
+}
+
+// gasPriceCapSigner wraps a signer to refuse to sign transactions with a gas
+// price above the cap
+func gasPriceCapSigner(signer bind.SignerFn) bind.SignerFn {
+	return func(txSigner types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if err := checkGasPriceCap(tx); err != nil {
+			return nil, err
+		}
+		return signer(txSigner, address, tx)
+	}
+}
+
+// gasPriceStrategies maps a gas price strategy to the percentage of the base
+// fee (or, prior to London, the node's gas price) to pay
+var gasPriceStrategies = map[string]int64{
+	"safe":     100,
+	"standard": 125,
+	"fast":     200,
+}
+
+// gasSuggestPrice suggests a gas price according to the given strategy.  On
+// chains with a base fee this is a multiple of the latest base fee plus the
+// node's suggested priority fee, otherwise it is a multiple of the node's
+// suggested gas price.
+func gasSuggestPrice(ctx context.Context, strategy string) (*big.Int, error) {
+	multiplier, exists := gasPriceStrategies[strategy]
+	if !exists {
+		return nil, fmt.Errorf("unknown gas price strategy %s", strategy)
+	}
+
+	var block *struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := rpcClient.CallContext(ctx, &block, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, err
+	}
+
+	suggested, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil || block.BaseFee == nil {
+		return new(big.Int).Div(new(big.Int).Mul(suggested, big.NewInt(multiplier)), big.NewInt(100)), nil
+	}
+
+	baseFee := block.BaseFee.ToInt()
+	var priorityFee hexutil.Big
+	if err := rpcClient.CallContext(ctx, &priorityFee, "eth_maxPriorityFeePerGas"); err != nil {
+		// Not all nodes support this call; derive the priority fee from the gas price
+		priorityFee = hexutil.Big(*new(big.Int).Sub(suggested, baseFee))
+		if priorityFee.ToInt().Sign() < 0 {
+			priorityFee = hexutil.Big(*big.NewInt(0))
+		}
+	}
+	price := new(big.Int).Div(new(big.Int).Mul(baseFee, big.NewInt(multiplier)), big.NewInt(100))
+	return price.Add(price, priorityFee.ToInt()), nil
+}
+
+// gasBlockFees contains the fee information for a block
+type gasBlockFees struct {
+	Number *big.Int
+	// BaseFee is nil prior to London
+	BaseFee      *big.Int
+	GasPrices    []*big.Int
+	PriorityFees []*big.Int
+}
+
+// gasObtainBlockFees obtains the fees paid in the most recent blocks.  Blocks
+// are fetched directly as go-ethereum's Block cannot decode typed transactions.
+func gasObtainBlockFees(ctx context.Context, blocks int64) ([]*gasBlockFees, error) {
+	var latest hexutil.Big
+	if err := rpcClient.CallContext(ctx, &latest, "eth_blockNumber"); err != nil {
+		return nil, err
+	}
+
+	fees := make([]*gasBlockFees, 0, blocks)
+	for number := latest.ToInt().Int64(); number > latest.ToInt().Int64()-blocks && number >= 0; number-- {
+		var block *struct {
+			BaseFee      *hexutil.Big `json:"baseFeePerGas"`
+			Transactions []struct {
+				GasPrice *hexutil.Big `json:"gasPrice"`
+			} `json:"transactions"`
+		}
+		if err := rpcClient.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeBig(big.NewInt(number)), true); err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		blockFees := &gasBlockFees{
+			Number: big.NewInt(number),
+		}
+		if block.BaseFee != nil {
+			blockFees.BaseFee = block.BaseFee.ToInt()
+		}
+		for _, tx := range block.Transactions {
+			if tx.GasPrice == nil {
+				continue
+			}
+			// Mined transactions report their effective gas price
+			gasPrice := tx.GasPrice.ToInt()
+			blockFees.GasPrices = append(blockFees.GasPrices, gasPrice)
+			if blockFees.BaseFee != nil {
+				blockFees.PriorityFees = append(blockFees.PriorityFees, new(big.Int).Sub(gasPrice, blockFees.BaseFee))
+			}
+		}
+		fees = append(fees, blockFees)
+	}
+	return fees, nil
+}
+
+// gasSort sorts a list of values in increasing order
+func gasSort(values []*big.Int) {
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+}
+
+// gasPercentile returns the given percentile of a sorted list of values
+func gasPercentile(sorted []*big.Int, percentile int) *big.Int {
+	if len(sorted) == 0 {
+		return nil
+	}
+	index := (len(sorted) - 1) * percentile / 100
+	return sorted[index]
+}
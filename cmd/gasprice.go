@@ -0,0 +1,127 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var gasPriceBlocks int64
+var gasPriceJSON bool
+
+// gasPriceSummary is the JSON representation of the gas price summary.
+// All values are in Wei.
+type gasPriceSummary struct {
+	Blocks                  int64  `json:"blocks"`
+	Transactions            int    `json:"transactions"`
+	Minimum                 string `json:"minimum,omitempty"`
+	Median                  string `json:"median,omitempty"`
+	Percentile90            string `json:"percentile90,omitempty"`
+	BaseFeeOldest           string `json:"baseFeeOldest,omitempty"`
+	BaseFeeLatest           string `json:"baseFeeLatest,omitempty"`
+	PriorityFeeMedian       string `json:"priorityFeeMedian,omitempty"`
+	PriorityFeePercentile90 string `json:"priorityFeePercentile90,omitempty"`
+}
+
+// gasPriceCmd represents the gas price command
+var gasPriceCmd = &cobra.Command{
+	Use:   "price",
+	Short: "Obtain gas prices paid in recent blocks",
+	Long: `Obtain the minimum, median and 90th percentile gas prices paid by transactions in recent blocks.  For example:
+
+    ethereal gas price --blocks=50
+
+On chains with a base fee the base fee of the oldest and latest blocks are also displayed, along with suggested priority fees.
+
+In quiet mode this will return 0 if gas prices are obtained, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(gasPriceBlocks > 0, quiet, cli.ExitUsage, "--blocks must be greater than 0")
+
+		ctx, cancel := localContext()
+		defer cancel()
+		fees, err := gasObtainBlockFees(ctx, gasPriceBlocks)
+		cli.ErrCheck(err, quiet, "Failed to obtain gas prices")
+
+		var gasPrices []*big.Int
+		var priorityFees []*big.Int
+		for _, blockFees := range fees {
+			gasPrices = append(gasPrices, blockFees.GasPrices...)
+			priorityFees = append(priorityFees, blockFees.PriorityFees...)
+		}
+		cli.Assert(len(gasPrices) > 0, quiet, "No transactions in recent blocks")
+		gasSort(gasPrices)
+		gasSort(priorityFees)
+
+		if quiet {
+			os.Exit(0)
+		}
+
+		summary := &gasPriceSummary{
+			Blocks:       int64(len(fees)),
+			Transactions: len(gasPrices),
+			Minimum:      gasPrices[0].String(),
+			Median:       gasPercentile(gasPrices, 50).String(),
+			Percentile90: gasPercentile(gasPrices, 90).String(),
+		}
+		if fees[0].BaseFee != nil && fees[len(fees)-1].BaseFee != nil {
+			// Fees are obtained from the latest block backwards
+			summary.BaseFeeLatest = fees[0].BaseFee.String()
+			summary.BaseFeeOldest = fees[len(fees)-1].BaseFee.String()
+		}
+		if len(priorityFees) > 0 {
+			summary.PriorityFeeMedian = gasPercentile(priorityFees, 50).String()
+			summary.PriorityFeePercentile90 = gasPercentile(priorityFees, 90).String()
+		}
+
+		if gasPriceJSON {
+			data, err := json.Marshal(summary)
+			cli.ErrCheck(err, quiet, "Failed to generate JSON")
+			fmt.Fprintf(cli.Out, "%s\n", string(data))
+			os.Exit(0)
+		}
+
+		fmt.Fprintf(cli.Out, "Blocks:\t\t\t%d\n", summary.Blocks)
+		fmt.Fprintf(cli.Out, "Transactions:\t\t%d\n", summary.Transactions)
+		fmt.Fprintf(cli.Out, "Minimum:\t\t%s\n", gasPriceString(gasPrices[0]))
+		fmt.Fprintf(cli.Out, "Median:\t\t\t%s\n", gasPriceString(gasPercentile(gasPrices, 50)))
+		fmt.Fprintf(cli.Out, "90th percentile:\t%s\n", gasPriceString(gasPercentile(gasPrices, 90)))
+		if summary.BaseFeeLatest != "" {
+			fmt.Fprintf(cli.Out, "Base fee (oldest):\t%s\n", gasPriceString(fees[len(fees)-1].BaseFee))
+			fmt.Fprintf(cli.Out, "Base fee (latest):\t%s\n", gasPriceString(fees[0].BaseFee))
+		}
+		if len(priorityFees) > 0 {
+			fmt.Fprintf(cli.Out, "Priority fee (median):\t%s\n", gasPriceString(gasPercentile(priorityFees, 50)))
+			fmt.Fprintf(cli.Out, "Priority fee (90th):\t%s\n", gasPriceString(gasPercentile(priorityFees, 90)))
+		}
+	},
+}
+
+// gasPriceString displays a gas price in both Wei and a more readable unit
+func gasPriceString(value *big.Int) string {
+	return fmt.Sprintf("%s (%s Wei)", etherutils.WeiToString(value, true), value.String())
+}
+
+func init() {
+	gasCmd.AddCommand(gasPriceCmd)
+	gasPriceCmd.Flags().Int64Var(&gasPriceBlocks, "blocks", 20, "Number of recent blocks to sample")
+	gasPriceCmd.Flags().BoolVar(&gasPriceJSON, "json", false, "Output the information as JSON")
+}
@@ -0,0 +1,173 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var gasWatchBelow string
+var gasWatchAbove string
+var gasWatchInterval time.Duration
+var gasWatchMaxWait time.Duration
+var gasWatchBaseFee bool
+var gasWatchStrategy string
+var gasWatchExec string
+var gasWatchExit bool
+
+// gasWatchCmd represents the gas watch command
+var gasWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch for the gas price to cross a threshold",
+	Long: `Poll the gas price and report when it crosses a threshold.  For example:
+
+    ethereal gas watch --below=20gwei --interval=15s
+
+One of --below or --above is required.  The gas price watched is that which would be suggested for a transaction with the strategy given by --strategy; with --base-fee the base fee of the latest block is watched instead.  A line is output each time the price crosses the threshold in either direction.
+
+When the condition is met --exec, if supplied, is run with the shell, with the price in Wei in the environment variable ETHEREAL_GAS_PRICE.  With --exit the watch ends when the condition is first met, which allows a transaction to wait for cheap gas, for example:
+
+    ethereal gas watch --below=20gwei --exit && ethereal ether transfer ...
+
+--max-wait limits how long to watch for; by default the watch continues until it is interrupted.  Failures to obtain the price are reported and the watch continues.
+
+In quiet mode this will return 0 if the condition is met, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(gasWatchBelow != "" || gasWatchAbove != "", quiet, cli.ExitUsage, "--below or --above is required")
+		cli.AssertCode(gasWatchBelow == "" || gasWatchAbove == "", quiet, cli.ExitUsage, "Cannot supply both --below and --above")
+		cli.AssertCode(gasWatchInterval > 0, quiet, cli.ExitUsage, "--interval must be greater than 0")
+		_, exists := gasPriceStrategies[gasWatchStrategy]
+		cli.Assert(exists, quiet, fmt.Sprintf("Unknown gas price strategy %s", gasWatchStrategy))
+
+		below := gasWatchBelow != ""
+		thresholdStr := gasWatchBelow
+		if !below {
+			thresholdStr = gasWatchAbove
+		}
+		threshold, err := etherutils.StringToWei(thresholdStr)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid threshold %s", thresholdStr))
+
+		name := "Gas price"
+		if gasWatchBaseFee {
+			name = "Base fee"
+		}
+		var deadline time.Time
+		if gasWatchMaxWait > 0 {
+			deadline = time.Now().Add(gasWatchMaxWait)
+		}
+
+		// The condition is initially unknown, so the first price is
+		// always reported
+		var met *bool
+		for {
+			price, err := gasWatchPrice()
+			if err != nil {
+				cli.Warn(quiet, fmt.Sprintf("Failed to obtain %s: %v", name, err))
+			} else {
+				diagnosticIf(verbose, fmt.Sprintf("%s is %s", name, etherutils.WeiToString(price, true)))
+				nowMet := (below && price.Cmp(threshold) < 0) || (!below && price.Cmp(threshold) > 0)
+				if met == nil || nowMet != *met {
+					outputIf(!quiet, gasWatchCrossing(name, price, threshold, nowMet == below))
+				}
+				if nowMet && (met == nil || !*met) {
+					if gasWatchExec != "" {
+						err := gasWatchRun(price)
+						if gasWatchExit {
+							cli.ErrCheck(err, quiet, "Command failed")
+						} else if err != nil {
+							cli.Warn(quiet, fmt.Sprintf("Command failed: %v", err))
+						}
+					}
+					if gasWatchExit {
+						os.Exit(0)
+					}
+				}
+				met = &nowMet
+			}
+
+			if !deadline.IsZero() && time.Now().Add(gasWatchInterval).After(deadline) {
+				break
+			}
+			time.Sleep(gasWatchInterval)
+		}
+
+		if met != nil && *met {
+			os.Exit(0)
+		}
+		outputIf(!quiet, fmt.Sprintf("Condition not met within %v", gasWatchMaxWait))
+		os.Exit(1)
+	},
+}
+
+// gasWatchPrice obtains the price being watched
+func gasWatchPrice() (*big.Int, error) {
+	ctx, cancel := localContext()
+	defer cancel()
+	if !gasWatchBaseFee {
+		return gasSuggestPrice(ctx, gasWatchStrategy)
+	}
+
+	var block *struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	err := rpcClient.CallContext(ctx, &block, "eth_getBlockByNumber", "latest", false)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil || block.BaseFee == nil {
+		return nil, fmt.Errorf("the chain does not have a base fee")
+	}
+	return block.BaseFee.ToInt(), nil
+}
+
+// gasWatchCrossing describes the price in relation to the threshold
+func gasWatchCrossing(name string, price *big.Int, threshold *big.Int, below bool) string {
+	relation := "above"
+	if below {
+		relation = "below"
+	}
+	return fmt.Sprintf("%s\t%s is %s, %s %s", time.Now().UTC().Format("2006-01-02 15:04:05"), name, etherutils.WeiToString(price, true), relation, etherutils.WeiToString(threshold, true))
+}
+
+// gasWatchRun runs the command supplied with --exec
+func gasWatchRun(price *big.Int) error {
+	command := exec.Command("sh", "-c", gasWatchExec)
+	command.Env = append(os.Environ(), fmt.Sprintf("ETHEREAL_GAS_PRICE=%s", price.String()))
+	command.Stdin = os.Stdin
+	command.Stdout = cli.Out
+	command.Stderr = os.Stderr
+	return command.Run()
+}
+
+func init() {
+	gasCmd.AddCommand(gasWatchCmd)
+	gasWatchCmd.Flags().StringVar(&gasWatchBelow, "below", "", "Report when the price falls below this value, for example 20gwei")
+	gasWatchCmd.Flags().StringVar(&gasWatchAbove, "above", "", "Report when the price rises above this value, for example 100gwei")
+	gasWatchCmd.Flags().DurationVar(&gasWatchInterval, "interval", 15*time.Second, "Time between checks of the price")
+	gasWatchCmd.Flags().DurationVar(&gasWatchMaxWait, "max-wait", 0, "Longest time for which to watch (0 to watch until interrupted)")
+	gasWatchCmd.Flags().BoolVar(&gasWatchBaseFee, "base-fee", false, "Watch the base fee of the latest block rather than the suggested gas price")
+	gasWatchCmd.Flags().StringVar(&gasWatchStrategy, "strategy", "standard", "Strategy for suggesting the gas price (safe, standard or fast)")
+	gasWatchCmd.Flags().StringVar(&gasWatchExec, "exec", "", "Command to run with the shell when the condition is met")
+	gasWatchCmd.Flags().BoolVar(&gasWatchExit, "exit", false, "Exit when the condition is met")
+}
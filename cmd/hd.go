@@ -0,0 +1,50 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hdCmd represents the hd command
+var hdCmd = &cobra.Command{
+	Use:   "hd",
+	Short: "Manage hierarchical deterministic wallets",
+	Long:  `Derive accounts from BIP-39 mnemonics.`,
+}
+
+func init() {
+	RootCmd.AddCommand(hdCmd)
+}
+
+// obtainMnemonic obtains a mnemonic either as supplied or from the first line
+// of a file.  It returns an empty string if neither is supplied.
+func obtainMnemonic(mnemonic string, file string) (string, error) {
+	if mnemonic != "" && file != "" {
+		return "", errors.New("cannot supply both a mnemonic and a mnemonic file")
+	}
+	if file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read mnemonic file: %v", err)
+		}
+		mnemonic = strings.SplitN(string(data), "\n", 2)[0]
+	}
+	return strings.TrimSpace(mnemonic), nil
+}
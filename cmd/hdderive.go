@@ -0,0 +1,85 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/hd"
+)
+
+var hdDeriveMnemonic string
+var hdDeriveMnemonicFile string
+var hdDerivePassphrase string
+var hdDerivePath string
+var hdDeriveCount int
+var hdDeriveShowPrivateKeys bool
+
+// hdDeriveCmd represents the hd derive command
+var hdDeriveCmd = &cobra.Command{
+	Use:   "derive",
+	Short: "Derive addresses from a mnemonic",
+	Long: `Derive addresses from a BIP-39 mnemonic.  For example:
+
+    ethereal hd derive --mnemonic-file=mnemonic.txt --path="m/44'/60'/0'/0/0" --count=10
+
+The mnemonic is supplied with --mnemonic or on the first line of --mnemonic-file, and its checksum is validated.  If the mnemonic has a passphrase (sometimes known as the 25th word) it is supplied with --passphrase.
+
+The path and address of each account is output.  --count derives that number of accounts, incrementing the last index of the path for each.  Private keys are only output if --show-private-keys is supplied.
+
+In quiet mode this will return 0 if the addresses are derived, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(hdDeriveMnemonic != "" || hdDeriveMnemonicFile != "", quiet, cli.ExitUsage, "--mnemonic or --mnemonic-file is required")
+		cli.AssertCode(hdDeriveCount > 0, quiet, cli.ExitUsage, "--count must be at least 1")
+		mnemonic, err := obtainMnemonic(hdDeriveMnemonic, hdDeriveMnemonicFile)
+		cli.ErrCheck(err, quiet, "Failed to obtain mnemonic")
+		seed, err := hd.SeedFromMnemonic(mnemonic, hdDerivePassphrase)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid mnemonic")
+		path, err := hd.ParsePath(hdDerivePath)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid path")
+		cli.Assert(len(path) > 0, quiet, "Path must contain at least one index")
+		cli.AssertCode(uint64(path[len(path)-1]&^hd.HardenedOffset)+uint64(hdDeriveCount) <= uint64(hd.HardenedOffset), quiet, cli.ExitUsage, "--count too large for path")
+
+		for i := 0; i < hdDeriveCount; i++ {
+			key, err := hd.DeriveKey(seed, path)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to derive key at %s", hd.FormatPath(path)))
+			if !quiet {
+				address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+				if hdDeriveShowPrivateKeys {
+					fmt.Fprintf(cli.Out, "%s\t%s\t0x%s\n", hd.FormatPath(path), address, hex.EncodeToString(crypto.FromECDSA(key)))
+				} else {
+					fmt.Fprintf(cli.Out, "%s\t%s\n", hd.FormatPath(path), address)
+				}
+			}
+			path[len(path)-1]++
+		}
+		os.Exit(0)
+	},
+}
+
+func init() {
+	hdCmd.AddCommand(hdDeriveCmd)
+	hdDeriveCmd.Flags().StringVar(&hdDeriveMnemonic, "mnemonic", "", "BIP-39 mnemonic from which to derive the addresses")
+	hdDeriveCmd.Flags().StringVar(&hdDeriveMnemonicFile, "mnemonic-file", "", "File containing the BIP-39 mnemonic from which to derive the addresses on its first line")
+	hdDeriveCmd.Flags().StringVar(&hdDerivePassphrase, "passphrase", "", "Optional passphrase for the mnemonic")
+	hdDeriveCmd.Flags().StringVar(&hdDerivePath, "path", cli.DefaultLedgerPath, "Derivation path of the first address")
+	hdDeriveCmd.Flags().IntVar(&hdDeriveCount, "count", 1, "Number of addresses to derive")
+	hdDeriveCmd.Flags().BoolVar(&hdDeriveShowPrivateKeys, "show-private-keys", false, "Output the private key of each address")
+}
@@ -0,0 +1,116 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+var logsChunkSize uint64
+
+// addLogsFlags adds the flags for commands that query logs
+func addLogsFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(&logsChunkSize, "chunk-size", 10000, "Number of blocks to query for logs at a time")
+}
+
+// filterLogs obtains the logs matching a query.  The block range of the query
+// is split in to windows of --chunk-size blocks that are queried in turn, and
+// if a node refuses a window as too large it is halved and retried.  Logs are
+// returned in chronological order.
+func filterLogs(query ethereum.FilterQuery) ([]types.Log, error) {
+	from, to, err := logsRange(query.FromBlock, query.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	chunkSize := logsChunkSize
+	if chunkSize == 0 {
+		chunkSize = 1
+	}
+
+	logs := make([]types.Log, 0)
+	for start := from; start <= to; {
+		end := start + chunkSize - 1
+		if end > to || end < start {
+			end = to
+		}
+		query.FromBlock = new(big.Int).SetUint64(start)
+		query.ToBlock = new(big.Int).SetUint64(end)
+		ctx, cancel := localContext()
+		windowLogs, err := client.FilterLogs(ctx, query)
+		cancel()
+		if err != nil {
+			if logsRangeTooLarge(err) && end > start {
+				chunkSize = (end - start + 1) / 2
+				diagnosticIf(verbose, fmt.Sprintf("Query of blocks %d to %d was too large; reducing to %d blocks", start, end, chunkSize))
+				continue
+			}
+			return nil, fmt.Errorf("failed to obtain logs for blocks %d to %d: %v", start, end, err)
+		}
+		logs = append(logs, windowLogs...)
+		if end == to {
+			break
+		}
+		start = end + 1
+	}
+	return logs, nil
+}
+
+// logsRange resolves the block range of a log query, where a nil block is
+// the latest block
+func logsRange(fromBlock *big.Int, toBlock *big.Int) (uint64, uint64, error) {
+	var latest *big.Int
+	if fromBlock == nil || toBlock == nil {
+		ctx, cancel := localContext()
+		defer cancel()
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to obtain latest block: %v", err)
+		}
+		latest = header.Number
+	}
+	if fromBlock == nil {
+		fromBlock = latest
+	}
+	if toBlock == nil {
+		toBlock = latest
+	}
+	if !fromBlock.IsUint64() || !toBlock.IsUint64() {
+		return 0, 0, fmt.Errorf("invalid block range")
+	}
+	if fromBlock.Cmp(toBlock) > 0 {
+		return 0, 0, fmt.Errorf("from block %v is after to block %v", fromBlock, toBlock)
+	}
+	return fromBlock.Uint64(), toBlock.Uint64(), nil
+}
+
+// logsRangeTooLarge returns true if the error shows that a node refused a
+// log query because it covered too many blocks or returned too many results
+func logsRangeTooLarge(err error) bool {
+	if transientError(err) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, tooLarge := range []string{"returned more than", "too large", "block range", "too many results", "too many logs", "exceed", "response size"} {
+		if strings.Contains(msg, tooLarge) {
+			return true
+		}
+	}
+	return false
+}
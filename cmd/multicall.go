@@ -0,0 +1,83 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util/multicall"
+)
+
+var multicallEnabled bool
+var multicallAddressStr string
+
+// multicallBatchSize is the maximum number of reads in a single multicall
+const multicallBatchSize = 500
+
+// addMulticallFlags adds the flags for batching reads through Multicall3
+func addMulticallFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&multicallEnabled, "multicall", true, "Batch reads through a Multicall3 contract when it is available")
+	cmd.Flags().StringVar(&multicallAddressStr, "multicall-address", multicall.DefaultAddress.Hex(), "Address of the Multicall3 contract")
+}
+
+// multicallAddress provides the address of the multicall contract if reads
+// should be batched through it at the given block, otherwise nil
+func multicallAddress(blockNumber *big.Int) *common.Address {
+	if !multicallEnabled {
+		return nil
+	}
+	address, err := ens.Resolve(client, multicallAddressStr)
+	if err != nil {
+		cli.Warn(quiet || !verbose, fmt.Sprintf("Failed to resolve multicall address: %v; using individual calls", err))
+		return nil
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	if !multicall.Available(ctx, client, address, blockNumber) {
+		cli.Warn(quiet || !verbose, fmt.Sprintf("No multicall contract at %s; using individual calls", address.Hex()))
+		return nil
+	}
+	return &address
+}
+
+// multicallUint256s carries out reads that each return a uint256 through the
+// multicall contract, in batches.  The value of a read that fails is nil, so
+// that the caller can fall back to an individual call for it.  An error is
+// returned if the multicall itself fails.
+func multicallUint256s(address common.Address, calls []multicall.Call, blockNumber *big.Int) ([]*big.Int, error) {
+	values := make([]*big.Int, len(calls))
+	for start := 0; start < len(calls); start += multicallBatchSize {
+		end := start + multicallBatchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+		ctx, cancel := localContext()
+		results, err := multicall.Aggregate(ctx, client, address, calls[start:end], blockNumber)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range results {
+			if value, err := result.Uint256(); err == nil {
+				values[start+i] = value
+			}
+		}
+	}
+	return values, nil
+}
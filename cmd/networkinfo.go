@@ -0,0 +1,123 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var networkInfoJSON bool
+
+// networkInfo is the JSON representation of the network information
+type networkInfo struct {
+	ChainID       string  `json:"chainId"`
+	ClientVersion string  `json:"clientVersion,omitempty"`
+	BlockNumber   uint64  `json:"blockNumber"`
+	Peers         *uint64 `json:"peers,omitempty"`
+	Syncing       bool    `json:"syncing"`
+	CurrentBlock  uint64  `json:"currentBlock,omitempty"`
+	HighestBlock  uint64  `json:"highestBlock,omitempty"`
+}
+
+// networkInfoCmd represents the network info command
+var networkInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Obtain information about the network",
+	Long: `Obtain information about the network and the connected node: the chain ID, the node's client version, the current block number, the number of peers and if the node is syncing.  For example:
+
+    ethereal network info
+
+Some nodes and services do not provide the client version or peer count, in which case they are not shown.  Use --json to output the information as JSON.
+
+In quiet mode this will return 0 if the node is fully synced, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		ctx, cancel := localContext()
+		defer cancel()
+
+		info := &networkInfo{}
+
+		var chainIDHex hexutil.Big
+		err := rpcClient.CallContext(ctx, &chainIDHex, "eth_chainId")
+		if err == nil {
+			info.ChainID = chainIDHex.ToInt().String()
+		} else {
+			// Node predates eth_chainId; fall back to the network ID
+			info.ChainID = chainID.String()
+		}
+
+		var clientVersion string
+		if err := rpcClient.CallContext(ctx, &clientVersion, "web3_clientVersion"); err == nil {
+			info.ClientVersion = clientVersion
+		}
+
+		var blockNumber hexutil.Uint64
+		err = rpcClient.CallContext(ctx, &blockNumber, "eth_blockNumber")
+		cli.ErrCheck(err, quiet, "Failed to obtain block number")
+		info.BlockNumber = uint64(blockNumber)
+
+		var peers hexutil.Uint64
+		if err := rpcClient.CallContext(ctx, &peers, "net_peerCount"); err == nil {
+			count := uint64(peers)
+			info.Peers = &count
+		}
+
+		progress, err := client.SyncProgress(ctx)
+		cli.ErrCheck(err, quiet, "Failed to obtain sync status")
+		if progress != nil {
+			info.Syncing = true
+			info.CurrentBlock = progress.CurrentBlock
+			info.HighestBlock = progress.HighestBlock
+		}
+
+		if quiet {
+			if info.Syncing {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		if networkInfoJSON {
+			data, err := json.Marshal(info)
+			cli.ErrCheck(err, quiet, "Failed to generate JSON")
+			fmt.Fprintln(cli.Out, string(data))
+			os.Exit(0)
+		}
+
+		fmt.Fprintf(cli.Out, "Chain ID:\t\t%s\n", info.ChainID)
+		if info.ClientVersion != "" {
+			fmt.Fprintf(cli.Out, "Client:\t\t\t%s\n", info.ClientVersion)
+		}
+		fmt.Fprintf(cli.Out, "Block:\t\t\t%d\n", info.BlockNumber)
+		if info.Peers != nil {
+			fmt.Fprintf(cli.Out, "Peers:\t\t\t%d\n", *info.Peers)
+		}
+		if info.Syncing {
+			fmt.Fprintf(cli.Out, "Syncing:\t\tYes (block %d of %d)\n", info.CurrentBlock, info.HighestBlock)
+		} else {
+			fmt.Fprintln(cli.Out, "Syncing:\t\tNo")
+		}
+	},
+}
+
+func init() {
+	networkCmd.AddCommand(networkInfoCmd)
+	networkInfoCmd.Flags().BoolVar(&networkInfoJSON, "json", false, "Output the information as JSON")
+}
@@ -0,0 +1,93 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// nodeSent contains the hashes of transactions that the node has signed and
+// sent itself, which must not be sent again
+var nodeSent = make(map[common.Hash]bool)
+
+// nodeSendTransactionArgs are the arguments to eth_sendTransaction
+type nodeSendTransactionArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to,omitempty"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     hexutil.Bytes   `json:"data"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+}
+
+// nodeSigning returns true if transactions should be signed and sent by the
+// node rather than signed locally
+func nodeSigning() bool {
+	return viper.GetBool("node-signing")
+}
+
+// nodeSendTransaction has the node sign and send an unsigned transaction with
+// its account for the sender, returning the transaction as signed by the
+// node
+func nodeSendTransaction(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	args := &nodeSendTransactionArgs{
+		From:     from,
+		To:       tx.To(),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: (*hexutil.Big)(tx.GasPrice()),
+		Value:    (*hexutil.Big)(tx.Value()),
+		Data:     tx.Data(),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	var hash common.Hash
+	if err := rpcClient.CallContext(ctx, &hash, "eth_sendTransaction", args); err != nil {
+		if methodUnavailable(err) {
+			return nil, errors.New("node does not support signing transactions")
+		}
+		return nil, fmt.Errorf("node failed to send transaction: %v", err)
+	}
+	nodeSent[hash] = true
+
+	// Fetched directly as go-ethereum's TransactionByHash rejects pending
+	// transactions from some nodes
+	var signedTx *types.Transaction
+	err := rpcClient.CallContext(ctx, &signedTx, "eth_getTransactionByHash", hash)
+	if err == nil && signedTx == nil {
+		err = ethereum.NotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("node sent transaction %s but it could not be obtained: %v", hash.Hex(), err)
+	}
+	return signedTx, nil
+}
+
+// sendTransaction sends a signed transaction, unless it has already been sent
+// by the node
+func sendTransaction(ctx context.Context, signedTx *types.Transaction) error {
+	if nodeSent[signedTx.Hash()] {
+		return nil
+	}
+	return broadcastTransaction(ctx, signedTx)
+}
@@ -0,0 +1,130 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var nodeTxpoolAddress string
+
+// nodeTxpoolTransaction is a transaction as returned by txpool_content
+type nodeTxpoolTransaction struct {
+	Hash     common.Hash  `json:"hash"`
+	GasPrice *hexutil.Big `json:"gasPrice"`
+}
+
+// nodeTxpoolContent is the content of the transaction pool, keyed by sender
+// and then nonce
+type nodeTxpoolContent struct {
+	Pending map[common.Address]map[string]*nodeTxpoolTransaction `json:"pending"`
+	Queued  map[common.Address]map[string]*nodeTxpoolTransaction `json:"queued"`
+}
+
+// nodeTxpoolCmd represents the node txpool command
+var nodeTxpoolCmd = &cobra.Command{
+	Use:   "txpool",
+	Short: "Obtain the status of the node's transaction pool",
+	Long: `Obtain the number of pending and queued transactions in the node's transaction pool, either overall or for a single address.  For example:
+
+    ethereal node txpool --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+
+In verbose mode the nonce, gas price and hash of each transaction for the address are also displayed.  This is useful to find transactions that are stuck before cancelling them with "ethereal transaction cancel".
+
+The node must provide the txpool API, which is often not enabled by default or by hosted services.
+
+In quiet mode this will return 0 if the transaction pool status is obtained, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		ctx, cancel := localContext()
+		defer cancel()
+
+		if nodeTxpoolAddress == "" {
+			var status struct {
+				Pending hexutil.Uint64 `json:"pending"`
+				Queued  hexutil.Uint64 `json:"queued"`
+			}
+			err := rpcClient.CallContext(ctx, &status, "txpool_status")
+			cli.AssertCode(err == nil || !methodUnavailable(err), quiet, cli.ExitNetwork, "The node does not provide the txpool API")
+			cli.ErrCheck(err, quiet, "Failed to obtain transaction pool status")
+			if quiet {
+				os.Exit(0)
+			}
+			fmt.Fprintf(cli.Out, "Pending:\t%d\n", status.Pending)
+			fmt.Fprintf(cli.Out, "Queued:\t\t%d\n", status.Queued)
+			os.Exit(0)
+		}
+
+		address, err := ens.Resolve(client, nodeTxpoolAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve address %s", nodeTxpoolAddress))
+
+		var content nodeTxpoolContent
+		err = rpcClient.CallContext(ctx, &content, "txpool_content")
+		cli.AssertCode(err == nil || !methodUnavailable(err), quiet, cli.ExitNetwork, "The node does not provide the txpool API")
+		cli.ErrCheck(err, quiet, "Failed to obtain transaction pool content")
+		if quiet {
+			os.Exit(0)
+		}
+
+		pending := content.Pending[address]
+		queued := content.Queued[address]
+		fmt.Fprintf(cli.Out, "Pending:\t%d\n", len(pending))
+		nodeTxpoolPrintTransactions(pending)
+		fmt.Fprintf(cli.Out, "Queued:\t\t%d\n", len(queued))
+		nodeTxpoolPrintTransactions(queued)
+	},
+}
+
+// nodeTxpoolPrintTransactions prints transactions in nonce order when in
+// verbose mode
+func nodeTxpoolPrintTransactions(txs map[string]*nodeTxpoolTransaction) {
+	if !verbose {
+		return
+	}
+	nonces := make([]uint64, 0, len(txs))
+	byNonce := make(map[uint64]*nodeTxpoolTransaction)
+	for key, tx := range txs {
+		nonce, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			continue
+		}
+		nonces = append(nonces, nonce)
+		byNonce[nonce] = tx
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	for _, nonce := range nonces {
+		tx := byNonce[nonce]
+		gasPrice := big.NewInt(0)
+		if tx.GasPrice != nil {
+			gasPrice = tx.GasPrice.ToInt()
+		}
+		fmt.Fprintf(cli.Out, "\t%d\t%s\t%s\n", nonce, etherutils.WeiToString(gasPrice, true), tx.Hash.Hex())
+	}
+}
+
+func init() {
+	nodeCmd.AddCommand(nodeTxpoolCmd)
+	nodeTxpoolCmd.Flags().StringVar(&nodeTxpoolAddress, "address", "", "Address for which to show transactions")
+}
@@ -0,0 +1,61 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/price"
+)
+
+var priceSource price.Source
+var priceSourceOnce sync.Once
+
+// fiatSuffix returns the value of an amount of an asset in the currency
+// supplied with --currency, in the form " (1234.56 USD)" for appending to the
+// display of the amount.  If no currency is supplied, or the price cannot be
+// obtained, then it returns an empty string.
+func fiatSuffix(asset string, amount *big.Int, decimals int) string {
+	currency := viper.GetString("currency")
+	if currency == "" || offline {
+		return ""
+	}
+	priceSourceOnce.Do(func() {
+		var err error
+		priceSource, err = price.NewSource(viper.GetString("price-source"))
+		if err != nil {
+			cli.Warn(quiet, fmt.Sprintf("Failed to create price source: %v", err))
+		}
+	})
+	if priceSource == nil {
+		return ""
+	}
+	value, err := price.Value(priceSource, asset, currency, amount, decimals)
+	if err != nil {
+		diagnosticIf(verbose, fmt.Sprintf("Failed to obtain %s price: %v", currency, err))
+		return ""
+	}
+	return fmt.Sprintf(" (%s %s)", value.Text('f', 2), strings.ToUpper(currency))
+}
+
+// etherFiatSuffix returns the value of an amount of Wei in the currency
+// supplied with --currency; see fiatSuffix
+func etherFiatSuffix(amount *big.Int) string {
+	return fiatSuffix(price.Ether, amount, 18)
+}
@@ -14,27 +14,37 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/orinocopay/go-etherutils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
 )
 
 var cfgFile string
@@ -42,18 +52,30 @@ var quiet bool
 var verbose bool
 var offline bool
 
+var rpcClient *rpc.Client
 var client *ethclient.Client
 var chainID *big.Int
+var noReplayProtection bool
 var referrer common.Address
 
 // TODO make maps keyed on address?
 var nonce int64
 var wallet accounts.Wallet
 var account *accounts.Account
+var keystoreKey *keystore.Key
 
 // Common variables
 var gasPrice *big.Int
 var gasLimit uint64
+var gasMarginPercent int64
+
+// defaultGasPrice is used when the gas price cannot be suggested, for example
+// when offline
+const defaultGasPrice = "4 GWei"
+
+// localAnnotation marks commands that do not need a connection to an
+// Ethereum node
+const localAnnotation = "local"
 
 var err error
 
@@ -71,6 +93,14 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if err := cli.SetErrorFormat(viper.GetString("error-format")); err != nil {
+		cli.ErrCode(viper.GetBool("quiet"), cli.ExitUsage, fmt.Sprintf("Invalid --error-format: %v", err))
+	}
+
+	if err := cli.SetOutput(viper.GetString("output")); err != nil {
+		cli.Err(viper.GetBool("quiet"), fmt.Sprintf("Failed to open output file: %v", err))
+	}
+
 	if cmd.Name() == "version" {
 		// User just wants the version
 		return
@@ -80,12 +110,14 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 	quiet = viper.GetBool("quiet")
 	verbose = viper.GetBool("verbose")
 	offline = viper.GetBool("offline")
+	dryRun = viper.GetBool("dry-run")
+	noReplayProtection = viper.GetBool("no-replay-protection")
 	if offline {
 		// Also need chain ID
 		chainID = big.NewInt(viper.GetInt64("chainid"))
 	}
 	if quiet && verbose {
-		cli.Err(quiet, "Cannot supply both quiet and verbose flags")
+		cli.ErrCode(quiet, cli.ExitUsage, "Cannot supply both quiet and verbose flags")
 	}
 	// ...lots of commands have (e.g.) 'passphrase' as an option but we want to
 	// bind it to this particular command and this is the first chance we get
@@ -95,20 +127,67 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 	if cmd.Flags().Lookup("privatekey") != nil {
 		viper.BindPFlag("privatekey", cmd.Flags().Lookup("privatekey"))
 	}
+	if cmd.Flags().Lookup("keystore-file") != nil {
+		viper.BindPFlag("keystore-file", cmd.Flags().Lookup("keystore-file"))
+	}
+	if cmd.Flags().Lookup("passphrase-file") != nil {
+		viper.BindPFlag("passphrase-file", cmd.Flags().Lookup("passphrase-file"))
+	}
+	if cmd.Flags().Lookup("passphrase") != nil {
+		passphrase, err := cli.ObtainPassphrase(viper.GetString("passphrase"), viper.GetString("passphrase-file"))
+		cli.ErrCheck(err, quiet, "Failed to obtain passphrase")
+		viper.Set("passphrase", passphrase)
+	}
 	if cmd.Flags().Lookup("nonce") != nil {
 		viper.BindPFlag("nonce", cmd.Flags().Lookup("nonce"))
 	}
-	// Set up gas price if we have it
+	if cmd.Flags().Lookup("simulate") != nil {
+		viper.BindPFlag("simulate", cmd.Flags().Lookup("simulate"))
+	}
+	if cmd.Flags().Lookup("state-override") != nil {
+		bindStateOverrideFlags(cmd)
+		// Overrides only apply to simulations, so must not be used when
+		// sending transactions
+		cli.Assert(dryRun || cmd.Flags().Lookup("simulate") == nil || !stateOverridesSupplied(), quiet, "State overrides can only be used with --dry-run when sending transactions")
+	}
+	if cmd.Flags().Lookup("node-signing") != nil {
+		viper.BindPFlag("node-signing", cmd.Flags().Lookup("node-signing"))
+		cli.AssertCode(!offline || !viper.GetBool("node-signing"), quiet, cli.ExitUsage, "--node-signing is not available when offline")
+		cli.AssertCode(!noReplayProtection || !viper.GetBool("node-signing"), quiet, cli.ExitUsage, "--no-replay-protection is not available with --node-signing")
+	}
+	if cmd.Flags().Lookup("broadcast-to") != nil {
+		viper.BindPFlag("broadcast-to", cmd.Flags().Lookup("broadcast-to"))
+		cli.AssertCode(!offline || len(broadcastEndpoints()) == 0, quiet, cli.ExitUsage, "--broadcast-to is not available when offline")
+	}
+	if cmd.Flags().Lookup("ledger") != nil {
+		viper.BindPFlag("ledger", cmd.Flags().Lookup("ledger"))
+		viper.BindPFlag("hd-path", cmd.Flags().Lookup("hd-path"))
+	}
+	// Set up gas price if we have it; if not it is suggested once connected
 	if cmd.Flags().Lookup("gasprice") != nil {
 		viper.BindPFlag("gasprice", cmd.Flags().Lookup("gasprice"))
+		viper.BindPFlag("gas-price-strategy", cmd.Flags().Lookup("gas-price-strategy"))
 		if viper.GetString("gasprice") == "" {
-			gasPrice, err = etherutils.StringToWei("4 GWei")
-			cli.ErrCheck(err, quiet, "Invalid gas price")
+			_, exists := gasPriceStrategies[viper.GetString("gas-price-strategy")]
+			cli.Assert(exists, quiet, fmt.Sprintf("Unknown gas price strategy %s", viper.GetString("gas-price-strategy")))
+			gasPrice, err = etherutils.StringToWei(defaultGasPrice)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid gas price")
 		} else {
 			gasPrice, err = etherutils.StringToWei(viper.GetString("gasprice"))
-			cli.ErrCheck(err, quiet, "Invalid gas price")
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid gas price")
 		}
 	}
+	if capStr := viper.GetString("gas-price-cap"); capStr != "" {
+		gasPriceCap, err = etherutils.StringToWei(capStr)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid gas price cap")
+	}
+	if cmd.Flags().Lookup("ignore-gas-price-cap") != nil {
+		gasPriceCapForced, _ = cmd.Flags().GetBool("ignore-gas-price-cap")
+	}
+	if thresholdStr := viper.GetString("confirm-threshold"); thresholdStr != "" {
+		confirmThreshold, err = etherutils.StringToWei(thresholdStr)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid confirmation threshold")
+	}
 	// Set up nonce if we have it
 	nonce = viper.GetInt64("nonce")
 
@@ -117,6 +196,21 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 		if viper.GetInt("gaslimit") > 0 {
 			gasLimit = uint64(viper.GetInt("gaslimit"))
 		}
+		viper.BindPFlag("gas-margin-percent", cmd.Flags().Lookup("gas-margin-percent"))
+		gasMarginPercent = viper.GetInt64("gas-margin-percent")
+		cli.AssertCode(gasMarginPercent >= 0, quiet, cli.ExitUsage, "--gas-margin-percent cannot be negative")
+	}
+
+	// Offline transactions cannot obtain anything from the network so
+	// require all values to be supplied
+	if offline && cmd.Flags().Lookup("nonce") != nil {
+		cli.AssertCode(nonce >= 0, quiet, cli.ExitUsage, "--nonce is required when offline")
+		cli.AssertCode(viper.GetString("gasprice") != "", quiet, cli.ExitUsage, "--gasprice is required when offline")
+		cli.AssertCode(gasLimit > 0, quiet, cli.ExitUsage, "--gaslimit is required when offline")
+		cli.AssertCode(chainID.Sign() > 0 || noReplayProtection, quiet, cli.ExitUsage, "--chainid is required when offline, or --no-replay-protection to sign transactions without replay protection")
+	}
+	if noReplayProtection && cmd.Flags().Lookup("nonce") != nil {
+		cli.Warn(quiet, "transactions will be signed without replay protection, so can be replayed on other chains")
 	}
 
 	// Set default log file if no alternative is provided
@@ -132,44 +226,123 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 	log.SetFormatter(&log.JSONFormatter{})
 
 	// Create a connection to an Ethereum node
-	if !offline {
-		client, err = ethclient.Dial(viper.GetString("connection"))
+	if !offline && cmd.Annotations[localAnnotation] == "" {
+		rpcClient, err = dialConnections(connectionEndpoints())
 		cli.ErrCheck(err, quiet, "Failed to connect to Ethereum")
+		client = ethclient.NewClient(rpcClient)
 		// Fetch the chain ID
 		ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
 		defer cancel()
-		chainID, err = client.NetworkID(ctx)
+		var reported bool
+		chainID, reported, err = connectedChainID(ctx)
 		cli.ErrCheck(err, quiet, "Failed to obtain chain ID")
+		if expectedChainID := viper.GetInt64("chainid"); expectedChainID != 0 {
+			if reported {
+				cli.Assert(chainID.Int64() == expectedChainID, quiet, fmt.Sprintf("Connected to chain ID %v but expected chain ID %d", chainID, expectedChainID))
+			} else {
+				// The node only reports its network ID, so use the chain ID
+				// supplied in its place
+				diagnosticIf(verbose, fmt.Sprintf("Node does not report its chain ID; using chain ID %d", expectedChainID))
+				chainID = big.NewInt(expectedChainID)
+			}
+		}
+		if cmd.Flags().Lookup("nonce") != nil {
+			cli.Assert(chainID.Sign() > 0 || noReplayProtection, quiet, "Node does not have a chain ID; supply --chainid, or --no-replay-protection to sign transactions without replay protection")
+		}
+
+		if cmd.Flags().Lookup("gasprice") != nil && viper.GetString("gasprice") == "" {
+			strategy := viper.GetString("gas-price-strategy")
+			gasPrice, err = gasSuggestPrice(ctx, strategy)
+			cli.ErrCheck(err, quiet, "Failed to obtain suggested gas price")
+			diagnosticIf(verbose, fmt.Sprintf("Gas price is %s (%s strategy)", etherutils.WeiToString(gasPrice, true), strategy))
+		}
 	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// Errors in parsing the command line occur before --error-format has been
+	// read, so look for it directly.  Cobra's own error message is silenced
+	// in favour of ours, as is the usage if the error is written as JSON.
+	RootCmd.SilenceErrors = true
+	if commandLineErrorFormat(os.Args[1:]) == "json" {
+		RootCmd.SilenceUsage = true
+		cli.SetErrorFormat("json")
+	}
 	if err := RootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		// Commands exit themselves, so errors here are from parsing the
+		// command line
+		cli.ErrCode(false, cli.ExitUsage, err.Error())
+	}
+}
+
+// commandLineErrorFormat obtains the value of --error-format from the
+// command line, if present
+func commandLineErrorFormat(args []string) string {
+	format := ""
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "--error-format=") {
+			format = strings.TrimPrefix(arg, "--error-format=")
+		} else if arg == "--error-format" && i+1 < len(args) {
+			format = args[i+1]
+		}
 	}
+	return format
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ethereal.yaml)")
+	RootCmd.PersistentFlags().String("profile", "", "the named profile in the config file from which to take settings.  Settings supplied on the command line override those in the profile")
+	viper.BindPFlag("profile", RootCmd.PersistentFlags().Lookup("profile"))
 	RootCmd.PersistentFlags().String("log", "", "log activity to the named file (default $HOME/ethereal.log).  Logs are written for every action that generates a transaction")
 	viper.BindPFlag("log", RootCmd.PersistentFlags().Lookup("log"))
-	RootCmd.PersistentFlags().Bool("quiet", false, "do not generate any output, but return a 0 exit code on success and 1 on failure.  The definitions of success and failure for a given command can be found in that command's help")
+	RootCmd.PersistentFlags().Bool("quiet", false, "do not generate any output, but return a 0 exit code on success and 1 on failure.  The definitions of success and failure for a given command can be found in that command's help.  Errors return 2 for invalid usage, 3 for network or node failures and 4 for execution failures such as reverts")
 	viper.BindPFlag("quiet", RootCmd.PersistentFlags().Lookup("quiet"))
 	RootCmd.PersistentFlags().Bool("verbose", false, "generate additional output where appropriate")
 	viper.BindPFlag("verbose", RootCmd.PersistentFlags().Lookup("verbose"))
-	RootCmd.PersistentFlags().String("connection", "https://api.orinocopay.com:8546/", "the IPC or RPC path to an Ethereum node.  If you are running your own local instance of Ethereum this might be /home/user/.ethereum/geth.ipc (IPC) or http://localhost:8545/ (RPC)")
+	RootCmd.PersistentFlags().String("output", "", "write command output to the named file rather than stdout (\"-\" for stdout).  Errors, warnings, verbose diagnostics and logs are not written to the file")
+	viper.BindPFlag("output", RootCmd.PersistentFlags().Lookup("output"))
+	RootCmd.PersistentFlags().String("error-format", "text", "the format of errors and warnings written to stderr: text or json.  In json format each is written as an object on its own line, for example {\"error\":\"...\",\"code\":1}")
+	viper.BindPFlag("error-format", RootCmd.PersistentFlags().Lookup("error-format"))
+	RootCmd.PersistentFlags().StringSlice("connection", []string{"https://api.orinocopay.com:8546/"}, "the IPC path, WebSocket URL or HTTP URL of an Ethereum node.  If you are running your own local instance of Ethereum this might be /home/user/.ethereum/geth.ipc (IPC), ws://localhost:8546/ (WebSocket) or http://localhost:8545/ (HTTP).  Can be supplied multiple times, or separated by commas, to fail over to the next node if one is unreachable, returns an HTTP error or reports an internal error or rate limit.  Errors with the request itself, such as a call that reverts, are not failed over.  Failover is per request for HTTP connections, and on connecting for WebSocket and IPC connections")
 	viper.BindPFlag("connection", RootCmd.PersistentFlags().Lookup("connection"))
+	RootCmd.PersistentFlags().StringArrayVar(&rpcHeaders, "rpc-header", nil, "a header of the form \"Name: value\" to send with each request to an HTTP connection, for example to supply an API key.  Can be supplied multiple times.  Cannot be used with more than one connection")
 	RootCmd.PersistentFlags().Duration("timeout", 30*time.Second, "the time after which a network request will be deemed to have failed.  Increase this if you are running on a error-prone, high-latency or low-bandwidth connection")
 	viper.BindPFlag("timeout", RootCmd.PersistentFlags().Lookup("timeout"))
+	RootCmd.PersistentFlags().Duration("wait-timeout", 10*time.Minute, "the time after which commands that wait for transactions to be mined, such as transaction wait, ens register and contract deploy --wait, give up waiting.  This is separate from --timeout, which applies to each network request")
+	viper.BindPFlag("wait-timeout", RootCmd.PersistentFlags().Lookup("wait-timeout"))
+	RootCmd.PersistentFlags().Int("rpc-retries", 0, "the number of times to retry a read-only request to an HTTP connection that fails with a transient error, such as a dropped connection or a rate limit")
+	viper.BindPFlag("rpc-retries", RootCmd.PersistentFlags().Lookup("rpc-retries"))
+	RootCmd.PersistentFlags().Duration("rpc-retry-delay", time.Second, "the delay before the first retry of a failed network request; this doubles with each subsequent retry")
+	viper.BindPFlag("rpc-retry-delay", RootCmd.PersistentFlags().Lookup("rpc-retry-delay"))
 	RootCmd.PersistentFlags().Bool("offline", false, "print the transaction a hex string and do not send it")
 	viper.BindPFlag("offline", RootCmd.PersistentFlags().Lookup("offline"))
-	RootCmd.PersistentFlags().Int64("chainid", 0, "the chain ID of the network (only required when offline)")
+	RootCmd.PersistentFlags().Bool("dry-run", false, "display the details of a transaction and the result of simulating it rather than sending it.  For commands that send multiple transactions only the first is displayed")
+	viper.BindPFlag("dry-run", RootCmd.PersistentFlags().Lookup("dry-run"))
+	RootCmd.PersistentFlags().Int64("chainid", 0, "the chain ID of the network (only required when offline; if supplied when online the connected node must match, or if the node only reports its network ID this is used in its place)")
 	viper.BindPFlag("chainid", RootCmd.PersistentFlags().Lookup("chainid"))
+	RootCmd.PersistentFlags().Bool("no-replay-protection", false, "sign transactions without EIP-155 replay protection, allowing them to be replayed on other chains")
+	viper.BindPFlag("no-replay-protection", RootCmd.PersistentFlags().Lookup("no-replay-protection"))
+	RootCmd.PersistentFlags().String("gas-price-cap", "", "the maximum gas price for transactions, for example 200gwei.  Transactions with a higher gas price are refused unless --ignore-gas-price-cap is supplied")
+	viper.BindPFlag("gas-price-cap", RootCmd.PersistentFlags().Lookup("gas-price-cap"))
+	RootCmd.PersistentFlags().String("confirm-threshold", "", "the value or fee, for example 1ether, above which a transaction must be confirmed before it is sent.  Confirmation is only requested when running in a terminal")
+	viper.BindPFlag("confirm-threshold", RootCmd.PersistentFlags().Lookup("confirm-threshold"))
+	RootCmd.PersistentFlags().Bool("yes", false, "send transactions without asking for confirmation")
+	viper.BindPFlag("yes", RootCmd.PersistentFlags().Lookup("yes"))
+	RootCmd.PersistentFlags().String("currency", "", "the fiat currency in which to also display values, for example usd or eur")
+	viper.BindPFlag("currency", RootCmd.PersistentFlags().Lookup("currency"))
+	RootCmd.PersistentFlags().String("price-source", "coingecko", "the source of prices for --currency: coingecko, cryptocompare or the URL of a CoinGecko-compatible API")
+	viper.BindPFlag("price-source", RootCmd.PersistentFlags().Lookup("price-source"))
+	RootCmd.PersistentFlags().String("etherscan-api-key", "", "the API key for Etherscan, used by commands that obtain history or verify contracts.  A key for a single chain can be supplied as etherscan.<chain ID>.api-key in the config file")
+	viper.BindPFlag("etherscan-api-key", RootCmd.PersistentFlags().Lookup("etherscan-api-key"))
+	RootCmd.PersistentFlags().String("etherscan-url", "", "the URL of an Etherscan-compatible API to use in place of Etherscan.  A URL for a single chain can be supplied as etherscan.<chain ID>.url in the config file")
+	viper.BindPFlag("etherscan-url", RootCmd.PersistentFlags().Lookup("etherscan-url"))
 	RootCmd.PersistentFlags().Int("usbwallets", 1, "number of USB wallets to show")
 	viper.BindPFlag("usbwallets", RootCmd.PersistentFlags().Lookup("usbwallets"))
 }
@@ -183,7 +356,7 @@ func initConfig() {
 		// Find home directory.
 		home, err := homedir.Dir()
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 
@@ -196,6 +369,27 @@ func initConfig() {
 
 	// If a config file is found, read it in.
 	viper.ReadInConfig()
+
+	if profile := viper.GetString("profile"); profile != "" {
+		err := applyProfile(profile)
+		cli.ErrCheck(err, viper.GetBool("quiet"), fmt.Sprintf("Failed to apply profile %s", profile))
+	}
+}
+
+// applyProfile merges the settings of the named profile over the top-level
+// settings in the config file.  Settings supplied on the command line or in
+// the environment continue to take precedence.
+func applyProfile(profile string) error {
+	settings := viper.Sub(fmt.Sprintf("profiles.%s", profile))
+	if settings == nil {
+		return errors.New("profile not found in config file")
+	}
+	data, err := json.Marshal(settings.AllSettings())
+	if err != nil {
+		return err
+	}
+	viper.SetConfigType("json")
+	return viper.MergeConfig(bytes.NewReader(data))
 }
 
 //
@@ -206,9 +400,25 @@ func initConfig() {
 func addTransactionFlags(cmd *cobra.Command, explanation string) {
 	cmd.Flags().String("passphrase", "", fmt.Sprintf("passphrase for %s", explanation))
 	cmd.Flags().String("privatekey", "", fmt.Sprintf("private key for %s", explanation))
-	cmd.Flags().String("gasprice", "", "Gas price for the transaction")
+	cmd.Flags().String("keystore-file", "", fmt.Sprintf("keystore (v3) JSON file holding the key for %s; decrypted with the passphrase", explanation))
+	cmd.Flags().String("passphrase-file", "", fmt.Sprintf("file containing the passphrase for %s on its first line", explanation))
+	cmd.Flags().String("gasprice", "", "Gas price for the transaction; if not supplied this is suggested according to the gas price strategy")
+	cmd.Flags().String("gas-price-strategy", "standard", "Strategy for suggesting the gas price (safe, standard or fast)")
 	cmd.Flags().Int64("gaslimit", 0, "Gas limit for the transaction; 0 is auto-select")
+	cmd.Flags().Int64("gas-margin-percent", 20, "Percentage added to the estimated gas limit when it is auto-selected")
 	cmd.Flags().Int64("nonce", -1, "Nonce for the transaction; -1 is auto-select")
+	cmd.Flags().Bool("simulate", true, "Simulate contract interactions before sending them, and do not send them if they would fail")
+	cmd.Flags().Bool("ledger", false, fmt.Sprintf("use a connected Ledger to sign for %s", explanation))
+	cmd.Flags().Bool("node-signing", false, "Have the node sign and send the transaction with its own account for the sender")
+	cmd.Flags().String("hd-path", cli.DefaultLedgerPath, "Derivation path of the Ledger account")
+	addBroadcastFlags(cmd)
+	addStateOverrideFlags(cmd)
+	cmd.Flags().Bool("ignore-gas-price-cap", false, "Send the transaction even if its gas price is above --gas-price-cap")
+}
+
+// addBroadcastFlags adds flags used by commands that broadcast transactions
+func addBroadcastFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("broadcast-to", nil, "URL of a further node to which to send the transaction at the same time as the connection.  Can be supplied multiple times")
 }
 
 // Obtain the current nonce for the given address
@@ -250,6 +460,13 @@ func estimateGas(fromAddress common.Address, toAddress *common.Address, amount *
 	defer cancel()
 	gas, err = client.EstimateGas(ctx, msg)
 	if err != nil {
+		// See if the transaction reverts, to provide a more useful error
+		result, callErr := client.CallContract(ctx, msg, nil)
+		if reason, reverted := callRevertReason(result, callErr); reverted {
+			err = fmt.Errorf("transaction would revert: %s", reason)
+		} else {
+			err = fmt.Errorf("transaction would fail: %v", err)
+		}
 		return
 	}
 	return
@@ -270,6 +487,9 @@ func createTransaction(fromAddress common.Address, toAddress *common.Address, am
 		if err != nil {
 			return
 		}
+		// Add a margin as gas used can change between estimation and execution
+		gasLimit += gasLimit * uint64(gasMarginPercent) / 100
+		diagnosticIf(verbose, fmt.Sprintf("Gas limit is %d", gasLimit))
 	}
 
 	// Create the transaction
@@ -290,6 +510,32 @@ func createSignedTransaction(fromAddress common.Address, toAddress *common.Addre
 		return
 	}
 
+	if err = checkGasPriceCap(tx); err != nil {
+		return
+	}
+
+	// Check that the transaction will succeed
+	if err = preflightTransaction(fromAddress, tx); err != nil {
+		return
+	}
+
+	if err = confirmTransaction(tx); err != nil {
+		return
+	}
+
+	if nodeSigning() {
+		if dryRun {
+			dryRunTransaction(fromAddress, tx)
+		}
+		// The node signs and sends the transaction itself
+		signedTx, err = nodeSendTransaction(fromAddress, tx)
+		if err != nil {
+			return
+		}
+		nextNonce(fromAddress)
+		return
+	}
+
 	// Sign the transaction
 	signedTx, err = signTransaction(fromAddress, tx)
 	if err != nil {
@@ -297,6 +543,10 @@ func createSignedTransaction(fromAddress common.Address, toAddress *common.Addre
 		return
 	}
 
+	if dryRun {
+		dryRunTransaction(fromAddress, signedTx)
+	}
+
 	// Increment the nonce for the next transaction
 	nextNonce(fromAddress)
 
@@ -304,20 +554,43 @@ func createSignedTransaction(fromAddress common.Address, toAddress *common.Addre
 }
 
 func generateTxOpts(sender common.Address) (opts *bind.TransactOpts, err error) {
+	if nodeSigning() {
+		err = errors.New("--node-signing is not supported by this command")
+		return
+	}
+
 	// Signer depends on what information is available to us
 	var signer bind.SignerFn
-	if viper.GetString("passphrase") != "" {
+	if viper.GetBool("ledger") {
+		signer = func(_ types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return signTransaction(address, tx)
+		}
+	} else if viper.GetString("keystore-file") != "" {
+		if err = obtainKeystoreKey(); err != nil {
+			return
+		}
+		signer = keySigner(keystoreKey.PrivateKey)
+	} else if passphraseAvailable() {
 		var wallet accounts.Wallet
 		var account *accounts.Account
 		wallet, account, err = obtainWalletAndAccount(sender)
 		if err != nil {
 			return
 		}
-		signer = etherutils.AccountSigner(chainID, &wallet, account, viper.GetString("passphrase"))
+		signer = etherutils.AccountSigner(signingChainID(), &wallet, account, viper.GetString("passphrase"))
 	} else if viper.GetString("privatekey") != "" {
 		key, err := crypto.HexToECDSA(viper.GetString("privatekey"))
-		cli.ErrCheck(err, quiet, "Invalid private key")
-		signer = etherutils.KeySigner(chainID, key)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid private key")
+		signer = keySigner(key)
+	}
+
+	if signer != nil {
+		if dryRun {
+			signer = dryRunSigner(signer)
+		} else {
+			signer = preflightSigner(confirmSigner(signer))
+		}
+		signer = gasPriceCapSigner(signer)
 	}
 
 	curNonce, err := currentNonce(sender)
@@ -340,6 +613,49 @@ func generateTxOpts(sender common.Address) (opts *bind.TransactOpts, err error)
 	return
 }
 
+// connectedChainID obtains the chain ID of the connected node.  Nodes that
+// predate eth_chainId only provide their network ID, which is not always the
+// same as their chain ID, so this also returns if the chain ID was reported
+func connectedChainID(ctx context.Context) (*big.Int, bool, error) {
+	var id hexutil.Big
+	err := rpcClient.CallContext(ctx, &id, "eth_chainId")
+	if err == nil {
+		return id.ToInt(), true, nil
+	}
+	if !methodUnavailable(err) {
+		return nil, false, err
+	}
+	networkID, err := client.NetworkID(ctx)
+	return networkID, false, err
+}
+
+// signingChainID provides the chain ID with which transactions are signed,
+// which is nil if they are not replay protected
+func signingChainID() *big.Int {
+	if noReplayProtection {
+		return nil
+	}
+	return chainID
+}
+
+// transactionSigner provides the signer for transactions
+func transactionSigner() types.Signer {
+	if noReplayProtection {
+		return types.HomesteadSigner{}
+	}
+	return types.NewEIP155Signer(chainID)
+}
+
+// keySigner generates a signer using a private key
+func keySigner(key *ecdsa.PrivateKey) bind.SignerFn {
+	return func(_ types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != crypto.PubkeyToAddress(key.PublicKey) {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		return types.SignTx(tx, transactionSigner(), key)
+	}
+}
+
 func obtainWalletAndAccount(address common.Address) (wallet accounts.Wallet, account *accounts.Account, err error) {
 	wallet, err = cli.ObtainWallet(chainID, address)
 	if err == nil {
@@ -349,7 +665,26 @@ func obtainWalletAndAccount(address common.Address) (wallet accounts.Wallet, acc
 }
 
 func signTransaction(signer common.Address, tx *types.Transaction) (signedTx *types.Transaction, err error) {
-	if viper.GetString("passphrase") != "" {
+	if viper.GetBool("ledger") {
+		if err = obtainLedgerAccount(); err != nil {
+			return
+		}
+		if account.Address != signer {
+			return nil, fmt.Errorf("Ledger account %s at %s does not match %s", account.Address.Hex(), viper.GetString("hd-path"), signer.Hex())
+		}
+		if !quiet {
+			fmt.Fprintln(os.Stderr, "Please confirm the transaction on the Ledger")
+		}
+		signedTx, err = wallet.SignTx(*account, tx, signingChainID())
+	} else if viper.GetString("keystore-file") != "" {
+		if err = obtainKeystoreKey(); err != nil {
+			return
+		}
+		if signer != keystoreKey.Address {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		signedTx, err = types.SignTx(tx, transactionSigner(), keystoreKey.PrivateKey)
+	} else if passphraseAvailable() {
 		if wallet == nil {
 			// Fetch the wallet and account for the sender
 			wallet, account, err = obtainWalletAndAccount(signer)
@@ -357,22 +692,141 @@ func signTransaction(signer common.Address, tx *types.Transaction) (signedTx *ty
 				return
 			}
 		}
-		signedTx, err = wallet.SignTxWithPassphrase(*account, viper.GetString("passphrase"), tx, chainID)
+		signedTx, err = wallet.SignTxWithPassphrase(*account, viper.GetString("passphrase"), tx, signingChainID())
 	} else if viper.GetString("privatekey") != "" {
 		key, err := crypto.HexToECDSA(viper.GetString("privatekey"))
-		cli.ErrCheck(err, quiet, "Invalid private key")
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid private key")
 		keyAddr := crypto.PubkeyToAddress(key.PublicKey)
 		if signer != keyAddr {
 			return nil, errors.New("not authorized to sign this account")
 		}
-		signedTx, err = types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+		signedTx, err = types.SignTx(tx, transactionSigner(), key)
 	}
 	return
 }
 
+// signHash signs a hash with the key for the given address, returning the
+// signature in [R || S || V] format where V is 0 or 1
+func signHash(signer common.Address, hash []byte) (signature []byte, err error) {
+	if viper.GetBool("ledger") {
+		err = errors.New("signing data is not supported with a Ledger")
+	} else if viper.GetString("keystore-file") != "" {
+		if err = obtainKeystoreKey(); err != nil {
+			return
+		}
+		if signer != keystoreKey.Address {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		return crypto.Sign(hash, keystoreKey.PrivateKey)
+	} else if passphraseAvailable() {
+		if wallet == nil {
+			// Fetch the wallet and account for the signer
+			wallet, account, err = obtainWalletAndAccount(signer)
+			if err != nil {
+				return
+			}
+		}
+		signature, err = wallet.SignHashWithPassphrase(*account, viper.GetString("passphrase"), hash)
+	} else if viper.GetString("privatekey") != "" {
+		key, err := crypto.HexToECDSA(viper.GetString("privatekey"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %v", err)
+		}
+		if signer != crypto.PubkeyToAddress(key.PublicKey) {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		return crypto.Sign(hash, key)
+	} else {
+		err = errors.New("no passphrase or private key supplied")
+	}
+	return
+}
+
+// obtainLedgerAccount obtains the Ledger wallet and account, if not already
+// obtained
+func obtainLedgerAccount() (err error) {
+	if wallet == nil {
+		wallet, account, err = cli.ObtainLedgerAccount(viper.GetString("hd-path"))
+	}
+	return
+}
+
+// passphraseAvailable returns true if a passphrase has been supplied.  If
+// there is neither a passphrase nor a private key then the user is prompted
+// for a passphrase.
+func passphraseAvailable() bool {
+	if viper.GetString("passphrase") == "" && viper.GetString("privatekey") == "" {
+		passphrase, err := cli.PromptPassphrase()
+		if err == nil {
+			viper.Set("passphrase", passphrase)
+		}
+	}
+	return viper.GetString("passphrase") != ""
+}
+
+// obtainKeystoreKey decrypts the key in the keystore file, if not already
+// decrypted
+func obtainKeystoreKey() (err error) {
+	if keystoreKey == nil {
+		if !passphraseAvailable() {
+			return errors.New("no passphrase supplied for keystore file")
+		}
+		keystoreKey, err = cli.ObtainKeystoreKey(viper.GetString("keystore-file"), viper.GetString("passphrase"))
+	}
+	return
+}
+
+// senderImplicit returns true if the address from which to send a
+// transaction can be obtained from the signing key source
+func senderImplicit() bool {
+	return viper.GetBool("ledger") || viper.GetString("keystore-file") != ""
+}
+
+// senderAddress obtains the address from which to send a transaction.  If
+// no address is supplied and a Ledger or keystore file is in use then this
+// is the address of the relevant account.
+func senderAddress(input string) (common.Address, error) {
+	if viper.GetString("keystore-file") != "" {
+		if err := obtainKeystoreKey(); err != nil {
+			return common.Address{}, err
+		}
+		if input == "" {
+			return keystoreKey.Address, nil
+		}
+	}
+	if input == "" {
+		if !viper.GetBool("ledger") {
+			return common.Address{}, errors.New("--from is required")
+		}
+		if err := obtainLedgerAccount(); err != nil {
+			return common.Address{}, err
+		}
+		return account.Address, nil
+	}
+	if offline && strings.HasSuffix(input, ".eth") {
+		return common.Address{}, errors.New("ENS names cannot be resolved when offline")
+	}
+	address, err := ens.Resolve(client, input)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if keystoreKey != nil && keystoreKey.Address != address {
+		return common.Address{}, fmt.Errorf("keystore file is for %s, not %s", keystoreKey.Address.Hex(), address.Hex())
+	}
+	return address, nil
+}
+
 func outputIf(condition bool, msg string) {
 	if condition {
-		fmt.Println(msg)
+		fmt.Fprintln(cli.Out, msg)
+	}
+}
+
+// diagnosticIf writes a diagnostic message, such as progress or verbose
+// detail, to stderr so that it does not mix with the command output
+func diagnosticIf(condition bool, msg string) {
+	if condition {
+		fmt.Fprintln(os.Stderr, msg)
 	}
 }
 
@@ -380,6 +834,54 @@ func localContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
 }
 
+// waitContext provides a context for waiting on the network, for example
+// for a transaction to be mined, which can take far longer than a request
+func waitContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), viper.GetDuration("wait-timeout"))
+}
+
+// transientError returns true if the error is one that might not occur if
+// the call is retried, such as a dropped connection or a rate limit.
+func transientError(err error) bool {
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return transientStatus(statusErr.statusCode)
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return transientRPCErrorCodes[rpcErr.ErrorCode()]
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Gateway errors can return non-JSON bodies with a success status
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// methodUnavailable returns true if the error shows that the node does not
+// provide the requested method, for example because its namespace is not
+// enabled
+func methodUnavailable(err error) bool {
+	if rpcErr, isRPCError := err.(rpc.Error); isRPCError && rpcErr.ErrorCode() == -32601 {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, unavailable := range []string{"method not found", "does not exist", "not available", "not supported"} {
+		if strings.Contains(msg, unavailable) {
+			return true
+		}
+	}
+	return false
+}
+
 func txFrom(tx *types.Transaction) (address common.Address, err error) {
 	V, _, _ := tx.RawSignatureValues()
 	signer := deriveSigner(V)
@@ -0,0 +1,146 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// sendState is the progress of a command that sends a number of
+// transactions, recorded in a state file so that an interrupted run can be
+// resumed without sending any transaction twice
+type sendState struct {
+	path string
+	// Input is the hash of the file that lists the transactions, so that
+	// a state file is not used with a different list
+	Input string `json:"input"`
+	// From is the address that sends the transactions
+	From string `json:"from"`
+	// Sent contains the transactions that have been sent, keyed by the item
+	// in the list that they were sent for
+	Sent map[string]*sendStateEntry `json:"sent"`
+}
+
+// sendStateEntry is a transaction recorded in a state file
+type sendStateEntry struct {
+	Nonce       uint64 `json:"nonce"`
+	Transaction string `json:"transaction"`
+	// replaced is set if the node does not know the transaction but its
+	// nonce has been used
+	replaced bool
+}
+
+// loadSendState loads the state file at the given path, or creates a new
+// state if the file does not exist.  The file must have been created for the
+// same sender and input.
+func loadSendState(path string, from common.Address, input []byte) (*sendState, error) {
+	state := &sendState{
+		path:  path,
+		Input: fmt.Sprintf("%x", sha256.Sum256(input)),
+		From:  from.Hex(),
+		Sent:  make(map[string]*sendStateEntry),
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing := &sendState{}
+	if err := json.Unmarshal(data, existing); err != nil {
+		return nil, fmt.Errorf("invalid state file: %v", err)
+	}
+	if existing.From != state.From {
+		return nil, fmt.Errorf("state file is for transactions from %s", existing.From)
+	}
+	if existing.Input != state.Input {
+		return nil, errors.New("state file is for a different list of transactions; use a new state file if the list has changed")
+	}
+	if existing.Sent != nil {
+		state.Sent = existing.Sent
+	}
+	return state, nil
+}
+
+// previouslySent returns the transaction recorded for an item, if it was
+// sent by an earlier run.  A transaction that the node does not know about
+// and whose nonce has not been used was dropped by the network, so is
+// removed from the state so that it is sent again.
+func (s *sendState) previouslySent(key string) (*sendStateEntry, error) {
+	entry, exists := s.Sent[key]
+	if !exists {
+		return nil, nil
+	}
+
+	ctx, cancel := localContext()
+	defer cancel()
+	var tx json.RawMessage
+	err := rpcClient.CallContext(ctx, &tx, "eth_getTransactionByHash", common.HexToHash(entry.Transaction))
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain transaction %s: %v", entry.Transaction, err)
+	}
+	if len(tx) > 0 && string(tx) != "null" {
+		return entry, nil
+	}
+
+	nonce, err := client.PendingNonceAt(ctx, common.HexToAddress(s.From))
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain nonce: %v", err)
+	}
+	if nonce > entry.Nonce {
+		// The nonce has been used, most likely by a replacement of the
+		// transaction
+		diagnosticIf(verbose, fmt.Sprintf("Transaction %s is unknown but its nonce %d has been used", entry.Transaction, entry.Nonce))
+		entry.replaced = true
+		return entry, nil
+	}
+	diagnosticIf(verbose, fmt.Sprintf("Transaction %s was dropped and will be sent again", entry.Transaction))
+	delete(s.Sent, key)
+	return nil, nil
+}
+
+// record records a transaction sent for an item, writing the state file.
+// The file is replaced rather than updated in place so that an interruption
+// cannot leave it incomplete.
+func (s *sendState) record(key string, nonce uint64, txHash common.Hash) error {
+	s.Sent[key] = &sendStateEntry{Nonce: nonce, Transaction: txHash.Hex()}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFile, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	return os.Rename(tmpFile.Name(), s.path)
+}
@@ -0,0 +1,92 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/util/eip712"
+)
+
+var signatureMessage string
+var signatureType string
+var signatureDataFile string
+
+// signatureCmd represents the signature command
+var signatureCmd = &cobra.Command{
+	Use:     "signature",
+	Aliases: []string{"sig"},
+	Short:   "Manage signatures",
+	Long:    `Sign messages and verify signatures.`,
+}
+
+func init() {
+	RootCmd.AddCommand(signatureCmd)
+}
+
+func signatureFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&signatureMessage, "message", "", "Message (as a string, or as a hex string if prefixed with 0x)")
+	cmd.Flags().StringVar(&signatureType, "type", "personal", "Type of signature (raw, personal or eip712)")
+	cmd.Flags().StringVar(&signatureDataFile, "data-file", "", "File containing EIP-712 typed data as JSON")
+}
+
+// signatureMessageBytes obtains the bytes of the message.  Messages prefixed
+// with 0x that are valid hex are decoded, otherwise the message is used as-is
+func signatureMessageBytes(message string) []byte {
+	if strings.HasPrefix(message, "0x") {
+		data, err := hex.DecodeString(message[2:])
+		if err == nil {
+			return data
+		}
+	}
+	return []byte(message)
+}
+
+// signatureHash obtains the hash to be signed for the supplied message
+// according to the signature type
+func signatureHash() ([]byte, error) {
+	switch signatureType {
+	case "raw":
+		if signatureMessage == "" {
+			return nil, fmt.Errorf("--message is required")
+		}
+		return crypto.Keccak256(signatureMessageBytes(signatureMessage)), nil
+	case "personal":
+		if signatureMessage == "" {
+			return nil, fmt.Errorf("--message is required")
+		}
+		message := signatureMessageBytes(signatureMessage)
+		return crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))), message), nil
+	case "eip712":
+		if signatureDataFile == "" {
+			return nil, fmt.Errorf("--data-file is required")
+		}
+		data, err := ioutil.ReadFile(signatureDataFile)
+		if err != nil {
+			return nil, err
+		}
+		typedData, err := eip712.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid typed data: %v", err)
+		}
+		return typedData.Hash()
+	default:
+		return nil, fmt.Errorf("unknown signature type %s", signatureType)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var signatureSignFromAddress string
+
+// signatureSignCmd represents the signature sign command
+var signatureSignCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Sign a message",
+	Long: `Sign a message.  For example:
+
+    ethereal signature sign --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --message="hello" --passphrase=secret
+
+The type of signature is selected with --type:
+
+  - raw signs the keccak256 hash of the message
+  - personal (the default) signs the message with the "\x19Ethereum Signed Message" prefix, as per personal_sign and eth_sign
+  - eip712 signs the typed data supplied as JSON in the file given by --data-file
+
+The signature is displayed in hex, with the V value as 27 or 28.  In verbose mode the R, S and V values are also displayed.
+
+In quiet mode this will return 0 if the message is signed, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(signatureSignFromAddress != "", quiet, cli.ExitUsage, "--from is required")
+		fromAddress, err := ens.Resolve(client, signatureSignFromAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", signatureSignFromAddress))
+
+		hash, err := signatureHash()
+		cli.ErrCheck(err, quiet, "Failed to obtain hash to sign")
+		diagnosticIf(verbose, fmt.Sprintf("Hash:\t0x%x", hash))
+
+		signature, err := signHash(fromAddress, hash)
+		cli.ErrCheck(err, quiet, "Failed to sign message")
+		// Convert V to the commonly-used form
+		signature[64] += 27
+
+		if quiet {
+			os.Exit(0)
+		}
+
+		diagnosticIf(verbose, fmt.Sprintf("R:\t0x%x\nS:\t0x%x\nV:\t%d", signature[:32], signature[32:64], signature[64]))
+		fmt.Fprintf(cli.Out, "0x%x\n", signature)
+	},
+}
+
+func init() {
+	signatureCmd.AddCommand(signatureSignCmd)
+	signatureFlags(signatureSignCmd)
+	signatureSignCmd.Flags().StringVar(&signatureSignFromAddress, "from", "", "Address with which to sign the message")
+	signatureSignCmd.Flags().String("passphrase", "", "passphrase for the address with which to sign the message")
+	signatureSignCmd.Flags().String("passphrase-file", "", "file containing the passphrase for the address with which to sign the message on its first line")
+	signatureSignCmd.Flags().String("privatekey", "", "private key for the address with which to sign the message")
+}
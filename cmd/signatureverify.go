@@ -0,0 +1,96 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var signatureVerifySignature string
+var signatureVerifyAddress string
+
+// signatureVerifyCmd represents the signature verify command
+var signatureVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a signature",
+	Long: `Verify that a signature was created by an address.  For example:
+
+    ethereal signature verify --message="hello" --signature=0xe5dd...201b --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+
+The type of signature is selected with --type, and has the same options as "signature sign".  If --address is not supplied then the address that created the signature is displayed.
+
+In quiet mode this will return 0 if the signature was created by the address (or, if no address is supplied, the signer can be recovered), otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(signatureVerifySignature != "", quiet, cli.ExitUsage, "--signature is required")
+		signature, err := hex.DecodeString(strings.TrimPrefix(signatureVerifySignature, "0x"))
+		cli.ErrCheck(err, quiet, "Failed to decode signature")
+		cli.Assert(len(signature) == 65, quiet, "Signature must be 65 bytes")
+		// Convert V to the form used internally
+		if signature[64] >= 27 {
+			signature[64] -= 27
+		}
+		cli.AssertCode(signature[64] <= 1, quiet, cli.ExitUsage, "Invalid V value in signature")
+
+		hash, err := signatureHash()
+		cli.ErrCheck(err, quiet, "Failed to obtain hash to verify")
+		diagnosticIf(verbose, fmt.Sprintf("Hash:\t0x%x", hash))
+
+		pubKey, err := crypto.SigToPub(hash, signature)
+		cli.ErrCheck(err, quiet, "Failed to recover signer")
+		signer := crypto.PubkeyToAddress(*pubKey)
+
+		if verbose && !offline {
+			name, err := ens.ReverseResolve(client, &signer)
+			if err == nil && name != "" {
+				fmt.Fprintf(cli.Out, "Signer:\t%s (%s)\n", signer.Hex(), name)
+			} else {
+				fmt.Fprintf(cli.Out, "Signer:\t%s\n", signer.Hex())
+			}
+		}
+
+		if signatureVerifyAddress == "" {
+			if !quiet && !verbose {
+				fmt.Fprintln(cli.Out, signer.Hex())
+			}
+			os.Exit(0)
+		}
+
+		address, err := ens.Resolve(client, signatureVerifyAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve address %s", signatureVerifyAddress))
+		if signer != address {
+			if !quiet {
+				fmt.Fprintln(cli.Out, "Signature not verified")
+			}
+			os.Exit(1)
+		}
+		if !quiet {
+			fmt.Fprintln(cli.Out, "Signature verified")
+		}
+	},
+}
+
+func init() {
+	signatureCmd.AddCommand(signatureVerifyCmd)
+	signatureFlags(signatureVerifyCmd)
+	signatureVerifyCmd.Flags().StringVar(&signatureVerifySignature, "signature", "", "Signature (as a hex string)")
+	signatureVerifyCmd.Flags().StringVar(&signatureVerifyAddress, "address", "", "Address expected to have created the signature")
+}
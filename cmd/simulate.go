@@ -0,0 +1,130 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// simulateTransaction runs a transaction against the latest state with
+// eth_call, returning an error if it would fail
+func simulateTransaction(from common.Address, tx *types.Transaction) error {
+	msg := ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	var result []byte
+	var err error
+	if stateOverridesSupplied() {
+		overrides, overridesErr := stateOverrides()
+		if overridesErr != nil {
+			return overridesErr
+		}
+		result, err = stateOverrideCall(ctx, msg, nil, false, overrides)
+	} else {
+		result, err = client.CallContract(ctx, msg, nil)
+	}
+	if reason, reverted := callRevertReason(result, err); reverted {
+		return fmt.Errorf("transaction would revert: %s", reason)
+	}
+	if err != nil {
+		return fmt.Errorf("transaction would fail: %v", err)
+	}
+	return nil
+}
+
+// callRevertReason obtains the revert reason from the result of eth_call.
+// Depending on the node the Error(string) payload is either returned as the
+// result or as the data of the error.
+func callRevertReason(result []byte, err error) (reason string, reverted bool) {
+	if err == nil {
+		return contractRevertReason(result)
+	}
+	// The RPC error type is not exported, so access its data by reflection
+	value := reflect.ValueOf(err)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return "", false
+	}
+	field := value.FieldByName("Data")
+	if !field.IsValid() || !field.CanInterface() {
+		return "", false
+	}
+	data, isString := field.Interface().(string)
+	if !isString {
+		return "", false
+	}
+	payload, decodeErr := hexutil.Decode(data)
+	if decodeErr != nil {
+		return "", false
+	}
+	return contractRevertReason(payload)
+}
+
+// simulationRequired returns true if a transaction should be simulated
+// before it is sent.  Simulation is carried out for contract interactions
+// unless disabled with --simulate=false.
+func simulationRequired(tx *types.Transaction) bool {
+	if offline || dryRun || !viper.GetBool("simulate") {
+		return false
+	}
+	if len(tx.Data()) > 0 || tx.To() == nil {
+		return true
+	}
+	// A plain transfer only needs simulating if it is to a contract
+	ctx, cancel := localContext()
+	defer cancel()
+	code, err := client.CodeAt(ctx, *tx.To(), nil)
+	return err != nil || len(code) > 0
+}
+
+// preflightTransaction simulates a transaction if required, returning an
+// error if it would fail
+func preflightTransaction(from common.Address, tx *types.Transaction) error {
+	if !simulationRequired(tx) {
+		return nil
+	}
+	diagnosticIf(verbose, "Simulating transaction")
+	if err := simulateTransaction(from, tx); err != nil {
+		return fmt.Errorf("%v (use --simulate=false to send regardless)", err)
+	}
+	return nil
+}
+
+// preflightSigner wraps a signer so that the transaction is simulated before
+// it is signed
+func preflightSigner(signer bind.SignerFn) bind.SignerFn {
+	return func(txSigner types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if err := preflightTransaction(address, tx); err != nil {
+			return nil, err
+		}
+		return signer(txSigner, address, tx)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+// stateOverride is the state of an account to use in place of its actual
+// state for eth_call, in the format accepted by geth and compatible nodes
+type stateOverride struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes              `json:"code,omitempty"`
+	State     map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// addStateOverrideFlags adds flags for commands that make calls which can be
+// made against overridden state
+func addStateOverrideFlags(cmd *cobra.Command) {
+	cmd.Flags().String("state-override", "", "State override object for simulated calls as JSON, or path to a file containing it, mapping addresses to their balance, nonce, code, state or stateDiff")
+	cmd.Flags().StringSlice("override-balance", nil, "Balance of an account for simulated calls, as ADDRESS=AMOUNT (e.g. 0x5FfC014343cd971B7eb70732021E26C35B744cc4=10ether).  Can be supplied multiple times")
+	cmd.Flags().StringSlice("override-code", nil, "Code of an account for simulated calls, as ADDRESS=0xCODE.  Can be supplied multiple times")
+}
+
+// bindStateOverrideFlags binds the state override flags of a command
+func bindStateOverrideFlags(cmd *cobra.Command) {
+	viper.BindPFlag("state-override", cmd.Flags().Lookup("state-override"))
+	viper.BindPFlag("override-balance", cmd.Flags().Lookup("override-balance"))
+	viper.BindPFlag("override-code", cmd.Flags().Lookup("override-code"))
+}
+
+// stateOverridesSupplied returns true if any state overrides were supplied
+func stateOverridesSupplied() bool {
+	return viper.GetString("state-override") != "" || len(viper.GetStringSlice("override-balance")) > 0 || len(viper.GetStringSlice("override-code")) > 0
+}
+
+// stateOverrides obtains the state overrides supplied on the command line.
+// Balances and code supplied with their own flags take precedence over those
+// in the state override object.
+func stateOverrides() (map[common.Address]*stateOverride, error) {
+	overrides := make(map[common.Address]*stateOverride)
+	if input := strings.TrimSpace(viper.GetString("state-override")); input != "" {
+		data := []byte(input)
+		if !strings.HasPrefix(input, "{") {
+			// Input is a path
+			var err error
+			data, err = ioutil.ReadFile(input)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read state override file: %v", err)
+			}
+		}
+		if err := json.Unmarshal(data, &overrides); err != nil {
+			return nil, fmt.Errorf("invalid state override: %v", err)
+		}
+	}
+
+	for _, input := range viper.GetStringSlice("override-balance") {
+		address, value, err := stateOverrideParse(input)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := etherutils.StringToWei(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance %s: %v", value, err)
+		}
+		stateOverrideFor(overrides, address).Balance = (*hexutil.Big)(balance)
+	}
+
+	for _, input := range viper.GetStringSlice("override-code") {
+		address, value, err := stateOverrideParse(input)
+		if err != nil {
+			return nil, err
+		}
+		code, err := hexutil.Decode(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid code for %s: %v", address.Hex(), err)
+		}
+		stateOverrideFor(overrides, address).Code = (*hexutil.Bytes)(&code)
+	}
+
+	return overrides, nil
+}
+
+// stateOverrideParse parses an override of the form ADDRESS=VALUE
+func stateOverrideParse(input string) (common.Address, string, error) {
+	parts := strings.SplitN(input, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return common.Address{}, "", fmt.Errorf("invalid override %s; must be ADDRESS=VALUE", input)
+	}
+	address, err := ens.Resolve(client, strings.TrimSpace(parts[0]))
+	if err != nil {
+		return common.Address{}, "", fmt.Errorf("failed to resolve address %s: %v", parts[0], err)
+	}
+	return address, strings.TrimSpace(parts[1]), nil
+}
+
+// stateOverrideFor obtains the override for an address, creating it if
+// required
+func stateOverrideFor(overrides map[common.Address]*stateOverride, address common.Address) *stateOverride {
+	override, exists := overrides[address]
+	if !exists || override == nil {
+		override = &stateOverride{}
+		overrides[address] = override
+	}
+	return override
+}
+
+// stateOverrideCall makes a call with eth_call against overridden state.
+// The block is either a number, or nil for the latest block, or pending.
+func stateOverrideCall(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int, pending bool, overrides map[common.Address]*stateOverride) ([]byte, error) {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	block := "latest"
+	if pending {
+		block = "pending"
+	} else if blockNumber != nil {
+		block = hexutil.EncodeBig(blockNumber)
+	}
+
+	var result hexutil.Bytes
+	err := rpcClient.CallContext(ctx, &result, "eth_call", arg, block, overrides)
+	if err != nil && stateOverrideUnsupported(err) {
+		return nil, fmt.Errorf("the node does not support state overrides (%v)", err)
+	}
+	return result, err
+}
+
+// stateOverrideUnsupported returns true if an error shows that the node does
+// not accept state overrides for eth_call
+func stateOverrideUnsupported(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, unsupported := range []string{"too many arguments", "invalid params", "invalid number of params", "expected 2", "state override"} {
+		if strings.Contains(msg, unsupported) {
+			return true
+		}
+	}
+	return false
+}
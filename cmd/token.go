@@ -15,10 +15,12 @@ package cmd
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
 	"github.com/wealdtech/ethereal/util/contracts"
 )
@@ -34,6 +36,9 @@ var tokenCmd = &cobra.Command{
 
 var unknownAddress = common.HexToAddress("00")
 
+// tokenMaxAmount is the maximum value of a uint256, used for unlimited approvals
+var tokenMaxAmount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
 func tokenContractAddress(input string) (address common.Address, err error) {
 	// Guess 1 - might be an ENS name or a hex string
 	address, err = ens.Resolve(client, input)
@@ -57,6 +62,27 @@ func tokenContract(input string) (contract *contracts.ERC20, err error) {
 	return
 }
 
+// tokenDecimals obtains the number of decimals for a token.  Some tokens do
+// not implement decimals(), in which case the common value of 18 is assumed
+func tokenDecimals(token *contracts.ERC20) uint8 {
+	decimals, err := token.Decimals(nil)
+	if err != nil {
+		cli.Warn(quiet, "Failed to obtain token decimals; assuming 18")
+		decimals = 18
+	}
+	return decimals
+}
+
+// tokenSymbol obtains the symbol for a token, or an empty string if the
+// token does not have a symbol
+func tokenSymbol(token *contracts.ERC20) string {
+	symbol, err := token.Symbol(nil)
+	if err != nil {
+		return ""
+	}
+	return symbol
+}
+
 func init() {
 	RootCmd.AddCommand(tokenCmd)
 }
@@ -36,22 +36,23 @@ var tokenAllowanceCmd = &cobra.Command{
 
     ethereal token allowance --token=omg --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --spender=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d
 
+The holder can also be supplied as --owner.  An allowance of the maximum possible amount is displayed as "max".
+
 In quiet mode this will return 0 if the allowance is greater than 0, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(tokenAllowanceHolderAddress != "", quiet, "--holder is required")
+		cli.AssertCode(tokenAllowanceHolderAddress != "", quiet, cli.ExitUsage, "--holder is required")
 		holderAddress, err := ens.Resolve(client, tokenAllowanceHolderAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", tokenAllowanceHolderAddress))
 
-		cli.Assert(tokenAllowanceSpenderAddress != "", quiet, "--spender is required")
+		cli.AssertCode(tokenAllowanceSpenderAddress != "", quiet, cli.ExitUsage, "--spender is required")
 		spenderAddress, err := ens.Resolve(client, tokenAllowanceSpenderAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain spender address")
 
-		cli.Assert(tokenStr != "", quiet, "--token is required")
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
 		token, err := tokenContract(tokenStr)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
-		decimals, err := token.Decimals(nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
+		decimals := tokenDecimals(token)
 
 		allowance, err := token.Allowance(nil, holderAddress, spenderAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain allowance")
@@ -65,9 +66,11 @@ In quiet mode this will return 0 if the allowance is greater than 0, otherwise 1
 		}
 
 		if tokenAllowanceRaw {
-			fmt.Printf("%s\n", allowance.String())
+			fmt.Fprintf(cli.Out, "%s\n", allowance.String())
+		} else if allowance.Cmp(tokenMaxAmount) == 0 {
+			fmt.Fprintln(cli.Out, "max")
 		} else {
-			fmt.Printf("%s\n", util.TokenValueToString(allowance, decimals, false))
+			fmt.Fprintf(cli.Out, "%s\n", util.TokenValueToString(allowance, decimals, false))
 		}
 	},
 }
@@ -77,5 +80,6 @@ func init() {
 	tokenFlags(tokenAllowanceCmd)
 	tokenAllowanceCmd.Flags().BoolVar(&tokenAllowanceRaw, "raw", false, "Display raw output (no decimals)")
 	tokenAllowanceCmd.Flags().StringVar(&tokenAllowanceHolderAddress, "holder", "", "Address that holds tokens")
+	tokenAllowanceCmd.Flags().StringVar(&tokenAllowanceHolderAddress, "owner", "", "Address that holds tokens (alternative to --holder)")
 	tokenAllowanceCmd.Flags().StringVar(&tokenAllowanceSpenderAddress, "spender", "", "Address that can spend tokens")
 }
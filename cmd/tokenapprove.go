@@ -40,33 +40,41 @@ var tokenApproveCmd = &cobra.Command{
 
     ethereal token approve --token=omg --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --spender=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --amount=10 --passphrase=secret
 
+An amount of "max" approves the maximum possible amount.  The holder can also be supplied as --from.
+
 In quiet mode this will return 0 if the approval transaction is successfully sent, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
-		cli.Assert(tokenApproveHolderAddress != "", quiet, "--holder is required")
+		cli.AssertCode(tokenApproveHolderAddress != "", quiet, cli.ExitUsage, "--holder is required")
 		holderAddress, err := ens.Resolve(client, tokenApproveHolderAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", tokenApproveHolderAddress))
 
-		cli.Assert(tokenApproveSpenderAddress != "", quiet, "--spender is required")
+		cli.AssertCode(tokenApproveSpenderAddress != "", quiet, cli.ExitUsage, "--spender is required")
 		spenderAddress, err := ens.Resolve(client, tokenApproveSpenderAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve spender address %s", tokenApproveSpenderAddress))
 
-		cli.Assert(tokenStr != "", quiet, "--token is required")
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
 		token, err := tokenContract(tokenStr)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
-		decimals, err := token.Decimals(nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
+		decimals := tokenDecimals(token)
 
-		cli.Assert(tokenApproveAmount != "", quiet, "--amount is required")
-		amount, err := util.StringToTokenValue(tokenApproveAmount, decimals)
-		cli.ErrCheck(err, quiet, "Invalid amount")
+		cli.AssertCode(tokenApproveAmount != "", quiet, cli.ExitUsage, "--amount is required")
+		var amount *big.Int
+		if tokenApproveAmount == "max" {
+			amount = tokenMaxAmount
+		} else {
+			amount, err = util.StringToTokenValue(tokenApproveAmount, decimals)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid amount")
+		}
 
 		allowance, err := token.Allowance(nil, holderAddress, spenderAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain allowance")
 
-		cli.Assert(allowance.Cmp(big.NewInt(0)) == 0 || amount.Cmp(big.NewInt(0)) == 0, quiet, fmt.Sprintf("Allowance is currently %s; it must be set to zero before being changed to avoid a potential double spend", util.TokenValueToString(allowance, decimals, false)))
+		if allowance.Cmp(big.NewInt(0)) != 0 && amount.Cmp(big.NewInt(0)) != 0 {
+			cli.Warn(quiet, fmt.Sprintf("Allowance is currently %s; changing it without first setting it to zero allows the spender to potentially spend both the old and new allowances", util.TokenValueToString(allowance, decimals, false)))
+		}
 
 		opts, err := generateTxOpts(holderAddress)
 		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
@@ -78,7 +86,7 @@ In quiet mode this will return 0 if the approval transaction is successfully sen
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			log.WithFields(log.Fields{
@@ -98,7 +106,7 @@ In quiet mode this will return 0 if the approval transaction is successfully sen
 				os.Exit(0)
 			}
 
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
@@ -106,8 +114,9 @@ In quiet mode this will return 0 if the approval transaction is successfully sen
 func init() {
 	tokenCmd.AddCommand(tokenApproveCmd)
 	tokenFlags(tokenApproveCmd)
-	tokenApproveCmd.Flags().StringVar(&tokenApproveAmount, "amount", "", "Amount to approve")
+	tokenApproveCmd.Flags().StringVar(&tokenApproveAmount, "amount", "", "Amount to approve (\"max\" for the maximum possible amount)")
 	tokenApproveCmd.Flags().StringVar(&tokenApproveHolderAddress, "holder", "", "Address that holds tokens")
+	tokenApproveCmd.Flags().StringVar(&tokenApproveHolderAddress, "from", "", "Address that holds tokens (alternative to --holder)")
 	tokenApproveCmd.Flags().StringVar(&tokenApproveSpenderAddress, "spender", "", "Address that can spend tokens")
 	addTransactionFlags(tokenApproveCmd, "the address from which to approve tokens")
 }
@@ -18,13 +18,16 @@ import (
 	"math/big"
 	"os"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/ens"
 	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
+	"github.com/wealdtech/ethereal/util/multicall"
 )
 
-var tokenBalanceHolderAddress string
+var tokenBalanceHolderAddresses []string
 var tokenBalanceRaw bool
 
 // tokenBalanceCmd represents the ether balance command
@@ -35,41 +38,156 @@ var tokenBalanceCmd = &cobra.Command{
 
     ethereal token balance --token=omg --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
-In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.`,
+Multiple holders can be supplied, in which case the balance for each is printed on its own line.  Use --format=csv to output a row for each holder in CSV format.  If the token does not provide its decimals then balances are printed in raw units.  Balances are obtained concurrently, and the number of concurrent lookups can be changed with --concurrency.  When multiple holders are supplied and the chain has a Multicall3 contract the balances are obtained in batches through it, which is much faster against remote nodes; --multicall-address changes the address of the contract and --multicall=false disables it.  By default the command stops at the first failed lookup; --continue-on-error displays failures and continues.
+
+In quiet mode this will return 0 if the balance of each holder is greater than 0, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(tokenBalanceHolderAddress != "", quiet, "--holder is required")
-		address, err := ens.Resolve(client, tokenBalanceHolderAddress)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", tokenBalanceHolderAddress))
+		cli.AssertCode(len(tokenBalanceHolderAddresses) > 0, quiet, cli.ExitUsage, "--holder is required")
 
-		cli.Assert(tokenStr != "", quiet, "--token is required")
-		token, err := tokenContract(tokenStr)
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
+		tokenAddress, err := tokenContractAddress(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+		token, err := contracts.NewERC20(tokenAddress, client)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
+		raw := tokenBalanceRaw
 		decimals, err := token.Decimals(nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
+		if err != nil {
+			cli.Warn(quiet || raw, "Failed to obtain token decimals; displaying raw units")
+			raw = true
+		}
+		symbol := tokenSymbol(token)
 
-		balance, err := token.BalanceOf(nil, address)
-		cli.ErrCheck(err, quiet, "Failed to obtain token balance")
+		csv := csvFormat()
+		table := newCSVTable("holder", "name", "balance_raw", "balance", "symbol", "error")
+		results, errs := concurrentLookups(len(tokenBalanceHolderAddresses), func(i int) (interface{}, error) {
+			address, err := ens.Resolve(client, tokenBalanceHolderAddresses[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve holder address: %v", err)
+			}
+			return &tokenBalanceResult{address: address}, nil
+		})
+		if len(tokenBalanceHolderAddresses) > 1 {
+			tokenBalanceMulticall(tokenAddress, results)
+		}
+		results, errs = concurrentLookups(len(tokenBalanceHolderAddresses), func(i int) (interface{}, error) {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			result := results[i].(*tokenBalanceResult)
+			if result.balance == nil {
+				balance, err := token.BalanceOf(nil, result.address)
+				if err != nil {
+					return nil, fmt.Errorf("failed to obtain token balance: %v", err)
+				}
+				result.balance = balance
+			}
+			return result, nil
+		})
 
-		if quiet {
+		failed := false
+		allNonZero := true
+		for i, holder := range tokenBalanceHolderAddresses {
+			if errs[i] != nil {
+				failed = true
+				allNonZero = false
+				if !continueOnError {
+					cli.ErrCheck(errs[i], quiet, fmt.Sprintf("Failed to obtain token balance for %s", holder))
+				}
+				if csv {
+					table.add(holder, "", "", "", "", errs[i].Error())
+				} else if !quiet {
+					fmt.Fprintf(cli.Out, "%s\tError: %v\n", holder, errs[i])
+				}
+				continue
+			}
+			result := results[i].(*tokenBalanceResult)
+			balance := result.balance
 			if balance.Cmp(big.NewInt(0)) == 0 {
-				os.Exit(1)
+				allNonZero = false
+			}
+			if quiet {
+				continue
+			}
+
+			if csv {
+				formatted := ""
+				if !raw {
+					formatted = util.TokenValueToString(balance, decimals, false)
+				}
+				table.add(result.address.Hex(), etherBalanceName(holder, result.address), balance.String(), formatted, symbol)
+				continue
+			}
+
+			var balanceStr string
+			if raw {
+				balanceStr = balance.String()
 			} else {
-				os.Exit(0)
+				balanceStr = util.TokenValueToString(balance, decimals, false)
+				if symbol != "" {
+					balanceStr = fmt.Sprintf("%s %s", balanceStr, symbol)
+				}
+			}
+			if len(tokenBalanceHolderAddresses) == 1 {
+				fmt.Fprintf(cli.Out, "%s\n", balanceStr)
+			} else {
+				fmt.Fprintf(cli.Out, "%s\t%s\n", holder, balanceStr)
 			}
 		}
 
-		if tokenBalanceRaw {
-			fmt.Printf("%s\n", balance.String())
-		} else {
-			fmt.Printf("%s\n", util.TokenValueToString(balance, decimals, false))
+		if quiet {
+			if allNonZero {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+		if csv {
+			table.write()
+		}
+		if failed {
+			os.Exit(1)
 		}
 	},
 }
 
+// tokenBalanceResult is the result of a token balance lookup
+type tokenBalanceResult struct {
+	address common.Address
+	balance *big.Int
+}
+
+// tokenBalanceMulticall obtains the balances of resolved holders through the
+// multicall contract, if it is available.  Balances that cannot be obtained
+// this way are left for individual lookups.
+func tokenBalanceMulticall(tokenAddress common.Address, results []interface{}) {
+	address := multicallAddress(nil)
+	if address == nil {
+		return
+	}
+	pending := make([]*tokenBalanceResult, 0, len(results))
+	calls := make([]multicall.Call, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			pending = append(pending, result.(*tokenBalanceResult))
+			calls = append(calls, multicall.BalanceOfCall(tokenAddress, result.(*tokenBalanceResult).address))
+		}
+	}
+	balances, err := multicallUint256s(*address, calls, nil)
+	if err != nil {
+		cli.Warn(quiet || !verbose, fmt.Sprintf("Multicall failed: %v; using individual calls", err))
+		return
+	}
+	for i, balance := range balances {
+		pending[i].balance = balance
+	}
+}
+
 func init() {
 	tokenFlags(tokenBalanceCmd)
 	tokenCmd.AddCommand(tokenBalanceCmd)
 	tokenBalanceCmd.Flags().BoolVar(&tokenBalanceRaw, "raw", false, "Display raw output (no decimals)")
-	tokenBalanceCmd.Flags().StringVar(&tokenBalanceHolderAddress, "holder", "", "Holder of tokens")
+	addConcurrencyFlags(tokenBalanceCmd)
+	addMulticallFlags(tokenBalanceCmd)
+	addFormatFlag(tokenBalanceCmd)
+	tokenBalanceCmd.Flags().StringSliceVar(&tokenBalanceHolderAddresses, "holder", nil, "Holder of tokens (can be supplied multiple times)")
 }
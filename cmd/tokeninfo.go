@@ -14,14 +14,32 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
 	"github.com/wealdtech/ethereal/util"
 )
 
+var tokenInfoJSON bool
+
+// tokenInfo contains the information obtained about a token
+type tokenInfo struct {
+	Address        string   `json:"address"`
+	ENSName        string   `json:"ensName,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Symbol         string   `json:"symbol,omitempty"`
+	Decimals       *uint8   `json:"decimals,omitempty"`
+	TotalSupply    string   `json:"totalSupply,omitempty"`
+	TotalSupplyRaw string   `json:"totalSupplyRaw,omitempty"`
+	Missing        []string `json:"missing,omitempty"`
+}
+
 // tokenInfoCmd represents the token info command
 var tokenInfoCmd = &cobra.Command{
 	Use:   "info",
@@ -30,41 +48,96 @@ var tokenInfoCmd = &cobra.Command{
 
     ethereal token info --token=omg
 
-In quiet mode this will return 0 if the token exists, otherwise 1.`,
+Any standard ERC-20 functions that the token does not implement are listed as missing.  Use --json to output the information as JSON.
+
+In quiet mode this will return 0 if the token implements the required ERC-20 functions, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(tokenStr != "", quiet, "--token is required")
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
+		address, err := tokenContractAddress(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract address")
 		token, err := tokenContract(tokenStr)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
-		if quiet {
-			os.Exit(0)
+		info := &tokenInfo{
+			Address: address.Hex(),
 		}
+		info.ENSName, _ = ens.ReverseResolve(client, &address)
 
-		name, err := token.Name(nil)
-		if err == nil {
-			fmt.Printf("Name:\t\t%s\n", name)
+		// Required functions
+		compliant := true
+		totalSupply, err := token.TotalSupply(nil)
+		if err != nil {
+			info.Missing = append(info.Missing, "totalSupply()")
+			compliant = false
+		}
+		_, err = token.BalanceOf(nil, common.Address{})
+		if err != nil {
+			info.Missing = append(info.Missing, "balanceOf(address)")
+			compliant = false
 		}
 
-		if verbose {
-			address, err := tokenContractAddress(tokenStr)
-			if err == nil {
-				fmt.Printf("Address:\t%s\n", address.Hex())
+		if quiet {
+			if compliant {
+				os.Exit(0)
 			}
+			os.Exit(1)
 		}
 
+		// Optional functions
+		name, err := token.Name(nil)
+		if err == nil {
+			info.Name = name
+		} else {
+			info.Missing = append(info.Missing, "name()")
+		}
 		symbol, err := token.Symbol(nil)
 		if err == nil {
-			fmt.Printf("Symbol:\t\t%s\n", symbol)
+			info.Symbol = symbol
+		} else {
+			info.Missing = append(info.Missing, "symbol()")
 		}
-
 		decimals, err := token.Decimals(nil)
 		if err == nil {
-			fmt.Printf("Decimals:\t%d\n", decimals)
+			info.Decimals = &decimals
+		} else {
+			info.Missing = append(info.Missing, "decimals()")
 		}
 
-		totalSupply, err := token.TotalSupply(nil)
-		if err == nil {
-			fmt.Printf("Total supply:\t%s\n", util.TokenValueToString(totalSupply, decimals, true))
+		if totalSupply != nil {
+			info.TotalSupplyRaw = totalSupply.String()
+			if info.Decimals != nil {
+				info.TotalSupply = util.TokenValueToString(totalSupply, decimals, true)
+			} else {
+				info.TotalSupply = totalSupply.String()
+			}
+		}
+
+		if tokenInfoJSON {
+			data, err := json.Marshal(info)
+			cli.ErrCheck(err, quiet, "Failed to generate JSON")
+			fmt.Fprintf(cli.Out, "%s\n", string(data))
+			os.Exit(0)
+		}
+
+		if info.Name != "" {
+			fmt.Fprintf(cli.Out, "Name:\t\t%s\n", info.Name)
+		}
+		if info.ENSName != "" {
+			fmt.Fprintf(cli.Out, "Address:\t%s (%s)\n", info.Address, info.ENSName)
+		} else {
+			fmt.Fprintf(cli.Out, "Address:\t%s\n", info.Address)
+		}
+		if info.Symbol != "" {
+			fmt.Fprintf(cli.Out, "Symbol:\t\t%s\n", info.Symbol)
+		}
+		if info.Decimals != nil {
+			fmt.Fprintf(cli.Out, "Decimals:\t%d\n", *info.Decimals)
+		}
+		if info.TotalSupply != "" {
+			fmt.Fprintf(cli.Out, "Total supply:\t%s\n", info.TotalSupply)
+		}
+		if len(info.Missing) > 0 {
+			fmt.Fprintf(cli.Out, "Missing:\t%s\n", strings.Join(info.Missing, ", "))
 		}
 	},
 }
@@ -72,4 +145,5 @@ In quiet mode this will return 0 if the token exists, otherwise 1.`,
 func init() {
 	tokenFlags(tokenInfoCmd)
 	tokenCmd.AddCommand(tokenInfoCmd)
+	tokenInfoCmd.Flags().BoolVar(&tokenInfoJSON, "json", false, "Output the information as JSON")
 }
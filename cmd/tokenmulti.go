@@ -0,0 +1,55 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/util/contracts"
+)
+
+// tokenMultiCmd represents the token multi command
+var tokenMultiCmd = &cobra.Command{
+	Use:   "multi",
+	Short: "Manage multi-tokens",
+	Long:  `Obtain balances of and transfer ERC-1155 multi-tokens.`,
+}
+
+// Obtain the multi-token contract given a string
+func tokenMultiContract(input string) (contract *contracts.ERC1155, err error) {
+	address, err := tokenContractAddress(input)
+	if err == nil {
+		contract, err = contracts.NewERC1155(address, client)
+	}
+	return
+}
+
+// tokenMultiID parses the ID of a token, in decimal or hex
+func tokenMultiID(input string) (*big.Int, error) {
+	id, success := new(big.Int).SetString(input, 0)
+	if !success || id.Sign() < 0 {
+		return nil, fmt.Errorf("invalid token ID %s", input)
+	}
+	return id, nil
+}
+
+func init() {
+	tokenCmd.AddCommand(tokenMultiCmd)
+}
+
+func tokenMultiFlags(cmd *cobra.Command) {
+	tokenFlags(cmd)
+}
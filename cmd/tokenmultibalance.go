@@ -0,0 +1,99 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var tokenMultiBalanceHolderAddress string
+var tokenMultiBalanceIDs []string
+
+// tokenMultiBalanceCmd represents the token multi balance command
+var tokenMultiBalanceCmd = &cobra.Command{
+	Use:   "balance",
+	Short: "Obtain the multi-token balance for an address",
+	Long: `Obtain the balance of an ERC-1155 multi-token for an address.  For example:
+
+    ethereal token multi balance --token=0x76BE3b62873462d2142405439777e971754E8E77 --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --id=10
+
+Multiple IDs can be supplied, separated by commas or by supplying --id multiple times, in which case the balances are obtained together with balanceOfBatch() and the balance for each ID is printed on its own line.
+
+In quiet mode this will return 0 if the balance for each ID is greater than 0, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(tokenMultiBalanceHolderAddress != "", quiet, cli.ExitUsage, "--holder is required")
+		holder, err := ens.Resolve(client, tokenMultiBalanceHolderAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", tokenMultiBalanceHolderAddress))
+
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
+		token, err := tokenMultiContract(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+
+		cli.AssertCode(len(tokenMultiBalanceIDs) > 0, quiet, cli.ExitUsage, "--id is required")
+		ids := make([]*big.Int, len(tokenMultiBalanceIDs))
+		for i := range tokenMultiBalanceIDs {
+			ids[i], err = tokenMultiID(tokenMultiBalanceIDs[i])
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid ID")
+		}
+
+		var balances []*big.Int
+		if len(ids) == 1 {
+			balance, err := token.BalanceOf(nil, holder, ids[0])
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain balance of token %s", tokenMultiBalanceIDs[0]))
+			balances = []*big.Int{balance}
+		} else {
+			holders := make([]common.Address, len(ids))
+			for i := range holders {
+				holders[i] = holder
+			}
+			balances, err = token.BalanceOfBatch(nil, holders, ids)
+			cli.ErrCheck(err, quiet, "Failed to obtain balances of tokens")
+			cli.Assert(len(balances) == len(ids), quiet, fmt.Sprintf("Token returned %d balances for %d IDs", len(balances), len(ids)))
+		}
+
+		allNonZero := true
+		for i, balance := range balances {
+			if balance.Sign() == 0 {
+				allNonZero = false
+			}
+			if quiet {
+				continue
+			}
+			if len(balances) == 1 {
+				fmt.Fprintln(cli.Out, balance.String())
+			} else {
+				fmt.Fprintf(cli.Out, "%v\t%v\n", ids[i], balance)
+			}
+		}
+		if !allNonZero {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+func init() {
+	tokenMultiCmd.AddCommand(tokenMultiBalanceCmd)
+	tokenMultiFlags(tokenMultiBalanceCmd)
+	tokenMultiBalanceCmd.Flags().StringVar(&tokenMultiBalanceHolderAddress, "holder", "", "Address of the holder of the tokens")
+	tokenMultiBalanceCmd.Flags().StringSliceVar(&tokenMultiBalanceIDs, "id", nil, "ID of the token.  Can be supplied multiple times")
+}
@@ -0,0 +1,125 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var tokenMultiTransferFromAddress string
+var tokenMultiTransferToAddress string
+var tokenMultiTransferID string
+var tokenMultiTransferAmount string
+var tokenMultiTransferData string
+var tokenMultiTransferForce bool
+
+// tokenMultiTransferCmd represents the token multi transfer command
+var tokenMultiTransferCmd = &cobra.Command{
+	Use:   "transfer",
+	Short: "Transfer multi-tokens to a given address",
+	Long: `Transfer an amount of an ERC-1155 multi-token from one address to another.  For example:
+
+    ethereal token multi transfer --token=0x76BE3b62873462d2142405439777e971754E8E77 --id=10 --amount=2 --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --passphrase=secret
+
+The transfer is carried out with safeTransferFrom(), passing the data supplied with --data to the recipient if it is a contract.  The amount is in the token's raw units.  The command checks that the from address holds enough of the token before sending the transaction; use --force to skip this check.
+
+In quiet mode this will return 0 if the transfer transaction is successfully sent, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(tokenMultiTransferFromAddress != "", quiet, cli.ExitUsage, "--from is required")
+		fromAddress, err := ens.Resolve(client, tokenMultiTransferFromAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", tokenMultiTransferFromAddress))
+
+		cli.AssertCode(tokenMultiTransferToAddress != "", quiet, cli.ExitUsage, "--to is required")
+		toAddress, err := ens.Resolve(client, tokenMultiTransferToAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", tokenMultiTransferToAddress))
+		cli.Assert(toAddress != ens.UnknownAddress, quiet, fmt.Sprintf("%s resolves to the zero address; refusing to send", tokenMultiTransferToAddress))
+
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
+		token, err := tokenMultiContract(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+
+		cli.AssertCode(tokenMultiTransferID != "", quiet, cli.ExitUsage, "--id is required")
+		id, err := tokenMultiID(tokenMultiTransferID)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid ID")
+
+		cli.AssertCode(tokenMultiTransferAmount != "", quiet, cli.ExitUsage, "--amount is required")
+		amount, success := new(big.Int).SetString(tokenMultiTransferAmount, 10)
+		cli.AssertCode(success && amount.Sign() > 0, quiet, cli.ExitUsage, fmt.Sprintf("Invalid amount %s", tokenMultiTransferAmount))
+
+		data, err := hex.DecodeString(strings.TrimPrefix(tokenMultiTransferData, "0x"))
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid data")
+
+		if !tokenMultiTransferForce && !offline {
+			balance, err := token.BalanceOf(nil, fromAddress, id)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain balance of token %s", tokenMultiTransferID))
+			cli.Assert(balance.Cmp(amount) >= 0, quiet, fmt.Sprintf("Balance of %v of token %s insufficient for transfer", balance, tokenMultiTransferID))
+		}
+
+		opts, err := generateTxOpts(fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+
+		signedTx, err := token.SafeTransferFrom(opts, fromAddress, toAddress, id, amount, data)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+		if offline {
+			if !quiet {
+				buf := new(bytes.Buffer)
+				signedTx.EncodeRLP(buf)
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
+			}
+		} else {
+			log.WithFields(log.Fields{
+				"group":         "token",
+				"command":       "multi transfer",
+				"token":         tokenStr,
+				"id":            id.String(),
+				"amount":        amount.String(),
+				"from":          fromAddress.Hex(),
+				"to":            toAddress.Hex(),
+				"networkid":     chainID,
+				"gas":           signedTx.Gas(),
+				"gasprice":      signedTx.GasPrice().String(),
+				"transactionid": signedTx.Hash().Hex(),
+			}).Info("success")
+
+			if quiet {
+				os.Exit(0)
+			}
+
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+		}
+	},
+}
+
+func init() {
+	tokenMultiCmd.AddCommand(tokenMultiTransferCmd)
+	tokenMultiFlags(tokenMultiTransferCmd)
+	tokenMultiTransferCmd.Flags().StringVar(&tokenMultiTransferFromAddress, "from", "", "Address from which to transfer the tokens")
+	tokenMultiTransferCmd.Flags().StringVar(&tokenMultiTransferToAddress, "to", "", "Address to which to transfer the tokens")
+	tokenMultiTransferCmd.Flags().StringVar(&tokenMultiTransferID, "id", "", "ID of the token")
+	tokenMultiTransferCmd.Flags().StringVar(&tokenMultiTransferAmount, "amount", "", "Amount of the token to transfer")
+	tokenMultiTransferCmd.Flags().StringVar(&tokenMultiTransferData, "data", "", "Data to pass to the recipient (as a hex string)")
+	tokenMultiTransferCmd.Flags().BoolVar(&tokenMultiTransferForce, "force", false, "Transfer without checking the balance of the from address")
+	addTransactionFlags(tokenMultiTransferCmd, "the address from which to transfer the tokens")
+}
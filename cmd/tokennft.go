@@ -0,0 +1,46 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/util/contracts"
+)
+
+var tokenNftID string
+
+// tokenNftCmd represents the token nft command
+var tokenNftCmd = &cobra.Command{
+	Use:   "nft",
+	Short: "Manage non-fungible tokens",
+	Long:  `Obtain information about and transfer ERC-721 non-fungible tokens.`,
+}
+
+// Obtain the non-fungible token contract given a string
+func tokenNftContract(input string) (contract *contracts.ERC721, err error) {
+	address, err := tokenContractAddress(input)
+	if err == nil {
+		contract, err = contracts.NewERC721(address, client)
+	}
+	return
+}
+
+func init() {
+	tokenCmd.AddCommand(tokenNftCmd)
+}
+
+func tokenNftFlags(cmd *cobra.Command) {
+	tokenFlags(cmd)
+	cmd.Flags().StringVar(&tokenNftID, "id", "", "ID of the token")
+}
@@ -0,0 +1,64 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+// tokenNftOwnerCmd represents the token nft owner command
+var tokenNftOwnerCmd = &cobra.Command{
+	Use:   "owner",
+	Short: "Obtain the owner of a non-fungible token",
+	Long: `Obtain the owner of a non-fungible token.  For example:
+
+    ethereal token nft owner --token=0x06012c8cf97BEaD5deAe237070F9587f8E7A266d --id=1
+
+In quiet mode this will return 0 if the token has an owner, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
+		token, err := tokenNftContract(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+
+		cli.AssertCode(tokenNftID != "", quiet, cli.ExitUsage, "--id is required")
+		id, success := new(big.Int).SetString(tokenNftID, 0)
+		cli.AssertCode(success, quiet, cli.ExitUsage, fmt.Sprintf("Invalid token ID %s", tokenNftID))
+
+		owner, err := token.OwnerOf(nil, id)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain owner of token %s", tokenNftID))
+		cli.Assert(owner != ens.UnknownAddress, quiet, fmt.Sprintf("Token %s has no owner", tokenNftID))
+
+		if quiet {
+			os.Exit(0)
+		}
+
+		name, err := ens.ReverseResolve(client, &owner)
+		if err == nil && name != "" {
+			fmt.Fprintf(cli.Out, "%s (%s)\n", owner.Hex(), name)
+		} else {
+			fmt.Fprintln(cli.Out, owner.Hex())
+		}
+	},
+}
+
+func init() {
+	tokenNftCmd.AddCommand(tokenNftOwnerCmd)
+	tokenNftFlags(tokenNftOwnerCmd)
+}
@@ -0,0 +1,109 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var tokenNftTransferFromAddress string
+var tokenNftTransferToAddress string
+var tokenNftTransferForce bool
+
+// tokenNftTransferCmd represents the token nft transfer command
+var tokenNftTransferCmd = &cobra.Command{
+	Use:   "transfer",
+	Short: "Transfer a non-fungible token to a given address",
+	Long: `Transfer a non-fungible token from one address to another.  For example:
+
+    ethereal token nft transfer --token=0x06012c8cf97BEaD5deAe237070F9587f8E7A266d --id=1 --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --passphrase=secret
+
+The transfer is carried out with safeTransferFrom().  The command checks that the from address owns the token before sending the transaction; use --force to skip this check.
+
+In quiet mode this will return 0 if the transfer transaction is successfully sent, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(tokenNftTransferFromAddress != "", quiet, cli.ExitUsage, "--from is required")
+		fromAddress, err := ens.Resolve(client, tokenNftTransferFromAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", tokenNftTransferFromAddress))
+
+		cli.AssertCode(tokenNftTransferToAddress != "", quiet, cli.ExitUsage, "--to is required")
+		toAddress, err := ens.Resolve(client, tokenNftTransferToAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", tokenNftTransferToAddress))
+
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
+		token, err := tokenNftContract(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+
+		cli.AssertCode(tokenNftID != "", quiet, cli.ExitUsage, "--id is required")
+		id, success := new(big.Int).SetString(tokenNftID, 0)
+		cli.AssertCode(success, quiet, cli.ExitUsage, fmt.Sprintf("Invalid token ID %s", tokenNftID))
+
+		if !tokenNftTransferForce {
+			owner, err := token.OwnerOf(nil, id)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain owner of token %s", tokenNftID))
+			cli.Assert(owner == fromAddress, quiet, fmt.Sprintf("Token %s is owned by %s, not %s", tokenNftID, owner.Hex(), fromAddress.Hex()))
+		}
+
+		opts, err := generateTxOpts(fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+
+		signedTx, err := token.SafeTransferFrom(opts, fromAddress, toAddress, id)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+		if offline {
+			if !quiet {
+				buf := new(bytes.Buffer)
+				signedTx.EncodeRLP(buf)
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
+			}
+		} else {
+			log.WithFields(log.Fields{
+				"group":         "token",
+				"command":       "nft transfer",
+				"token":         tokenStr,
+				"id":            id.String(),
+				"from":          fromAddress.Hex(),
+				"to":            toAddress.Hex(),
+				"networkid":     chainID,
+				"gas":           signedTx.Gas(),
+				"gasprice":      signedTx.GasPrice().String(),
+				"transactionid": signedTx.Hash().Hex(),
+			}).Info("success")
+
+			if quiet {
+				os.Exit(0)
+			}
+
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+		}
+	},
+}
+
+func init() {
+	tokenNftCmd.AddCommand(tokenNftTransferCmd)
+	tokenNftFlags(tokenNftTransferCmd)
+	tokenNftTransferCmd.Flags().StringVar(&tokenNftTransferFromAddress, "from", "", "Address from which to transfer the token")
+	tokenNftTransferCmd.Flags().StringVar(&tokenNftTransferToAddress, "to", "", "Address to which to transfer the token")
+	tokenNftTransferCmd.Flags().BoolVar(&tokenNftTransferForce, "force", false, "Transfer without checking that the from address owns the token")
+	addTransactionFlags(tokenNftTransferCmd, "the address from which to transfer the token")
+}
@@ -43,15 +43,15 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
-		cli.Assert(tokenSweepFromAddress != "", quiet, "--from is required")
+		cli.AssertCode(tokenSweepFromAddress != "", quiet, cli.ExitUsage, "--from is required")
 		fromAddress, err := ens.Resolve(client, tokenSweepFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", tokenSweepFromAddress))
 
-		cli.Assert(tokenSweepToAddress != "", quiet, "--to is required")
+		cli.AssertCode(tokenSweepToAddress != "", quiet, cli.ExitUsage, "--to is required")
 		toAddress, err := ens.Resolve(client, tokenSweepToAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", tokenSweepToAddress))
 
-		cli.Assert(tokenStr != "", quiet, "--token is required")
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
 		token, err := tokenContract(tokenStr)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
@@ -65,7 +65,7 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 			if err == nil {
 				decimals, err := token.Decimals(nil)
 				if err == nil {
-					fmt.Println("Sweeping %s %s\n", util.TokenValueToString(balance, decimals, false), symbol)
+					fmt.Fprintf(cli.Out, "Sweeping %s %s\n", util.TokenValueToString(balance, decimals, false), symbol)
 				}
 			}
 		}
@@ -80,7 +80,7 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			log.WithFields(log.Fields{
@@ -100,7 +100,7 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 				os.Exit(0)
 			}
 
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
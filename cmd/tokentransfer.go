@@ -39,32 +39,40 @@ var tokenTransferCmd = &cobra.Command{
 
     ethereal token transfer --token=omg --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --amount=10 --passphrase=secret
 
+The amount is in whole tokens, and can have as many decimal places as the token supports.
+
 In quiet mode this will return 0 if the transfer transaction is successfully sent, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(tokenTransferFromAddress != "", quiet, "--from is required")
+		cli.AssertCode(tokenTransferFromAddress != "", quiet, cli.ExitUsage, "--from is required")
 		fromAddress, err := ens.Resolve(client, tokenTransferFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", tokenTransferFromAddress))
 
-		cli.Assert(tokenTransferToAddress != "", quiet, "--to is required")
+		cli.AssertCode(tokenTransferToAddress != "", quiet, cli.ExitUsage, "--to is required")
 		toAddress, err := ens.Resolve(client, tokenTransferToAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", tokenTransferToAddress))
 
-		cli.Assert(tokenStr != "", quiet, "--token is required")
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
 		token, err := tokenContract(tokenStr)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
-		decimals, err := token.Decimals(nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
+		decimals := tokenDecimals(token)
+		symbol := tokenSymbol(token)
 
-		cli.Assert(tokenTransferAmount != "", quiet, "--amount is required")
+		cli.AssertCode(tokenTransferAmount != "", quiet, cli.ExitUsage, "--amount is required")
 		amount, err := util.StringToTokenValue(tokenTransferAmount, decimals)
-		cli.ErrCheck(err, quiet, "Invalid amount")
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid amount")
 
 		// Obtain the balance of the address
 		balance, err := token.BalanceOf(nil, fromAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
 		cli.Assert(balance.Cmp(amount) >= 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", util.TokenValueToString(balance, decimals, false)))
 
+		amountStr := util.TokenValueToString(amount, decimals, false)
+		if symbol != "" {
+			amountStr = fmt.Sprintf("%s %s", amountStr, symbol)
+		}
+		diagnosticIf(verbose, fmt.Sprintf("Transferring %s from %s to %s", amountStr, fromAddress.Hex(), toAddress.Hex()))
+
 		opts, err := generateTxOpts(fromAddress)
 		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
 
@@ -75,13 +83,14 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			log.WithFields(log.Fields{
 				"group":         "token",
 				"command":       "transfer",
 				"token":         tokenStr,
+				"symbol":        symbol,
 				"from":          fromAddress.Hex(),
 				"to":            toAddress.Hex(),
 				"amount":        amount.String(),
@@ -95,7 +104,7 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 				os.Exit(0)
 			}
 
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
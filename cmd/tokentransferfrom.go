@@ -44,28 +44,28 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
-		cli.Assert(tokenTransferFromFromAddress != "", quiet, "--from is required")
+		cli.AssertCode(tokenTransferFromFromAddress != "", quiet, cli.ExitUsage, "--from is required")
 		fromAddress, err := ens.Resolve(client, tokenTransferFromFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", tokenTransferFromFromAddress))
 
-		cli.Assert(tokenTransferFromToAddress != "", quiet, "--to is required")
+		cli.AssertCode(tokenTransferFromToAddress != "", quiet, cli.ExitUsage, "--to is required")
 		toAddress, err := ens.Resolve(client, tokenTransferFromToAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", tokenTransferFromToAddress))
 
-		cli.Assert(tokenTransferFromByAddress != "", quiet, "--by is required")
+		cli.AssertCode(tokenTransferFromByAddress != "", quiet, cli.ExitUsage, "--by is required")
 		byAddress, err := ens.Resolve(client, tokenTransferFromByAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve by address %s", tokenTransferFromByAddress))
 
-		cli.Assert(tokenStr != "", quiet, "--token is required")
+		cli.AssertCode(tokenStr != "", quiet, cli.ExitUsage, "--token is required")
 		token, err := tokenContract(tokenStr)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
 		decimals, err := token.Decimals(nil)
 		cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
 
-		cli.Assert(tokenTransferFromAmount != "", quiet, "--amount is required")
+		cli.AssertCode(tokenTransferFromAmount != "", quiet, cli.ExitUsage, "--amount is required")
 		amount, err := util.StringToTokenValue(tokenTransferFromAmount, decimals)
-		cli.ErrCheck(err, quiet, "Invalid amount")
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid amount")
 
 		// Obtain the balance of the address
 		balance, err := token.BalanceOf(nil, fromAddress)
@@ -86,7 +86,7 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			log.WithFields(log.Fields{
@@ -107,7 +107,7 @@ In quiet mode this will return 0 if the transfer transaction is successfully sen
 				os.Exit(0)
 			}
 
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
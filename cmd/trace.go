@@ -0,0 +1,110 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+// traceCall is a call in the trace of a transaction, as generated by the
+// node's callTracer
+type traceCall struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []*traceCall    `json:"calls,omitempty"`
+}
+
+// traceTransaction obtains the call trace of a mined transaction
+func traceTransaction(txHash common.Hash) (*traceCall, error) {
+	ctx, cancel := localContext()
+	defer cancel()
+	var trace *traceCall
+	err := rpcClient.CallContext(ctx, &trace, "debug_traceTransaction", txHash, map[string]string{"tracer": "callTracer"})
+	if err != nil {
+		if methodUnavailable(err) {
+			return nil, errors.New("the node does not provide the debug API")
+		}
+		return nil, err
+	}
+	if trace == nil {
+		return nil, errors.New("the node returned an empty trace")
+	}
+	traceRevertReasons(trace)
+	return trace, nil
+}
+
+// traceRevertReasons fills in the revert reasons of failed calls for nodes
+// that do not supply them
+func traceRevertReasons(call *traceCall) {
+	if call.Error != "" && call.RevertReason == "" {
+		if reason, reverted := contractRevertReason(call.Output); reverted {
+			call.RevertReason = reason
+		}
+	}
+	for _, subcall := range call.Calls {
+		traceRevertReasons(subcall)
+	}
+}
+
+// traceOutput outputs a call and its subcalls, indented by depth
+func traceOutput(call *traceCall, depth int) {
+	indent := strings.Repeat("\t", depth+1)
+	line := fmt.Sprintf("%s%s %s", indent, call.Type, call.From.Hex())
+	if call.To != nil {
+		line = fmt.Sprintf("%s -> %s", line, call.To.Hex())
+	}
+	if call.Value != nil && call.Value.ToInt().Sign() > 0 {
+		line = fmt.Sprintf("%s (%s)", line, etherutils.WeiToString(call.Value.ToInt(), true))
+	}
+	fmt.Fprintln(cli.Out, line)
+
+	if len(call.Input) > 0 {
+		if strings.HasPrefix(call.Type, "CREATE") {
+			fmt.Fprintf(cli.Out, "%s  Init code:\t%d bytes\n", indent, len(call.Input))
+		} else if _, _, _, _, exists := txdata.DataToFunction(call.Input); exists {
+			fmt.Fprintf(cli.Out, "%s  Function:\t%s\n", indent, txdata.DataToString(call.Input))
+		} else {
+			fmt.Fprintf(cli.Out, "%s  Input:\t%#x\n", indent, []byte(call.Input))
+		}
+	}
+	fmt.Fprintf(cli.Out, "%s  Gas used:\t%d of %d\n", indent, call.GasUsed, call.Gas)
+	if len(call.Output) > 0 && call.Error == "" && !strings.HasPrefix(call.Type, "CREATE") {
+		fmt.Fprintf(cli.Out, "%s  Output:\t%#x\n", indent, []byte(call.Output))
+	}
+	if call.Error != "" {
+		fmt.Fprintf(cli.Out, "%s  Error:\t%s\n", indent, call.Error)
+		if call.RevertReason != "" {
+			fmt.Fprintf(cli.Out, "%s  Reason:\t%s\n", indent, call.RevertReason)
+		}
+	}
+	for _, subcall := range call.Calls {
+		traceOutput(subcall, depth+1)
+	}
+}
@@ -14,7 +14,23 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/txdata"
 )
 
 var transactionStr string
@@ -33,3 +49,134 @@ func init() {
 func transactionFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&transactionStr, "transaction", "t", "", "raw transaction data or ID of the transaction")
 }
+
+// transactionDecodeRaw decodes a raw signed transaction supplied as a hex
+// string
+func transactionDecodeRaw(input string) (*types.Transaction, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data: %v", err)
+	}
+	tx := &types.Transaction{}
+	err = tx.DecodeRLP(rlp.NewStream(bytes.NewReader(data), 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %v", err)
+	}
+	return tx, nil
+}
+
+// transactionReceiptBlock obtains the number of the block in which a
+// transaction was mined, along with its receipt status.  This is fetched
+// directly as go-ethereum's Receipt does not expose the block number.
+func transactionReceiptBlock(ctx context.Context, txHash common.Hash) (blockNumber *big.Int, status uint64, err error) {
+	var receipt *struct {
+		BlockNumber *hexutil.Big    `json:"blockNumber"`
+		Status      *hexutil.Uint64 `json:"status"`
+	}
+	err = rpcClient.CallContext(ctx, &receipt, "eth_getTransactionReceipt", txHash)
+	if err != nil {
+		return
+	}
+	if receipt == nil || receipt.BlockNumber == nil {
+		err = ethereum.NotFound
+		return
+	}
+	blockNumber = receipt.BlockNumber.ToInt()
+	if receipt.Status == nil {
+		// Pre-Byzantium receipts have no status
+		status = uint64(types.ReceiptStatusSuccessful)
+	} else {
+		status = uint64(*receipt.Status)
+	}
+	return
+}
+
+// transactionMinReplacementGasPrice returns the minimum gas price that nodes
+// will accept for a transaction that replaces one with the given gas price
+func transactionMinReplacementGasPrice(gasPrice *big.Int) *big.Int {
+	minGasPrice := new(big.Int).Add(gasPrice, new(big.Int).Div(gasPrice, big.NewInt(10)))
+	return minGasPrice.Add(minGasPrice, big.NewInt(10))
+}
+
+// transactionWaitForReceipt waits for a transaction to be mined, returning
+// its receipt.
+func transactionWaitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	waiter := newBlockWaiter(ctx, transactionWaitPollInterval)
+	defer waiter.close()
+	for {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil && receipt != nil {
+			return receipt, nil
+		}
+		if err != nil && err != ethereum.NotFound && !transientError(err) {
+			return nil, err
+		}
+		if !waiter.wait(ctx) {
+			return nil, fmt.Errorf("timed out waiting for transaction %s to be mined", txHash.Hex())
+		}
+	}
+}
+
+// transactionDataFromFile reads hex-encoded transaction data from a file.
+// Surrounding whitespace and an optional 0x prefix are ignored.
+func transactionDataFromFile(path string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dataStr := strings.TrimPrefix(strings.TrimSpace(string(contents)), "0x")
+	if len(dataStr)%2 == 1 {
+		return nil, fmt.Errorf("data in %s has an odd number of hex characters", path)
+	}
+	data, err := hex.DecodeString(dataStr)
+	if err != nil {
+		return nil, fmt.Errorf("data in %s is not valid hex: %v", path, err)
+	}
+	return data, nil
+}
+
+// transactionData obtains transaction data supplied either directly as a hex
+// string or in a file
+func transactionData(dataStr string, dataFile string) ([]byte, error) {
+	if dataFile != "" {
+		return transactionDataFromFile(dataFile)
+	}
+	dataStr = strings.TrimPrefix(dataStr, "0x")
+	if len(dataStr)%2 == 1 {
+		// Doesn't like odd numbers
+		dataStr = "0" + dataStr
+	}
+	return hex.DecodeString(dataStr)
+}
+
+// transactionAddSignatures adds custom function signatures, supplied either
+// directly as a semicolon-separated list or in a file, to those used to
+// decode transaction data
+func transactionAddSignatures(signatures string, signaturesFile string) {
+	if signaturesFile != "" {
+		warnings, err := txdata.LoadSignaturesFile(signaturesFile)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to load signatures from %s", signaturesFile))
+		for _, warning := range warnings {
+			cli.Warn(quiet, warning)
+		}
+	}
+	if signatures != "" {
+		for _, signature := range strings.Split(signatures, ";") {
+			txdata.AddFunctionSignature(signature)
+		}
+	}
+}
+
+// transactionAddABIFunctions adds the functions in an ABI, or the ABI in a
+// file, to those used to decode transaction data
+func transactionAddABIFunctions(input string) {
+	abiData := []byte(input)
+	if strings.Contains(input, string(filepath.Separator)) {
+		// ABI value is a path
+		var err error
+		abiData, err = ioutil.ReadFile(input)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read ABI %s", input))
+	}
+	err := txdata.AddABIFunctions(abiData)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse ABI %s", input))
+}
@@ -0,0 +1,352 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	etherutils "github.com/orinocopay/go-etherutils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var transactionBatchFromAddress string
+var transactionBatchFile string
+var transactionBatchStopOnError bool
+var transactionBatchStateFile string
+
+// transactionBatchItem is a single payment in a batch
+type transactionBatchItem struct {
+	line      int
+	recipient string
+	address   common.Address
+	amount    *big.Int
+	result    string
+	// previous is true if the payment was sent by an earlier run
+	previous bool
+	err      error
+}
+
+// transactionBatchCmd represents the transaction batch command
+var transactionBatchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Send Ether to a number of addresses",
+	Long: `Send Ether to a number of addresses listed in a CSV file.  For example:
+
+    ethereal transaction batch --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --file=payments.csv --passphrase=secret
+
+Each line of the file contains the address to which to send Ether, which can be an ENS name, and the amount to send, for example:
+
+    0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d,1.5ether
+    wealdtech.eth,200finney
+
+A header line of "address,amount" is ignored, as are blank lines and lines starting with "#".  Transactions are sent with consecutive nonces, and a line is output for each payment with the hash of its transaction or the reason it failed.  Lines that fail are skipped, unless --stop-on-error is supplied in which case no further transactions are sent.
+
+Progress is shown on stderr while the transactions are sent, followed by a summary of the number of payments that succeeded and failed and the total amount sent.  Use --format=csv or --format=json to output the results as CSV or a single JSON array rather than a line for each payment.
+
+If --state-file is supplied then the transaction sent for each line is recorded in that file as soon as it is sent.  If the run is interrupted it can be run again with the same file and state file, and lines that were sent by the earlier run are skipped.  Before a line is skipped its transaction is checked with the node; if the transaction has been dropped and its nonce not used then the line is sent again.
+
+If --dry-run is supplied then the whole file is checked and the total amount to be sent is displayed, but no transactions are sent.
+
+In quiet mode this will return 0 if all of the transactions are successfully sent, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(transactionBatchFile != "", quiet, cli.ExitUsage, "--file is required")
+		cli.AssertCode(transactionBatchFromAddress != "" || senderImplicit(), quiet, cli.ExitUsage, "--from is required")
+		fromAddress, err := senderAddress(transactionBatchFromAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", transactionBatchFromAddress))
+
+		data, err := ioutil.ReadFile(transactionBatchFile)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read %s", transactionBatchFile))
+		items, err := transactionBatchParse(bytes.NewReader(data))
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read %s", transactionBatchFile))
+		cli.Assert(len(items) > 0, quiet, fmt.Sprintf("No payments in %s", transactionBatchFile))
+
+		var state *sendState
+		if transactionBatchStateFile != "" {
+			cli.AssertCode(!offline, quiet, cli.ExitUsage, "--state-file is not available when offline")
+			state, err = loadSendState(transactionBatchStateFile, fromAddress, data)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to load state file %s", transactionBatchStateFile))
+			for _, item := range items {
+				if item.err != nil {
+					continue
+				}
+				entry, err := state.previouslySent(transactionBatchStateKey(item))
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to check transaction for line %d", item.line))
+				if entry != nil {
+					item.result = entry.Transaction
+					item.previous = true
+				}
+			}
+		}
+
+		total := big.NewInt(0)
+		toSend := 0
+		invalid := 0
+		for _, item := range items {
+			switch {
+			case item.err != nil:
+				invalid++
+			case !item.previous:
+				total.Add(total, item.amount)
+				toSend++
+			}
+		}
+
+		jsonOutput := jsonFormat()
+		csv := !jsonOutput && csvFormat()
+
+		if dryRun {
+			if !quiet {
+				transactionBatchOutput(items, jsonOutput, csv)
+				if !jsonOutput && !csv {
+					fmt.Fprintf(cli.Out, "Total:\t%s in %d transactions\n", etherutils.WeiToString(total, true), toSend)
+				}
+			}
+			if invalid > 0 {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		if !offline {
+			ctx, cancel := localContext()
+			defer cancel()
+			balance, err := client.BalanceAt(ctx, fromAddress, nil)
+			cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
+			cli.Assert(balance.Cmp(total) > 0, quiet, fmt.Sprintf("Balance of %s insufficient for total of %s", etherutils.WeiToString(balance, true), etherutils.WeiToString(total, true)))
+
+			// Obtain the starting nonce once, after which it is incremented
+			// locally for each transaction
+			_, err = currentNonce(fromAddress)
+			cli.ErrCheck(err, quiet, "Failed to obtain nonce")
+		}
+
+		progress := cli.NewProgress(quiet, toSend+invalid, "sent")
+		failed := false
+		processed := 0
+		for _, item := range items {
+			if failed && transactionBatchStopOnError {
+				break
+			}
+			if item.err == nil && !item.previous {
+				item.result, item.err = transactionBatchSend(fromAddress, item, state)
+			}
+			if item.err != nil {
+				failed = true
+			}
+			processed++
+			if !quiet && !jsonOutput && !csv {
+				progress.Clear()
+				transactionBatchOutput([]*transactionBatchItem{item}, false, false)
+			}
+			if !item.previous {
+				progress.Done(item.err, item.amount)
+			}
+		}
+		progress.Summary()
+		if !quiet && (jsonOutput || csv) {
+			transactionBatchOutput(items[:processed], jsonOutput, csv)
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+// transactionBatchParse parses the payments in a batch file.  Payments that
+// cannot be parsed or resolved are returned with an error.
+func transactionBatchParse(input io.Reader) ([]*transactionBatchItem, error) {
+	items := make([]*transactionBatchItem, 0)
+	scanner := bufio.NewScanner(input)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		record, err := csv.NewReader(strings.NewReader(text)).Read()
+		if err != nil {
+			items = append(items, &transactionBatchItem{line: line, err: err})
+			continue
+		}
+		if len(items) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+			// Header
+			continue
+		}
+		item := &transactionBatchItem{line: line}
+		items = append(items, item)
+		if len(record) != 2 {
+			item.err = fmt.Errorf("expected 2 fields but found %d", len(record))
+			continue
+		}
+		item.recipient = strings.TrimSpace(record[0])
+		item.amount, err = etherutils.StringToWei(strings.TrimSpace(record[1]))
+		if err != nil {
+			item.err = fmt.Errorf("invalid amount %s", record[1])
+			continue
+		}
+		if offline && strings.HasSuffix(item.recipient, ".eth") {
+			item.err = fmt.Errorf("ENS names cannot be resolved when offline")
+			continue
+		}
+		item.address, err = ens.Resolve(client, item.recipient)
+		if err != nil {
+			item.err = fmt.Errorf("failed to resolve %s: %v", item.recipient, err)
+			continue
+		}
+		if item.address == ens.UnknownAddress {
+			item.err = fmt.Errorf("%s resolves to the zero address", item.recipient)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// transactionBatchSend sends a single payment, recording it in the state if
+// supplied.  It returns the transaction hash, or the signed transaction
+// itself if offline.
+func transactionBatchSend(fromAddress common.Address, item *transactionBatchItem, state *sendState) (string, error) {
+	signedTx, err := createSignedTransaction(fromAddress, &item.address, item.amount, gasLimit, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if offline {
+		buf := new(bytes.Buffer)
+		signedTx.EncodeRLP(buf)
+		return fmt.Sprintf("0x%s", hex.EncodeToString(buf.Bytes())), nil
+	}
+
+	ctx, cancel := localContext()
+	defer cancel()
+	if err = sendTransaction(ctx, signedTx); err != nil {
+		// Reuse the nonce for the next transaction
+		nonce = int64(signedTx.Nonce())
+		return "", err
+	}
+	if state != nil {
+		err = state.record(transactionBatchStateKey(item), signedTx.Nonce(), signedTx.Hash())
+		// Continuing could send the payment again on a later run
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Sent transaction %s for line %d but failed to update state file", signedTx.Hash().Hex(), item.line))
+	}
+
+	log.WithFields(log.Fields{
+		"group":         "transaction",
+		"command":       "batch",
+		"from":          fromAddress.Hex(),
+		"to":            item.address.Hex(),
+		"amount":        item.amount.String(),
+		"networkid":     chainID,
+		"gas":           signedTx.Gas(),
+		"gasprice":      signedTx.GasPrice().String(),
+		"transactionid": signedTx.Hash().Hex(),
+	}).Info("success")
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// transactionBatchStateKey is the key of a payment in the state file
+func transactionBatchStateKey(item *transactionBatchItem) string {
+	return fmt.Sprintf("line %d", item.line)
+}
+
+// transactionBatchResult is the JSON representation of a payment in a batch
+type transactionBatchResult struct {
+	Line        int    `json:"line"`
+	Recipient   string `json:"recipient,omitempty"`
+	Address     string `json:"address,omitempty"`
+	Amount      string `json:"amount,omitempty"`
+	Transaction string `json:"transaction,omitempty"`
+	Raw         string `json:"raw,omitempty"`
+	Previous    bool   `json:"previouslySent,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// transactionBatchOutput outputs the results of payments
+func transactionBatchOutput(items []*transactionBatchItem, jsonOutput bool, csv bool) {
+	results := make([]*transactionBatchResult, len(items))
+	for i, item := range items {
+		results[i] = &transactionBatchResult{Line: item.line, Recipient: item.recipient}
+		if item.address != ens.UnknownAddress {
+			results[i].Address = item.address.Hex()
+		}
+		if item.amount != nil {
+			results[i].Amount = item.amount.String()
+		}
+		results[i].Previous = item.previous
+		if item.err != nil {
+			results[i].Error = item.err.Error()
+		} else if offline {
+			results[i].Raw = item.result
+		} else {
+			results[i].Transaction = item.result
+		}
+	}
+
+	switch {
+	case jsonOutput:
+		writeJSONArray(results)
+	case csv:
+		table := newCSVTable("line", "recipient", "address", "amount_wei", "amount", "transaction", "raw", "error")
+		for i, result := range results {
+			amount := ""
+			if items[i].amount != nil {
+				amount = etherutils.WeiToString(items[i].amount, true)
+			}
+			table.add(fmt.Sprintf("%d", result.Line), result.Recipient, result.Address, result.Amount, amount, result.Transaction, result.Raw, result.Error)
+		}
+		table.write()
+	default:
+		for i, item := range items {
+			amount := ""
+			if item.amount != nil {
+				amount = etherutils.WeiToString(item.amount, true)
+			}
+			result := item.result
+			if item.err != nil {
+				result = fmt.Sprintf("Error: %s", results[i].Error)
+			} else if item.previous {
+				result = fmt.Sprintf("%s (previously sent)", result)
+			}
+			fmt.Fprintf(cli.Out, "%d\t%s\t%s\t%s\n", item.line, item.recipient, amount, result)
+		}
+	}
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionBatchCmd)
+	transactionBatchCmd.Flags().StringVar(&transactionBatchFromAddress, "from", "", "Address from which to send Ether")
+	transactionBatchCmd.Flags().StringVar(&transactionBatchFile, "file", "", "CSV file containing the address and amount of each payment")
+	transactionBatchCmd.Flags().BoolVar(&transactionBatchStopOnError, "stop-on-error", false, "Stop sending transactions after the first failure")
+	transactionBatchCmd.Flags().StringVar(&transactionBatchStateFile, "state-file", "", "File in which to record the transactions sent, so that an interrupted run can be resumed")
+	addFormatFlagWithJSON(transactionBatchCmd)
+	addTransactionFlags(transactionBatchCmd, "the address from which to send Ether")
+}
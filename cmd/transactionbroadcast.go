@@ -0,0 +1,96 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var transactionBroadcastRaw string
+
+// transactionBroadcastCmd represents the transaction broadcast command
+var transactionBroadcastCmd = &cobra.Command{
+	Use:   "broadcast",
+	Short: "Broadcast a signed transaction",
+	Long: `Broadcast a transaction that has already been signed, for example one created with "ethereal transaction send --offline".  For example:
+
+    ethereal transaction broadcast --raw=0xf86c058504a817c80082520894...
+
+The transaction is decoded and its signature checked before it is sent.  If the transaction is for a different chain to that of the connected node it will not be sent.
+
+The transaction can be sent to further nodes at the same time as the connection by supplying their URLs with --broadcast-to, which can be repeated.  The transaction is sent if any of the nodes accepts it, and the nodes that accepted it are displayed.
+
+In quiet mode this will return 0 if the transaction is successfully sent, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Cannot broadcast a transaction when offline")
+		cli.AssertCode(transactionBroadcastRaw != "", quiet, cli.ExitUsage, "--raw is required")
+
+		transactionBroadcast("broadcast", transactionBroadcastRaw)
+
+		os.Exit(0)
+	},
+}
+
+// transactionBroadcast validates and sends a raw signed transaction
+func transactionBroadcast(command string, raw string) {
+	signedTx, err := transactionDecodeRaw(raw)
+	cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid transaction")
+
+	fromAddress, err := txFrom(signedTx)
+	cli.ErrCheck(err, quiet, "Failed to obtain from address")
+	if signedTx.Protected() {
+		cli.Assert(signedTx.ChainId().Cmp(chainID) == 0, quiet, fmt.Sprintf("Transaction is for chain ID %v but connected to chain ID %v", signedTx.ChainId(), chainID))
+	}
+	if dryRun {
+		dryRunTransaction(fromAddress, signedTx)
+	}
+
+	ctx, cancel := localContext()
+	defer cancel()
+	err = sendTransaction(ctx, signedTx)
+	cli.ErrCheck(err, quiet, "Failed to send transaction")
+
+	to := ""
+	if signedTx.To() != nil {
+		to = signedTx.To().Hex()
+	}
+	log.WithFields(log.Fields{
+		"group":         "transaction",
+		"command":       command,
+		"from":          fromAddress.Hex(),
+		"to":            to,
+		"amount":        signedTx.Value().String(),
+		"data":          hex.EncodeToString(signedTx.Data()),
+		"networkid":     chainID,
+		"gas":           signedTx.Gas(),
+		"gasprice":      signedTx.GasPrice().String(),
+		"transactionid": signedTx.Hash().Hex(),
+	}).Info("success")
+
+	if !quiet {
+		fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
+	}
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionBroadcastCmd)
+	transactionBroadcastCmd.Flags().StringVar(&transactionBroadcastRaw, "raw", "", "signed transaction to broadcast (as a hex string)")
+	addBroadcastFlags(transactionBroadcastCmd)
+}
@@ -17,7 +17,6 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
-	"math/big"
 	"os"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -30,6 +29,7 @@ import (
 
 var transactionCancelAmount string
 var transactionCancelToAddress string
+var transactionCancelFromAddress string
 
 // transactionCancelCmd represents the transaction up command
 var transactionCancelCmd = &cobra.Command{
@@ -39,34 +39,63 @@ var transactionCancelCmd = &cobra.Command{
 
     ethereal transaction cancel --transaction=0x454d2274155cce506359de6358785ce5366f6c13e825263674c272eec8532c0c
 
-Note that Ethereum does not have the ability to cancel a pending transaction, so this overwrites the pending transaction with a 0-value transfer back to the address sender.  It will, however, still need to be mined so choose an appropriate gas price.  If not supplied then the gas price will default to 11% higher than the gas price of the transaction to be cancelled.
+Note that Ethereum does not have the ability to cancel a pending transaction, so this overwrites the pending transaction with a 0-value transfer back to the address sender.  It will, however, still need to be mined so choose an appropriate gas price.  If not supplied then the gas price will default to the suggested gas price, or 10% higher than the gas price of the transaction to be cancelled if that is greater.
+
+If the pending transaction is no longer available from the node then it can be cancelled by supplying its nonce and sending address instead of its hash, for example:
+
+    ethereal transaction cancel --nonce=12 --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --gasprice=20gwei --passphrase=secret
+
+In this situation the gas price of the pending transaction is unknown, so a gas price must be supplied.
 
 The cancellation transaction will cost 21000 gas.
 
+The transaction can be signed with a connected Ledger by supplying --ledger, with the account selected by --hd-path.  In this case the from address is taken from the Ledger if not supplied.
+
 In quiet mode this will return 0 if the cancel transaction is successfully sent, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(transactionStr != "", quiet, "--transaction is required")
-		txHash := common.HexToHash(transactionStr)
-		ctx, cancel := localContext()
-		defer cancel()
-		tx, pending, err := client.TransactionByHash(ctx, txHash)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
-		cli.Assert(pending, quiet, fmt.Sprintf("Transaction %s has already been mined", txHash.Hex()))
-
-		minGasPrice := big.NewInt(0).Add(big.NewInt(0).Add(tx.GasPrice(), big.NewInt(0).Div(tx.GasPrice(), big.NewInt(10))), big.NewInt(10))
-		if viper.GetString("gasprice") == "" {
-			// No gas price supplied; use the calculated minimum
-			gasPrice = minGasPrice
+		byNonce := nonce != -1 || transactionCancelFromAddress != ""
+		cli.AssertCode(transactionStr != "" || byNonce, quiet, cli.ExitUsage, "--transaction or --nonce and --from are required")
+		cli.AssertCode(transactionStr == "" || !byNonce, quiet, cli.ExitUsage, "Cannot supply both --transaction and --nonce/--from")
+
+		var fromAddress common.Address
+		if byNonce {
+			cli.AssertCode(nonce != -1, quiet, cli.ExitUsage, "--nonce is required when cancelling by nonce")
+			cli.AssertCode(transactionCancelFromAddress != "" || senderImplicit(), quiet, cli.ExitUsage, "--from is required when cancelling by nonce")
+			cli.AssertCode(viper.GetString("gasprice") != "", quiet, cli.ExitUsage, "--gasprice is required when cancelling by nonce")
+			fromAddress, err = senderAddress(transactionCancelFromAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", transactionCancelFromAddress))
+
+			ctx, cancel := localContext()
+			defer cancel()
+			minedNonce, err := client.NonceAt(ctx, fromAddress, nil)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain nonce for %s", fromAddress.Hex()))
+			cli.Assert(uint64(nonce) >= minedNonce, quiet, fmt.Sprintf("Transaction with nonce %d has already been mined", nonce))
 		} else {
-			// Gas price supplied; ensure it is at least 10% more than the current gas price
-			cli.Assert(gasPrice.Cmp(minGasPrice) >= 0, quiet, fmt.Sprintf("Gas price must be at least %s", etherutils.WeiToString(minGasPrice, true)))
+			txHash := common.HexToHash(transactionStr)
+			ctx, cancel := localContext()
+			defer cancel()
+			tx, pending, err := client.TransactionByHash(ctx, txHash)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
+			cli.Assert(pending, quiet, fmt.Sprintf("Transaction %s has already been mined", txHash.Hex()))
+
+			minGasPrice := transactionMinReplacementGasPrice(tx.GasPrice())
+			if viper.GetString("gasprice") == "" {
+				// No gas price supplied; use the suggested gas price if it is high enough to replace the transaction
+				if gasPrice.Cmp(minGasPrice) < 0 {
+					gasPrice = minGasPrice
+				}
+			} else {
+				// Gas price supplied; ensure it is at least 10% more than the current gas price
+				cli.Assert(gasPrice.Cmp(minGasPrice) >= 0, quiet, fmt.Sprintf("Gas price must be at least %s", etherutils.WeiToString(minGasPrice, true)))
+			}
+
+			fromAddress, err = txFrom(tx)
+			cli.ErrCheck(err, quiet, "Failed to obtain from address")
+
+			nonce = int64(tx.Nonce())
 		}
 
 		// Create and sign the transaction
-		fromAddress, err := txFrom(tx)
-		cli.ErrCheck(err, quiet, "Failed to obtain from address")
-
-		nonce = int64(tx.Nonce())
 		signedTx, err := createSignedTransaction(fromAddress, &fromAddress, nil, gasLimit, nil)
 		cli.ErrCheck(err, quiet, "Failed to create transaction")
 
@@ -74,12 +103,12 @@ In quiet mode this will return 0 if the cancel transaction is successfully sent,
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			ctx, cancel := localContext()
 			defer cancel()
-			err = client.SendTransaction(ctx, signedTx)
+			err = sendTransaction(ctx, signedTx)
 			cli.ErrCheck(err, quiet, "Failed to send transaction")
 
 			log.WithFields(log.Fields{
@@ -95,7 +124,7 @@ In quiet mode this will return 0 if the cancel transaction is successfully sent,
 			if quiet {
 				os.Exit(0)
 			}
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
@@ -105,5 +134,6 @@ func init() {
 	transactionFlags(transactionCancelCmd)
 	transactionCancelCmd.Flags().StringVar(&transactionCancelAmount, "amount", "", "Amount of Ether to transfer")
 	transactionCancelCmd.Flags().StringVar(&transactionCancelToAddress, "to", "", "Address to which to transfer Ether")
+	transactionCancelCmd.Flags().StringVar(&transactionCancelFromAddress, "from", "", "Address of the transaction to cancel (when cancelling by nonce)")
 	addTransactionFlags(transactionCancelCmd, "the address that holds the funds")
 }
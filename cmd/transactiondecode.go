@@ -0,0 +1,102 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+var transactionDecodeData string
+var transactionDecodeDataFile string
+var transactionDecodeAbi string
+var transactionDecodeOnline bool
+var transactionDecodeSignatures string
+var transactionDecodeSignaturesFile string
+
+// transactionDecodeCmd represents the transaction decode command
+var transactionDecodeCmd = &cobra.Command{
+	Use:   "decode",
+	Short: "Decode transaction data",
+	Long: `Decode transaction data to show the function it calls and the arguments it passes, without requiring a transaction.  For example:
+
+    ethereal transaction decode --data=0xa9059cbb0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4000000000000000000000000000000000000000000000000000000000000000a
+
+Large amounts of data can be supplied in a file with --data-file.  Functions are decoded in the same way as "ethereal transaction info": custom function signatures can be supplied with --signatures or --signatures-file, and if --online is supplied then signatures that are not known locally are looked up with 4byte.directory.  If an ABI is supplied with --abi then its functions are used to decode the data, including those with struct arguments, and argument names are also displayed.
+
+This command does not need a connection to an Ethereum node.
+
+In quiet mode this will return 0 if the data is decoded, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(transactionDecodeData != "" || transactionDecodeDataFile != "", quiet, cli.ExitUsage, "--data or --data-file is required")
+		cli.AssertCode(transactionDecodeData == "" || transactionDecodeDataFile == "", quiet, cli.ExitUsage, "only one of --data and --data-file can be supplied")
+
+		var data []byte
+		var err error
+		if transactionDecodeDataFile != "" {
+			data, err = transactionDataFromFile(transactionDecodeDataFile)
+			cli.ErrCheck(err, quiet, "Failed to read data file")
+		} else {
+			data, err = hex.DecodeString(strings.TrimPrefix(transactionDecodeData, "0x"))
+			cli.ErrCheck(err, quiet, "Failed to parse data")
+		}
+		cli.Assert(len(data) >= 4, quiet, "Data is too short to contain a function selector")
+
+		txdata.InitFunctionMap()
+		if transactionDecodeOnline {
+			txdata.EnableOnlineLookup()
+		}
+		transactionAddSignatures(transactionDecodeSignatures, transactionDecodeSignaturesFile)
+		if transactionDecodeAbi != "" {
+			transactionAddABIFunctions(transactionDecodeAbi)
+		}
+
+		name, paramTypes, values, ambiguous, exists := txdata.DataToFunction(data)
+		cli.Assert(exists, quiet, fmt.Sprintf("Unknown function selector 0x%x", data[:4]))
+
+		if quiet {
+			os.Exit(0)
+		}
+
+		fmt.Fprintf(cli.Out, "Function:\t%s(%s)\n", name, strings.Join(paramTypes, ","))
+		if ambiguous {
+			fmt.Fprintln(cli.Out, "Warning:\tthe function selector matches multiple signatures")
+		}
+		argNames := txdata.ArgumentNames(data)
+		for i := range values {
+			argName := fmt.Sprintf("%d", i)
+			if i < len(argNames) && argNames[i] != "" {
+				argName = argNames[i]
+			}
+			fmt.Fprintf(cli.Out, "\t%s (%s):\t%s\n", argName, paramTypes[i], values[i])
+		}
+	},
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionDecodeCmd)
+	transactionDecodeCmd.Flags().StringVar(&transactionDecodeData, "data", "", "data to decode (as a hex string)")
+	transactionDecodeCmd.Flags().StringVar(&transactionDecodeDataFile, "data-file", "", "file containing data to decode (as a hex string)")
+	transactionDecodeCmd.Flags().StringVar(&transactionDecodeAbi, "abi", "", "ABI, or path to ABI, used to decode the data")
+	transactionDecodeCmd.Flags().BoolVar(&transactionDecodeOnline, "online", false, "Look up unknown function signatures online")
+	transactionDecodeCmd.Flags().StringVar(&transactionDecodeSignatures, "signatures", "", "Semicolon-separated list of custom transaction signatures (e.g. myFunc(address,bytes32);myFunc2(bool)")
+	transactionDecodeCmd.Flags().StringVar(&transactionDecodeSignaturesFile, "signatures-file", "", "File containing custom transaction signatures, either one per line or as JSON")
+}
@@ -0,0 +1,98 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var transactionEstimateAmount string
+var transactionEstimateFromAddress string
+var transactionEstimateToAddress string
+var transactionEstimateData string
+var transactionEstimateDataFile string
+
+// transactionEstimateCmd represents the transaction estimate command
+var transactionEstimateCmd = &cobra.Command{
+	Use:   "estimate",
+	Short: "Estimate the cost of a transaction",
+	Long: `Estimate the cost of a transaction without sending it.  For example:
+
+    ethereal transaction estimate --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x2ab7150Bba7D5F181b3aF5623e52b15bB1054845 --amount=1ether --data=0x12345
+
+The gas that the transaction is expected to use is estimated by the node, and multiplied by the gas price to give the fee.  The gas price can be supplied with --gasprice, otherwise it is suggested according to --gas-price-strategy.  The estimate is of the gas used, and does not include the margin that is added to the gas limit when the transaction is sent.  If --currency is supplied then the fee is also displayed in that currency.
+
+Addresses can be supplied as either an address or an ENS name.  If the to address is not supplied then the transaction is a contract creation, and must have data.
+
+In quiet mode this will return 0 if the cost can be estimated, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Cannot estimate the cost of a transaction when offline")
+		cli.AssertCode(transactionEstimateFromAddress != "", quiet, cli.ExitUsage, "--from is required")
+		cli.AssertCode(transactionEstimateData == "" || transactionEstimateDataFile == "", quiet, cli.ExitUsage, "only one of --data and --data-file can be supplied")
+		fromAddress, err := ens.Resolve(client, transactionEstimateFromAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", transactionEstimateFromAddress))
+
+		var toAddress *common.Address
+		if transactionEstimateToAddress == "" {
+			cli.Assert(transactionEstimateData != "" || transactionEstimateDataFile != "", quiet, "Transactions without a to address are contract creations and must have data")
+		} else {
+			tmp, err := ens.Resolve(client, transactionEstimateToAddress)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", transactionEstimateToAddress))
+			toAddress = &tmp
+		}
+
+		amount := big.NewInt(0)
+		if transactionEstimateAmount != "" {
+			amount, err = etherutils.StringToWei(transactionEstimateAmount)
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid amount")
+		}
+
+		data, err := transactionData(transactionEstimateData, transactionEstimateDataFile)
+		cli.ErrCheck(err, quiet, "Failed to parse data")
+
+		gas, err := estimateGas(fromAddress, toAddress, amount, data)
+		cli.ErrCheck(err, quiet, "Failed to estimate gas")
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintf(cli.Out, "Gas:\t\t%d\n", gas)
+		fmt.Fprintf(cli.Out, "Gas price:\t%s\n", etherutils.WeiToString(gasPrice, true))
+		fmt.Fprintf(cli.Out, "Fee:\t\t%s%s\n", etherutils.WeiToString(fee, true), etherFiatSuffix(fee))
+		if amount.Sign() > 0 {
+			total := new(big.Int).Add(amount, fee)
+			fmt.Fprintf(cli.Out, "Total:\t\t%s%s\n", etherutils.WeiToString(total, true), etherFiatSuffix(total))
+		}
+	},
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionEstimateCmd)
+	transactionEstimateCmd.Flags().StringVar(&transactionEstimateAmount, "amount", "", "Amount of Ether to send with the transaction")
+	transactionEstimateCmd.Flags().StringVar(&transactionEstimateFromAddress, "from", "", "Address from which to send the transaction")
+	transactionEstimateCmd.Flags().StringVar(&transactionEstimateToAddress, "to", "", "Address to which to send the transaction")
+	transactionEstimateCmd.Flags().StringVar(&transactionEstimateData, "data", "", "Data for the transaction")
+	transactionEstimateCmd.Flags().StringVar(&transactionEstimateDataFile, "data-file", "", "File containing the data for the transaction as a hex string")
+	transactionEstimateCmd.Flags().String("gasprice", "", "Gas price for the transaction; if not supplied this is suggested according to the gas price strategy")
+	transactionEstimateCmd.Flags().String("gas-price-strategy", "standard", "Strategy for suggesting the gas price (safe, standard or fast)")
+}
@@ -16,13 +16,15 @@ package cmd
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/rlp"
 	etherutils "github.com/orinocopay/go-etherutils"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
@@ -33,6 +35,11 @@ import (
 var transactionInfoRaw bool
 var transactionInfoJson bool
 var transactionInfoSignatures string
+var transactionInfoSignaturesFile string
+var transactionInfoAbi string
+var transactionInfoOnline bool
+var transactionInfoFormat string
+var transactionInfoTrace bool
 
 // transactionInfoCmd represents the transaction info command
 var transactionInfoCmd = &cobra.Command{
@@ -42,19 +49,27 @@ var transactionInfoCmd = &cobra.Command{
 
     ethereal transaction info --transaction=0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
+Output can be in a human-readable or JSON format, selected with --format=text or --format=json.  The JSON format contains the decoded information shown by this command, including the function called and logs, and is designed for use by other tools.  This differs from the --json flag, which outputs go-ethereum's internal representation of the transaction.
+
+Custom function signatures can be supplied with --signatures, or loaded from a file with --signatures-file.  The file can contain one signature per line, or be JSON containing either an array of signatures or an object mapping selectors to signatures.  If a signature in the file has the same selector as one that is already known then a warning is printed and the existing signature is kept.  If --online is supplied then function signatures that are not known locally are looked up with 4byte.directory.
+
+In verbose mode the logs emitted by the transaction are displayed.  Logs from commonly-used events such as ERC-20 and ERC-721 transfers are decoded, and if an ABI is supplied with --abi then logs that match events in the ABI are also decoded.
+
+If a mined transaction failed then the reason is obtained by replaying the transaction at the block in which it was mined, and displayed if the transaction reverted with an error message or a panic code.  This requires a node that holds historical state, such as an archive node.
+
+If --trace is supplied then the call trace of a mined transaction is also displayed.  This shows each call made during the transaction, indented by depth, along with the function called, the gas used, the output and, for calls that failed, the error and revert reason.  Functions are decoded in the same way as those of the transaction itself, and if --abi is supplied then the functions in the ABI are also used.  Tracing requires a node that provides the debug API and holds the state for the transaction.
+
 In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(transactionStr != "", quiet, "--transaction is required")
+		cli.AssertCode(transactionStr != "", quiet, cli.ExitUsage, "--transaction is required")
+		cli.AssertCode(transactionInfoFormat == "text" || transactionInfoFormat == "json", quiet, cli.ExitUsage, fmt.Sprintf("Unknown format %s", transactionInfoFormat))
 		var txHash common.Hash
 		var pending bool
 		var tx *types.Transaction
 		if len(transactionStr) > 66 {
 			// Assume input is a raw transaction
-			data, err := hex.DecodeString(strings.TrimPrefix(transactionStr, "0x"))
-			cli.ErrCheck(err, quiet, "Failed to decode data")
-			tx = &types.Transaction{}
-			stream := rlp.NewStream(bytes.NewReader(data), 0)
-			err = tx.DecodeRLP(stream)
+			var err error
+			tx, err = transactionDecodeRaw(transactionStr)
 			cli.ErrCheck(err, quiet, "Failed to decode raw transaction")
 			txHash = tx.Hash()
 		} else {
@@ -74,45 +89,76 @@ In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 		if transactionInfoRaw {
 			buf := new(bytes.Buffer)
 			tx.EncodeRLP(buf)
-			fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+			fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			os.Exit(0)
 		}
 
 		if transactionInfoJson {
 			json, err := tx.MarshalJSON()
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain JSON for transaction %s", txHash.Hex()))
-			fmt.Printf("%s\n", string(json))
+			fmt.Fprintf(cli.Out, "%s\n", string(json))
 			os.Exit(0)
 		}
 
 		txdata.InitFunctionMap()
-		if transactionInfoSignatures != "" {
-			for _, signature := range strings.Split(transactionInfoSignatures, ";") {
-				txdata.AddFunctionSignature(signature)
+		txdata.InitEventMap()
+		if transactionInfoOnline {
+			txdata.EnableOnlineLookup()
+		}
+		transactionAddSignatures(transactionInfoSignatures, transactionInfoSignaturesFile)
+
+		events := make(map[common.Hash]abi.Event)
+		if transactionInfoAbi != "" {
+			parsedAbi, err := contractParseAbi(transactionInfoAbi)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse ABI %s", transactionInfoAbi))
+			for _, event := range parsedAbi.Events {
+				if !event.Anonymous {
+					events[event.Id()] = event
+				}
 			}
+			if transactionInfoTrace {
+				transactionAddABIFunctions(transactionInfoAbi)
+			}
+		}
+
+		var trace *traceCall
+		if transactionInfoTrace {
+			cli.Assert(!pending, quiet, "Pending transactions cannot be traced")
+			var err error
+			trace, err = traceTransaction(txHash)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to trace transaction %s", txHash.Hex()))
 		}
 
 		var receipt *types.Receipt
+		if !pending {
+			ctx, cancel := localContext()
+			defer cancel()
+			receipt, _ = client.TransactionReceipt(ctx, txHash)
+		}
+
+		if transactionInfoFormat == "json" {
+			transactionInfoOutputJSON(tx, txHash, pending, receipt, events, trace)
+			os.Exit(0)
+		}
+
 		if pending {
 			if tx.To() == nil {
-				fmt.Printf("Type:\t\t\tPending contract creation\n")
+				fmt.Fprintf(cli.Out, "Type:\t\t\tPending contract creation\n")
 			} else {
-				fmt.Printf("Type:\t\t\tPending transaction\n")
+				fmt.Fprintf(cli.Out, "Type:\t\t\tPending transaction\n")
 			}
 		} else {
 			if tx.To() == nil {
-				fmt.Printf("Type:\t\t\tMined contract creation\n")
+				fmt.Fprintf(cli.Out, "Type:\t\t\tMined contract creation\n")
 			} else {
-				fmt.Printf("Type:\t\t\tMined transaction\n")
+				fmt.Fprintf(cli.Out, "Type:\t\t\tMined transaction\n")
 			}
-			ctx, cancel := localContext()
-			defer cancel()
-			receipt, err = client.TransactionReceipt(ctx, txHash)
 			if receipt != nil {
 				if receipt.Status == 0 {
-					fmt.Printf("Result:\t\t\tFailed\n")
+					fmt.Fprintf(cli.Out, "Result:\t\t\tFailed\n")
+					fmt.Fprintf(cli.Out, "Reason:\t\t\t%s\n", transactionInfoRevertReason(tx, txHash))
 				} else {
-					fmt.Printf("Result:\t\t\tSucceeded\n")
+					fmt.Fprintf(cli.Out, "Result:\t\t\tSucceeded\n")
 				}
 			}
 		}
@@ -121,9 +167,9 @@ In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 		if err == nil {
 			to, err := ens.ReverseResolve(client, &fromAddress)
 			if err == nil {
-				fmt.Printf("From:\t\t\t%v (%s)\n", to, fromAddress.Hex())
+				fmt.Fprintf(cli.Out, "From:\t\t\t%v (%s)\n", to, fromAddress.Hex())
 			} else {
-				fmt.Printf("From:\t\t\t%v\n", fromAddress.Hex())
+				fmt.Fprintf(cli.Out, "From:\t\t\t%v\n", fromAddress.Hex())
 			}
 		}
 
@@ -133,50 +179,40 @@ In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 				contractAddress := receipt.ContractAddress
 				to, err := ens.ReverseResolve(client, &contractAddress)
 				if err == nil {
-					fmt.Printf("Contract address:\t%v (%s)\n", to, contractAddress.Hex())
+					fmt.Fprintf(cli.Out, "Contract address:\t%v (%s)\n", to, contractAddress.Hex())
 				} else {
-					fmt.Printf("Contract address:\t%v\n", contractAddress.Hex())
+					fmt.Fprintf(cli.Out, "Contract address:\t%v\n", contractAddress.Hex())
 				}
 			}
 		} else {
 			to, err := ens.ReverseResolve(client, tx.To())
 			if err == nil {
-				fmt.Printf("To:\t\t\t%v (%s)\n", to, tx.To().Hex())
+				fmt.Fprintf(cli.Out, "To:\t\t\t%v (%s)\n", to, tx.To().Hex())
 			} else {
-				fmt.Printf("To:\t\t\t%v\n", tx.To().Hex())
+				fmt.Fprintf(cli.Out, "To:\t\t\t%v\n", tx.To().Hex())
 			}
 		}
 
-		fmt.Printf("Nonce:\t\t\t%v\n", tx.Nonce())
-		fmt.Printf("Gas limit:\t\t%v\n", tx.Gas())
+		fmt.Fprintf(cli.Out, "Nonce:\t\t\t%v\n", tx.Nonce())
+		fmt.Fprintf(cli.Out, "Gas limit:\t\t%v\n", tx.Gas())
 		if receipt != nil {
-			fmt.Printf("Gas used:\t\t%v\n", receipt.GasUsed)
+			fmt.Fprintf(cli.Out, "Gas used:\t\t%v\n", receipt.GasUsed)
 		}
-		fmt.Printf("Gas price:\t\t%v\n", etherutils.WeiToString(tx.GasPrice(), true))
-		fmt.Printf("Value:\t\t\t%v\n", etherutils.WeiToString(tx.Value(), true))
+		fmt.Fprintf(cli.Out, "Gas price:\t\t%v\n", etherutils.WeiToString(tx.GasPrice(), true))
+		fmt.Fprintf(cli.Out, "Value:\t\t\t%v\n", etherutils.WeiToString(tx.Value(), true))
 
 		if len(tx.Data()) > 0 {
-			fmt.Printf("Data:\t\t\t%v\n", txdata.DataToString(tx.Data()))
-		}
-
-		if verbose && len(receipt.Logs) > 0 {
-			fmt.Printf("Logs:\n")
-			for i, log := range receipt.Logs {
-				fmt.Printf("\t%d:\n", i)
-				fmt.Printf("\t\tAddress:\t%v\n", log.Address.Hex())
-				if len(log.Topics) > 0 {
-					fmt.Printf("\t\tTopics:\n")
-					for j, topic := range log.Topics {
-						fmt.Printf("\t\t\t%d:\t%v\n", j, topic.Hex())
-					}
-				}
-				if len(log.Data) > 0 {
-					fmt.Printf("\t\tData:\n")
-					for j := 0; j*32 < len(log.Data); j++ {
-						fmt.Printf("\t\t\t%d:\t0x%s\n", j, hex.EncodeToString(log.Data[j*32:(j+1)*32]))
-					}
-				}
-			}
+			fmt.Fprintf(cli.Out, "Data:\t\t\t%v\n", txdata.DataToString(tx.Data()))
+		}
+
+		if verbose && receipt != nil && len(receipt.Logs) > 0 {
+			fmt.Fprintf(cli.Out, "Logs:\n")
+			transactionOutputLogs(receipt.Logs, events)
+		}
+
+		if trace != nil {
+			fmt.Fprintf(cli.Out, "Trace:\n")
+			traceOutput(trace, 0)
 		}
 	},
 }
@@ -185,6 +221,269 @@ func init() {
 	transactionCmd.AddCommand(transactionInfoCmd)
 	transactionFlags(transactionInfoCmd)
 	transactionInfoCmd.Flags().BoolVar(&transactionInfoRaw, "raw", false, "Output the transaction as raw hex")
-	transactionInfoCmd.Flags().BoolVar(&transactionInfoJson, "json", false, "Output the transaction as json")
+	transactionInfoCmd.Flags().BoolVar(&transactionInfoJson, "json", false, "Output the transaction as go-ethereum json")
+	transactionInfoCmd.Flags().StringVar(&transactionInfoFormat, "format", "text", "Output format (text or json)")
+	transactionInfoCmd.Flags().StringVar(&transactionInfoAbi, "abi", "", "ABI, or path to ABI, used to decode transaction logs and, with --trace, calls")
+	transactionInfoCmd.Flags().BoolVar(&transactionInfoTrace, "trace", false, "Display the call trace of the transaction (requires the debug API)")
+	transactionInfoCmd.Flags().BoolVar(&transactionInfoOnline, "online", false, "Look up unknown function signatures online")
 	transactionInfoCmd.Flags().StringVar(&transactionInfoSignatures, "signatures", "", "Semicolon-separated list of custom transaction signatures (e.g. myFunc(address,bytes32);myFunc2(bool)")
+	transactionInfoCmd.Flags().StringVar(&transactionInfoSignaturesFile, "signatures-file", "", "File containing custom transaction signatures, either one per line or as JSON")
+}
+
+// transactionOutputLogs outputs the logs emitted by a transaction, decoding
+// those that match the supplied events or commonly-used events
+func transactionOutputLogs(logs []*types.Log, events map[common.Hash]abi.Event) {
+	for i, log := range logs {
+		fmt.Fprintf(cli.Out, "\t%d:\n", i)
+		fmt.Fprintf(cli.Out, "\t\tAddress:\t%v\n", log.Address.Hex())
+		if len(log.Topics) > 0 {
+			if event, exists := events[log.Topics[0]]; exists {
+				args, err := transactionInfoDecodeLog(event, log)
+				if err == nil {
+					fmt.Fprintf(cli.Out, "\t\tEvent:\t\t%s\n", event.Name)
+					if len(args) > 0 {
+						fmt.Fprintf(cli.Out, "\t\tArguments:\n")
+						for _, arg := range args {
+							fmt.Fprintf(cli.Out, "\t\t\t%s (%s):\t%s\n", arg.Name, arg.Type, arg.Value)
+						}
+					}
+					continue
+				}
+				fmt.Fprintf(cli.Out, "\t\tFailed to decode %s: %v\n", event.Name, err)
+			} else if name, args, exists := transactionInfoKnownEvent(log); exists {
+				fmt.Fprintf(cli.Out, "\t\tEvent:\t\t%s\n", name)
+				if len(args) > 0 {
+					fmt.Fprintf(cli.Out, "\t\tArguments:\n")
+					for _, arg := range args {
+						fmt.Fprintf(cli.Out, "\t\t\t%s (%s):\t%s\n", arg.Name, arg.Type, arg.Value)
+					}
+				}
+				continue
+			}
+		}
+		if len(log.Topics) > 0 {
+			fmt.Fprintf(cli.Out, "\t\tTopics:\n")
+			for j, topic := range log.Topics {
+				fmt.Fprintf(cli.Out, "\t\t\t%d:\t%v\n", j, topic.Hex())
+			}
+		}
+		if len(log.Data) > 0 {
+			fmt.Fprintf(cli.Out, "\t\tData:\n")
+			for j := 0; j*32 < len(log.Data); j++ {
+				fmt.Fprintf(cli.Out, "\t\t\t%d:\t0x%s\n", j, hex.EncodeToString(log.Data[j*32:(j+1)*32]))
+			}
+		}
+	}
+}
+
+type transactionInfoAddress struct {
+	Address string `json:"address"`
+	Name    string `json:"name,omitempty"`
+}
+
+type transactionInfoArgument struct {
+	Name  string `json:"name,omitempty"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type transactionInfoMethod struct {
+	Name      string                    `json:"name"`
+	Arguments []transactionInfoArgument `json:"arguments"`
+	Ambiguous bool                      `json:"ambiguous,omitempty"`
+}
+
+type transactionInfoLog struct {
+	Address   string                    `json:"address"`
+	Topics    []string                  `json:"topics"`
+	Data      string                    `json:"data"`
+	Event     string                    `json:"event,omitempty"`
+	Arguments []transactionInfoArgument `json:"arguments,omitempty"`
+}
+
+type transactionInfoJSON struct {
+	Hash             string                  `json:"hash"`
+	Pending          bool                    `json:"pending"`
+	ContractCreation bool                    `json:"contractCreation"`
+	Succeeded        *bool                   `json:"succeeded,omitempty"`
+	RevertReason     string                  `json:"revertReason,omitempty"`
+	From             *transactionInfoAddress `json:"from,omitempty"`
+	To               *transactionInfoAddress `json:"to,omitempty"`
+	ContractAddress  *transactionInfoAddress `json:"contractAddress,omitempty"`
+	Nonce            uint64                  `json:"nonce"`
+	GasLimit         uint64                  `json:"gasLimit"`
+	GasUsed          *uint64                 `json:"gasUsed,omitempty"`
+	GasPrice         string                  `json:"gasPrice"`
+	Value            string                  `json:"value"`
+	Data             string                  `json:"data,omitempty"`
+	Method           *transactionInfoMethod  `json:"method,omitempty"`
+	Logs             []transactionInfoLog    `json:"logs,omitempty"`
+	Trace            *traceCall              `json:"trace,omitempty"`
+}
+
+// transactionInfoRevertReason obtains the reason that a mined transaction
+// failed by replaying it with eth_call at the block in which it was mined.
+// This requires a node that holds historical state, and as the transaction is
+// replayed against the state at the end of its block rather than at its
+// position within the block the reason is not guaranteed to be accurate.
+func transactionInfoRevertReason(tx *types.Transaction, txHash common.Hash) string {
+	fromAddress, err := txFrom(tx)
+	if err != nil {
+		return fmt.Sprintf("unavailable (failed to obtain sender: %v)", err)
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	blockNumber, _, err := transactionReceiptBlock(ctx, txHash)
+	if err != nil {
+		return fmt.Sprintf("unavailable (failed to obtain block: %v)", err)
+	}
+	msg := ethereum.CallMsg{
+		From:     fromAddress,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	result, err := client.CallContract(ctx, msg, blockNumber)
+	if reason, reverted := callRevertReason(result, err); reverted {
+		return reason
+	}
+	if err != nil {
+		if transactionInfoMissingState(err) {
+			return "unavailable (the node does not hold historical state; connect to an archive node)"
+		}
+		return err.Error()
+	}
+	return "unavailable (the transaction does not fail when replayed)"
+}
+
+// transactionInfoMissingState returns true if an error shows that the node
+// does not have the state required for a call
+func transactionInfoMissingState(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, text := range []string{"missing trie node", "historical state", "header not found", "pruned", "required historical"} {
+		if strings.Contains(msg, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// transactionInfoAddressFor creates an address with its ENS name, if available
+func transactionInfoAddressFor(address common.Address) *transactionInfoAddress {
+	res := &transactionInfoAddress{Address: address.Hex()}
+	name, err := ens.ReverseResolve(client, &address)
+	if err == nil {
+		res.Name = name
+	}
+	return res
+}
+
+// transactionInfoOutputJSON outputs decoded information about a transaction as JSON.
+// Values are in Wei.
+func transactionInfoOutputJSON(tx *types.Transaction, txHash common.Hash, pending bool, receipt *types.Receipt, events map[common.Hash]abi.Event, trace *traceCall) {
+	output := &transactionInfoJSON{
+		Hash:             txHash.Hex(),
+		Trace:            trace,
+		Pending:          pending,
+		ContractCreation: tx.To() == nil,
+		Nonce:            tx.Nonce(),
+		GasLimit:         tx.Gas(),
+		GasPrice:         tx.GasPrice().String(),
+		Value:            tx.Value().String(),
+	}
+
+	fromAddress, err := txFrom(tx)
+	if err == nil {
+		output.From = transactionInfoAddressFor(fromAddress)
+	}
+	if tx.To() != nil {
+		output.To = transactionInfoAddressFor(*tx.To())
+	}
+
+	if len(tx.Data()) > 0 {
+		output.Data = fmt.Sprintf("0x%s", hex.EncodeToString(tx.Data()))
+		name, paramTypes, values, ambiguous, exists := txdata.DataToFunction(tx.Data())
+		if exists {
+			output.Method = &transactionInfoMethod{
+				Name:      name,
+				Arguments: make([]transactionInfoArgument, len(values)),
+				Ambiguous: ambiguous,
+			}
+			for i := range values {
+				output.Method.Arguments[i] = transactionInfoArgument{Type: paramTypes[i], Value: values[i]}
+			}
+		}
+	}
+
+	if receipt != nil {
+		succeeded := receipt.Status != 0
+		output.Succeeded = &succeeded
+		if !succeeded {
+			output.RevertReason = transactionInfoRevertReason(tx, txHash)
+		}
+		output.GasUsed = &receipt.GasUsed
+		if tx.To() == nil {
+			output.ContractAddress = transactionInfoAddressFor(receipt.ContractAddress)
+		}
+		for _, log := range receipt.Logs {
+			logOutput := transactionInfoLog{
+				Address: log.Address.Hex(),
+				Topics:  make([]string, len(log.Topics)),
+				Data:    fmt.Sprintf("0x%s", hex.EncodeToString(log.Data)),
+			}
+			for i, topic := range log.Topics {
+				logOutput.Topics[i] = topic.Hex()
+			}
+			if len(log.Topics) > 0 {
+				if event, exists := events[log.Topics[0]]; exists {
+					args, err := transactionInfoDecodeLog(event, log)
+					if err == nil {
+						logOutput.Event = event.Name
+						logOutput.Arguments = args
+					}
+				} else if name, args, exists := transactionInfoKnownEvent(log); exists {
+					logOutput.Event = name
+					logOutput.Arguments = args
+				}
+			}
+			output.Logs = append(output.Logs, logOutput)
+		}
+	}
+
+	data, err := json.Marshal(output)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain JSON for transaction %s", txHash.Hex()))
+	fmt.Fprintf(cli.Out, "%s\n", string(data))
+}
+
+// transactionInfoDecodeLog decodes the arguments of a log given the event
+// that generated it
+func transactionInfoDecodeLog(event abi.Event, log *types.Log) ([]transactionInfoArgument, error) {
+	decoded, err := contractDecodeLog(event, log)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]transactionInfoArgument, len(decoded))
+	for i, arg := range decoded {
+		args[i] = transactionInfoArgument{Name: arg.Name, Type: arg.Type, Value: arg.Value}
+	}
+	return args, nil
+}
+
+// transactionInfoKnownEvent decodes the arguments of a log if it was
+// generated by a commonly-used event
+func transactionInfoKnownEvent(log *types.Log) (string, []transactionInfoArgument, bool) {
+	name, names, paramTypes, values, exists := txdata.LogToEvent(log.Topics, log.Data)
+	if !exists {
+		return "", nil, false
+	}
+	args := make([]transactionInfoArgument, len(values))
+	for i := range values {
+		args[i] = transactionInfoArgument{Name: names[i], Type: paramTypes[i], Value: values[i]}
+		if args[i].Name == "" {
+			args[i].Name = fmt.Sprintf("%d", i)
+		}
+	}
+	return name, args, true
 }
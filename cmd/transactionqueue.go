@@ -0,0 +1,297 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var transactionQueueFromAddress string
+var transactionQueueFile string
+var transactionQueueSequential bool
+var transactionQueueStateFile string
+
+// transactionQueueEntry is an entry in a queue file
+type transactionQueueEntry struct {
+	To    string `json:"to"`
+	Value string `json:"value"`
+	Data  string `json:"data"`
+	Gas   uint64 `json:"gas"`
+}
+
+// transactionQueueItem is a transaction in a queue
+type transactionQueueItem struct {
+	index  int
+	to     *common.Address
+	amount *big.Int
+	data   []byte
+	gas    uint64
+	// previous is the transaction sent for the item by an earlier run
+	previous *sendStateEntry
+}
+
+// transactionQueueCmd represents the transaction queue command
+var transactionQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Send a sequence of dependent transactions",
+	Long: `Send a sequence of transactions with consecutive nonces, in order.  For example:
+
+    ethereal transaction queue --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --file=calls.json --passphrase=secret
+
+The file contains a JSON array of transactions, each with "to", "value" and "data", and optionally "gas" to set its gas limit.  "to" can be an ENS name, and if omitted the transaction creates a contract.  For example:
+
+    [
+      {"to": "0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d", "data": "0x095ea7b3..."},
+      {"to": "router.eth", "value": "0.5 ether", "data": "0x7ff36ab5..."}
+    ]
+
+The first transaction takes the account's pending nonce, or --nonce if supplied, and each subsequent transaction the next nonce.  By default all of the transactions are signed before any are sent, and then sent in order.  With --sequential each transaction is signed and sent only once the previous transaction has been mined successfully, which allows gas limits to be estimated and transactions to be simulated against the state that earlier transactions create; otherwise later transactions that depend on earlier ones should be given "gas" and may need --simulate=false.  Each wait is limited by --wait-timeout.
+
+A line is output for each transaction with its nonce and hash.  If a transaction is rejected, or with --sequential reverts, no further transactions are sent and its nonce is reported.  If --offline is supplied the signed transactions are output rather than sent.
+
+If --state-file is supplied then each transaction is recorded in that file as soon as it is sent.  If the run is interrupted it can be run again with the same file and state file, and entries that were sent by the earlier run are skipped; with --sequential their transactions are waited for before later entries are sent.  Before an entry is skipped its transaction is checked with the node; if the transaction has been dropped and its nonce not used then the entry is sent again.
+
+In quiet mode this will return 0 if all of the transactions are sent, and with --sequential mined successfully, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(transactionQueueFile != "", quiet, cli.ExitUsage, "--file is required")
+		cli.AssertCode(transactionQueueFromAddress != "" || senderImplicit(), quiet, cli.ExitUsage, "--from is required")
+		cli.AssertCode(!offline || !transactionQueueSequential, quiet, cli.ExitUsage, "--sequential is not available when offline")
+		cli.AssertCode(!offline || transactionQueueStateFile == "", quiet, cli.ExitUsage, "--state-file is not available when offline")
+		fromAddress, err := senderAddress(transactionQueueFromAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", transactionQueueFromAddress))
+
+		data, err := ioutil.ReadFile(transactionQueueFile)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read %s", transactionQueueFile))
+		items, err := transactionQueueParse(data)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse %s", transactionQueueFile))
+		cli.Assert(len(items) > 0, quiet, fmt.Sprintf("No transactions in %s", transactionQueueFile))
+
+		var state *sendState
+		if transactionQueueStateFile != "" {
+			state, err = loadSendState(transactionQueueStateFile, fromAddress, data)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to load state file %s", transactionQueueStateFile))
+			for _, item := range items {
+				item.previous, err = state.previouslySent(transactionQueueStateKey(item))
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to check transaction for entry %d", item.index+1))
+				if item.previous != nil {
+					diagnosticIf(verbose, fmt.Sprintf("Entry %d was sent by an earlier run in transaction %s", item.index+1, item.previous.Transaction))
+				}
+			}
+		}
+		unsent := 0
+		for _, item := range items {
+			if item.previous == nil {
+				unsent++
+			}
+		}
+		if unsent == 0 {
+			outputIf(!quiet, "All transactions have been sent")
+			os.Exit(0)
+		}
+
+		firstNonce, err := currentNonce(fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to obtain nonce")
+		diagnosticIf(verbose, fmt.Sprintf("Sending %d transactions with nonces %d to %d", unsent, firstNonce, firstNonce+uint64(unsent)-1))
+
+		if transactionQueueSequential {
+			for _, item := range items {
+				if item.previous == nil {
+					signedTx, err := transactionQueueSign(fromAddress, item)
+					transactionQueueCheck(err, item, uint64(nonce), unsent-1, "could not be created")
+					transactionQueueSend(fromAddress, item, unsent-1, signedTx, state)
+					unsent--
+					transactionQueueWait(item, signedTx.Hash(), signedTx.Nonce(), unsent)
+				} else if !item.previous.replaced {
+					// Later transactions can depend on this one
+					transactionQueueWait(item, common.HexToHash(item.previous.Transaction), item.previous.Nonce, unsent)
+				}
+			}
+			os.Exit(0)
+		}
+
+		signedTxs := make([]*types.Transaction, len(items))
+		for i, item := range items {
+			if item.previous == nil {
+				signedTxs[i], err = transactionQueueSign(fromAddress, item)
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to create transaction for entry %d with nonce %d", i+1, nonce))
+			}
+		}
+
+		if offline {
+			if !quiet {
+				for _, signedTx := range signedTxs {
+					buf := new(bytes.Buffer)
+					signedTx.EncodeRLP(buf)
+					fmt.Fprintf(cli.Out, "%d\t0x%s\n", signedTx.Nonce(), hex.EncodeToString(buf.Bytes()))
+				}
+			}
+			os.Exit(0)
+		}
+
+		for i, item := range items {
+			if signedTxs[i] != nil {
+				unsent--
+				transactionQueueSend(fromAddress, item, unsent, signedTxs[i], state)
+			}
+		}
+		os.Exit(0)
+	},
+}
+
+// transactionQueueParse parses the entries of a queue file
+func transactionQueueParse(data []byte) ([]*transactionQueueItem, error) {
+	var entries []*transactionQueueEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	items := make([]*transactionQueueItem, len(entries))
+	for i, entry := range entries {
+		if entry == nil {
+			return nil, fmt.Errorf("entry %d is empty", i+1)
+		}
+		item := &transactionQueueItem{
+			index:  i,
+			amount: big.NewInt(0),
+			gas:    entry.Gas,
+		}
+		if entry.To != "" {
+			if offline && strings.HasSuffix(entry.To, ".eth") {
+				return nil, fmt.Errorf("entry %d: ENS names cannot be resolved when offline", i+1)
+			}
+			to, err := ens.Resolve(client, entry.To)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: failed to resolve %s: %v", i+1, entry.To, err)
+			}
+			item.to = &to
+		}
+		if entry.Value != "" {
+			amount, err := etherutils.StringToWei(entry.Value)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: invalid value %s", i+1, entry.Value)
+			}
+			item.amount = amount
+		}
+		if entry.Data != "" {
+			data, err := hex.DecodeString(strings.TrimPrefix(entry.Data, "0x"))
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: invalid data", i+1)
+			}
+			item.data = data
+		}
+		if item.to == nil && len(item.data) == 0 {
+			return nil, fmt.Errorf("entry %d: data is required to create a contract", i+1)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// transactionQueueSign creates and signs the transaction for an item
+func transactionQueueSign(fromAddress common.Address, item *transactionQueueItem) (*types.Transaction, error) {
+	gas := item.gas
+	if gas == 0 {
+		gas = gasLimit
+	}
+	return createSignedTransaction(fromAddress, item.to, item.amount, gas, item.data)
+}
+
+// transactionQueueSend sends a signed transaction from the queue, recording
+// it in the state if supplied, and exits if it is rejected
+func transactionQueueSend(fromAddress common.Address, item *transactionQueueItem, remaining int, signedTx *types.Transaction, state *sendState) {
+	ctx, cancel := localContext()
+	err := sendTransaction(ctx, signedTx)
+	cancel()
+	transactionQueueCheck(err, item, signedTx.Nonce(), remaining, "was rejected")
+	if state != nil {
+		err = state.record(transactionQueueStateKey(item), signedTx.Nonce(), signedTx.Hash())
+		// Continuing could send the transaction again on a later run
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Sent transaction %s for entry %d but failed to update state file", signedTx.Hash().Hex(), item.index+1))
+	}
+
+	to := ""
+	if signedTx.To() != nil {
+		to = signedTx.To().Hex()
+	}
+	log.WithFields(log.Fields{
+		"group":         "transaction",
+		"command":       "queue",
+		"from":          fromAddress.Hex(),
+		"to":            to,
+		"amount":        signedTx.Value().String(),
+		"data":          "0x" + hex.EncodeToString(signedTx.Data()),
+		"networkid":     chainID,
+		"gas":           signedTx.Gas(),
+		"gasprice":      signedTx.GasPrice().String(),
+		"transactionid": signedTx.Hash().Hex(),
+	}).Info("success")
+
+	outputIf(!quiet, fmt.Sprintf("%d\t%s", signedTx.Nonce(), signedTx.Hash().Hex()))
+}
+
+// transactionQueueWait waits for a transaction from the queue to be mined,
+// and exits if it fails
+func transactionQueueWait(item *transactionQueueItem, txHash common.Hash, txNonce uint64, remaining int) {
+	ctx, cancel := waitContext()
+	receipt, err := transactionWaitForReceipt(ctx, txHash)
+	cancel()
+	transactionQueueCheck(err, item, txNonce, remaining, "was not mined")
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		transactionQueueCheck(fmt.Errorf("transaction %s reverted", txHash.Hex()), item, txNonce, remaining, "failed")
+	}
+}
+
+// transactionQueueCheck exits if a transaction in the queue failed,
+// reporting its nonce and the number of transactions not sent as a result
+func transactionQueueCheck(err error, item *transactionQueueItem, nonce uint64, remaining int, failure string) {
+	if err == nil {
+		return
+	}
+	switch remaining {
+	case 0:
+	case 1:
+		cli.Warn(quiet, "the remaining transaction was not sent")
+	default:
+		cli.Warn(quiet, fmt.Sprintf("the remaining %d transactions were not sent", remaining))
+	}
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Transaction for entry %d with nonce %d %s", item.index+1, nonce, failure))
+}
+
+// transactionQueueStateKey is the key of a queue item in the state file
+func transactionQueueStateKey(item *transactionQueueItem) string {
+	return fmt.Sprintf("entry %d", item.index+1)
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionQueueCmd)
+	transactionQueueCmd.Flags().StringVar(&transactionQueueFromAddress, "from", "", "Address from which to send the transactions")
+	transactionQueueCmd.Flags().StringVar(&transactionQueueFile, "file", "", "JSON file containing the transactions to send")
+	transactionQueueCmd.Flags().BoolVar(&transactionQueueSequential, "sequential", false, "Wait for each transaction to be mined before sending the next")
+	transactionQueueCmd.Flags().StringVar(&transactionQueueStateFile, "state-file", "", "File in which to record the transactions sent, so that an interrupted run can be resumed")
+	addTransactionFlags(transactionQueueCmd, "the address from which to send the transactions")
+}
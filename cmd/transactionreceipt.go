@@ -0,0 +1,138 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+var transactionReceiptJSON bool
+var transactionReceiptAbi string
+
+// transactionReceiptExtra contains the fields of a receipt that go-ethereum's
+// Receipt does not expose
+type transactionReceiptExtra struct {
+	BlockNumber       *hexutil.Big    `json:"blockNumber"`
+	From              *common.Address `json:"from"`
+	To                *common.Address `json:"to"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+}
+
+// transactionReceiptCmd represents the transaction receipt command
+var transactionReceiptCmd = &cobra.Command{
+	Use:   "receipt",
+	Short: "Obtain the receipt of a transaction",
+	Long: `Obtain the receipt of a mined transaction.  For example:
+
+    ethereal transaction receipt --transaction=0x5219b09d629158c2759035c97b11b604f57d0c733515738aaae0d2dafb41ab98
+
+This shows the outcome of the transaction: its status, the gas it used, the effective gas price paid, the cumulative gas used in the block up to and including it, the address of the contract if it created one, and its logs.  Logs from commonly-used events are decoded, and if an ABI is supplied with --abi then logs that match events in the ABI are also decoded.  Use --json to output the receipt exactly as returned by the node.
+
+In quiet mode this will return 0 if the transaction was mined and succeeded, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(transactionStr != "", quiet, cli.ExitUsage, "--transaction is required")
+		txHash := common.HexToHash(transactionStr)
+
+		ctx, cancel := localContext()
+		defer cancel()
+		raw, err := transactionReceiptRaw(ctx, txHash)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain receipt for transaction %s", txHash.Hex()))
+		receipt := &types.Receipt{}
+		err = json.Unmarshal(raw, receipt)
+		cli.ErrCheck(err, quiet, "Failed to decode receipt")
+		extra := &transactionReceiptExtra{}
+		err = json.Unmarshal(raw, extra)
+		cli.ErrCheck(err, quiet, "Failed to decode receipt")
+
+		if quiet {
+			if receipt.Status == types.ReceiptStatusFailed {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		if transactionReceiptJSON {
+			fmt.Fprintln(cli.Out, string(raw))
+			os.Exit(0)
+		}
+
+		if receipt.Status == types.ReceiptStatusFailed {
+			fmt.Fprintf(cli.Out, "Status:\t\t\tFailed\n")
+		} else {
+			fmt.Fprintf(cli.Out, "Status:\t\t\tSucceeded\n")
+		}
+		if extra.BlockNumber != nil {
+			fmt.Fprintf(cli.Out, "Block:\t\t\t%v\n", extra.BlockNumber.ToInt())
+		}
+		if extra.To == nil && receipt.ContractAddress != (common.Address{}) {
+			fmt.Fprintf(cli.Out, "Contract address:\t%v\n", receipt.ContractAddress.Hex())
+		}
+		fmt.Fprintf(cli.Out, "Gas used:\t\t%v\n", receipt.GasUsed)
+		fmt.Fprintf(cli.Out, "Cumulative gas used:\t%v\n", receipt.CumulativeGasUsed)
+		if extra.EffectiveGasPrice != nil {
+			fmt.Fprintf(cli.Out, "Effective gas price:\t%v\n", etherutils.WeiToString(extra.EffectiveGasPrice.ToInt(), true))
+		}
+
+		if len(receipt.Logs) > 0 {
+			txdata.InitEventMap()
+			events := make(map[common.Hash]abi.Event)
+			if transactionReceiptAbi != "" {
+				parsedAbi, err := contractParseAbi(transactionReceiptAbi)
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse ABI %s", transactionReceiptAbi))
+				for _, event := range parsedAbi.Events {
+					if !event.Anonymous {
+						events[event.Id()] = event
+					}
+				}
+			}
+			fmt.Fprintf(cli.Out, "Logs:\n")
+			transactionOutputLogs(receipt.Logs, events)
+		}
+		os.Exit(0)
+	},
+}
+
+// transactionReceiptRaw obtains the receipt of a transaction as returned by
+// the node, which contains fields that go-ethereum's Receipt does not
+func transactionReceiptRaw(ctx context.Context, txHash common.Hash) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := rpcClient.CallContext(ctx, &raw, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, ethereum.NotFound
+	}
+	return raw, nil
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionReceiptCmd)
+	transactionFlags(transactionReceiptCmd)
+	transactionReceiptCmd.Flags().BoolVar(&transactionReceiptJSON, "json", false, "Output the receipt as JSON")
+	transactionReceiptCmd.Flags().StringVar(&transactionReceiptAbi, "abi", "", "ABI, or path to ABI, used to decode logs")
+}
@@ -22,8 +22,6 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/rlp"
 	etherutils "github.com/orinocopay/go-etherutils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -35,6 +33,7 @@ var transactionSendAmount string
 var transactionSendFromAddress string
 var transactionSendToAddress string
 var transactionSendData string
+var transactionSendDataFile string
 var transactionSendRaw string
 
 // transactionSendCmd represents the transaction send command
@@ -45,57 +44,48 @@ var transactionSendCmd = &cobra.Command{
 
     ethereal transaction send --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x2ab7150Bba7D5F181b3aF5623e52b15bB1054845	 --amount=1ether --passphrase=secret --data=0x12345
 
-In quiet mode this will return 0 if the transaction is successfully sent, otherwise 1.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if transactionSendRaw != "" {
-			// Send a raw transaction
+The to address can be supplied as either an address or an ENS name.  If the to address is not supplied then the transaction is a contract creation, and must have data.  Large amounts of data can be supplied in a file with --data-file, which should contain the data as a hex string.
 
-			// Decode the raw transaction
-			data, err := hex.DecodeString(strings.TrimPrefix(transactionSendRaw, "0x"))
-			cli.ErrCheck(err, quiet, "Failed to decode data")
-			signedTx := &types.Transaction{}
-			stream := rlp.NewStream(bytes.NewReader(data), 0)
-			err = signedTx.DecodeRLP(stream)
-			cli.ErrCheck(err, quiet, "Failed to decode transaction")
+The transaction can be displayed and simulated without being sent by supplying --dry-run.
 
-			ctx, cancel := localContext()
-			defer cancel()
-			err = client.SendTransaction(ctx, signedTx)
-			cli.ErrCheck(err, quiet, "Failed to send transaction")
+The transaction can be built and signed without connecting to a node by supplying --offline, in which case the signed transaction is printed as a hex string rather than sent.  As nothing can be obtained from the network in this mode --nonce, --gasprice, --gaslimit and --chainid must all be supplied, and addresses must be supplied in hex rather than as ENS names.  For example:
 
-			fromAddress, err := txFrom(signedTx)
-			cli.ErrCheck(err, quiet, "Failed to obtain from address")
+    ethereal transaction send --offline --chainid=1 --nonce=5 --gasprice=20gwei --gaslimit=21000 --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x2ab7150Bba7D5F181b3aF5623e52b15bB1054845 --amount=1ether --passphrase=secret
 
-			log.WithFields(log.Fields{
-				"group":         "transaction",
-				"command":       "send",
-				"from":          fromAddress.Hex(),
-				"to":            signedTx.To().Hex(),
-				"amount":        signedTx.Value().String(),
-				"data":          hex.EncodeToString(signedTx.Data()),
-				"networkid":     chainID,
-				"gas":           signedTx.Gas(),
-				"gasprice":      signedTx.GasPrice().String(),
-				"transactionid": signedTx.Hash().Hex(),
-			}).Info("success")
+The resultant hex string can be sent from a connected machine with "ethereal transaction broadcast".
 
-			if !quiet {
-				fmt.Println(signedTx.Hash().Hex())
-			}
+The transaction can be sent to further nodes at the same time as the connection by supplying their URLs with --broadcast-to, which can be repeated.  The transaction is sent if any of the nodes accepts it, and the nodes that accepted it are displayed.
+
+The transaction can be signed with the key in a single keystore file by supplying --keystore-file along with --passphrase or --passphrase-file.  In this case the from address is taken from the keystore file if not supplied.
+
+The transaction can be signed with a connected Ledger by supplying --ledger, with the account selected by --hd-path.  In this case the from address is taken from the Ledger if not supplied.
+
+In quiet mode this will return 0 if the transaction is successfully sent, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if transactionSendRaw != "" {
+			// Send a raw transaction
+
+			cli.Assert(!offline, quiet, "Cannot send a raw transaction when offline")
+			transactionBroadcast("send", transactionSendRaw)
 			os.Exit(0)
 		}
 
-		cli.Assert(transactionSendFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, transactionSendFromAddress)
+		cli.AssertCode(transactionSendData == "" || transactionSendDataFile == "", quiet, cli.ExitUsage, "only one of --data and --data-file can be supplied")
+		cli.AssertCode(transactionSendFromAddress != "" || senderImplicit(), quiet, cli.ExitUsage, "--from is required")
+		fromAddress, err := senderAddress(transactionSendFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", transactionSendFromAddress))
 
+		if offline {
+			cli.Assert(!strings.HasSuffix(transactionSendToAddress, ".eth"), quiet, "ENS names cannot be resolved when offline")
+		}
 		var toAddress *common.Address
 		if transactionSendToAddress == "" {
 			// This is valid because it can be a contract creation, but only if there is data as well
-			cli.Assert(transactionSendData != "", quiet, "Transactions without a to address are contract creations and must have data")
+			cli.Assert(transactionSendData != "" || transactionSendDataFile != "", quiet, "Transactions without a to address are contract creations and must have data")
 		} else {
 			tmp, err := ens.Resolve(client, transactionSendToAddress)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", transactionSendToAddress))
+			cli.Assert(tmp != ens.UnknownAddress, quiet, fmt.Sprintf("%s resolves to the zero address; refusing to send", transactionSendToAddress))
 			toAddress = &tmp
 		}
 
@@ -104,23 +94,19 @@ In quiet mode this will return 0 if the transaction is successfully sent, otherw
 			amount = big.NewInt(0)
 		} else {
 			amount, err = etherutils.StringToWei(transactionSendAmount)
-			cli.ErrCheck(err, quiet, "Invalid amount")
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid amount")
 		}
 
-		// Obtain the balance of the address
-		ctx, cancel := localContext()
-		defer cancel()
-		balance, err := client.BalanceAt(ctx, fromAddress, nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
-		cli.Assert(balance.Cmp(amount) > 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", etherutils.WeiToString(balance, true)))
-
-		// Turn the data string in to hex
-		transactionSendData = strings.TrimPrefix(transactionSendData, "0x")
-		if len(transactionSendData)%2 == 1 {
-			// Doesn't like odd numbers
-			transactionSendData = "0" + transactionSendData
+		if !offline {
+			// Obtain the balance of the address
+			ctx, cancel := localContext()
+			defer cancel()
+			balance, err := client.BalanceAt(ctx, fromAddress, nil)
+			cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
+			cli.Assert(balance.Cmp(amount) > 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", etherutils.WeiToString(balance, true)))
 		}
-		data, err := hex.DecodeString(transactionSendData)
+
+		data, err := transactionData(transactionSendData, transactionSendDataFile)
 		cli.ErrCheck(err, quiet, "Failed to parse data")
 
 		// Create and sign the transaction
@@ -131,46 +117,37 @@ In quiet mode this will return 0 if the transaction is successfully sent, otherw
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			ctx, cancel := localContext()
 			defer cancel()
-			err = client.SendTransaction(ctx, signedTx)
+			err = sendTransaction(ctx, signedTx)
 			cli.ErrCheck(err, quiet, "Failed to send transaction")
 
-			if toAddress == nil {
-				log.WithFields(log.Fields{
-					"group":         "transaction",
-					"command":       "send",
-					"from":          fromAddress.Hex(),
-					"to":            toAddress.Hex(),
-					"amount":        amount.String(),
-					"data":          hex.EncodeToString(data),
-					"networkid":     chainID,
-					"gas":           signedTx.Gas(),
-					"gasprice":      signedTx.GasPrice().String(),
-					"transactionid": signedTx.Hash().Hex(),
-				}).Info("success")
-			} else {
-				log.WithFields(log.Fields{
-					"group":         "transaction",
-					"command":       "send",
-					"from":          fromAddress.Hex(),
-					"to":            toAddress.Hex(),
-					"amount":        amount.String(),
-					"data":          hex.EncodeToString(data),
-					"networkid":     chainID,
-					"gas":           signedTx.Gas(),
-					"gasprice":      signedTx.GasPrice().String(),
-					"transactionid": signedTx.Hash().Hex(),
-				}).Info("success")
+			fields := log.Fields{
+				"group":         "transaction",
+				"command":       "send",
+				"from":          fromAddress.Hex(),
+				"amount":        amount.String(),
+				"data":          hex.EncodeToString(data),
+				"networkid":     chainID,
+				"gas":           signedTx.Gas(),
+				"gasprice":      signedTx.GasPrice().String(),
+				"transactionid": signedTx.Hash().Hex(),
+			}
+			if toAddress != nil {
+				fields["to"] = toAddress.Hex()
+				if toName, err := ens.ReverseResolve(client, toAddress); err == nil {
+					fields["toname"] = toName
+				}
 			}
+			log.WithFields(fields).Info("success")
 
 			if quiet {
 				os.Exit(0)
 			}
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
@@ -181,6 +158,7 @@ func init() {
 	transactionSendCmd.Flags().StringVar(&transactionSendFromAddress, "from", "", "Address from which to transfer Ether")
 	transactionSendCmd.Flags().StringVar(&transactionSendToAddress, "to", "", "Address to which to transfer Ether")
 	transactionSendCmd.Flags().StringVar(&transactionSendData, "data", "", "data to send with transaction (as a hex string)")
-	transactionSendCmd.Flags().StringVar(&transactionSendRaw, "raw", "", "raw transaction (as a hex string).  This overrides all other options")
+	transactionSendCmd.Flags().StringVar(&transactionSendDataFile, "data-file", "", "file containing data to send with transaction (as a hex string)")
+	transactionSendCmd.Flags().StringVar(&transactionSendRaw, "raw", "", "raw transaction (as a hex string).  This overrides all other options; see also \"transaction broadcast\"")
 	addTransactionFlags(transactionSendCmd, "the address from which to transfer Ether")
 }
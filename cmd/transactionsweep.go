@@ -0,0 +1,40 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// transactionSweepCmd represents the transaction sweep command
+var transactionSweepCmd = &cobra.Command{
+	Use:   "sweep",
+	Short: "Sweep all Ether from one address to another",
+	Long: `Sweep all Ether from one address to another, leaving the sending address with a balance of 0.  For example:
+
+    ethereal transaction sweep --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --passphrase=secret
+
+The amount sent is the balance of the address less the cost of gas for the transaction.  If the balance is not sufficient to cover the cost of gas then an error is returned.
+
+If --dry-run is supplied then the sweep transaction, including the amount that would be swept, is displayed but not sent.
+
+In quiet mode this will return 0 if the sweep transaction is successfully sent, otherwise 1.`,
+	Run: etherSweep,
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionSweepCmd)
+	etherSweepFlags(transactionSweepCmd)
+	addTransactionFlags(transactionSweepCmd, "the address that holds the funds")
+}
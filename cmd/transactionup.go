@@ -28,19 +28,26 @@ import (
 	"github.com/wealdtech/ethereal/cli"
 )
 
+var transactionUpBumpPercent float64
+
 // transactionUpCmd represents the transaction up command
 var transactionUpCmd = &cobra.Command{
-	Use:   "up",
-	Short: "Increase the gas cost for a pending transaction",
+	Use:     "up",
+	Aliases: []string{"speedup"},
+	Short:   "Increase the gas cost for a pending transaction",
 	Long: `Increase the gas cost for a pending transaction.  For example:
 
     ethereal transaction up --gasprice=20gwei --passphrase=secret --transaction=0x454d2274155cce506359de6358785ce5366f6c13e825263674c272eec8532c0c
 
-If no gas price is supplied then it will default to 10% higher than the current gas price for the transaction.
+If no gas price is supplied then it will default to 10% higher than the current gas price for the transaction, or the suggested gas price if that is greater.  A different increase can be supplied with --bump-percent, for example:
+
+    ethereal transaction speedup --bump-percent=25 --passphrase=secret --transaction=0x454d2274155cce506359de6358785ce5366f6c13e825263674c272eec8532c0c
+
+The increase must be at least 10%, as nodes will not accept a replacement transaction with a smaller increase.
 
 In quiet mode this will return 0 if the transaction is successfully sent, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(transactionStr != "", quiet, "--transaction is required")
+		cli.AssertCode(transactionStr != "", quiet, cli.ExitUsage, "--transaction is required")
 		txHash := common.HexToHash(transactionStr)
 		ctx, cancel := localContext()
 		defer cancel()
@@ -48,11 +55,17 @@ In quiet mode this will return 0 if the transaction is successfully sent, otherw
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
 		cli.Assert(pending, quiet, fmt.Sprintf("Transaction %s has already been mined", txHash.Hex()))
 
-		minGasPrice := big.NewInt(0).Add(big.NewInt(0).Add(tx.GasPrice(), big.NewInt(0).Div(tx.GasPrice(), big.NewInt(10))), big.NewInt(10))
+		cli.AssertCode(transactionUpBumpPercent >= 10, quiet, cli.ExitUsage, "--bump-percent must be at least 10")
+		minGasPrice := transactionMinReplacementGasPrice(tx.GasPrice())
 		if viper.GetString("gasprice") == "" {
-			// No gas price supplied; use the calculated minimum
-			gasPrice = minGasPrice
+			// No gas price supplied; use the requested increase, or the suggested gas price if that is higher
+			bump := big.NewInt(0).Div(big.NewInt(0).Mul(tx.GasPrice(), big.NewInt(int64(transactionUpBumpPercent*1000))), big.NewInt(100000))
+			bumpedGasPrice := big.NewInt(0).Add(big.NewInt(0).Add(tx.GasPrice(), bump), big.NewInt(10))
+			if gasPrice.Cmp(bumpedGasPrice) < 0 {
+				gasPrice = bumpedGasPrice
+			}
 		} else {
+			cli.AssertCode(!cmd.Flags().Changed("bump-percent"), quiet, cli.ExitUsage, "Cannot supply both --gasprice and --bump-percent")
 			// Gas price supplied; ensure it is at least 10% more than the current gas price
 			cli.Assert(gasPrice.Cmp(minGasPrice) >= 0, quiet, fmt.Sprintf("Gas price must be at least %s", etherutils.WeiToString(minGasPrice, true)))
 		}
@@ -69,12 +82,12 @@ In quiet mode this will return 0 if the transaction is successfully sent, otherw
 			if !quiet {
 				buf := new(bytes.Buffer)
 				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				fmt.Fprintf(cli.Out, "0x%s\n", hex.EncodeToString(buf.Bytes()))
 			}
 		} else {
 			ctx, cancel := localContext()
 			defer cancel()
-			err = client.SendTransaction(ctx, signedTx)
+			err = sendTransaction(ctx, signedTx)
 			cli.ErrCheck(err, quiet, "Failed to send transaction")
 
 			if tx.To() == nil {
@@ -107,7 +120,7 @@ In quiet mode this will return 0 if the transaction is successfully sent, otherw
 			if quiet {
 				os.Exit(0)
 			}
-			fmt.Println(signedTx.Hash().Hex())
+			fmt.Fprintln(cli.Out, signedTx.Hash().Hex())
 		}
 	},
 }
@@ -115,5 +128,6 @@ In quiet mode this will return 0 if the transaction is successfully sent, otherw
 func init() {
 	transactionCmd.AddCommand(transactionUpCmd)
 	transactionFlags(transactionUpCmd)
+	transactionUpCmd.Flags().Float64Var(&transactionUpBumpPercent, "bump-percent", 10, "Percentage by which to increase the gas price if --gasprice is not supplied")
 	addTransactionFlags(transactionUpCmd, "the address that holds the funds")
 }
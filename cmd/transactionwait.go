@@ -0,0 +1,87 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var transactionWaitConfirmations int64
+
+// transactionWaitPollInterval is the time between checks for the transaction
+var transactionWaitPollInterval = 5 * time.Second
+
+// transactionWaitCmd represents the transaction wait command
+var transactionWaitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait for a transaction to be mined",
+	Long: `Wait for a transaction to be mined and optionally confirmed by subsequent blocks.  For example:
+
+    ethereal transaction wait --transaction=0x5219b09d629158c2759035c97b11b604f57d0c733515738aaae0d2dafb41ab98 --confirmations=6 --wait-timeout=10m
+
+The command waits for at most the duration given by --wait-timeout.  If the connection is over WebSocket or IPC then new blocks are notified as they arrive, otherwise the node is polled.  If the transaction is mined but fails then this will exit immediately with an error.
+
+In quiet mode this will return 0 if the transaction is mined successfully with the required number of confirmations, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(transactionStr != "", quiet, cli.ExitUsage, "--transaction is required")
+		cli.AssertCode(transactionWaitConfirmations >= 0, quiet, cli.ExitUsage, "--confirmations cannot be negative")
+		txHash := common.HexToHash(transactionStr)
+
+		ctx, cancel := waitContext()
+		defer cancel()
+		waiter := newBlockWaiter(ctx, transactionWaitPollInterval)
+		defer waiter.close()
+		for {
+			blockNumber, status, err := transactionReceiptBlock(ctx, txHash)
+			if err == nil {
+				cli.AssertCode(status != 0, quiet, cli.ExitExecution, fmt.Sprintf("Transaction %s failed in block %v", txHash.Hex(), blockNumber))
+				header, err := client.HeaderByNumber(ctx, nil)
+				if ctx.Err() == nil {
+					cli.ErrCheck(err, quiet, "Failed to obtain latest block")
+					confirmations := big.NewInt(0).Sub(header.Number, blockNumber)
+					if confirmations.Cmp(big.NewInt(transactionWaitConfirmations)) >= 0 {
+						if !quiet {
+							fmt.Fprintf(cli.Out, "Mined in block %v with %v confirmations\n", blockNumber, confirmations)
+						}
+						os.Exit(0)
+					}
+					diagnosticIf(verbose, fmt.Sprintf("Mined in block %v; %v of %d confirmations", blockNumber, confirmations, transactionWaitConfirmations))
+				}
+			} else if ctx.Err() == nil && err != ethereum.NotFound {
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain receipt for transaction %s", txHash.Hex()))
+			} else {
+				diagnosticIf(verbose, "Transaction not yet mined")
+			}
+
+			if !waiter.wait(ctx) {
+				cli.Err(quiet, fmt.Sprintf("Timed out waiting for transaction %s", txHash.Hex()))
+			}
+		}
+	},
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionWaitCmd)
+	transactionFlags(transactionWaitCmd)
+	transactionWaitCmd.Flags().Int64Var(&transactionWaitConfirmations, "confirmations", 0, "Number of blocks required on top of the block containing the transaction")
+}
@@ -0,0 +1,223 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+var transactionWatchAddress string
+var transactionWatchFrom string
+var transactionWatchTo string
+
+// transactionWatchTx contains the fields of a pending transaction that are
+// used when watching
+type transactionWatchTx struct {
+	Hash  common.Hash     `json:"hash"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Input hexutil.Bytes   `json:"input"`
+	Nonce hexutil.Uint64  `json:"nonce"`
+}
+
+// transactionWatchFilter selects the transactions to display; all addresses
+// that are set must match
+type transactionWatchFilter struct {
+	address *common.Address
+	from    *common.Address
+	to      *common.Address
+}
+
+// transactionWatchCmd represents the transaction watch command
+var transactionWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch for pending transactions",
+	Long: `Watch for pending transactions from or to an address.  For example:
+
+    ethereal transaction watch --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --timeout=10m
+
+--address matches transactions either from or to the address.  --from and --to match only transactions from or to their address respectively, and can be combined with each other or with --address, in which case transactions must match all of them.
+
+Each matching transaction is displayed once, with its data decoded if the function it calls is known.  The command watches for at most the duration given by --timeout.  If the connection is over WebSocket or IPC then the command subscribes to the node's pending transactions, otherwise it polls the node's transaction pool, which requires the txpool API.
+
+In quiet mode this will return 0 as soon as a matching transaction is seen, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported with this command")
+		cli.AssertCode(transactionWatchAddress != "" || transactionWatchFrom != "" || transactionWatchTo != "", quiet, cli.ExitUsage, "--address, --from or --to is required")
+
+		filter := &transactionWatchFilter{}
+		filter.address = transactionWatchResolve(transactionWatchAddress)
+		filter.from = transactionWatchResolve(transactionWatchFrom)
+		filter.to = transactionWatchResolve(transactionWatchTo)
+
+		txdata.InitFunctionMap()
+		ctx, cancel := localContext()
+		defer cancel()
+		seen := make(map[common.Hash]bool)
+		report := func(tx *transactionWatchTx) {
+			if seen[tx.Hash] || !filter.matches(tx) {
+				return
+			}
+			seen[tx.Hash] = true
+			if quiet {
+				os.Exit(0)
+			}
+			transactionWatchOutput(tx)
+		}
+
+		hashes := make(chan common.Hash, 256)
+		sub, err := rpcClient.EthSubscribe(ctx, hashes, "newPendingTransactions")
+		if err == nil {
+			diagnosticIf(verbose, "Subscribed to pending transactions")
+			transactionWatchSubscribed(ctx, hashes, sub, report)
+			sub.Unsubscribe()
+		}
+		if ctx.Err() == nil {
+			diagnosticIf(verbose, "Polling the transaction pool")
+			transactionWatchPoll(ctx, report)
+		}
+
+		if quiet {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+// transactionWatchResolve resolves an address supplied as a flag, returning
+// nil if the flag is not supplied
+func transactionWatchResolve(input string) *common.Address {
+	if input == "" {
+		return nil
+	}
+	address, err := ens.Resolve(client, input)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve address %s", input))
+	return &address
+}
+
+// matches returns true if the transaction passes the filter
+func (f *transactionWatchFilter) matches(tx *transactionWatchTx) bool {
+	if f.address != nil && tx.From != *f.address && (tx.To == nil || *tx.To != *f.address) {
+		return false
+	}
+	if f.from != nil && tx.From != *f.from {
+		return false
+	}
+	if f.to != nil && (tx.To == nil || *tx.To != *f.to) {
+		return false
+	}
+	return true
+}
+
+// transactionWatchSubscribed reports transactions as the node notifies them.
+// It returns when the context is done or if the subscription fails.
+func transactionWatchSubscribed(ctx context.Context, hashes chan common.Hash, sub *rpc.ClientSubscription, report func(*transactionWatchTx)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-sub.Err():
+			diagnosticIf(verbose, fmt.Sprintf("Subscription to pending transactions failed: %v", err))
+			return
+		case hash := <-hashes:
+			var tx *transactionWatchTx
+			if err := rpcClient.CallContext(ctx, &tx, "eth_getTransactionByHash", hash); err != nil {
+				if ctx.Err() == nil {
+					diagnosticIf(verbose, fmt.Sprintf("Failed to obtain transaction %s: %v", hash.Hex(), err))
+				}
+				continue
+			}
+			if tx != nil {
+				// Transactions can be mined or dropped before they are obtained
+				report(tx)
+			}
+		}
+	}
+}
+
+// transactionWatchPoll reports transactions from the node's transaction pool
+// until the context is done
+func transactionWatchPoll(ctx context.Context, report func(*transactionWatchTx)) {
+	for {
+		var content struct {
+			Pending map[common.Address]map[string]*transactionWatchTx `json:"pending"`
+		}
+		err := rpcClient.CallContext(ctx, &content, "txpool_content")
+		if ctx.Err() != nil {
+			return
+		}
+		cli.AssertCode(err == nil || !methodUnavailable(err), quiet, cli.ExitNetwork, "The node does not provide the txpool API; use a WebSocket or IPC connection to subscribe to pending transactions")
+		cli.ErrCheck(err, quiet, "Failed to obtain transaction pool content")
+		pending := make([]*transactionWatchTx, 0)
+		for from, txs := range content.Pending {
+			for _, tx := range txs {
+				tx.From = from
+				pending = append(pending, tx)
+			}
+		}
+		sort.Slice(pending, func(i, j int) bool {
+			if pending[i].From != pending[j].From {
+				return bytes.Compare(pending[i].From.Bytes(), pending[j].From.Bytes()) < 0
+			}
+			return pending[i].Nonce < pending[j].Nonce
+		})
+		for _, tx := range pending {
+			report(tx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(transactionWaitPollInterval):
+		}
+	}
+}
+
+// transactionWatchOutput displays a pending transaction
+func transactionWatchOutput(tx *transactionWatchTx) {
+	to := "contract creation"
+	if tx.To != nil {
+		to = tx.To.Hex()
+	}
+	value := "0"
+	if tx.Value != nil {
+		value = etherutils.WeiToString(tx.Value.ToInt(), true)
+	}
+	fmt.Fprintf(cli.Out, "%s\t%s -> %s\t%s\n", tx.Hash.Hex(), tx.From.Hex(), to, value)
+	if len(tx.Input) > 0 {
+		fmt.Fprintf(cli.Out, "\t%s\n", txdata.DataToString(tx.Input))
+	}
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionWatchCmd)
+	transactionWatchCmd.Flags().StringVar(&transactionWatchAddress, "address", "", "Address from or to which to watch for transactions")
+	transactionWatchCmd.Flags().StringVar(&transactionWatchFrom, "from", "", "Address from which to watch for transactions")
+	transactionWatchCmd.Flags().StringVar(&transactionWatchTo, "to", "", "Address to which to watch for transactions")
+}
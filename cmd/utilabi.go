@@ -0,0 +1,55 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+// utilAbiCmd represents the util abi command
+var utilAbiCmd = &cobra.Command{
+	Use:   "abi",
+	Short: "Encode and decode ABI data",
+	Long:  `Encode values to and decode values from the format used in contract calls.`,
+}
+
+func init() {
+	utilCmd.AddCommand(utilAbiCmd)
+}
+
+// utilAbiTypes obtains the types of the values to encode or decode, either as
+// supplied or from a function signature.  If a signature is supplied its
+// selector is also returned.
+func utilAbiTypes(typesStr string, signature string) (selector []byte, types []string) {
+	var err error
+	switch {
+	case typesStr != "" && signature != "":
+		cli.ErrCode(quiet, cli.ExitUsage, "only one of --types and --signature can be supplied")
+	case signature != "":
+		_, types, err = txdata.SignatureTypes(signature)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid signature %s", signature))
+		selector = crypto.Keccak256([]byte(signature))[:4]
+	case typesStr != "":
+		types, err = txdata.ParseTypes(typesStr)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid types %s", typesStr))
+	default:
+		cli.ErrCode(quiet, cli.ExitUsage, "--types or --signature is required")
+	}
+	return
+}
@@ -0,0 +1,69 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/txdata"
+)
+
+var utilAbiDecodeTypes string
+var utilAbiDecodeSignature string
+
+// utilAbiDecodeCmd represents the util abi decode command
+var utilAbiDecodeCmd = &cobra.Command{
+	Use:   "decode [data]",
+	Short: "Decode ABI-encoded data",
+	Long: `Decode ABI-encoded data, displaying each value on its own line.  For example:
+
+    ethereal util abi decode --types=address,uint256 0x0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc40000000000000000000000000000000000000000000000000000000000000064
+
+If a function signature is supplied with --signature in place of --types then the data is decoded as a call to the function, and must start with the function's selector.  This command does not need a connection to an Ethereum node.
+
+In quiet mode this will return 0 if the data is decoded, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(len(args) == 1, quiet, cli.ExitUsage, "data is required")
+		selector, types := utilAbiTypes(utilAbiDecodeTypes, utilAbiDecodeSignature)
+		data, err := hex.DecodeString(strings.TrimPrefix(args[0], "0x"))
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid data")
+		if selector != nil {
+			cli.Assert(len(data) >= 4 && bytes.Equal(data[:4], selector), quiet, fmt.Sprintf("Data does not start with selector 0x%x", selector))
+			data = data[4:]
+		}
+
+		values, err := txdata.DecodeValues(types, data)
+		cli.ErrCheck(err, quiet, "Failed to decode data")
+
+		if quiet {
+			os.Exit(0)
+		}
+		for _, value := range values {
+			fmt.Fprintln(cli.Out, value)
+		}
+	},
+}
+
+func init() {
+	utilAbiCmd.AddCommand(utilAbiDecodeCmd)
+	utilAbiDecodeCmd.Flags().StringVar(&utilAbiDecodeTypes, "types", "", "Comma-separated types of the values")
+	utilAbiDecodeCmd.Flags().StringVar(&utilAbiDecodeSignature, "signature", "", "Signature of the function whose call is decoded")
+}
@@ -0,0 +1,71 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var utilAbiEncodeTypes string
+var utilAbiEncodeSignature string
+
+// utilAbiEncodeCmd represents the util abi encode command
+var utilAbiEncodeCmd = &cobra.Command{
+	Use:   "encode [value...]",
+	Short: "ABI-encode values",
+	Long: `ABI-encode values, displaying the encoded data as hex.  For example:
+
+    ethereal util abi encode --types=address,uint256 0x5FfC014343cd971B7eb70732021E26C35B744cc4 100
+
+If a function signature is supplied with --signature in place of --types then the values are encoded as the parameters of the function and prefixed with the function's selector, giving data that can be sent to a contract.  For example:
+
+    ethereal util abi encode --signature="transfer(address,uint256)" 0x5FfC014343cd971B7eb70732021E26C35B744cc4 100
+
+Integers are supplied in decimal, and addresses and bytes in hex.  This command does not need a connection to an Ethereum node.
+
+In quiet mode this will return 0 if the values are encoded, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		selector, types := utilAbiTypes(utilAbiEncodeTypes, utilAbiEncodeSignature)
+		cli.Assert(len(args) == len(types), quiet, fmt.Sprintf("%d values are required but %d were supplied", len(types), len(args)))
+
+		arguments := make(abi.Arguments, len(types))
+		values := make([]interface{}, len(types))
+		for i, typeStr := range types {
+			argType, err := abi.NewType(typeStr)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Unsupported type %s", typeStr))
+			arguments[i] = abi.Argument{Type: argType}
+			values[i], err = contractStringToValue(argType, args[i])
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid value %s for %s", args[i], typeStr))
+		}
+		data, err := arguments.Pack(values...)
+		cli.ErrCheck(err, quiet, "Failed to encode values")
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintf(cli.Out, "0x%x%x\n", selector, data)
+	},
+}
+
+func init() {
+	utilAbiCmd.AddCommand(utilAbiEncodeCmd)
+	utilAbiEncodeCmd.Flags().StringVar(&utilAbiEncodeTypes, "types", "", "Comma-separated types of the values")
+	utilAbiEncodeCmd.Flags().StringVar(&utilAbiEncodeSignature, "signature", "", "Signature of the function whose parameters are encoded")
+}
@@ -0,0 +1,92 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var utilConvertValue string
+var utilConvertFrom string
+var utilConvertTo string
+var utilConvertWei bool
+
+// utilConvertCmd represents the util convert command
+var utilConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert between units of Ether",
+	Long: `Convert a value between units of Ether.  For example:
+
+    ethereal util convert --value=1.5 --from=ether --to=gwei
+
+The units are wei, kwei, mwei, gwei, szabo, finney and ether.  If --from is not supplied the value can include its unit, for example --value="1.5 ether", and otherwise is taken to be in wei.  --wei is a shortcut for --to=wei.  Conversions are exact, with no rounding.  This command does not need a connection to an Ethereum node.
+
+In quiet mode this will return 0 if the value is converted, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(utilConvertValue != "", quiet, cli.ExitUsage, "--value is required")
+		to := utilConvertTo
+		if utilConvertWei {
+			cli.AssertCode(!cmd.Flags().Changed("to"), quiet, cli.ExitUsage, "only one of --to and --wei can be supplied")
+			to = "wei"
+		}
+		multiplier, err := utilConvertMultiplier(to)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid --to")
+
+		input := utilConvertValue
+		if utilConvertFrom != "" {
+			if _, err := utilConvertMultiplier(utilConvertFrom); err != nil {
+				cli.ErrCode(quiet, cli.ExitUsage, fmt.Sprintf("Invalid --from: %v", err))
+			}
+			input = fmt.Sprintf("%s%s", utilConvertValue, utilConvertUnit(utilConvertFrom))
+		}
+		wei, err := etherutils.StringToWei(input)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid value %s", utilConvertValue))
+
+		if quiet {
+			os.Exit(0)
+		}
+		decimals := uint8(len(multiplier.String()) - 1)
+		fmt.Fprintln(cli.Out, util.TokenValueToString(wei, decimals, false))
+	},
+}
+
+// utilConvertUnit maps unit names to those understood by etherutils
+func utilConvertUnit(unit string) string {
+	if strings.ToLower(unit) == "szabo" {
+		return "microether"
+	}
+	return unit
+}
+
+// utilConvertMultiplier returns the number of wei in a unit
+func utilConvertMultiplier(unit string) (*big.Int, error) {
+	return etherutils.UnitToMultiplier(utilConvertUnit(unit))
+}
+
+func init() {
+	utilCmd.AddCommand(utilConvertCmd)
+	utilConvertCmd.Flags().StringVar(&utilConvertValue, "value", "", "Value to convert")
+	utilConvertCmd.Flags().StringVar(&utilConvertFrom, "from", "", "Unit of the value")
+	utilConvertCmd.Flags().StringVar(&utilConvertTo, "to", "ether", "Unit to which to convert the value")
+	utilConvertCmd.Flags().BoolVar(&utilConvertWei, "wei", false, "Convert the value to wei")
+}
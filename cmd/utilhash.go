@@ -0,0 +1,70 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var utilHashString string
+var utilHashHex string
+
+// utilHashCmd represents the util hash command
+var utilHashCmd = &cobra.Command{
+	Use:   "hash",
+	Short: "Calculate the Keccak-256 hash of data",
+	Long: `Calculate the Keccak-256 hash of a string or of hex data.  For example:
+
+    ethereal util hash --string="transfer(address,uint256)"
+
+    ethereal util hash --hex=0x0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc40000000000000000000000000000000000000000000000000000000000000001
+
+The hash is displayed along with its first four bytes, which for a function signature is the function's selector.  This command does not need a connection to an Ethereum node.
+
+In quiet mode this will return 0 if the hash is calculated, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(utilHashString != "" || utilHashHex != "", quiet, cli.ExitUsage, "--string or --hex is required")
+		cli.AssertCode(utilHashString == "" || utilHashHex == "", quiet, cli.ExitUsage, "only one of --string and --hex can be supplied")
+
+		var data []byte
+		if utilHashHex != "" {
+			var err error
+			data, err = hex.DecodeString(strings.TrimPrefix(utilHashHex, "0x"))
+			cli.ErrCheckCode(err, quiet, cli.ExitUsage, "Invalid hex")
+		} else {
+			data = []byte(utilHashString)
+		}
+		hash := crypto.Keccak256(data)
+
+		if quiet {
+			os.Exit(0)
+		}
+		fmt.Fprintf(cli.Out, "Hash:\t\t0x%x\n", hash)
+		fmt.Fprintf(cli.Out, "Selector:\t0x%x\n", hash[:4])
+	},
+}
+
+func init() {
+	utilCmd.AddCommand(utilHashCmd)
+	utilHashCmd.Flags().StringVar(&utilHashString, "string", "", "String to hash")
+	utilHashCmd.Flags().StringVar(&utilHashHex, "hex", "", "Hex data to hash")
+}
@@ -0,0 +1,61 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/ens"
+)
+
+var utilNamehashName string
+
+// utilNamehashCmd represents the util namehash command
+var utilNamehashCmd = &cobra.Command{
+	Use:   "namehash",
+	Short: "Calculate the namehash of an ENS name",
+	Long: `Calculate the namehash of an ENS name, as used to identify the name in the ENS registry and resolvers.  For example:
+
+    ethereal util namehash --name=enstest.eth
+
+The name is normalized before it is hashed.  In verbose mode the normalized name and the hash of its first label are also displayed.  This command does not need a connection to an Ethereum node.
+
+In quiet mode this will return 0 if the name is valid, otherwise 1.`,
+	Annotations: map[string]string{localAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.AssertCode(utilNamehashName != "", quiet, cli.ExitUsage, "--name is required")
+		name, err := ens.NormaliseDomainStrict(utilNamehashName)
+		cli.ErrCheckCode(err, quiet, cli.ExitUsage, fmt.Sprintf("Invalid name %s", utilNamehashName))
+
+		if quiet {
+			os.Exit(0)
+		}
+		diagnosticIf(verbose, fmt.Sprintf("Name:\t\t%s", name))
+		if verbose && name != "" {
+			label, err := ens.DomainPart(name, 1)
+			if err == nil {
+				fmt.Fprintf(cli.Out, "Labelhash:\t0x%x\n", ens.LabelHash(label))
+			}
+		}
+		fmt.Fprintf(cli.Out, "0x%x\n", ens.NameHash(name))
+	},
+}
+
+func init() {
+	utilCmd.AddCommand(utilNamehashCmd)
+	utilNamehashCmd.Flags().StringVar(&utilNamehashName, "name", "", "ENS name to hash")
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
 )
 
 // versionCmd represents the version command
@@ -27,7 +28,7 @@ var versionCmd = &cobra.Command{
 
     ethereal version.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("1.2.128")
+		fmt.Fprintln(cli.Out, "1.2.128")
 	},
 }
 
@@ -0,0 +1,202 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const erc721MetadataABI = `[{"constant":true,"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"tokenId","type":"uint256"}],"name":"tokenURI","outputs":[{"name":"","type":"string"}],"payable":false,"stateMutability":"view","type":"function"}]`
+const erc1155MetadataABI = `[{"constant":true,"inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"uri","outputs":[{"name":"","type":"string"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// avatarNFTRegex matches avatar records that refer to an NFT, for example
+// eip155:1/erc721:0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB/1234
+var avatarNFTRegex = regexp.MustCompile(`^eip155:([0-9]+)/(erc721|erc1155):(0x[0-9a-fA-F]{40})/([0-9]+)$`)
+
+// Avatar is the parsed value of an avatar text record
+type Avatar struct {
+	// URI is the URI of the avatar if it is not an NFT
+	URI string
+	// ChainID is the chain on which the NFT resides
+	ChainID *big.Int
+	// Standard is the token standard of the NFT, either erc721 or erc1155
+	Standard string
+	// Contract is the address of the NFT contract
+	Contract common.Address
+	// TokenID is the ID of the NFT within its contract
+	TokenID *big.Int
+}
+
+// IsNFT returns true if the avatar refers to an NFT
+func (a *Avatar) IsNFT() bool {
+	return a.Standard != ""
+}
+
+// ParseAvatar parses the value of an avatar text record
+func ParseAvatar(record string) (*Avatar, error) {
+	record = strings.TrimSpace(record)
+	if record == "" {
+		return nil, errors.New("no avatar")
+	}
+	if match := avatarNFTRegex.FindStringSubmatch(strings.ToLower(record)); match != nil {
+		chainID, _ := new(big.Int).SetString(match[1], 10)
+		tokenID, _ := new(big.Int).SetString(match[4], 10)
+		return &Avatar{
+			ChainID:  chainID,
+			Standard: match[2],
+			Contract: common.HexToAddress(match[3]),
+			TokenID:  tokenID,
+		}, nil
+	}
+	if strings.HasPrefix(strings.ToLower(record), "eip155:") {
+		return nil, fmt.Errorf("invalid NFT avatar %s", record)
+	}
+	if strings.HasPrefix(record, "/ipfs/") || strings.HasPrefix(record, "/ipns/") {
+		return &Avatar{URI: record}, nil
+	}
+	parsed, err := url.Parse(record)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avatar URI %s", record)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "ipfs", "ipns", "ar", "data":
+		return &Avatar{URI: record}, nil
+	case "":
+		return nil, fmt.Errorf("avatar URI %s has no scheme", record)
+	default:
+		return nil, fmt.Errorf("unsupported avatar URI scheme %s", parsed.Scheme)
+	}
+}
+
+// GatewayURL converts IPFS, IPNS and Arweave URIs to HTTP URLs, using the
+// given IPFS gateway.  Other URIs are returned unchanged
+func GatewayURL(uri string, gateway string) string {
+	gateway = strings.TrimSuffix(strings.TrimSuffix(gateway, "/"), "/ipfs")
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		// Some URIs repeat the namespace, for example ipfs://ipfs/Qm...
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return fmt.Sprintf("%s/ipfs/%s", gateway, path)
+	case strings.HasPrefix(uri, "ipns://"):
+		return fmt.Sprintf("%s/ipns/%s", gateway, strings.TrimPrefix(uri, "ipns://"))
+	case strings.HasPrefix(uri, "/ipfs/") || strings.HasPrefix(uri, "/ipns/"):
+		return gateway + uri
+	case strings.HasPrefix(uri, "ar://"):
+		return fmt.Sprintf("https://arweave.net/%s", strings.TrimPrefix(uri, "ar://"))
+	default:
+		return uri
+	}
+}
+
+// ERC1155URI substitutes the token ID in to an ERC-1155 metadata URI, which
+// uses {id} as a placeholder for the ID in 64-character hex
+func ERC1155URI(uri string, id *big.Int) string {
+	return strings.Replace(uri, "{id}", fmt.Sprintf("%064x", id), -1)
+}
+
+// DecodeDataURI decodes the content of a data: URI
+func DecodeDataURI(uri string) ([]byte, error) {
+	if !strings.HasPrefix(uri, "data:") {
+		return nil, errors.New("not a data URI")
+	}
+	parts := strings.SplitN(strings.TrimPrefix(uri, "data:"), ",", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("data URI has no content")
+	}
+	if strings.HasSuffix(parts[0], ";base64") {
+		return base64.StdEncoding.DecodeString(parts[1])
+	}
+	content, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+// AvatarImage obtains the image URI from NFT metadata
+func AvatarImage(metadata []byte) (string, error) {
+	var fields struct {
+		Image     string `json:"image"`
+		ImageURL  string `json:"image_url"`
+		ImageData string `json:"image_data"`
+	}
+	if err := json.Unmarshal(metadata, &fields); err != nil {
+		return "", fmt.Errorf("invalid metadata: %v", err)
+	}
+	switch {
+	case fields.Image != "":
+		return fields.Image, nil
+	case fields.ImageURL != "":
+		return fields.ImageURL, nil
+	case fields.ImageData != "":
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(fields.ImageData)), nil
+	default:
+		return "", errors.New("metadata has no image")
+	}
+}
+
+// AvatarMetadataURI obtains the metadata URI of an NFT avatar from its
+// contract
+func AvatarMetadataURI(client *ethclient.Client, avatar *Avatar) (uri string, err error) {
+	if avatar.Standard == "erc1155" {
+		contract, err := boundContract(client, avatar.Contract, erc1155MetadataABI)
+		if err != nil {
+			return "", err
+		}
+		if err = contract.Call(nil, &uri, "uri", avatar.TokenID); err != nil {
+			return "", err
+		}
+		return ERC1155URI(uri, avatar.TokenID), nil
+	}
+	contract, err := boundContract(client, avatar.Contract, erc721MetadataABI)
+	if err != nil {
+		return
+	}
+	err = contract.Call(nil, &uri, "tokenURI", avatar.TokenID)
+	return
+}
+
+// AvatarOwned returns true if an address holds the NFT of an avatar
+func AvatarOwned(client *ethclient.Client, avatar *Avatar, address common.Address) (bool, error) {
+	if avatar.Standard == "erc1155" {
+		contract, err := boundContract(client, avatar.Contract, erc1155MetadataABI)
+		if err != nil {
+			return false, err
+		}
+		var balance *big.Int
+		if err = contract.Call(nil, &balance, "balanceOf", address, avatar.TokenID); err != nil {
+			return false, err
+		}
+		return balance.Sign() > 0, nil
+	}
+	contract, err := boundContract(client, avatar.Contract, erc721MetadataABI)
+	if err != nil {
+		return false, err
+	}
+	var owner common.Address
+	if err = contract.Call(nil, &owner, "ownerOf", avatar.TokenID); err != nil {
+		return false, err
+	}
+	return owner == address, nil
+}
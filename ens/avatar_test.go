@@ -0,0 +1,154 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAvatar(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   string
+		uri      string
+		chainID  int64
+		standard string
+		contract string
+		tokenID  int64
+		err      bool
+	}{
+		{name: "Empty", record: "", err: true},
+		{name: "HTTPS", record: "https://example.com/avatar.png", uri: "https://example.com/avatar.png"},
+		{name: "IPFS", record: "ipfs://QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz", uri: "ipfs://QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz"},
+		{name: "IPFSPath", record: "/ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz", uri: "/ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz"},
+		{name: "Data", record: "data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=", uri: "data:image/svg+xml;base64,PHN2Zz48L3N2Zz4="},
+		{name: "NoScheme", record: "example.com/avatar.png", err: true},
+		{name: "BadScheme", record: "ftp://example.com/avatar.png", err: true},
+		{name: "ERC721", record: "eip155:1/erc721:0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB/1234", chainID: 1, standard: "erc721", contract: "0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB", tokenID: 1234},
+		{name: "ERC1155", record: "eip155:1/erc1155:0x495f947276749ce646f68ac8c248420045cb7b5e/8112316025873927737505937898915153732580103913704334048512380490797008551937", chainID: 1, standard: "erc1155", contract: "0x495f947276749Ce646f68AC8c248420045cb7b5e"},
+		{name: "OtherChain", record: "eip155:137/erc721:0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB/5", chainID: 137, standard: "erc721", contract: "0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB", tokenID: 5},
+		{name: "BadStandard", record: "eip155:1/erc20:0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB/1", err: true},
+		{name: "BadContract", record: "eip155:1/erc721:0xb47e3cd837/1", err: true},
+		{name: "BadTokenID", record: "eip155:1/erc721:0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB/x", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			avatar, err := ParseAvatar(test.record)
+			if test.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.uri, avatar.URI)
+			assert.Equal(t, test.standard != "", avatar.IsNFT())
+			if avatar.IsNFT() {
+				assert.Equal(t, big.NewInt(test.chainID), avatar.ChainID)
+				assert.Equal(t, test.standard, avatar.Standard)
+				assert.Equal(t, common.HexToAddress(test.contract), avatar.Contract)
+				if test.tokenID != 0 {
+					assert.Equal(t, big.NewInt(test.tokenID), avatar.TokenID)
+				}
+			}
+		})
+	}
+}
+
+func TestGatewayURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		gateway string
+		url     string
+	}{
+		{name: "HTTPS", uri: "https://example.com/avatar.png", gateway: "https://ipfs.io", url: "https://example.com/avatar.png"},
+		{name: "IPFS", uri: "ipfs://QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz/1.png", gateway: "https://ipfs.io", url: "https://ipfs.io/ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz/1.png"},
+		{name: "IPFSRepeated", uri: "ipfs://ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz", gateway: "https://ipfs.io", url: "https://ipfs.io/ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz"},
+		{name: "IPFSPath", uri: "/ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz", gateway: "https://ipfs.io", url: "https://ipfs.io/ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz"},
+		{name: "IPNS", uri: "ipns://example.eth", gateway: "https://ipfs.io", url: "https://ipfs.io/ipns/example.eth"},
+		{name: "GatewaySlash", uri: "ipfs://QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz", gateway: "https://gateway.example.com/", url: "https://gateway.example.com/ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz"},
+		{name: "GatewayIPFS", uri: "ipfs://QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz", gateway: "https://gateway.example.com/ipfs/", url: "https://gateway.example.com/ipfs/QmUbTVz1xcvU3Pd6Wh3rJXfZxnxy5Tps4q2Crg7mKNKJvz"},
+		{name: "Arweave", uri: "ar://abc123", gateway: "https://ipfs.io", url: "https://arweave.net/abc123"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.url, GatewayURL(test.uri, test.gateway))
+		})
+	}
+}
+
+func TestERC1155URI(t *testing.T) {
+	assert.Equal(t, "https://example.com/00000000000000000000000000000000000000000000000000000000000004d2.json", ERC1155URI("https://example.com/{id}.json", big.NewInt(1234)))
+	assert.Equal(t, "https://example.com/1234.json", ERC1155URI("https://example.com/1234.json", big.NewInt(1234)))
+}
+
+func TestDecodeDataURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		content string
+		err     bool
+	}{
+		{name: "NotData", uri: "https://example.com/", err: true},
+		{name: "NoContent", uri: "data:application/json", err: true},
+		{name: "Base64", uri: "data:application/json;base64,eyJpbWFnZSI6IngifQ==", content: `{"image":"x"}`},
+		{name: "Plain", uri: "data:application/json,%7B%22image%22%3A%22x%22%7D", content: `{"image":"x"}`},
+		{name: "UTF8", uri: `data:application/json;utf8,{"image":"x"}`, content: `{"image":"x"}`},
+		{name: "BadBase64", uri: "data:application/json;base64,!!", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := DecodeDataURI(test.uri)
+			if test.err {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.content, string(content))
+			}
+		})
+	}
+}
+
+func TestAvatarImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		image    string
+		err      bool
+	}{
+		{name: "Invalid", metadata: "not json", err: true},
+		{name: "NoImage", metadata: `{"name":"test"}`, err: true},
+		{name: "Image", metadata: `{"image":"ipfs://Qm1","image_url":"https://example.com/2.png"}`, image: "ipfs://Qm1"},
+		{name: "ImageURL", metadata: `{"image_url":"https://example.com/2.png"}`, image: "https://example.com/2.png"},
+		{name: "ImageData", metadata: `{"image_data":"<svg></svg>"}`, image: "data:image/svg+xml;base64,PHN2Zz48L3N2Zz4="},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			image, err := AvatarImage([]byte(test.metadata))
+			if test.err {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.image, image)
+			}
+		})
+	}
+}
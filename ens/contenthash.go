@@ -0,0 +1,211 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Multicodec values used in content hashes
+const (
+	codecIPFS       = 0xe3
+	codecSwarm      = 0xe4
+	codecIPNS       = 0xe5
+	codecDagPB      = 0x70
+	codecLibp2pKey  = 0x72
+	codecSwarmManif = 0xfa
+	hashIdentity    = 0x00
+	hashSHA256      = 0x12
+	hashKeccak256   = 0x1b
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+const base36Alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var base32Encoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// ContenthashToURI decodes an EIP-1577 content hash to a URI such as
+// ipfs://Qm..., ipns://k51... or bzz://d1de...
+func ContenthashToURI(hash []byte) (string, error) {
+	codec, n := binary.Uvarint(hash)
+	if n <= 0 {
+		return "", errors.New("invalid content hash")
+	}
+	cid := hash[n:]
+	contentCodec, multihash, err := parseCID(cid)
+	if err != nil {
+		return "", err
+	}
+	switch codec {
+	case codecIPFS:
+		if contentCodec == codecDagPB && multihash[0] == hashSHA256 {
+			// Can be represented as a version 0 CID
+			return "ipfs://" + encodeBaseX(multihash, base58Alphabet), nil
+		}
+		return "ipfs://b" + base32Encoding.EncodeToString(cid), nil
+	case codecIPNS:
+		if multihash[0] == hashIdentity && contentCodec == codecDagPB {
+			// Legacy DNSLink name
+			return "ipns://" + string(multihash[2:]), nil
+		}
+		return "ipns://k" + encodeBaseX(cid, base36Alphabet), nil
+	case codecSwarm:
+		if contentCodec != codecSwarmManif || multihash[0] != hashKeccak256 {
+			return "", errors.New("invalid swarm content hash")
+		}
+		return "bzz://" + hex.EncodeToString(multihash[2:]), nil
+	default:
+		return "", fmt.Errorf("unsupported content hash codec 0x%x", codec)
+	}
+}
+
+// URIToContenthash encodes a URI such as ipfs://Qm..., ipns://k51... or
+// bzz://d1de... as an EIP-1577 content hash
+func URIToContenthash(uri string) ([]byte, error) {
+	parts := strings.SplitN(uri, "://", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid content URI %s", uri)
+	}
+	value := strings.TrimSuffix(parts[1], "/")
+	if strings.Contains(value, "/") {
+		return nil, errors.New("content URI cannot contain a path")
+	}
+
+	var codec uint64
+	var cid []byte
+	var err error
+	switch parts[0] {
+	case "ipfs":
+		codec = codecIPFS
+		cid, err = decodeCID(value, codecDagPB)
+	case "ipns":
+		codec = codecIPNS
+		cid, err = decodeCID(value, codecLibp2pKey)
+	case "bzz":
+		codec = codecSwarm
+		var swarmHash []byte
+		swarmHash, err = hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		if err == nil && len(swarmHash) != 32 {
+			err = errors.New("swarm hash must be 32 bytes")
+		}
+		cid = append([]byte{0x01, codecSwarmManif, 0x01, hashKeccak256, 0x20}, swarmHash...)
+	default:
+		return nil, fmt.Errorf("unsupported content URI scheme %s", parts[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err = parseCID(cid); err != nil {
+		return nil, err
+	}
+	return append(uvarint(codec), cid...), nil
+}
+
+// decodeCID decodes a textual CID.  Base58 values are version 0 CIDs, or for
+// IPNS peer IDs, and are given the supplied content codec
+func decodeCID(value string, codec uint64) ([]byte, error) {
+	var cid []byte
+	var err error
+	switch {
+	case strings.HasPrefix(value, "Qm") || strings.HasPrefix(value, "12D3Koo"):
+		var multihash []byte
+		multihash, err = decodeBaseX(value, base58Alphabet)
+		cid = append(append([]byte{0x01}, uvarint(codec)...), multihash...)
+	case strings.HasPrefix(value, "b"):
+		cid, err = base32Encoding.DecodeString(value[1:])
+	case strings.HasPrefix(value, "k"):
+		cid, err = decodeBaseX(value[1:], base36Alphabet)
+	default:
+		return nil, fmt.Errorf("unsupported CID %s", value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CID %s: %v", value, err)
+	}
+	return cid, nil
+}
+
+// parseCID parses a version 1 CID, returning its content codec and multihash
+func parseCID(cid []byte) (uint64, []byte, error) {
+	version, n := binary.Uvarint(cid)
+	if n <= 0 || version != 1 {
+		return 0, nil, errors.New("content hash does not contain a version 1 CID")
+	}
+	codec, m := binary.Uvarint(cid[n:])
+	if m <= 0 {
+		return 0, nil, errors.New("invalid CID codec")
+	}
+	multihash := cid[n+m:]
+	if len(multihash) < 2 || int(multihash[1]) != len(multihash)-2 {
+		return 0, nil, errors.New("invalid CID multihash")
+	}
+	return codec, multihash, nil
+}
+
+// uvarint encodes a value as an unsigned varint
+func uvarint(value uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return buf[:binary.PutUvarint(buf, value)]
+}
+
+// encodeBaseX encodes bytes with the given alphabet, with leading zero bytes
+// represented by the first character of the alphabet
+func encodeBaseX(input []byte, alphabet string) string {
+	base := big.NewInt(int64(len(alphabet)))
+	value := new(big.Int).SetBytes(input)
+	mod := new(big.Int)
+	var encoded []byte
+	for value.Sign() > 0 {
+		value.DivMod(value, base, mod)
+		encoded = append(encoded, alphabet[mod.Int64()])
+	}
+	for _, b := range input {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// decodeBaseX decodes a string encoded with encodeBaseX
+func decodeBaseX(input string, alphabet string) ([]byte, error) {
+	if input == "" {
+		return nil, errors.New("empty value")
+	}
+	base := big.NewInt(int64(len(alphabet)))
+	value := new(big.Int)
+	for _, c := range input {
+		digit := strings.IndexRune(alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		value.Mul(value, base)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+	zeros := 0
+	for zeros < len(input) && input[zeros] == alphabet[0] {
+		zeros++
+	}
+	return append(bytes.Repeat([]byte{0x00}, zeros), value.Bytes()...), nil
+}
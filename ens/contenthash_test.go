@@ -0,0 +1,93 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContenthash(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		hash string
+		// canonical is the URI decoded from the hash, if different from uri
+		canonical string
+		err       bool
+	}{
+		{name: "Empty", uri: "", err: true},
+		{name: "NoScheme", uri: "QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4", err: true},
+		{name: "BadScheme", uri: "http://example.com", err: true},
+		{name: "Path", uri: "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4/index.html", err: true},
+		{name: "IPFSv0", uri: "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4", hash: "e3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f"},
+		{name: "IPFSv1", uri: "ipfs://bafybeibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4", hash: "e3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f", canonical: "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"},
+		{name: "IPFSTrailingSlash", uri: "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4/", hash: "e3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f", canonical: "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"},
+		{name: "IPFSBadCID", uri: "ipfs://Qm0OIl", err: true},
+		{name: "IPNS", uri: "ipns://k51qzi5uqu5digqkrbjv15p9437459llvj3nlef68tjkmfpvc6ds6f370s3l9l", hash: "e50101720024080112205b8e1f2e4c0e8d8b5a5d6a33d42b8f43e11d7b3c1a3f2e4b6a6d1f2e3c4b5a69"},
+		{name: "Swarm", uri: "bzz://d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162", hash: "e40101fa011b20d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162"},
+		{name: "SwarmShort", uri: "bzz://d1de9994", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hash, err := URIToContenthash(test.uri)
+			if test.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.hash, hex.EncodeToString(hash))
+			uri, err := ContenthashToURI(hash)
+			assert.Nil(t, err)
+			if test.canonical != "" {
+				assert.Equal(t, test.canonical, uri)
+			} else {
+				assert.Equal(t, test.uri, uri)
+			}
+		})
+	}
+}
+
+func TestContenthashToURI(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+		uri  string
+		err  bool
+	}{
+		{name: "Empty", hash: "", err: true},
+		{name: "UnknownCodec", hash: "b29910" + "01701220" + "29f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f", err: true},
+		{name: "CIDv0", hash: "e301" + "1220" + "29f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f", err: true},
+		{name: "Truncated", hash: "e30101701220" + "29f2d17be6139079", err: true},
+		{name: "IPFSRaw", hash: "e3010155122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f", uri: "ipfs://bafkreibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4"},
+		{name: "IPNSDNSLink", hash: "e5010170000b6578616d706c652e636f6d", uri: "ipns://example.com"},
+		{name: "SwarmBadCodec", hash: "e40101701b20d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hash, _ := hex.DecodeString(test.hash)
+			uri, err := ContenthashToURI(hash)
+			if test.err {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.uri, uri)
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// registrarControllerABI is the ABI of the legacy .eth registrar controller
+const registrarControllerABI = `[{"constant":true,"inputs":[{"name":"name","type":"string"}],"name":"available","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"name","type":"string"},{"name":"duration","type":"uint256"}],"name":"rentPrice","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"prices","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"minCommitmentAge","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"maxCommitmentAge","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"","type":"bytes32"}],"name":"commitments","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"commitment","type":"bytes32"}],"name":"commit","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"name","type":"string"},{"name":"owner","type":"address"},{"name":"duration","type":"uint256"},{"name":"secret","type":"bytes32"}],"name":"register","outputs":[],"payable":true,"stateMutability":"payable","type":"function"},{"constant":false,"inputs":[{"name":"name","type":"string"},{"name":"owner","type":"address"},{"name":"duration","type":"uint256"},{"name":"secret","type":"bytes32"},{"name":"resolver","type":"address"},{"name":"addr","type":"address"}],"name":"registerWithConfig","outputs":[],"payable":true,"stateMutability":"payable","type":"function"},{"constant":false,"inputs":[{"name":"name","type":"string"},{"name":"duration","type":"uint256"}],"name":"renew","outputs":[],"payable":true,"stateMutability":"payable","type":"function"}]`
+
+// currentControllerABI is the ABI of the current .eth registrar controller.
+// Its register() and makeCommitment() take a bytes[] argument, which is
+// encoded by registrationArguments rather than through the ABI.
+const currentControllerABI = `[{"constant":true,"inputs":[{"name":"name","type":"string"},{"name":"duration","type":"uint256"}],"name":"rentPrice","outputs":[{"name":"base","type":"uint256"},{"name":"premium","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+const supportsInterfaceABI = `[{"constant":true,"inputs":[{"name":"interfaceID","type":"bytes4"}],"name":"supportsInterface","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+const interfaceResolverABI = `[{"constant":true,"inputs":[{"name":"node","type":"bytes32"},{"name":"interfaceID","type":"bytes4"}],"name":"interfaceImplementer","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+const priceOracleABI = `[{"constant":true,"inputs":[{"name":"name","type":"string"},{"name":"expires","type":"uint256"},{"name":"duration","type":"uint256"}],"name":"premium","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// controllerInterfaceID is the interface ID of the current .eth registrar
+// controller, published by the resolver for "eth"
+var controllerInterfaceID = [4]byte{0x61, 0x2e, 0x8c, 0x09}
+
+// legacyControllerInterfaceID is the interface ID of the legacy .eth
+// registrar controller
+var legacyControllerInterfaceID = [4]byte{0x01, 0x8f, 0xac, 0x06}
+
+// registerSelector is the selector of register() on the current controller
+var registerSelector = crypto.Keccak256([]byte("register(string,address,uint256,bytes32,address,bytes[],bool,uint16)"))[:4]
+
+// setAddrSelector is the selector of setAddr(bytes32,address) on a resolver
+var setAddrSelector = crypto.Keccak256([]byte("setAddr(bytes32,address)"))[:4]
+
+// MinRegistrationDuration is the shortest period for which a .eth name can be
+// registered
+const MinRegistrationDuration = 28 * 24 * time.Hour
+
+// GracePeriod is the period after a .eth name expires during which only its
+// owner can renew it
+const GracePeriod = 90 * 24 * time.Hour
+
+// PremiumPeriod is the period after the grace period during which a premium
+// is charged to register a .eth name, falling to nothing at its end
+const PremiumPeriod = 21 * 24 * time.Hour
+
+// RegistrarControllerAddress obtains the address of the controller through
+// which .eth names are registered and renewed, preferring the current
+// controller to the legacy controller
+func RegistrarControllerAddress(client *ethclient.Client) (address common.Address, err error) {
+	resolver, err := resolverAddress(client, "eth")
+	if err != nil {
+		return
+	}
+	contract, err := boundContract(client, resolver, interfaceResolverABI)
+	if err != nil {
+		return
+	}
+	for _, id := range [][4]byte{controllerInterfaceID, legacyControllerInterfaceID} {
+		err = contract.Call(nil, &address, "interfaceImplementer", NameHash("eth"), id)
+		if err != nil || address != UnknownAddress {
+			return
+		}
+	}
+	err = errors.New("no registrar controller for .eth")
+	return
+}
+
+// currentController returns true if the controller is the current .eth
+// registrar controller, or false if it is the legacy controller.  Any other
+// contract is refused, as the commitments and prices that it provides cannot
+// be relied upon.
+func currentController(client *ethclient.Client, controllerAddress common.Address) (bool, error) {
+	contract, err := boundContract(client, controllerAddress, supportsInterfaceABI)
+	if err != nil {
+		return false, err
+	}
+	var supported bool
+	if err = contract.Call(nil, &supported, "supportsInterface", controllerInterfaceID); err != nil {
+		return false, fmt.Errorf("failed to obtain interfaces of registrar controller %s: %v", controllerAddress.Hex(), err)
+	}
+	if supported {
+		return true, nil
+	}
+	if err = contract.Call(nil, &supported, "supportsInterface", legacyControllerInterfaceID); err != nil {
+		return false, fmt.Errorf("failed to obtain interfaces of registrar controller %s: %v", controllerAddress.Hex(), err)
+	}
+	if supported {
+		return false, nil
+	}
+	return false, fmt.Errorf("%s is not a supported registrar controller", controllerAddress.Hex())
+}
+
+// Available returns true if a .eth name is available for registration.  The
+// label is the name without the .eth suffix.
+func Available(client *ethclient.Client, controllerAddress common.Address, label string) (available bool, err error) {
+	contract, err := boundContract(client, controllerAddress, registrarControllerABI)
+	if err != nil {
+		return
+	}
+	err = contract.Call(nil, &available, "available", label)
+	return
+}
+
+// RentPrice obtains the price of registering or renewing a .eth name for a
+// duration, including any premium
+func RentPrice(client *ethclient.Client, controllerAddress common.Address, label string, duration time.Duration) (price *big.Int, err error) {
+	current, err := currentController(client, controllerAddress)
+	if err != nil {
+		return
+	}
+	if current {
+		base, premium, err := currentRentPrice(client, controllerAddress, label, duration)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).Add(base, premium), nil
+	}
+	contract, err := boundContract(client, controllerAddress, registrarControllerABI)
+	if err != nil {
+		return
+	}
+	price = new(big.Int)
+	err = contract.Call(nil, &price, "rentPrice", label, big.NewInt(int64(duration/time.Second)))
+	return
+}
+
+// currentRentPrice obtains the base price and premium of a .eth name from
+// the current controller, which returns them separately
+func currentRentPrice(client *ethclient.Client, controllerAddress common.Address, label string, duration time.Duration) (base *big.Int, premium *big.Int, err error) {
+	contract, err := boundContract(client, controllerAddress, currentControllerABI)
+	if err != nil {
+		return
+	}
+	price := struct {
+		Base    *big.Int
+		Premium *big.Int
+	}{}
+	if err = contract.Call(nil, &price, "rentPrice", label, big.NewInt(int64(duration/time.Second))); err != nil {
+		return
+	}
+	return price.Base, price.Premium, nil
+}
+
+// Premium obtains the premium included in the price of registering a .eth
+// name that has recently expired.  The expiry is that of the previous
+// registration.
+func Premium(client *ethclient.Client, controllerAddress common.Address, label string, expiry time.Time, duration time.Duration) (premium *big.Int, err error) {
+	current, err := currentController(client, controllerAddress)
+	if err != nil {
+		return
+	}
+	if current {
+		_, premium, err = currentRentPrice(client, controllerAddress, label, duration)
+		return
+	}
+	contract, err := boundContract(client, controllerAddress, registrarControllerABI)
+	if err != nil {
+		return
+	}
+	var oracleAddress common.Address
+	if err = contract.Call(nil, &oracleAddress, "prices"); err != nil {
+		return
+	}
+	oracle, err := boundContract(client, oracleAddress, priceOracleABI)
+	if err != nil {
+		return
+	}
+	premium = new(big.Int)
+	err = oracle.Call(nil, &premium, "premium", label, big.NewInt(expiry.Unix()), big.NewInt(int64(duration/time.Second)))
+	return
+}
+
+// CommitmentAges obtains the minimum and maximum time that must pass between
+// committing to a registration and carrying it out
+func CommitmentAges(client *ethclient.Client, controllerAddress common.Address) (minAge time.Duration, maxAge time.Duration, err error) {
+	contract, err := boundContract(client, controllerAddress, registrarControllerABI)
+	if err != nil {
+		return
+	}
+	min := new(big.Int)
+	if err = contract.Call(nil, &min, "minCommitmentAge"); err != nil {
+		return
+	}
+	max := new(big.Int)
+	if err = contract.Call(nil, &max, "maxCommitmentAge"); err != nil {
+		return
+	}
+	return time.Duration(min.Int64()) * time.Second, time.Duration(max.Int64()) * time.Second, nil
+}
+
+// CommitmentTime obtains the time at which a commitment was made, or the
+// zero time if it has not been made
+func CommitmentTime(client *ethclient.Client, controllerAddress common.Address, commitment common.Hash) (timestamp time.Time, err error) {
+	contract, err := boundContract(client, controllerAddress, registrarControllerABI)
+	if err != nil {
+		return
+	}
+	committed := new(big.Int)
+	if err = contract.Call(nil, &committed, "commitments", commitment); err != nil || committed.Sign() == 0 {
+		return
+	}
+	return time.Unix(committed.Int64(), 0), nil
+}
+
+// MakeCommitment creates the commitment for a registration.  The resolver
+// and address are only included if a resolver is supplied.  The commitment
+// of the current controller also includes the duration.
+func MakeCommitment(client *ethclient.Client, controllerAddress common.Address, label string, owner common.Address, duration time.Duration, secret [32]byte, resolver common.Address, address common.Address) (commitment common.Hash, err error) {
+	current, err := currentController(client, controllerAddress)
+	if err != nil {
+		return
+	}
+	labelHash := LabelHash(label)
+	if current {
+		args := registrationArguments(labelHash[:], false, owner, duration, secret, resolver, registrationData(label, address))
+		return crypto.Keccak256Hash(args), nil
+	}
+	if resolver == UnknownAddress && address == UnknownAddress {
+		return crypto.Keccak256Hash(labelHash[:], owner.Bytes(), secret[:]), nil
+	}
+	return crypto.Keccak256Hash(labelHash[:], owner.Bytes(), resolver.Bytes(), address.Bytes(), secret[:]), nil
+}
+
+// registrationData creates the resolver calls made by the current controller
+// on registration, which set the address if supplied
+func registrationData(label string, address common.Address) [][]byte {
+	if address == UnknownAddress {
+		return [][]byte{}
+	}
+	nameHash := NameHash(label + ".eth")
+	call := append([]byte{}, setAddrSelector...)
+	call = append(call, nameHash[:]...)
+	call = append(call, common.LeftPadBytes(address.Bytes(), 32)...)
+	return [][]byte{call}
+}
+
+// registrationArguments encodes the arguments to register() and
+// makeCommitment() on the current controller.  The name is encoded as a
+// string if dynamic, otherwise it is a label hash and encoded as bytes32.
+// The reverse record is not set and no fuses are burned.
+func registrationArguments(name []byte, dynamic bool, owner common.Address, duration time.Duration, secret [32]byte, resolver common.Address, data [][]byte) []byte {
+	const headLen = 8 * 32
+	var head, tail []byte
+	if dynamic {
+		head = append(head, abiWord(big.NewInt(headLen))...)
+		tail = append(tail, abiBytes(name)...)
+	} else {
+		head = append(head, common.LeftPadBytes(name, 32)...)
+	}
+	head = append(head, common.LeftPadBytes(owner.Bytes(), 32)...)
+	head = append(head, abiWord(big.NewInt(int64(duration/time.Second)))...)
+	head = append(head, secret[:]...)
+	head = append(head, common.LeftPadBytes(resolver.Bytes(), 32)...)
+	head = append(head, abiWord(big.NewInt(int64(headLen+len(tail))))...)
+	tail = append(tail, abiBytesArray(data)...)
+	// reverseRecord and ownerControlledFuses
+	head = append(head, abiWord(big.NewInt(0))...)
+	head = append(head, abiWord(big.NewInt(0))...)
+	return append(head, tail...)
+}
+
+// abiWord encodes a value as a 32-byte word
+func abiWord(value *big.Int) []byte {
+	return common.LeftPadBytes(value.Bytes(), 32)
+}
+
+// abiBytes encodes a dynamic byte array
+func abiBytes(data []byte) []byte {
+	encoded := abiWord(big.NewInt(int64(len(data))))
+	return append(encoded, common.RightPadBytes(data, (len(data)+31)/32*32)...)
+}
+
+// abiBytesArray encodes an array of dynamic byte arrays
+func abiBytesArray(items [][]byte) []byte {
+	encoded := abiWord(big.NewInt(int64(len(items))))
+	var tail []byte
+	for _, item := range items {
+		encoded = append(encoded, abiWord(big.NewInt(int64(32*len(items)+len(tail))))...)
+		tail = append(tail, abiBytes(item)...)
+	}
+	return append(encoded, tail...)
+}
+
+// Commit sends a commitment to register a name
+func Commit(opts *bind.TransactOpts, client *ethclient.Client, controllerAddress common.Address, commitment common.Hash) (tx *types.Transaction, err error) {
+	contract, err := boundContract(client, controllerAddress, registrarControllerABI)
+	if err != nil {
+		return
+	}
+	tx, err = contract.Transact(opts, "commit", commitment)
+	return
+}
+
+// Register registers a name for which a commitment has been made.  The
+// value of the transaction options must cover the rent price; any excess is
+// refunded.  If a resolver is supplied it is set for the name, along with the
+// address if supplied.
+func Register(opts *bind.TransactOpts, client *ethclient.Client, controllerAddress common.Address, label string, owner common.Address, duration time.Duration, secret [32]byte, resolver common.Address, address common.Address) (tx *types.Transaction, err error) {
+	current, err := currentController(client, controllerAddress)
+	if err != nil {
+		return
+	}
+	if current {
+		data := append([]byte{}, registerSelector...)
+		data = append(data, registrationArguments([]byte(label), true, owner, duration, secret, resolver, registrationData(label, address))...)
+		return transactData(opts, client, controllerAddress, data)
+	}
+	contract, err := boundContract(client, controllerAddress, registrarControllerABI)
+	if err != nil {
+		return
+	}
+	seconds := big.NewInt(int64(duration / time.Second))
+	if resolver == UnknownAddress && address == UnknownAddress {
+		tx, err = contract.Transact(opts, "register", label, owner, seconds, secret)
+	} else {
+		tx, err = contract.Transact(opts, "registerWithConfig", label, owner, seconds, secret, resolver, address)
+	}
+	return
+}
+
+// transactData sends a transaction with pre-encoded data to a contract, in
+// the same way as a bound contract does for a method in its ABI
+func transactData(opts *bind.TransactOpts, client *ethclient.Client, contractAddress common.Address, data []byte) (*types.Transaction, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	var nonce uint64
+	var err error
+	if opts.Nonce == nil {
+		if nonce, err = client.PendingNonceAt(ctx, opts.From); err != nil {
+			return nil, fmt.Errorf("failed to retrieve account nonce: %v", err)
+		}
+	} else {
+		nonce = opts.Nonce.Uint64()
+	}
+	gasPrice := opts.GasPrice
+	if gasPrice == nil {
+		if gasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %v", err)
+		}
+	}
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		msg := ethereum.CallMsg{From: opts.From, To: &contractAddress, Value: value, Data: data}
+		if gasLimit, err = client.EstimateGas(ctx, msg); err != nil {
+			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+		}
+	}
+	if opts.Signer == nil {
+		return nil, errors.New("no signer to authorize the transaction with")
+	}
+	signedTx, err := opts.Signer(types.HomesteadSigner{}, opts.From, types.NewTransaction(nonce, contractAddress, value, gasLimit, gasPrice, data))
+	if err != nil {
+		return nil, err
+	}
+	if err = client.SendTransaction(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// Renew extends the registration of a name.  The value of the transaction
+// options must cover the rent price; any excess is refunded.
+func Renew(opts *bind.TransactOpts, client *ethclient.Client, controllerAddress common.Address, label string, duration time.Duration) (tx *types.Transaction, err error) {
+	contract, err := boundContract(client, controllerAddress, registrarControllerABI)
+	if err != nil {
+		return
+	}
+	tx, err = contract.Transact(opts, "renew", label, big.NewInt(int64(duration/time.Second)))
+	return
+}
@@ -0,0 +1,88 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerSelectors(t *testing.T) {
+	assert.Equal(t, "74694a2b", hex.EncodeToString(registerSelector))
+	assert.Equal(t, "d5fa2b00", hex.EncodeToString(setAddrSelector))
+}
+
+func TestRegistrationArguments(t *testing.T) {
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000011")
+	resolver := common.HexToAddress("0x0000000000000000000000000000000000000022")
+	var secret [32]byte
+	secret[31] = 0x33
+
+	tests := []struct {
+		name     string
+		label    []byte
+		dynamic  bool
+		data     [][]byte
+		expected string
+	}{
+		{
+			name:    "Commitment",
+			label:   common.HexToHash("0x44").Bytes(),
+			dynamic: false,
+			data:    [][]byte{},
+			expected: `0000000000000000000000000000000000000000000000000000000000000044
+				0000000000000000000000000000000000000000000000000000000000000011
+				0000000000000000000000000000000000000000000000000000000001e13380
+				0000000000000000000000000000000000000000000000000000000000000033
+				0000000000000000000000000000000000000000000000000000000000000022
+				0000000000000000000000000000000000000000000000000000000000000100
+				0000000000000000000000000000000000000000000000000000000000000000
+				0000000000000000000000000000000000000000000000000000000000000000
+				0000000000000000000000000000000000000000000000000000000000000000`,
+		},
+		{
+			name:    "Registration",
+			label:   []byte("foo"),
+			dynamic: true,
+			data:    [][]byte{{0xaa, 0xbb}, {}},
+			expected: `0000000000000000000000000000000000000000000000000000000000000100
+				0000000000000000000000000000000000000000000000000000000000000011
+				0000000000000000000000000000000000000000000000000000000001e13380
+				0000000000000000000000000000000000000000000000000000000000000033
+				0000000000000000000000000000000000000000000000000000000000000022
+				0000000000000000000000000000000000000000000000000000000000000140
+				0000000000000000000000000000000000000000000000000000000000000000
+				0000000000000000000000000000000000000000000000000000000000000000
+				0000000000000000000000000000000000000000000000000000000000000003
+				666f6f0000000000000000000000000000000000000000000000000000000000
+				0000000000000000000000000000000000000000000000000000000000000002
+				0000000000000000000000000000000000000000000000000000000000000040
+				0000000000000000000000000000000000000000000000000000000000000080
+				0000000000000000000000000000000000000000000000000000000000000002
+				aabb000000000000000000000000000000000000000000000000000000000000
+				0000000000000000000000000000000000000000000000000000000000000000`,
+		},
+	}
+
+	for _, test := range tests {
+		expected := strings.Join(strings.Fields(test.expected), "")
+		actual := registrationArguments(test.label, test.dynamic, owner, 365*24*time.Hour, secret, resolver, test.data)
+		assert.Equal(t, expected, hex.EncodeToString(actual), test.name)
+	}
+}
@@ -15,6 +15,16 @@ func DomainLevel(name string) (level int) {
 
 // Normalise an ENS domain
 func NormaliseDomain(domain string) string {
+	output, err := NormaliseDomainStrict(domain)
+	if err != nil {
+		panic("ENS domain normalisation failed")
+	}
+	return output
+}
+
+// NormaliseDomainStrict normalises an ENS domain, returning an error if the
+// domain cannot be normalised
+func NormaliseDomainStrict(domain string) (string, error) {
 	wildcard := false
 	if strings.HasPrefix(domain, "*.") {
 		wildcard = true
@@ -22,7 +32,7 @@ func NormaliseDomain(domain string) string {
 	}
 	output, err := p.ToUnicode(strings.ToLower(domain))
 	if err != nil {
-		panic("ENS domain normalisation failed")
+		return "", err
 	}
 
 	// ToUnicode() removes leading periods.  Replace them
@@ -34,7 +44,7 @@ func NormaliseDomain(domain string) string {
 	if wildcard {
 		output = "*." + output
 	}
-	return output
+	return output, nil
 }
 
 // Obtain the TLD of an ENS domain
@@ -29,6 +29,33 @@ func TestNormaliseDomain(t *testing.T) {
 	}
 }
 
+func TestNormaliseDomainStrict(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		err    bool
+	}{
+		{"", "", false},
+		{"ETH", "eth", false},
+		{"WealdTech.eth", "wealdtech.eth", false},
+		{"subdomain.wealdtech.eth", "subdomain.wealdtech.eth", false},
+		{"*.wealdtech.eth", "*.wealdtech.eth", false},
+		{"weald_tech.eth", "", true},
+		{"weald tech.eth", "", true},
+	}
+
+	for _, tt := range tests {
+		result, err := NormaliseDomainStrict(tt.input)
+		if tt.err {
+			if err == nil {
+				t.Errorf("Failure: %v => %v (expected error)\n", tt.input, result)
+			}
+		} else if err != nil || tt.output != result {
+			t.Errorf("Failure: %v => %v, %v (expected %v)\n", tt.input, result, err, tt.output)
+		}
+	}
+}
+
 func TestTld(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -0,0 +1,159 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const nameWrapperABI = `[{"constant":true,"inputs":[{"name":"interfaceId","type":"bytes4"}],"name":"supportsInterface","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"data","type":"bytes"}],"name":"safeTransferFrom","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"resolver","type":"address"}],"name":"setResolver","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"parentNode","type":"bytes32"},{"name":"label","type":"string"},{"name":"owner","type":"address"},{"name":"fuses","type":"uint32"},{"name":"expiry","type":"uint64"}],"name":"setSubnodeOwner","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+const baseRegistrarABI = `[{"constant":true,"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"nameExpires","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"name":"safeTransferFrom","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+// erc1155InterfaceID is the interface ID for ERC-1155 tokens
+var erc1155InterfaceID = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+
+// IsNameWrapper returns true if the address is a name wrapper contract, in
+// which case names owned by it in the registry are wrapped
+func IsNameWrapper(client *ethclient.Client, address common.Address) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil || len(code) == 0 {
+		return false
+	}
+	contract, err := boundContract(client, address, nameWrapperABI)
+	if err != nil {
+		return false
+	}
+	var supported bool
+	if err = contract.Call(nil, &supported, "supportsInterface", erc1155InterfaceID); err != nil {
+		return false
+	}
+	return supported
+}
+
+// WrappedOwner obtains the owner of a name held by a name wrapper
+func WrappedOwner(client *ethclient.Client, wrapperAddress common.Address, name string) (owner common.Address, err error) {
+	contract, err := boundContract(client, wrapperAddress, nameWrapperABI)
+	if err != nil {
+		return
+	}
+	nameHash := NameHash(name)
+	err = contract.Call(nil, &owner, "ownerOf", new(big.Int).SetBytes(nameHash[:]))
+	return
+}
+
+// TransferWrappedName transfers a name held by a name wrapper
+func TransferWrappedName(opts *bind.TransactOpts, client *ethclient.Client, wrapperAddress common.Address, from common.Address, to common.Address, name string) (tx *types.Transaction, err error) {
+	contract, err := boundContract(client, wrapperAddress, nameWrapperABI)
+	if err != nil {
+		return
+	}
+	nameHash := NameHash(name)
+	tx, err = contract.Transact(opts, "safeTransferFrom", from, to, new(big.Int).SetBytes(nameHash[:]), big.NewInt(1), []byte{})
+	return
+}
+
+// SetWrappedSubdomainOwner creates or updates a subdomain of a name held by
+// a name wrapper, burning the supplied fuses
+func SetWrappedSubdomainOwner(opts *bind.TransactOpts, client *ethclient.Client, wrapperAddress common.Address, name string, subdomain string, owner common.Address, fuses uint32, expiry uint64) (tx *types.Transaction, err error) {
+	contract, err := boundContract(client, wrapperAddress, nameWrapperABI)
+	if err != nil {
+		return
+	}
+	tx, err = contract.Transact(opts, "setSubnodeOwner", NameHash(name), subdomain, owner, fuses, expiry)
+	return
+}
+
+// SetWrappedResolver sets the resolver for a name held by a name wrapper
+func SetWrappedResolver(opts *bind.TransactOpts, client *ethclient.Client, wrapperAddress common.Address, name string, resolverAddress common.Address) (tx *types.Transaction, err error) {
+	contract, err := boundContract(client, wrapperAddress, nameWrapperABI)
+	if err != nil {
+		return
+	}
+	tx, err = contract.Transact(opts, "setResolver", NameHash(name), resolverAddress)
+	return
+}
+
+// BaseRegistrarAddress obtains the address of the registrar that issues
+// .eth names as tokens; this is the owner of 'eth' in the registry
+func BaseRegistrarAddress(client *ethclient.Client) (address common.Address, err error) {
+	registry, err := RegistryContract(client)
+	if err != nil {
+		return
+	}
+	address, err = registry.Owner(nil, NameHash("eth"))
+	if err == nil && address == UnknownAddress {
+		err = errors.New("no registrar")
+	}
+	return
+}
+
+// Registrant obtains the holder of the registrar token for a .eth name
+func Registrant(client *ethclient.Client, registrarAddress common.Address, name string) (registrant common.Address, err error) {
+	domain, err := Domain(name)
+	if err != nil {
+		return
+	}
+	contract, err := boundContract(client, registrarAddress, baseRegistrarABI)
+	if err != nil {
+		return
+	}
+	labelHash := LabelHash(domain)
+	err = contract.Call(nil, &registrant, "ownerOf", new(big.Int).SetBytes(labelHash[:]))
+	return
+}
+
+// NameExpires obtains the time at which the registration of a .eth name
+// expires, or the zero time if it is not registered
+func NameExpires(client *ethclient.Client, registrarAddress common.Address, name string) (expiry time.Time, err error) {
+	domain, err := Domain(name)
+	if err != nil {
+		return
+	}
+	contract, err := boundContract(client, registrarAddress, baseRegistrarABI)
+	if err != nil {
+		return
+	}
+	labelHash := LabelHash(domain)
+	expires := new(big.Int)
+	if err = contract.Call(nil, &expires, "nameExpires", new(big.Int).SetBytes(labelHash[:])); err != nil || expires.Sign() == 0 {
+		return
+	}
+	return time.Unix(expires.Int64(), 0), nil
+}
+
+// TransferRegistration transfers the registrar token for a .eth name
+func TransferRegistration(opts *bind.TransactOpts, client *ethclient.Client, registrarAddress common.Address, from common.Address, to common.Address, name string) (tx *types.Transaction, err error) {
+	domain, err := Domain(name)
+	if err != nil {
+		return
+	}
+	contract, err := boundContract(client, registrarAddress, baseRegistrarABI)
+	if err != nil {
+		return
+	}
+	labelHash := LabelHash(domain)
+	tx, err = contract.Transact(opts, "safeTransferFrom", from, to, new(big.Int).SetBytes(labelHash[:]))
+	return
+}
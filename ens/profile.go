@@ -0,0 +1,102 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// The resolver binding predates the contenthash and multi-coin address
+// records, and the multi-coin functions overload addr() and setAddr(), so
+// these records are accessed through separate minimal ABIs.
+const contenthashABI = `[{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"contenthash","outputs":[{"name":"","type":"bytes"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"hash","type":"bytes"}],"name":"setContenthash","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+const multiCoinABI = `[{"constant":true,"inputs":[{"name":"node","type":"bytes32"},{"name":"coinType","type":"uint256"}],"name":"addr","outputs":[{"name":"","type":"bytes"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"coinType","type":"uint256"},{"name":"a","type":"bytes"}],"name":"setAddr","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+// Interface IDs for optional resolver functionality
+var (
+	// ContenthashInterfaceID is the interface ID for contenthash()
+	ContenthashInterfaceID = [4]byte{0xbc, 0x1c, 0x58, 0xd1}
+	// MultiCoinInterfaceID is the interface ID for addr(bytes32,uint256)
+	MultiCoinInterfaceID = [4]byte{0xf1, 0xcb, 0x7e, 0x06}
+	// TextInterfaceID is the interface ID for text()
+	TextInterfaceID = [4]byte{0x59, 0xd1, 0xd4, 0x3c}
+)
+
+// CoinTypes are the SLIP-44 coin types of commonly-used chains
+var CoinTypes = map[string]uint64{
+	"BTC":  0,
+	"LTC":  2,
+	"DOGE": 3,
+	"ETH":  60,
+	"ETC":  61,
+}
+
+// TextKeys are the commonly-used text record keys
+var TextKeys = []string{"email", "url", "avatar", "description", "notice", "keywords", "com.discord", "com.github", "com.reddit", "com.twitter", "org.telegram"}
+
+func boundContract(client *ethclient.Client, address common.Address, definition string) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, client, client, client), nil
+}
+
+// Contenthash obtains the content hash of a name from its resolver
+func Contenthash(client *ethclient.Client, resolverAddress common.Address, name string) (hash []byte, err error) {
+	contract, err := boundContract(client, resolverAddress, contenthashABI)
+	if err != nil {
+		return
+	}
+	err = contract.Call(nil, &hash, "contenthash", NameHash(name))
+	return
+}
+
+// CoinAddress obtains the address of a name for a given SLIP-44 coin type
+// from its resolver
+func CoinAddress(client *ethclient.Client, resolverAddress common.Address, name string, coinType uint64) (address []byte, err error) {
+	contract, err := boundContract(client, resolverAddress, multiCoinABI)
+	if err != nil {
+		return
+	}
+	err = contract.Call(nil, &address, "addr", NameHash(name), new(big.Int).SetUint64(coinType))
+	return
+}
+
+// SetCoinAddress sets the address of a name for a given SLIP-44 coin type
+func SetCoinAddress(opts *bind.TransactOpts, client *ethclient.Client, resolverAddress common.Address, name string, coinType uint64, address []byte) (tx *types.Transaction, err error) {
+	contract, err := boundContract(client, resolverAddress, multiCoinABI)
+	if err != nil {
+		return
+	}
+	tx, err = contract.Transact(opts, "setAddr", NameHash(name), new(big.Int).SetUint64(coinType), address)
+	return
+}
+
+// SetContenthash sets the content hash of a name
+func SetContenthash(opts *bind.TransactOpts, client *ethclient.Client, resolverAddress common.Address, name string, hash []byte) (tx *types.Transaction, err error) {
+	contract, err := boundContract(client, resolverAddress, contenthashABI)
+	if err != nil {
+		return
+	}
+	tx, err = contract.Transact(opts, "setContenthash", NameHash(name), hash)
+	return
+}
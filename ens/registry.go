@@ -41,13 +41,13 @@ func RegistryContractAddress(client *ethclient.Client) (address common.Address,
 		return
 	}
 
-	// Instantiate the registry contract
-	if chainID.Cmp(params.MainnetChainConfig.ChainId) == 0 {
-		address = common.HexToAddress("314159265dd8dbb310642f98f50c066173c1259b")
-	} else if chainID.Cmp(params.TestnetChainConfig.ChainId) == 0 {
-		address = common.HexToAddress("112234455c3a32fd11230c42e7bccd4a84e02010")
-	} else if chainID.Cmp(params.RinkebyChainConfig.ChainId) == 0 {
-		address = common.HexToAddress("e7410170f87102DF0055eB195163A03B7F2Bff4A")
+	// Instantiate the registry contract.  The registry was migrated to the
+	// same address on all networks; the new registry falls back to the old
+	// registry for names that have not been migrated
+	if chainID.Cmp(params.MainnetChainConfig.ChainId) == 0 ||
+		chainID.Cmp(params.TestnetChainConfig.ChainId) == 0 ||
+		chainID.Cmp(params.RinkebyChainConfig.ChainId) == 0 {
+		address = common.HexToAddress("00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
 	} else {
 		err = fmt.Errorf("No contract for network ID %v", chainID)
 	}
@@ -96,6 +96,32 @@ func Resolver(contract *registrycontract.RegistryContract, name string) (address
 	return
 }
 
+// The registry binding predates operator approvals, so they are accessed
+// through a separate minimal ABI.  The same function is used by the
+// registrar and name wrapper tokens.
+const operatorABI = `[{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"name":"isApprovedForAll","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// IsApprovedForAll returns true if the operator is authorised to manage all
+// of the owner's names in the registry
+func IsApprovedForAll(client *ethclient.Client, owner common.Address, operator common.Address) (approved bool, err error) {
+	address, err := RegistryContractAddress(client)
+	if err != nil {
+		return
+	}
+	return OperatorApproved(client, address, owner, operator)
+}
+
+// OperatorApproved returns true if the operator is authorised to act for
+// the owner in the given registry, registrar or name wrapper contract
+func OperatorApproved(client *ethclient.Client, contractAddress common.Address, owner common.Address, operator common.Address) (approved bool, err error) {
+	contract, err := boundContract(client, contractAddress, operatorABI)
+	if err != nil {
+		return
+	}
+	err = contract.Call(nil, &approved, "isApprovedForAll", owner, operator)
+	return
+}
+
 // SetResolver sets the resolver for a name
 func SetResolver(session *registrycontract.RegistryContractSession, name string, resolverAddr *common.Address) (tx *types.Transaction, err error) {
 	tx, err = session.SetResolver(NameHash(name), *resolverAddr)
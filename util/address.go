@@ -0,0 +1,24 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package util
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CreateAddress2 calculates the address of a contract deployed with CREATE2
+// from the deployer, the salt and the hash of the contract's init code
+func CreateAddress2(deployer common.Address, salt [32]byte, initCodeHash []byte) common.Address {
+	return common.BytesToAddress(crypto.Keccak256([]byte{0xff}, deployer.Bytes(), salt[:], initCodeHash)[12:])
+}
@@ -1,3 +1,5 @@
 package contracts
 
 //go:generate abigen -abi ERC20.abi -out ERC20.go -pkg contracts -type ERC20
+//go:generate abigen -abi ERC721.abi -out ERC721.go -pkg contracts -type ERC721
+//go:generate abigen -abi ERC1155.abi -out ERC1155.go -pkg contracts -type ERC1155
@@ -13,6 +13,7 @@
 package util
 
 import (
+	"fmt"
 	"math/big"
 	"regexp"
 	"strings"
@@ -64,12 +65,20 @@ func StringToTokenValue(input string, decimals uint8) (output *big.Int, err erro
 	// Count the number of items after the decimal point
 	parts := strings.Split(input, ".")
 	var additionalZeros int
-	if len(parts) == 2 {
-		// There is a decimal place
-		additionalZeros = int(decimals) - len(parts[1])
-	} else {
+	switch len(parts) {
+	case 1:
 		// There is not a decimal place
 		additionalZeros = int(decimals)
+	case 2:
+		// There is a decimal place
+		additionalZeros = int(decimals) - len(parts[1])
+		if additionalZeros < 0 {
+			err = fmt.Errorf("%s has more than %d decimal places", input, decimals)
+			return
+		}
+	default:
+		err = fmt.Errorf("%s has multiple decimal points", input)
+		return
 	}
 	// Remove the decimal point
 	tmp := strings.Replace(input, ".", "", -1)
@@ -77,7 +86,12 @@ func StringToTokenValue(input string, decimals uint8) (output *big.Int, err erro
 	tmp = tmp + strings.Repeat("0", additionalZeros)
 
 	// Set the output
-	output.SetString(tmp, 10)
+	_, success := output.SetString(tmp, 10)
+	if !success {
+		err = fmt.Errorf("%s is not a valid number", input)
+	} else if output.Sign() < 0 {
+		err = fmt.Errorf("%s is negative", input)
+	}
 
 	return
 }
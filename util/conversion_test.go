@@ -13,6 +13,7 @@
 package util
 
 import (
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -88,3 +89,21 @@ func TestStringToTokenValue(t *testing.T) {
 		}
 	}
 }
+
+func TestStringToTokenValueErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		decimals uint8
+	}{
+		{"1.5", 0},
+		{"1.2345", 3},
+		{"1.2.3", 18},
+		{"abc", 18},
+		{"-1.5", 18},
+	}
+
+	for _, tt := range tests {
+		_, err := StringToTokenValue(tt.input, tt.decimals)
+		assert.NotNil(t, err, fmt.Sprintf("Did not receive expected error for (\"%v\", %v)", tt.input, tt.decimals))
+	}
+}
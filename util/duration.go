@@ -0,0 +1,52 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var durationRegexp = regexp.MustCompile(`^([0-9]+)(y|w|d|h|m|s)$`)
+
+var durationUnits = map[string]time.Duration{
+	"y": 365 * 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"d": 24 * time.Hour,
+	"h": time.Hour,
+	"m": time.Minute,
+	"s": time.Second,
+}
+
+// StringToDuration turns a string such as "1y" or "90d" in to a duration.  A
+// year is taken to be 365 days.  Plain numbers are seconds, and anything else
+// is parsed as a Go duration such as "1h30m".
+func StringToDuration(input string) (time.Duration, error) {
+	if seconds, err := strconv.ParseUint(input, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	if match := durationRegexp.FindStringSubmatch(input); match != nil {
+		count, err := strconv.ParseInt(match[1], 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %s", input)
+		}
+		return time.Duration(count) * durationUnits[match[2]], nil
+	}
+	duration, err := time.ParseDuration(input)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %s", input)
+	}
+	return duration, nil
+}
@@ -0,0 +1,52 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package util
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringToDuration(t *testing.T) {
+	tests := []struct {
+		input  string
+		output time.Duration
+		err    bool
+	}{
+		{"1y", 365 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"90d", 90 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"5m", 5 * time.Minute, false},
+		{"30s", 30 * time.Second, false},
+		{"3600", time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"", 0, true},
+		{"1x", 0, true},
+		{"-1h", 0, true},
+		{"y", 0, true},
+	}
+
+	for i, test := range tests {
+		output, err := StringToDuration(test.input)
+		if test.err {
+			assert.NotNil(t, err, fmt.Sprintf("missing error at test %d", i))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+			assert.Equal(t, test.output, output, fmt.Sprintf("incorrect output at test %d", i))
+		}
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip712 provides hashing of typed structured data as defined in
+// EIP-712.
+package eip712
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Field is a single named and typed field of a struct
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is the EIP-712 structure containing the definition of the
+// types along with the domain and message to be hashed
+type TypedData struct {
+	Types       map[string][]Field     `json:"types"`
+	PrimaryType string                 `json:"primaryType"`
+	Domain      map[string]interface{} `json:"domain"`
+	Message     map[string]interface{} `json:"message"`
+}
+
+var intTypeRegexp = regexp.MustCompile(`^(u?)int([0-9]*)$`)
+var bytesTypeRegexp = regexp.MustCompile(`^bytes([0-9]+)$`)
+
+// Parse parses the JSON representation of typed data
+func Parse(input []byte) (*TypedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	// Numbers can be larger than will fit in a float64
+	decoder.UseNumber()
+	var data TypedData
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	if _, exists := data.Types["EIP712Domain"]; !exists {
+		return nil, fmt.Errorf("types do not contain EIP712Domain")
+	}
+	if _, exists := data.Types[data.PrimaryType]; !exists {
+		return nil, fmt.Errorf("types do not contain primary type %q", data.PrimaryType)
+	}
+	return &data, nil
+}
+
+// Hash returns the hash of the typed data to be signed
+func (t *TypedData) Hash() ([]byte, error) {
+	domainSeparator, err := t.HashStruct("EIP712Domain", t.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash domain: %v", err)
+	}
+	messageHash, err := t.HashStruct(t.PrimaryType, t.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash message: %v", err)
+	}
+	return crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, messageHash), nil
+}
+
+// HashStruct returns the hash of the encoding of a struct
+func (t *TypedData) HashStruct(typeName string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := t.encodeData(typeName, data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(encoded), nil
+}
+
+// EncodeType returns the encoding of a type and the types on which it depends
+func (t *TypedData) EncodeType(typeName string) string {
+	deps := t.dependencies(typeName, map[string]bool{})
+	sorted := make([]string, 0, len(deps))
+	for dep := range deps {
+		if dep != typeName {
+			sorted = append(sorted, dep)
+		}
+	}
+	sort.Strings(sorted)
+
+	var buffer bytes.Buffer
+	for _, name := range append([]string{typeName}, sorted...) {
+		fields := make([]string, len(t.Types[name]))
+		for i, field := range t.Types[name] {
+			fields[i] = field.Type + " " + field.Name
+		}
+		buffer.WriteString(fmt.Sprintf("%s(%s)", name, strings.Join(fields, ",")))
+	}
+	return buffer.String()
+}
+
+// dependencies finds all struct types referenced by a type, including itself
+func (t *TypedData) dependencies(typeName string, found map[string]bool) map[string]bool {
+	typeName = baseType(typeName)
+	if _, exists := t.Types[typeName]; !exists || found[typeName] {
+		return found
+	}
+	found[typeName] = true
+	for _, field := range t.Types[typeName] {
+		t.dependencies(field.Type, found)
+	}
+	return found
+}
+
+// encodeData encodes a struct as its type hash followed by its encoded fields
+func (t *TypedData) encodeData(typeName string, data map[string]interface{}) ([]byte, error) {
+	fields, exists := t.Types[typeName]
+	if !exists {
+		return nil, fmt.Errorf("unknown type %q", typeName)
+	}
+	encoded := crypto.Keccak256([]byte(t.EncodeType(typeName)))
+	for _, field := range fields {
+		value, exists := data[field.Name]
+		if !exists {
+			return nil, fmt.Errorf("missing value for %s.%s", typeName, field.Name)
+		}
+		encodedValue, err := t.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", typeName, field.Name, err)
+		}
+		encoded = append(encoded, encodedValue...)
+	}
+	return encoded, nil
+}
+
+// encodeValue encodes a single value as a 32-byte word
+func (t *TypedData) encodeValue(typeName string, value interface{}) ([]byte, error) {
+	// Arrays
+	if strings.HasSuffix(typeName, "]") {
+		elementType := typeName[:strings.LastIndex(typeName, "[")]
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected array for %s", typeName)
+		}
+		var encoded []byte
+		for _, element := range values {
+			encodedElement, err := t.encodeValue(elementType, element)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, encodedElement...)
+		}
+		return crypto.Keccak256(encoded), nil
+	}
+
+	// Structs
+	if _, exists := t.Types[typeName]; exists {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object for %s", typeName)
+		}
+		return t.HashStruct(typeName, data)
+	}
+
+	switch typeName {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string")
+		}
+		return crypto.Keccak256([]byte(str)), nil
+	case "bytes":
+		data, err := hexValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(data), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool")
+		}
+		if b {
+			return common.LeftPadBytes([]byte{1}, 32), nil
+		}
+		return make([]byte, 32), nil
+	case "address":
+		str, ok := value.(string)
+		if !ok || !common.IsHexAddress(str) {
+			return nil, fmt.Errorf("expected address")
+		}
+		return common.LeftPadBytes(common.HexToAddress(str).Bytes(), 32), nil
+	}
+
+	if match := bytesTypeRegexp.FindStringSubmatch(typeName); match != nil {
+		size, _ := strconv.Atoi(match[1])
+		data, err := hexValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if size < 1 || size > 32 || len(data) > size {
+			return nil, fmt.Errorf("invalid value for %s", typeName)
+		}
+		return common.RightPadBytes(data, 32), nil
+	}
+
+	if match := intTypeRegexp.FindStringSubmatch(typeName); match != nil {
+		bits := 256
+		if match[2] != "" {
+			bits, _ = strconv.Atoi(match[2])
+		}
+		return encodeInt(value, match[1] == "", bits)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", typeName)
+}
+
+// encodeInt encodes a signed or unsigned integer as a 32-byte word
+func encodeInt(value interface{}, signed bool, bits int) ([]byte, error) {
+	var str string
+	switch v := value.(type) {
+	case json.Number:
+		str = v.String()
+	case string:
+		str = v
+	case float64:
+		str = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("expected integer")
+	}
+	number, success := new(big.Int).SetString(str, 0)
+	if !success {
+		return nil, fmt.Errorf("invalid integer %s", str)
+	}
+	if bits < 8 || bits > 256 || bits%8 != 0 {
+		return nil, fmt.Errorf("invalid integer size %d", bits)
+	}
+
+	if signed {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		if number.Cmp(limit) >= 0 || number.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return nil, fmt.Errorf("%s out of range for int%d", str, bits)
+		}
+		if number.Sign() < 0 {
+			// Two's complement
+			number.Add(number, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+	} else if number.Sign() < 0 || number.BitLen() > bits {
+		return nil, fmt.Errorf("%s out of range for uint%d", str, bits)
+	}
+	return common.LeftPadBytes(number.Bytes(), 32), nil
+}
+
+// hexValue decodes a hex string value
+func hexValue(value interface{}) ([]byte, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected hex string")
+	}
+	return hex.DecodeString(strings.TrimPrefix(str, "0x"))
+}
+
+// baseType strips any array suffixes from a type
+func baseType(typeName string) string {
+	if pos := strings.Index(typeName, "["); pos != -1 {
+		return typeName[:pos]
+	}
+	return typeName
+}
@@ -0,0 +1,118 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip712
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Example from EIP-712
+var mailTypedData = []byte(`{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallet", "type": "address"}
+    ],
+    "Mail": [
+      {"name": "from", "type": "Person"},
+      {"name": "to", "type": "Person"},
+      {"name": "contents", "type": "string"}
+    ]
+  },
+  "primaryType": "Mail",
+  "domain": {
+    "name": "Ether Mail",
+    "version": "1",
+    "chainId": 1,
+    "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+  },
+  "message": {
+    "from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+    "to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+    "contents": "Hello, Bob!"
+  }
+}`)
+
+func TestEncodeType(t *testing.T) {
+	data, err := Parse(mailTypedData)
+	assert.Nil(t, err, "Failed to parse typed data")
+	assert.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", data.EncodeType("Mail"))
+}
+
+func TestHash(t *testing.T) {
+	data, err := Parse(mailTypedData)
+	assert.Nil(t, err, "Failed to parse typed data")
+
+	domainSeparator, err := data.HashStruct("EIP712Domain", data.Domain)
+	assert.Nil(t, err, "Failed to hash domain")
+	assert.Equal(t, "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", hex.EncodeToString(domainSeparator))
+
+	messageHash, err := data.HashStruct("Mail", data.Message)
+	assert.Nil(t, err, "Failed to hash message")
+	assert.Equal(t, "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e", hex.EncodeToString(messageHash))
+
+	hash, err := data.Hash()
+	assert.Nil(t, err, "Failed to hash typed data")
+	assert.Equal(t, "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hex.EncodeToString(hash))
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Invalid", `{`},
+		{"NoDomain", `{"types":{"Mail":[]},"primaryType":"Mail"}`},
+		{"NoPrimary", `{"types":{"EIP712Domain":[]},"primaryType":"Mail"}`},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.input))
+		assert.NotNil(t, err, tt.name)
+	}
+}
+
+func TestEncodeValueErrors(t *testing.T) {
+	data, err := Parse(mailTypedData)
+	assert.Nil(t, err, "Failed to parse typed data")
+
+	tests := []struct {
+		typeName string
+		value    interface{}
+	}{
+		{"uint8", "256"},
+		{"int8", "-129"},
+		{"uint256", "-1"},
+		{"bytes4", "0x0102030405"},
+		{"address", "0x1234"},
+		{"bool", "true"},
+		{"Person", "Bob"},
+		{"string[]", "Bob"},
+		{"fixed128x18", "1"},
+	}
+
+	for _, tt := range tests {
+		_, err := data.encodeValue(tt.typeName, tt.value)
+		assert.NotNil(t, err, tt.typeName)
+	}
+}
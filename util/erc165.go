@@ -0,0 +1,116 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// InterfaceIDs are the ERC-165 IDs of well-known interfaces, keyed by alias
+var InterfaceIDs = map[string][4]byte{
+	"erc165":           {0x01, 0xff, 0xc9, 0xa7},
+	"erc20":            {0x36, 0x37, 0x2b, 0x07},
+	"erc173":           {0x7f, 0x58, 0x28, 0xd0},
+	"erc721":           {0x80, 0xac, 0x58, 0xcd},
+	"erc721metadata":   {0x5b, 0x5e, 0x13, 0x9f},
+	"erc721enumerable": {0x78, 0x0e, 0x9d, 0x63},
+	"erc721receiver":   {0x15, 0x0b, 0x7a, 0x02},
+	"erc1155":          {0xd9, 0xb6, 0x7a, 0x26},
+	"erc1155metadata":  {0x0e, 0x89, 0x34, 0x1c},
+	"erc1155receiver":  {0x4e, 0x23, 0x12, 0xe0},
+	"erc1363":          {0xb0, 0x20, 0x2a, 0x11},
+	"erc2981":          {0x2a, 0x55, 0x20, 0x5a},
+	"erc4906":          {0x49, 0x06, 0x49, 0x06},
+	"erc4907":          {0xad, 0x09, 0x2b, 0x5c},
+	"erc5192":          {0xb4, 0x5a, 0x3c, 0x0e},
+}
+
+// supportsInterfaceSelector is the selector of supportsInterface(bytes4)
+var supportsInterfaceSelector = []byte{0x01, 0xff, 0xc9, 0xa7}
+
+// InterfaceAliases provides the aliases of well-known interfaces in
+// alphabetical order
+func InterfaceAliases() []string {
+	aliases := make([]string, 0, len(InterfaceIDs))
+	for alias := range InterfaceIDs {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// InterfaceID parses an interface ID, supplied either as the alias of a
+// well-known interface or as 4 bytes of hex
+func InterfaceID(input string) ([4]byte, error) {
+	var id [4]byte
+	if known, exists := InterfaceIDs[strings.ToLower(input)]; exists {
+		return known, nil
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil || len(data) != 4 {
+		return id, fmt.Errorf("invalid interface %s; must be a known interface or 4 bytes of hex", input)
+	}
+	copy(id[:], data)
+	return id, nil
+}
+
+// SupportsInterface returns true if the contract at the address claims to
+// support the interface.  Contracts that do not implement ERC-165 can claim
+// to support any interface, so should first be checked with
+// ImplementsERC165.
+func SupportsInterface(ctx context.Context, caller bind.ContractCaller, address common.Address, id [4]byte) (bool, error) {
+	return callSupportsInterface(ctx, caller, address, id)
+}
+
+// ImplementsERC165 returns true if the contract at the address implements
+// ERC-165, following the detection procedure in the standard
+func ImplementsERC165(ctx context.Context, caller bind.ContractCaller, address common.Address) (bool, error) {
+	supported, err := callSupportsInterface(ctx, caller, address, InterfaceIDs["erc165"])
+	if err != nil || !supported {
+		return false, err
+	}
+	supported, err = callSupportsInterface(ctx, caller, address, [4]byte{0xff, 0xff, 0xff, 0xff})
+	if err != nil {
+		return false, err
+	}
+	return !supported, nil
+}
+
+// callSupportsInterface calls supportsInterface(bytes4) on a contract.  Calls
+// that revert or return anything other than a boolean are treated as the
+// interface not being supported.
+func callSupportsInterface(ctx context.Context, caller bind.ContractCaller, address common.Address, id [4]byte) (bool, error) {
+	data := make([]byte, 36)
+	copy(data, supportsInterfaceSelector)
+	copy(data[4:], id[:])
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "revert") {
+			return false, nil
+		}
+		return false, err
+	}
+	if len(result) < 32 || !bytes.Equal(result[:31], make([]byte, 31)) {
+		return false, nil
+	}
+	return result[31] == 1, nil
+}
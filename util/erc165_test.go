@@ -0,0 +1,187 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package util
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func interfaceIDFor(signatures ...string) [4]byte {
+	var id [4]byte
+	for _, signature := range signatures {
+		hash := crypto.Keccak256([]byte(signature))
+		for i := range id {
+			id[i] ^= hash[i]
+		}
+	}
+	return id
+}
+
+func TestInterfaceIDs(t *testing.T) {
+	assert.Equal(t, interfaceIDFor("supportsInterface(bytes4)"), InterfaceIDs["erc165"])
+	assert.Equal(t, interfaceIDFor(
+		"balanceOf(address)",
+		"ownerOf(uint256)",
+		"safeTransferFrom(address,address,uint256,bytes)",
+		"safeTransferFrom(address,address,uint256)",
+		"transferFrom(address,address,uint256)",
+		"approve(address,uint256)",
+		"setApprovalForAll(address,bool)",
+		"getApproved(uint256)",
+		"isApprovedForAll(address,address)",
+	), InterfaceIDs["erc721"])
+	assert.Equal(t, interfaceIDFor("name()", "symbol()", "tokenURI(uint256)"), InterfaceIDs["erc721metadata"])
+	assert.Equal(t, interfaceIDFor("totalSupply()", "tokenOfOwnerByIndex(address,uint256)", "tokenByIndex(uint256)"), InterfaceIDs["erc721enumerable"])
+	assert.Equal(t, interfaceIDFor(
+		"safeTransferFrom(address,address,uint256,uint256,bytes)",
+		"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+		"balanceOf(address,uint256)",
+		"balanceOfBatch(address[],uint256[])",
+		"setApprovalForAll(address,bool)",
+		"isApprovedForAll(address,address)",
+	), InterfaceIDs["erc1155"])
+	assert.Equal(t, interfaceIDFor("uri(uint256)"), InterfaceIDs["erc1155metadata"])
+	assert.Equal(t, interfaceIDFor("royaltyInfo(uint256,uint256)"), InterfaceIDs["erc2981"])
+	assert.Equal(t, interfaceIDFor("owner()", "transferOwnership(address)"), InterfaceIDs["erc173"])
+	assert.Equal(t, interfaceIDFor(
+		"totalSupply()",
+		"balanceOf(address)",
+		"transfer(address,uint256)",
+		"transferFrom(address,address,uint256)",
+		"approve(address,uint256)",
+		"allowance(address,address)",
+	), InterfaceIDs["erc20"])
+	assert.Equal(t, interfaceIDFor("onERC721Received(address,address,uint256,bytes)"), InterfaceIDs["erc721receiver"])
+	assert.Equal(t, interfaceIDFor("onERC1155Received(address,address,uint256,uint256,bytes)", "onERC1155BatchReceived(address,address,uint256[],uint256[],bytes)"), InterfaceIDs["erc1155receiver"])
+	assert.Equal(t, interfaceIDFor("setUser(uint256,address,uint64)", "userOf(uint256)", "userExpires(uint256)"), InterfaceIDs["erc4907"])
+	assert.Equal(t, interfaceIDFor("locked(uint256)"), InterfaceIDs["erc5192"])
+}
+
+func TestInterfaceID(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected [4]byte
+		err      string
+	}{
+		{input: "erc721", expected: [4]byte{0x80, 0xac, 0x58, 0xcd}},
+		{input: "ERC1155", expected: [4]byte{0xd9, 0xb6, 0x7a, 0x26}},
+		{input: "0x12345678", expected: [4]byte{0x12, 0x34, 0x56, 0x78}},
+		{input: "abcdef01", expected: [4]byte{0xab, 0xcd, 0xef, 0x01}},
+		{input: "0x1234", err: "invalid interface 0x1234; must be a known interface or 4 bytes of hex"},
+		{input: "erc9999", err: "invalid interface erc9999; must be a known interface or 4 bytes of hex"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			id, err := InterfaceID(test.input)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, id)
+			}
+		})
+	}
+}
+
+// erc165Caller responds to supportsInterface calls
+type erc165Caller struct {
+	supported map[[4]byte]bool
+	revert    bool
+	raw       []byte
+	err       error
+}
+
+func (c *erc165Caller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x01}, nil
+}
+
+func (c *erc165Caller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.revert {
+		return nil, errors.New("execution reverted")
+	}
+	if c.raw != nil {
+		return c.raw, nil
+	}
+	var id [4]byte
+	copy(id[:], call.Data[4:8])
+	res := make([]byte, 32)
+	if c.supported[id] {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+func TestImplementsERC165(t *testing.T) {
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tests := []struct {
+		name        string
+		caller      *erc165Caller
+		implemented bool
+	}{
+		{
+			name:        "Implemented",
+			caller:      &erc165Caller{supported: map[[4]byte]bool{InterfaceIDs["erc165"]: true}},
+			implemented: true,
+		},
+		{
+			name:   "Reverts",
+			caller: &erc165Caller{revert: true},
+		},
+		{
+			name:   "Empty",
+			caller: &erc165Caller{raw: []byte{}},
+		},
+		{
+			name:   "NotBoolean",
+			caller: &erc165Caller{raw: common.LeftPadBytes([]byte{0x02, 0x01}, 32)},
+		},
+		{
+			name:   "ClaimsEverything",
+			caller: &erc165Caller{supported: map[[4]byte]bool{InterfaceIDs["erc165"]: true, {0xff, 0xff, 0xff, 0xff}: true}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			implemented, err := ImplementsERC165(context.Background(), test.caller, address)
+			assert.Nil(t, err)
+			assert.Equal(t, test.implemented, implemented)
+		})
+	}
+}
+
+func TestSupportsInterface(t *testing.T) {
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	caller := &erc165Caller{supported: map[[4]byte]bool{InterfaceIDs["erc165"]: true, InterfaceIDs["erc721"]: true}}
+
+	supported, err := SupportsInterface(context.Background(), caller, address, InterfaceIDs["erc721"])
+	assert.Nil(t, err)
+	assert.True(t, supported)
+	supported, err = SupportsInterface(context.Background(), caller, address, InterfaceIDs["erc1155"])
+	assert.Nil(t, err)
+	assert.False(t, supported)
+	_, err = SupportsInterface(context.Background(), &erc165Caller{err: errors.New("connection refused")}, address, InterfaceIDs["erc721"])
+	assert.EqualError(t, err, "connection refused")
+}
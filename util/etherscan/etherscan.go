@@ -0,0 +1,305 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package etherscan accesses the API of Etherscan and of block explorers with
+// a compatible API.
+package etherscan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultURL is the URL of Etherscan's API, which serves all of the chains
+// that Etherscan supports
+const DefaultURL = "https://api.etherscan.io/v2/api"
+
+// Client accesses the API of a block explorer
+type Client struct {
+	url        string
+	apiKey     string
+	chainID    *big.Int
+	httpClient *http.Client
+}
+
+// response is the envelope of all API responses
+type response struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// New creates a client for the API at the given URL, for the given chain.
+// Each request fails if it does not complete within the timeout.
+func New(apiURL string, apiKey string, chainID *big.Int, timeout time.Duration) *Client {
+	return &Client{
+		url:        apiURL,
+		apiKey:     apiKey,
+		chainID:    chainID,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// call calls the API.  Parameters in query are sent in the URL and those in
+// form, if supplied, are posted.
+func (c *Client) call(query url.Values, form url.Values) (*response, error) {
+	if c.chainID != nil {
+		query.Set("chainid", c.chainID.String())
+	}
+	if c.apiKey != "" {
+		query.Set("apikey", c.apiKey)
+	}
+	requestURL := c.url
+	if strings.Contains(requestURL, "?") {
+		requestURL += "&" + query.Encode()
+	} else {
+		requestURL += "?" + query.Encode()
+	}
+
+	var resp *http.Response
+	var err error
+	if form == nil {
+		resp, err = c.httpClient.Get(requestURL)
+	} else {
+		resp, err = c.httpClient.PostForm(requestURL, form)
+	}
+	if err != nil {
+		// The error contains the URL, which contains the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, fmt.Errorf("request failed: %w", urlErr.Err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %s", resp.Status)
+	}
+	res := &response{}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	return res, nil
+}
+
+// resultString provides the result of a response as a string, or its message
+// if the result is not a string
+func (r *response) resultString() string {
+	var result string
+	if err := json.Unmarshal(r.Result, &result); err != nil || result == "" {
+		return r.Message
+	}
+	return result
+}
+
+// ErrAlreadyVerified is returned when submitting the source of a contract
+// that is already verified
+var ErrAlreadyVerified = errors.New("contract source code already verified")
+
+// VerifyRequest contains the details of a contract to verify
+type VerifyRequest struct {
+	// Address is the address of the contract
+	Address string
+	// Input is the standard JSON input of the compiler
+	Input string
+	// ContractName is the name of the contract, prefixed by the name of the
+	// source that contains it, for example "contracts/Token.sol:Token"
+	ContractName string
+	// CompilerVersion is the full version of the compiler, for example
+	// "v0.8.19+commit.7dd6d404"
+	CompilerVersion string
+	// ConstructorArguments are the ABI-encoded arguments of the constructor
+	ConstructorArguments []byte
+}
+
+// VerifySource submits the source of a contract for verification, returning
+// a GUID with which the status of the verification can be obtained
+func (c *Client) VerifySource(req *VerifyRequest) (string, error) {
+	query := url.Values{}
+	query.Set("module", "contract")
+	query.Set("action", "verifysourcecode")
+	form := url.Values{}
+	form.Set("contractaddress", req.Address)
+	form.Set("sourceCode", req.Input)
+	form.Set("codeformat", "solidity-standard-json-input")
+	form.Set("contractname", req.ContractName)
+	form.Set("compilerversion", req.CompilerVersion)
+	// The misspelling is that of the API
+	form.Set("constructorArguements", fmt.Sprintf("%x", req.ConstructorArguments))
+	res, err := c.call(query, form)
+	if err != nil {
+		return "", err
+	}
+	result := res.resultString()
+	if res.Status != "1" {
+		if strings.Contains(strings.ToLower(result), "already verified") {
+			return "", ErrAlreadyVerified
+		}
+		return "", errors.New(result)
+	}
+	return result, nil
+}
+
+// VerifyStatus is the status of a verification
+type VerifyStatus struct {
+	// Pending is true if the verification has not yet completed
+	Pending bool
+	// Verified is true if the source was verified
+	Verified bool
+	// Message is the message from the explorer, which explains any failure
+	Message string
+}
+
+// VerifyStatus obtains the status of a verification
+func (c *Client) VerifyStatus(guid string) (*VerifyStatus, error) {
+	query := url.Values{}
+	query.Set("module", "contract")
+	query.Set("action", "checkverifystatus")
+	query.Set("guid", guid)
+	res, err := c.call(query, nil)
+	if err != nil {
+		return nil, err
+	}
+	status := &VerifyStatus{Message: res.resultString()}
+	lowerMessage := strings.ToLower(status.Message)
+	switch {
+	case strings.HasPrefix(lowerMessage, "pending"):
+		status.Pending = true
+	case res.Status == "1", strings.Contains(lowerMessage, "already verified"):
+		status.Verified = true
+	}
+	return status, nil
+}
+
+// maxListResults is the maximum number of results that the explorer returns
+// for a single listing, across all of its pages
+const maxListResults = 10000
+
+// Transaction is a transaction, internal transaction or token transfer as
+// listed by the explorer.  Fields that do not apply to a listing are empty.
+// All numeric values are as supplied by the explorer, in decimal.
+type Transaction struct {
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	Nonce           string `json:"nonce"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	Gas             string `json:"gas"`
+	GasPrice        string `json:"gasPrice"`
+	GasUsed         string `json:"gasUsed"`
+	IsError         string `json:"isError"`
+	Input           string `json:"input"`
+	ContractAddress string `json:"contractAddress"`
+	// Type is the type of an internal transaction, for example "call"
+	Type string `json:"type"`
+	// TraceID identifies an internal transaction within its transaction
+	TraceID      string `json:"traceId"`
+	TokenName    string `json:"tokenName"`
+	TokenSymbol  string `json:"tokenSymbol"`
+	TokenDecimal string `json:"tokenDecimal"`
+	TokenID      string `json:"tokenID"`
+	TokenValue   string `json:"tokenValue"`
+	// TxReceiptStatus is the status of the receipt of a transaction, "1"
+	// for success
+	TxReceiptStatus string `json:"txreceipt_status"`
+}
+
+// TransactionList lists the transactions sent from or to an address between
+// two blocks inclusive, in the order in which they were mined
+func (c *Client) TransactionList(address string, startBlock uint64, endBlock uint64) ([]*Transaction, error) {
+	return c.list("txlist", address, startBlock, endBlock)
+}
+
+// InternalTransactionList lists the internal transactions, that is the
+// transfers of Ether made by contracts, from or to an address between two
+// blocks inclusive
+func (c *Client) InternalTransactionList(address string, startBlock uint64, endBlock uint64) ([]*Transaction, error) {
+	return c.list("txlistinternal", address, startBlock, endBlock)
+}
+
+// TokenTransferList lists the ERC-20 token transfers from or to an address
+// between two blocks inclusive
+func (c *Client) TokenTransferList(address string, startBlock uint64, endBlock uint64) ([]*Transaction, error) {
+	return c.list("tokentx", address, startBlock, endBlock)
+}
+
+// NFTTransferList lists the ERC-721 token transfers from or to an address
+// between two blocks inclusive
+func (c *Client) NFTTransferList(address string, startBlock uint64, endBlock uint64) ([]*Transaction, error) {
+	return c.list("tokennfttx", address, startBlock, endBlock)
+}
+
+// MultiTokenTransferList lists the ERC-1155 token transfers from or to an
+// address between two blocks inclusive
+func (c *Client) MultiTokenTransferList(address string, startBlock uint64, endBlock uint64) ([]*Transaction, error) {
+	return c.list("token1155tx", address, startBlock, endBlock)
+}
+
+// list carries out an account listing between two blocks inclusive.  The
+// explorer returns a limited number of results for each listing, so larger
+// ranges are obtained with further listings starting at the last block
+// returned.
+func (c *Client) list(action string, address string, startBlock uint64, endBlock uint64) ([]*Transaction, error) {
+	txs := make([]*Transaction, 0)
+	for {
+		query := url.Values{}
+		query.Set("module", "account")
+		query.Set("action", action)
+		query.Set("address", address)
+		query.Set("startblock", fmt.Sprintf("%d", startBlock))
+		query.Set("endblock", fmt.Sprintf("%d", endBlock))
+		query.Set("page", "1")
+		query.Set("offset", fmt.Sprintf("%d", maxListResults))
+		query.Set("sort", "asc")
+		res, err := c.call(query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []*Transaction
+		if err := json.Unmarshal(res.Result, &page); err != nil {
+			// A failure has a string result explaining it
+			return nil, errors.New(res.resultString())
+		}
+		if res.Status != "1" && len(page) == 0 && !strings.HasPrefix(strings.ToLower(res.Message), "no transactions found") {
+			return nil, errors.New(res.Message)
+		}
+		if len(page) < maxListResults {
+			return append(txs, page...), nil
+		}
+
+		// The last block may not have been returned in full, so it is
+		// dropped and the listing starts again from it
+		var lastBlock uint64
+		if _, err := fmt.Sscan(page[len(page)-1].BlockNumber, &lastBlock); err != nil {
+			return nil, fmt.Errorf("invalid block number %q", page[len(page)-1].BlockNumber)
+		}
+		if lastBlock <= startBlock {
+			return nil, fmt.Errorf("more than %d results in block %d", maxListResults, lastBlock)
+		}
+		for _, tx := range page {
+			if tx.BlockNumber == page[len(page)-1].BlockNumber {
+				break
+			}
+			txs = append(txs, tx)
+		}
+		startBlock = lastBlock
+	}
+}
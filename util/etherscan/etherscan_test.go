@@ -0,0 +1,322 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etherscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "verifysourcecode", r.URL.Query().Get("action"))
+		assert.Equal(t, "5", r.URL.Query().Get("chainid"))
+		assert.Equal(t, "key", r.URL.Query().Get("apikey"))
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "solidity-standard-json-input", r.PostForm.Get("codeformat"))
+		assert.Equal(t, "0102", r.PostForm.Get("constructorArguements"))
+		switch r.PostForm.Get("contractaddress") {
+		case "0x0000000000000000000000000000000000000001":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"abcdef"}`)
+		case "0x0000000000000000000000000000000000000002":
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Contract source code already verified"}`)
+		default:
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid constructor arguments"}`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		address string
+		guid    string
+		err     string
+	}{
+		{
+			name:    "Good",
+			address: "0x0000000000000000000000000000000000000001",
+			guid:    "abcdef",
+		},
+		{
+			name:    "AlreadyVerified",
+			address: "0x0000000000000000000000000000000000000002",
+			err:     "contract source code already verified",
+		},
+		{
+			name:    "Rejected",
+			address: "0x0000000000000000000000000000000000000003",
+			err:     "Invalid constructor arguments",
+		},
+	}
+
+	client := New(server.URL, "key", big.NewInt(5), 5*time.Second)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			guid, err := client.VerifySource(&VerifyRequest{
+				Address:              test.address,
+				Input:                "{}",
+				ContractName:         "A.sol:A",
+				CompilerVersion:      "v0.8.19+commit.7dd6d404",
+				ConstructorArguments: []byte{0x01, 0x02},
+			})
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.guid, guid)
+			}
+		})
+	}
+}
+
+func TestVerifyStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "checkverifystatus", r.URL.Query().Get("action"))
+		switch r.URL.Query().Get("guid") {
+		case "pending":
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Pending in queue"}`)
+		case "pass":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"Pass - Verified"}`)
+		case "already":
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Already Verified"}`)
+		case "fail":
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Fail - Unable to verify. Compiled contract deployment bytecode does NOT match"}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		guid   string
+		status *VerifyStatus
+		err    string
+	}{
+		{
+			name:   "Pending",
+			guid:   "pending",
+			status: &VerifyStatus{Pending: true, Message: "Pending in queue"},
+		},
+		{
+			name:   "Pass",
+			guid:   "pass",
+			status: &VerifyStatus{Verified: true, Message: "Pass - Verified"},
+		},
+		{
+			name:   "AlreadyVerified",
+			guid:   "already",
+			status: &VerifyStatus{Verified: true, Message: "Already Verified"},
+		},
+		{
+			name:   "Fail",
+			guid:   "fail",
+			status: &VerifyStatus{Message: "Fail - Unable to verify. Compiled contract deployment bytecode does NOT match"},
+		},
+		{
+			name: "ServerError",
+			guid: "bad",
+			err:  "request failed with status 500 Internal Server Error",
+		},
+	}
+
+	client := New(server.URL, "", nil, 5*time.Second)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, err := client.VerifyStatus(test.guid)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.status, status)
+			}
+		})
+	}
+}
+
+func TestTransactionList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "txlist", query.Get("action"))
+		assert.Equal(t, "asc", query.Get("sort"))
+		switch query.Get("address") {
+		case "many":
+			// Two transactions in each block, with the listing truncated
+			// at block 5000
+			txs := make([]*Transaction, 0)
+			if query.Get("startblock") == "0" {
+				for i := 0; i < maxListResults; i++ {
+					txs = append(txs, &Transaction{BlockNumber: fmt.Sprintf("%d", i/2+1), Hash: fmt.Sprintf("%d", i)})
+				}
+			} else {
+				assert.Equal(t, "5000", query.Get("startblock"))
+				for i := maxListResults - 2; i < maxListResults+3; i++ {
+					txs = append(txs, &Transaction{BlockNumber: fmt.Sprintf("%d", i/2+1), Hash: fmt.Sprintf("%d", i)})
+				}
+			}
+			data, _ := json.Marshal(txs)
+			fmt.Fprintf(w, `{"status":"1","message":"OK","result":%s}`, string(data))
+		case "one":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"12","hash":"0x01","from":"0x02","gasUsed":"21000","gasPrice":"1000000000","isError":"0"}]}`)
+		case "none":
+			fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+		default:
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		address string
+		count   int
+		first   *Transaction
+		err     string
+	}{
+		{
+			name:    "Many",
+			address: "many",
+			count:   maxListResults + 3,
+			first:   &Transaction{BlockNumber: "1", Hash: "0"},
+		},
+		{
+			name:    "One",
+			address: "one",
+			count:   1,
+			first:   &Transaction{BlockNumber: "12", Hash: "0x01", From: "0x02", GasUsed: "21000", GasPrice: "1000000000", IsError: "0"},
+		},
+		{
+			name:    "None",
+			address: "none",
+		},
+		{
+			name:    "Error",
+			address: "bad",
+			err:     "Invalid API Key",
+		},
+	}
+
+	client := New(server.URL, "", nil, 5*time.Second)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			txs, err := client.TransactionList(test.address, 0, 99999999)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Len(t, txs, test.count)
+				if test.first != nil {
+					assert.Equal(t, test.first, txs[0])
+				}
+			}
+		})
+	}
+}
+
+func TestLists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "account", query.Get("module"))
+		assert.Equal(t, "0x01", query.Get("address"))
+		assert.Equal(t, "5", query.Get("startblock"))
+		assert.Equal(t, "10", query.Get("endblock"))
+		fmt.Fprintf(w, `{"status":"1","message":"OK","result":[{"blockNumber":"6","hash":"0x02","tokenSymbol":"%s"}]}`, query.Get("action"))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "", nil, 5*time.Second)
+	tests := []struct {
+		name   string
+		list   func(string, uint64, uint64) ([]*Transaction, error)
+		action string
+	}{
+		{
+			name:   "Transactions",
+			list:   client.TransactionList,
+			action: "txlist",
+		},
+		{
+			name:   "InternalTransactions",
+			list:   client.InternalTransactionList,
+			action: "txlistinternal",
+		},
+		{
+			name:   "TokenTransfers",
+			list:   client.TokenTransferList,
+			action: "tokentx",
+		},
+		{
+			name:   "NFTTransfers",
+			list:   client.NFTTransferList,
+			action: "tokennfttx",
+		},
+		{
+			name:   "MultiTokenTransfers",
+			list:   client.MultiTokenTransferList,
+			action: "token1155tx",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			txs, err := test.list("0x01", 5, 10)
+			assert.Nil(t, err)
+			assert.Equal(t, []*Transaction{{BlockNumber: "6", Hash: "0x02", TokenSymbol: test.action}}, txs)
+		})
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[]}`)
+	}))
+	defer slow.Close()
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{
+			name: "Timeout",
+			url:  slow.URL,
+		},
+		{
+			name: "ConnectionRefused",
+			url:  closed.URL,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := New(test.url, "secretkey", nil, 100*time.Millisecond)
+			_, err := client.TransactionList("0x01", 0, 0)
+			assert.NotNil(t, err)
+			if err != nil {
+				assert.False(t, strings.Contains(err.Error(), "secretkey"), err.Error())
+			}
+		})
+	}
+}
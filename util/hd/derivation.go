@@ -0,0 +1,146 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hd
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// HardenedOffset is added to the index of hardened children
+const HardenedOffset = uint32(0x80000000)
+
+var errInvalidKey = errors.New("derived key is invalid")
+
+// extendedKey is a BIP-32 extended private key
+type extendedKey struct {
+	key       []byte
+	chainCode []byte
+}
+
+// masterKey creates the master key from a seed
+func masterKey(seed []byte) (*extendedKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, errInvalidKey
+	}
+	return &extendedKey{key: sum[:32], chainCode: sum[32:]}, nil
+}
+
+// child derives the child key with the given index
+func (k *extendedKey) child(index uint32) (*extendedKey, error) {
+	var data []byte
+	if index >= HardenedOffset {
+		data = append([]byte{0x00}, k.key...)
+	} else {
+		privateKey, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = compressPubkey(&privateKey.PublicKey)
+	}
+	indexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(indexBytes, index)
+	data = append(data, indexBytes...)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return nil, errInvalidKey
+	}
+	key := tweak.Add(tweak, new(big.Int).SetBytes(k.key))
+	key.Mod(key, n)
+	if key.Sign() == 0 {
+		return nil, errInvalidKey
+	}
+	keyBytes := make([]byte, 32)
+	copy(keyBytes[32-len(key.Bytes()):], key.Bytes())
+	return &extendedKey{key: keyBytes, chainCode: sum[32:]}, nil
+}
+
+// compressPubkey serialises a public key in compressed form
+func compressPubkey(pubkey *ecdsa.PublicKey) []byte {
+	res := make([]byte, 33)
+	res[0] = 0x02 + byte(pubkey.Y.Bit(0))
+	x := pubkey.X.Bytes()
+	copy(res[33-len(x):], x)
+	return res
+}
+
+// ParsePath parses a derivation path such as "m/44'/60'/0'/0/0" in to its
+// indices.  Hardened indices can be marked with either ' or h.
+func ParsePath(path string) ([]uint32, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if components[0] != "m" {
+		return nil, fmt.Errorf("path %s does not start with m", path)
+	}
+	indices := make([]uint32, 0, len(components)-1)
+	for _, component := range components[1:] {
+		offset := uint32(0)
+		if strings.HasSuffix(component, "'") || strings.HasSuffix(component, "h") || strings.HasSuffix(component, "H") {
+			offset = HardenedOffset
+			component = component[:len(component)-1]
+		}
+		index, err := strconv.ParseUint(component, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("invalid path component %q in %s", component, path)
+		}
+		indices = append(indices, uint32(index)+offset)
+	}
+	return indices, nil
+}
+
+// FormatPath formats a list of indices as a derivation path
+func FormatPath(indices []uint32) string {
+	components := []string{"m"}
+	for _, index := range indices {
+		if index >= HardenedOffset {
+			components = append(components, fmt.Sprintf("%d'", index-HardenedOffset))
+		} else {
+			components = append(components, fmt.Sprintf("%d", index))
+		}
+	}
+	return strings.Join(components, "/")
+}
+
+// DeriveKey derives the private key at the given path from a seed
+func DeriveKey(seed []byte, path []uint32) (*ecdsa.PrivateKey, error) {
+	key, err := masterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range path {
+		key, err = key.child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return crypto.ToECDSA(key.key)
+}
@@ -0,0 +1,168 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordList(t *testing.T) {
+	assert.Equal(t, 2048, len(wordList), "incorrect word count")
+	assert.Equal(t, 2048, len(wordIndices), "duplicate words")
+	assert.Equal(t, "abandon", wordList[0])
+	assert.Equal(t, "zoo", wordList[2047])
+}
+
+func TestMnemonic(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{strings.Repeat("00", 16), "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"},
+		{strings.Repeat("7f", 16), "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{strings.Repeat("80", 16), "letter advice cage absurd amount doctor acoustic avoid letter advice cage above"},
+		{strings.Repeat("ff", 16), "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"},
+		{strings.Repeat("00", 24), strings.Repeat("abandon ", 17) + "agent"},
+		{strings.Repeat("ff", 24), strings.Repeat("zoo ", 17) + "when"},
+		{strings.Repeat("00", 32), strings.Repeat("abandon ", 23) + "art"},
+		{strings.Repeat("80", 32), "letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic bless"},
+		{strings.Repeat("ff", 32), strings.Repeat("zoo ", 23) + "vote"},
+		{"9e885d952ad362caeb4efe34a8e91bd2", "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic"},
+		{"6610b25967cdcca9d59875f5cb50b0ea75433311869e930b", "gravity machine north sort system female filter attitude volume fold club stay feature office ecology stable narrow fog"},
+	}
+
+	for i, test := range tests {
+		entropy, _ := hex.DecodeString(test.entropy)
+		mnemonic, err := NewMnemonic(entropy)
+		assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.mnemonic, mnemonic, fmt.Sprintf("incorrect mnemonic at test %d", i))
+		assert.Nil(t, ValidateMnemonic(test.mnemonic), fmt.Sprintf("failed to validate at test %d", i))
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		err      string
+	}{
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", ""},
+		{"  Abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon ABOUT\n", ""},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "invalid mnemonic checksum"},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "invalid number of words 11"},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon etherea", `unknown word "etherea"`},
+		{"", "invalid number of words 0"},
+	}
+
+	for i, test := range tests {
+		err := ValidateMnemonic(test.mnemonic)
+		if test.err == "" {
+			assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		} else {
+			assert.NotNil(t, err, fmt.Sprintf("missing error at test %d", i))
+			if err != nil {
+				assert.Equal(t, test.err, err.Error(), fmt.Sprintf("incorrect error at test %d", i))
+			}
+		}
+	}
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	for _, bits := range []int{128, 160, 192, 224, 256} {
+		mnemonic, err := GenerateMnemonic(bits)
+		assert.Nil(t, err, fmt.Sprintf("failed at %d bits", bits))
+		assert.Equal(t, bits*33/32/11, len(strings.Fields(mnemonic)), fmt.Sprintf("incorrect word count at %d bits", bits))
+		assert.Nil(t, ValidateMnemonic(mnemonic), fmt.Sprintf("failed to validate at %d bits", bits))
+	}
+	_, err := GenerateMnemonic(100)
+	assert.NotNil(t, err, "missing error for invalid length")
+}
+
+func TestSeedFromMnemonic(t *testing.T) {
+	seed, err := SeedFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR")
+	assert.Nil(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+
+	_, err = SeedFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "")
+	assert.NotNil(t, err, "missing error for bad checksum")
+}
+
+func TestDeriveKey(t *testing.T) {
+	// BIP-32 test vector 1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"m/0H", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0h/1/2h", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+
+	for i, test := range tests {
+		path, err := ParsePath(test.path)
+		assert.Nil(t, err, fmt.Sprintf("failed to parse path at test %d", i))
+		key, err := DeriveKey(seed, path)
+		assert.Nil(t, err, fmt.Sprintf("failed to derive key at test %d", i))
+		assert.Equal(t, test.key, hex.EncodeToString(crypto.FromECDSA(key)), fmt.Sprintf("incorrect key at test %d", i))
+	}
+}
+
+func TestDeriveAddress(t *testing.T) {
+	seed, err := SeedFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	assert.Nil(t, err)
+	path, err := ParsePath("m/44'/60'/0'/0/0")
+	assert.Nil(t, err)
+	key, err := DeriveKey(seed, path)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", crypto.PubkeyToAddress(key.PublicKey).Hex())
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		indices []uint32
+		err     string
+	}{
+		{"m", []uint32{}, ""},
+		{"m/44'/60'/0'/0/3", []uint32{HardenedOffset + 44, HardenedOffset + 60, HardenedOffset, 0, 3}, ""},
+		{"m/1h/2H", []uint32{HardenedOffset + 1, HardenedOffset + 2}, ""},
+		{"44'/60'", nil, "path 44'/60' does not start with m"},
+		{"m/a", nil, `invalid path component "a" in m/a`},
+		{"m/2147483648", nil, `invalid path component "2147483648" in m/2147483648`},
+		{"m//1", nil, `invalid path component "" in m//1`},
+	}
+
+	for i, test := range tests {
+		indices, err := ParsePath(test.path)
+		if test.err == "" {
+			assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+			assert.Equal(t, test.indices, indices, fmt.Sprintf("incorrect indices at test %d", i))
+			assert.Equal(t, strings.Replace(strings.Replace(test.path, "h", "'", -1), "H", "'", -1), FormatPath(indices), fmt.Sprintf("incorrect format at test %d", i))
+		} else {
+			assert.NotNil(t, err, fmt.Sprintf("missing error at test %d", i))
+			if err != nil {
+				assert.Equal(t, test.err, err.Error(), fmt.Sprintf("incorrect error at test %d", i))
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hd provides BIP-39 mnemonics and BIP-32 hierarchical deterministic
+// key derivation.
+package hd
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var wordList = strings.Fields(englishWords)
+
+var wordIndices map[string]int
+
+func init() {
+	wordIndices = make(map[string]int, len(wordList))
+	for i, word := range wordList {
+		wordIndices[word] = i
+	}
+}
+
+// NewMnemonic creates a mnemonic from the given entropy, which must be
+// between 128 and 256 bits long and a multiple of 32 bits
+func NewMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", fmt.Errorf("invalid entropy length %d bits", bits)
+	}
+	checksumBits := uint(bits / 32)
+	hash := sha256.Sum256(entropy)
+
+	// The entropy is followed by the first bits of its hash as a checksum
+	value := new(big.Int).SetBytes(entropy)
+	value.Lsh(value, checksumBits)
+	value.Or(value, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	words := make([]string, (bits+bits/32)/11)
+	mask := big.NewInt(2047)
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = wordList[new(big.Int).And(value, mask).Int64()]
+		value.Rsh(value, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// GenerateMnemonic creates a mnemonic from random entropy of the given number
+// of bits
+func GenerateMnemonic(bits int) (string, error) {
+	if bits%8 != 0 {
+		return "", fmt.Errorf("invalid entropy length %d bits", bits)
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks that a mnemonic contains a valid number of words,
+// that all of the words are in the word list, and that its checksum is
+// correct
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return fmt.Errorf("invalid number of words %d", len(words))
+	}
+	value := big.NewInt(0)
+	for _, word := range words {
+		index, exists := wordIndices[strings.ToLower(word)]
+		if !exists {
+			return fmt.Errorf("unknown word %q", word)
+		}
+		value.Lsh(value, 11)
+		value.Or(value, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(len(words) / 3)
+	checksum := new(big.Int).And(value, big.NewInt(int64(1<<checksumBits-1)))
+	value.Rsh(value, checksumBits)
+	entropy := make([]byte, (len(words)*11-int(checksumBits))/8)
+	valueBytes := value.Bytes()
+	copy(entropy[len(entropy)-len(valueBytes):], valueBytes)
+	hash := sha256.Sum256(entropy)
+	if int64(hash[0]>>(8-checksumBits)) != checksum.Int64() {
+		return fmt.Errorf("invalid mnemonic checksum")
+	}
+	return nil
+}
+
+// SeedFromMnemonic validates a mnemonic and creates the seed from it.  The
+// passphrase is optional.  Words and passphrase are used as supplied, without
+// Unicode normalisation.
+func SeedFromMnemonic(mnemonic string, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	normalised := strings.ToLower(strings.Join(strings.Fields(mnemonic), " "))
+	return pbkdf2.Key([]byte(normalised), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
@@ -0,0 +1,181 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package multicall batches read calls through a Multicall3 contract, so that
+// many reads can be carried out with a single eth_call.
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultAddress is the address at which Multicall3 is deployed on most
+// chains
+var DefaultAddress = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+var aggregate3Selector = crypto.Keccak256([]byte("aggregate3((address,bool,bytes)[])"))[:4]
+var getEthBalanceSelector = crypto.Keccak256([]byte("getEthBalance(address)"))[:4]
+var balanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+
+// Call is a single read call
+type Call struct {
+	Target common.Address
+	Data   []byte
+}
+
+// Result is the result of a single read call
+type Result struct {
+	Success bool
+	Data    []byte
+}
+
+// Available returns true if there is a contract at the multicall address at
+// the given block
+func Available(ctx context.Context, caller bind.ContractCaller, address common.Address, blockNumber *big.Int) bool {
+	code, err := caller.CodeAt(ctx, address, blockNumber)
+	return err == nil && len(code) > 0
+}
+
+// Aggregate carries out the calls in a single eth_call to the multicall
+// contract.  Individual calls are allowed to fail; their failure is reported
+// in the result rather than as an error.
+func Aggregate(ctx context.Context, caller bind.ContractCaller, address common.Address, calls []Call, blockNumber *big.Int) ([]Result, error) {
+	if len(calls) == 0 {
+		return []Result{}, nil
+	}
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{To: &address, Data: EncodeAggregate3(calls)}, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeAggregate3(output, len(calls))
+}
+
+// EthBalanceCall creates a call that obtains the Ether balance of an address
+// from the multicall contract at the given address
+func EthBalanceCall(multicall common.Address, holder common.Address) Call {
+	return Call{Target: multicall, Data: append(append([]byte{}, getEthBalanceSelector...), common.LeftPadBytes(holder.Bytes(), 32)...)}
+}
+
+// BalanceOfCall creates a call that obtains the token balance of an address
+func BalanceOfCall(token common.Address, holder common.Address) Call {
+	return Call{Target: token, Data: append(append([]byte{}, balanceOfSelector...), common.LeftPadBytes(holder.Bytes(), 32)...)}
+}
+
+// Uint256 decodes the result of a call that returns a single uint256
+func (r Result) Uint256() (*big.Int, error) {
+	if !r.Success {
+		return nil, errors.New("call failed")
+	}
+	if len(r.Data) != 32 {
+		return nil, fmt.Errorf("unexpected result length %d", len(r.Data))
+	}
+	return new(big.Int).SetBytes(r.Data), nil
+}
+
+// EncodeAggregate3 encodes a call to aggregate3() with each call allowed to
+// fail
+func EncodeAggregate3(calls []Call) []byte {
+	// Each call is encoded as a tuple of (address, bool, bytes)
+	tuples := make([][]byte, len(calls))
+	for i, call := range calls {
+		tuple := make([]byte, 0, 128+len(call.Data)+31)
+		tuple = append(tuple, word(new(big.Int).SetBytes(call.Target.Bytes()))...)
+		tuple = append(tuple, word(big.NewInt(1))...)
+		tuple = append(tuple, word(big.NewInt(0x60))...)
+		tuple = append(tuple, word(big.NewInt(int64(len(call.Data))))...)
+		tuple = append(tuple, common.RightPadBytes(call.Data, (len(call.Data)+31)/32*32)...)
+		tuples[i] = tuple
+	}
+
+	data := append([]byte{}, aggregate3Selector...)
+	data = append(data, word(big.NewInt(0x20))...)
+	data = append(data, word(big.NewInt(int64(len(calls))))...)
+	offset := 32 * len(calls)
+	for _, tuple := range tuples {
+		data = append(data, word(big.NewInt(int64(offset)))...)
+		offset += len(tuple)
+	}
+	for _, tuple := range tuples {
+		data = append(data, tuple...)
+	}
+	return data
+}
+
+// DecodeAggregate3 decodes the output of aggregate3(), which is an array of
+// (bool, bytes) tuples
+func DecodeAggregate3(output []byte, count int) ([]Result, error) {
+	start, err := readOffset(output, 0)
+	if err != nil {
+		return nil, err
+	}
+	length, err := readOffset(output, start)
+	if err != nil {
+		return nil, err
+	}
+	if length != count {
+		return nil, fmt.Errorf("expected %d results, received %d", count, length)
+	}
+	array := start + 32
+	results := make([]Result, count)
+	for i := range results {
+		offset, err := readOffset(output, array+32*i)
+		if err != nil {
+			return nil, err
+		}
+		tuple := array + offset
+		success, err := readOffset(output, tuple)
+		if err != nil {
+			return nil, err
+		}
+		dataOffset, err := readOffset(output, tuple+32)
+		if err != nil {
+			return nil, err
+		}
+		dataLength, err := readOffset(output, tuple+dataOffset)
+		if err != nil {
+			return nil, err
+		}
+		dataStart := tuple + dataOffset + 32
+		if dataStart+dataLength > len(output) {
+			return nil, errors.New("result data out of range")
+		}
+		results[i] = Result{Success: success != 0, Data: output[dataStart : dataStart+dataLength]}
+	}
+	return results, nil
+}
+
+// word encodes a value as a 32-byte word
+func word(value *big.Int) []byte {
+	return common.LeftPadBytes(value.Bytes(), 32)
+}
+
+// readOffset reads a word at the given position that is expected to be a
+// small value such as an offset or length
+func readOffset(data []byte, pos int) (int, error) {
+	if pos < 0 || pos+32 > len(data) {
+		return 0, errors.New("output too short")
+	}
+	value := new(big.Int).SetBytes(data[pos : pos+32])
+	if !value.IsInt64() || value.Int64() > int64(len(data)) {
+		return 0, errors.New("invalid offset in output")
+	}
+	return int(value.Int64()), nil
+}
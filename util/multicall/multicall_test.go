@@ -0,0 +1,150 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicall
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func hexBytes(input string) []byte {
+	res, err := hex.DecodeString(strings.Join(strings.Fields(input), ""))
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func TestSelectors(t *testing.T) {
+	assert.Equal(t, "82ad56cb", hex.EncodeToString(aggregate3Selector))
+	assert.Equal(t, "4d2301cc", hex.EncodeToString(getEthBalanceSelector))
+	assert.Equal(t, "70a08231", hex.EncodeToString(balanceOfSelector))
+}
+
+func TestEncodeAggregate3(t *testing.T) {
+	calls := []Call{
+		{Target: common.HexToAddress("0x0000000000000000000000000000000000000011"), Data: hexBytes("aabbccdd")},
+		{Target: common.HexToAddress("0x0000000000000000000000000000000000000022"), Data: []byte{}},
+	}
+	expected := hexBytes(`82ad56cb
+		0000000000000000000000000000000000000000000000000000000000000020
+		0000000000000000000000000000000000000000000000000000000000000002
+		0000000000000000000000000000000000000000000000000000000000000040
+		00000000000000000000000000000000000000000000000000000000000000e0
+		0000000000000000000000000000000000000000000000000000000000000011
+		0000000000000000000000000000000000000000000000000000000000000001
+		0000000000000000000000000000000000000000000000000000000000000060
+		0000000000000000000000000000000000000000000000000000000000000004
+		aabbccdd00000000000000000000000000000000000000000000000000000000
+		0000000000000000000000000000000000000000000000000000000000000022
+		0000000000000000000000000000000000000000000000000000000000000001
+		0000000000000000000000000000000000000000000000000000000000000060
+		0000000000000000000000000000000000000000000000000000000000000000`)
+	assert.Equal(t, hex.EncodeToString(expected), hex.EncodeToString(EncodeAggregate3(calls)))
+}
+
+// aggregate3Output is the output of aggregate3() for a successful call
+// returning 5 and a failed call returning no data
+var aggregate3Output = `0000000000000000000000000000000000000000000000000000000000000020
+	0000000000000000000000000000000000000000000000000000000000000002
+	0000000000000000000000000000000000000000000000000000000000000040
+	00000000000000000000000000000000000000000000000000000000000000c0
+	0000000000000000000000000000000000000000000000000000000000000001
+	0000000000000000000000000000000000000000000000000000000000000040
+	0000000000000000000000000000000000000000000000000000000000000020
+	0000000000000000000000000000000000000000000000000000000000000005
+	0000000000000000000000000000000000000000000000000000000000000000
+	0000000000000000000000000000000000000000000000000000000000000040
+	0000000000000000000000000000000000000000000000000000000000000000`
+
+func TestDecodeAggregate3(t *testing.T) {
+	tests := []struct {
+		output string
+		count  int
+		values []string
+		err    string
+	}{
+		{aggregate3Output, 2, []string{"5", ""}, ""},
+		{aggregate3Output, 3, nil, "expected 3 results, received 2"},
+		{"", 0, nil, "output too short"},
+		{aggregate3Output[:len(aggregate3Output)-64], 2, nil, "output too short"},
+	}
+
+	for i, test := range tests {
+		results, err := DecodeAggregate3(hexBytes(test.output), test.count)
+		if test.err != "" {
+			assert.NotNil(t, err, fmt.Sprintf("missing error at test %d", i))
+			if err != nil {
+				assert.Equal(t, test.err, err.Error(), fmt.Sprintf("incorrect error at test %d", i))
+			}
+			continue
+		}
+		assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		for j, result := range results {
+			value, err := result.Uint256()
+			if test.values[j] == "" {
+				assert.NotNil(t, err, fmt.Sprintf("missing error at test %d result %d", i, j))
+			} else {
+				assert.Nil(t, err, fmt.Sprintf("failed at test %d result %d", i, j))
+				assert.Equal(t, test.values[j], value.String(), fmt.Sprintf("incorrect value at test %d result %d", i, j))
+			}
+		}
+	}
+}
+
+// testCaller is a contract caller that returns fixed output
+type testCaller struct {
+	code   []byte
+	output []byte
+	msg    ethereum.CallMsg
+}
+
+func (c *testCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.code, nil
+}
+
+func (c *testCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.msg = call
+	return c.output, nil
+}
+
+func TestAggregate(t *testing.T) {
+	caller := &testCaller{output: hexBytes(aggregate3Output)}
+	assert.False(t, Available(context.Background(), caller, DefaultAddress, nil))
+	caller.code = []byte{0x01}
+	assert.True(t, Available(context.Background(), caller, DefaultAddress, nil))
+
+	holder := common.HexToAddress("0x2ab7150Bba7D5F181b3aF5623e52b15bB1054845")
+	token := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	calls := []Call{EthBalanceCall(DefaultAddress, holder), BalanceOfCall(token, holder)}
+	results, err := Aggregate(context.Background(), caller, DefaultAddress, calls, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.Equal(t, DefaultAddress, *caller.msg.To)
+	assert.Equal(t, EncodeAggregate3(calls), caller.msg.Data)
+	assert.Equal(t, "4d2301cc0000000000000000000000002ab7150bba7d5f181b3af5623e52b15bb1054845", hex.EncodeToString(calls[0].Data))
+	assert.Equal(t, token, calls[1].Target)
+
+	results, err = Aggregate(context.Background(), caller, DefaultAddress, []Call{}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(results))
+}
@@ -0,0 +1,164 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package price obtains the prices of Ether and tokens in fiat currencies.
+package price
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ether is the asset name used for the price of Ether.  Other assets are
+// identified by the hex address of their token contract.
+const Ether = "ETH"
+
+// Source provides the prices of assets
+type Source interface {
+	// Price returns the price of a single unit of an asset in a currency
+	Price(asset string, currency string) (*big.Float, error)
+}
+
+// NewSource creates a price source.  The name can be "coingecko",
+// "cryptocompare" or the base URL of a service with a CoinGecko-compatible
+// API.  Prices are cached for the lifetime of the source.
+func NewSource(name string) (Source, error) {
+	var source Source
+	switch {
+	case name == "" || name == "coingecko":
+		source = &coinGecko{baseURL: "https://api.coingecko.com/api/v3"}
+	case name == "cryptocompare":
+		source = &cryptoCompare{baseURL: "https://min-api.cryptocompare.com/data"}
+	case strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://"):
+		source = &coinGecko{baseURL: strings.TrimSuffix(name, "/")}
+	default:
+		return nil, fmt.Errorf("unknown price source %s", name)
+	}
+	return &cache{source: source, prices: make(map[string]*cachedPrice)}, nil
+}
+
+// Value returns the value in a currency of an amount of an asset with the
+// given number of decimals, for example 18 for Ether.
+func Value(source Source, asset string, currency string, amount *big.Int, decimals int) (*big.Float, error) {
+	price, err := source.Price(asset, currency)
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Float).SetInt(amount)
+	value.Mul(value, price)
+	return value.Quo(value, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))), nil
+}
+
+// cache remembers prices so that each is only fetched once.  Failures are
+// also remembered, to avoid repeatedly querying a source that is unavailable.
+type cache struct {
+	mutex  sync.Mutex
+	source Source
+	prices map[string]*cachedPrice
+}
+
+type cachedPrice struct {
+	price *big.Float
+	err   error
+}
+
+func (c *cache) Price(asset string, currency string) (*big.Float, error) {
+	key := strings.ToLower(asset) + "/" + strings.ToLower(currency)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cached, exists := c.prices[key]
+	if !exists {
+		cached = &cachedPrice{}
+		cached.price, cached.err = c.source.Price(asset, currency)
+		c.prices[key] = cached
+	}
+	return cached.price, cached.err
+}
+
+// fetch fetches and decodes a JSON response
+func fetch(requestURL string, response interface{}) error {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Get(requestURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("price request failed with status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// coinGecko obtains prices from CoinGecko
+type coinGecko struct {
+	baseURL string
+}
+
+func (s *coinGecko) Price(asset string, currency string) (*big.Float, error) {
+	currency = strings.ToLower(currency)
+	var requestURL string
+	var key string
+	if asset == Ether {
+		key = "ethereum"
+		requestURL = fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s", s.baseURL, key, url.QueryEscape(currency))
+	} else {
+		key = strings.ToLower(asset)
+		requestURL = fmt.Sprintf("%s/simple/token_price/ethereum?contract_addresses=%s&vs_currencies=%s", s.baseURL, url.QueryEscape(key), url.QueryEscape(currency))
+	}
+	var response map[string]map[string]json.Number
+	if err := fetch(requestURL, &response); err != nil {
+		return nil, err
+	}
+	price, exists := response[key][currency]
+	if !exists {
+		return nil, fmt.Errorf("no %s price for %s", currency, asset)
+	}
+	return parsePrice(price)
+}
+
+// cryptoCompare obtains prices from CryptoCompare.  CryptoCompare identifies
+// assets by symbol rather than address, so only supports the price of Ether.
+type cryptoCompare struct {
+	baseURL string
+}
+
+func (s *cryptoCompare) Price(asset string, currency string) (*big.Float, error) {
+	if asset != Ether {
+		return nil, errors.New("token prices are not supported by cryptocompare")
+	}
+	currency = strings.ToUpper(currency)
+	var response map[string]json.Number
+	if err := fetch(fmt.Sprintf("%s/price?fsym=ETH&tsyms=%s", s.baseURL, url.QueryEscape(currency)), &response); err != nil {
+		return nil, err
+	}
+	price, exists := response[currency]
+	if !exists {
+		return nil, fmt.Errorf("no %s price for %s", currency, asset)
+	}
+	return parsePrice(price)
+}
+
+func parsePrice(price json.Number) (*big.Float, error) {
+	res, ok := new(big.Float).SetString(price.String())
+	if !ok {
+		return nil, fmt.Errorf("invalid price %s", price)
+	}
+	return res, nil
+}
@@ -0,0 +1,108 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package price
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoinGecko(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/simple/price":
+			assert.Equal(t, "ethereum", r.URL.Query().Get("ids"))
+			fmt.Fprintf(w, `{"ethereum":{"%s":1234.5}}`, r.URL.Query().Get("vs_currencies"))
+		case "/simple/token_price/ethereum":
+			if r.URL.Query().Get("contract_addresses") == "0x6b175474e89094c44da98b954eedeac495271d0f" {
+				fmt.Fprint(w, `{"0x6b175474e89094c44da98b954eedeac495271d0f":{"usd":1.001}}`)
+			} else {
+				fmt.Fprint(w, `{}`)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source, err := NewSource(server.URL)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		asset    string
+		currency string
+		amount   *big.Int
+		decimals int
+		value    string
+		err      string
+	}{
+		{Ether, "usd", big.NewInt(1500000000000000000), 18, "1851.75", ""},
+		{Ether, "USD", big.NewInt(1000000000000000000), 18, "1234.50", ""},
+		{Ether, "eur", big.NewInt(0), 18, "0.00", ""},
+		{"0x6B175474E89094C44Da98b954EedeAC495271d0F", "usd", big.NewInt(2000000), 6, "2.00", ""},
+		{"0x0000000000000000000000000000000000000001", "usd", big.NewInt(1), 18, "", "no usd price for 0x0000000000000000000000000000000000000001"},
+	}
+
+	for i, test := range tests {
+		value, err := Value(source, test.asset, test.currency, test.amount, test.decimals)
+		if test.err == "" {
+			assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+			assert.Equal(t, test.value, value.Text('f', 2), fmt.Sprintf("incorrect value at test %d", i))
+		} else {
+			assert.NotNil(t, err, fmt.Sprintf("missing error at test %d", i))
+			if err != nil {
+				assert.Equal(t, test.err, err.Error(), fmt.Sprintf("incorrect error at test %d", i))
+			}
+		}
+	}
+	// Prices are cached, so each asset and currency is only requested once
+	assert.Equal(t, 4, requests)
+	_, err = source.Price(Ether, "usd")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, requests)
+}
+
+func TestFailure(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	source, err := NewSource(server.URL + "/")
+	assert.Nil(t, err)
+	_, err = source.Price(Ether, "usd")
+	assert.NotNil(t, err)
+	assert.Equal(t, "price request failed with status 429 Too Many Requests", err.Error())
+	_, err = source.Price(Ether, "usd")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestNewSource(t *testing.T) {
+	for _, name := range []string{"", "coingecko", "cryptocompare", "https://example.com/api"} {
+		_, err := NewSource(name)
+		assert.Nil(t, err, fmt.Sprintf("failed for %q", name))
+	}
+	_, err := NewSource("unknown")
+	assert.NotNil(t, err)
+}
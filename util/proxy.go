@@ -0,0 +1,142 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package util
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Storage slots used by proxies to hold the addresses of their
+// implementation, admin and beacon
+var (
+	// EIP1967ImplementationSlot is keccak256("eip1967.proxy.implementation")-1
+	EIP1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// EIP1967AdminSlot is keccak256("eip1967.proxy.admin")-1
+	EIP1967AdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	// EIP1967BeaconSlot is keccak256("eip1967.proxy.beacon")-1
+	EIP1967BeaconSlot = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+	// EIP1822ImplementationSlot is keccak256("PROXIABLE"), used by UUPS
+	// proxies that predate EIP-1967
+	EIP1822ImplementationSlot = common.HexToHash("0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7")
+	// ZeppelinOSImplementationSlot is keccak256("org.zeppelinos.proxy.implementation"),
+	// used by OpenZeppelin transparent proxies that predate EIP-1967
+	ZeppelinOSImplementationSlot = common.HexToHash("0x7050c9e0f4ca769c69bd3a8ef740bc37934f8e2c036e5a723fd8ee048ed3f8c3")
+	// ZeppelinOSAdminSlot is keccak256("org.zeppelinos.proxy.admin")
+	ZeppelinOSAdminSlot = common.HexToHash("0x10d6a54a4754c8869d6886b5f5d7fbfa5b4522237ea5c60d11bc4e7a1ff9390b")
+)
+
+// beaconImplementationSelector is the selector of implementation()
+var beaconImplementationSelector = []byte{0x5c, 0x60, 0xda, 0x1b}
+
+// ProxyReader is the access to the chain required to inspect a proxy
+type ProxyReader interface {
+	bind.ContractCaller
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// Proxy contains the details of a proxy contract
+type Proxy struct {
+	// Type is the layout of the proxy: "EIP-1967", "EIP-1967 beacon",
+	// "EIP-1822" or "ZeppelinOS"
+	Type           string
+	Implementation common.Address
+	// Admin is the zero address if the proxy does not have one
+	Admin common.Address
+	// Beacon is the zero address unless the proxy is a beacon proxy
+	Beacon common.Address
+}
+
+// ProxyDetails obtains the details of a proxy contract from its storage at
+// the given block, or the latest block if blockNumber is nil.  It returns
+// nil if the contract does not have an implementation in any of the known
+// proxy storage slots.
+func ProxyDetails(ctx context.Context, reader ProxyReader, address common.Address, blockNumber *big.Int) (*Proxy, error) {
+	admin, err := proxySlotAddress(ctx, reader, address, EIP1967AdminSlot, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	implementation, err := proxySlotAddress(ctx, reader, address, EIP1967ImplementationSlot, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if implementation != (common.Address{}) {
+		return &Proxy{Type: "EIP-1967", Implementation: implementation, Admin: admin}, nil
+	}
+
+	beacon, err := proxySlotAddress(ctx, reader, address, EIP1967BeaconSlot, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if beacon != (common.Address{}) {
+		implementation, err = beaconImplementation(ctx, reader, beacon, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return &Proxy{Type: "EIP-1967 beacon", Implementation: implementation, Admin: admin, Beacon: beacon}, nil
+	}
+
+	implementation, err = proxySlotAddress(ctx, reader, address, EIP1822ImplementationSlot, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if implementation != (common.Address{}) {
+		return &Proxy{Type: "EIP-1822", Implementation: implementation}, nil
+	}
+
+	implementation, err = proxySlotAddress(ctx, reader, address, ZeppelinOSImplementationSlot, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if implementation != (common.Address{}) {
+		admin, err = proxySlotAddress(ctx, reader, address, ZeppelinOSAdminSlot, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return &Proxy{Type: "ZeppelinOS", Implementation: implementation, Admin: admin}, nil
+	}
+
+	return nil, nil
+}
+
+// proxySlotAddress obtains the address held in a storage slot
+func proxySlotAddress(ctx context.Context, reader ProxyReader, address common.Address, slot common.Hash, blockNumber *big.Int) (common.Address, error) {
+	value, err := reader.StorageAt(ctx, address, slot, blockNumber)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(value), nil
+}
+
+// beaconImplementation calls implementation() on a beacon.  A beacon that
+// reverts or returns anything other than an address is treated as not
+// having an implementation.
+func beaconImplementation(ctx context.Context, caller bind.ContractCaller, beacon common.Address, blockNumber *big.Int) (common.Address, error) {
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &beacon, Data: beaconImplementationSelector}, blockNumber)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "revert") {
+			return common.Address{}, nil
+		}
+		return common.Address{}, err
+	}
+	if len(result) != 32 || new(big.Int).SetBytes(result).BitLen() > 160 {
+		return common.Address{}, nil
+	}
+	return common.BytesToAddress(result), nil
+}
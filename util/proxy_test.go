@@ -0,0 +1,136 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package util
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func eip1967Slot(name string) common.Hash {
+	slot := new(big.Int).SetBytes(crypto.Keccak256([]byte(name)))
+	return common.BigToHash(slot.Sub(slot, big.NewInt(1)))
+}
+
+func TestProxySlots(t *testing.T) {
+	assert.Equal(t, eip1967Slot("eip1967.proxy.implementation"), EIP1967ImplementationSlot)
+	assert.Equal(t, eip1967Slot("eip1967.proxy.admin"), EIP1967AdminSlot)
+	assert.Equal(t, eip1967Slot("eip1967.proxy.beacon"), EIP1967BeaconSlot)
+	assert.Equal(t, crypto.Keccak256Hash([]byte("PROXIABLE")), EIP1822ImplementationSlot)
+	assert.Equal(t, crypto.Keccak256Hash([]byte("org.zeppelinos.proxy.implementation")), ZeppelinOSImplementationSlot)
+	assert.Equal(t, crypto.Keccak256Hash([]byte("org.zeppelinos.proxy.admin")), ZeppelinOSAdminSlot)
+	assert.Equal(t, crypto.Keccak256([]byte("implementation()"))[:4], beaconImplementationSelector)
+}
+
+// proxyReader responds to storage reads and beacon calls
+type proxyReader struct {
+	storage map[common.Hash]common.Address
+	beacon  []byte
+	revert  bool
+	err     error
+}
+
+func (r *proxyReader) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x01}, nil
+}
+
+func (r *proxyReader) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if r.revert {
+		return nil, errors.New("execution reverted")
+	}
+	return r.beacon, nil
+}
+
+func (r *proxyReader) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return common.LeftPadBytes(r.storage[key].Bytes(), 32), nil
+}
+
+func TestProxyDetails(t *testing.T) {
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	implementation := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	admin := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	beacon := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	tests := []struct {
+		name     string
+		reader   *proxyReader
+		expected *Proxy
+		err      string
+	}{
+		{
+			name:   "NotProxy",
+			reader: &proxyReader{},
+		},
+		{
+			name:     "EIP1967",
+			reader:   &proxyReader{storage: map[common.Hash]common.Address{EIP1967ImplementationSlot: implementation, EIP1967AdminSlot: admin}},
+			expected: &Proxy{Type: "EIP-1967", Implementation: implementation, Admin: admin},
+		},
+		{
+			name:     "EIP1967NoAdmin",
+			reader:   &proxyReader{storage: map[common.Hash]common.Address{EIP1967ImplementationSlot: implementation}},
+			expected: &Proxy{Type: "EIP-1967", Implementation: implementation},
+		},
+		{
+			name:     "Beacon",
+			reader:   &proxyReader{storage: map[common.Hash]common.Address{EIP1967BeaconSlot: beacon}, beacon: common.LeftPadBytes(implementation.Bytes(), 32)},
+			expected: &Proxy{Type: "EIP-1967 beacon", Implementation: implementation, Beacon: beacon},
+		},
+		{
+			name:     "BeaconReverts",
+			reader:   &proxyReader{storage: map[common.Hash]common.Address{EIP1967BeaconSlot: beacon}, revert: true},
+			expected: &Proxy{Type: "EIP-1967 beacon", Beacon: beacon},
+		},
+		{
+			name:     "BeaconNotAddress",
+			reader:   &proxyReader{storage: map[common.Hash]common.Address{EIP1967BeaconSlot: beacon}, beacon: common.LeftPadBytes([]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, 32)},
+			expected: &Proxy{Type: "EIP-1967 beacon", Beacon: beacon},
+		},
+		{
+			name:     "EIP1822",
+			reader:   &proxyReader{storage: map[common.Hash]common.Address{EIP1822ImplementationSlot: implementation}},
+			expected: &Proxy{Type: "EIP-1822", Implementation: implementation},
+		},
+		{
+			name:     "ZeppelinOS",
+			reader:   &proxyReader{storage: map[common.Hash]common.Address{ZeppelinOSImplementationSlot: implementation, ZeppelinOSAdminSlot: admin}},
+			expected: &Proxy{Type: "ZeppelinOS", Implementation: implementation, Admin: admin},
+		},
+		{
+			name:   "Error",
+			reader: &proxyReader{err: errors.New("connection refused")},
+			err:    "connection refused",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			proxy, err := ProxyDetails(context.Background(), test.reader, address, nil)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, proxy)
+			}
+		})
+	}
+}
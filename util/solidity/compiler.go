@@ -0,0 +1,60 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solidity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CompilerListURL is the URL of the list of releases of the compiler
+const CompilerListURL = "https://binaries.soliditylang.org/bin/list.json"
+
+// LongVersion provides the full version of a release of the compiler, for
+// example "v0.8.19+commit.7dd6d404" for "0.8.19", from the list of releases
+// at the given URL.  Versions that are already full are returned unchanged.
+func LongVersion(listURL string, version string) (string, error) {
+	if strings.Contains(version, "+commit.") {
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		return version, nil
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Get(listURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("compiler list request failed with status %s", resp.Status)
+	}
+	var list struct {
+		Releases map[string]string `json:"releases"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return "", fmt.Errorf("invalid compiler list: %v", err)
+	}
+	release, exists := list.Releases[strings.TrimPrefix(version, "v")]
+	if !exists {
+		return "", fmt.Errorf("unknown compiler version %s", version)
+	}
+	// Releases are named after their files, for example
+	// soljson-v0.8.19+commit.7dd6d404.js
+	return strings.TrimSuffix(strings.TrimPrefix(release, "soljson-"), ".js"), nil
+}
@@ -0,0 +1,74 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solidity
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongVersion(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"builds":[],"releases":{"0.8.19":"soljson-v0.8.19+commit.7dd6d404.js","0.4.24":"soljson-v0.4.24+commit.e67f0147.js"},"latestRelease":"0.8.19"}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		version  string
+		expected string
+		err      string
+	}{
+		{
+			name:     "Short",
+			version:  "0.8.19",
+			expected: "v0.8.19+commit.7dd6d404",
+		},
+		{
+			name:     "ShortPrefixed",
+			version:  "v0.4.24",
+			expected: "v0.4.24+commit.e67f0147",
+		},
+		{
+			name:     "Long",
+			version:  "0.8.20+commit.a1b79de6",
+			expected: "v0.8.20+commit.a1b79de6",
+		},
+		{
+			name:    "Unknown",
+			version: "0.9.99",
+			err:     "unknown compiler version 0.9.99",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := LongVersion(server.URL, test.version)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, res)
+			}
+		})
+	}
+	// Full versions do not need the list
+	assert.Equal(t, 3, requests)
+}
@@ -0,0 +1,196 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solidity
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// StandardInput is the standard JSON input of the Solidity compiler, as
+// accepted by block explorers for source verification
+type StandardInput struct {
+	Language string             `json:"language"`
+	Sources  map[string]*Source `json:"sources"`
+	Settings *Settings          `json:"settings"`
+}
+
+// Source is a source file in the standard JSON input
+type Source struct {
+	Content string `json:"content"`
+}
+
+// Settings are the compiler settings in the standard JSON input
+type Settings struct {
+	Remappings      []string                       `json:"remappings,omitempty"`
+	Optimizer       *Optimizer                     `json:"optimizer"`
+	EVMVersion      string                         `json:"evmVersion,omitempty"`
+	OutputSelection map[string]map[string][]string `json:"outputSelection"`
+}
+
+// Optimizer contains the optimizer settings in the standard JSON input
+type Optimizer struct {
+	Enabled bool   `json:"enabled"`
+	Runs    uint64 `json:"runs"`
+}
+
+// NewStandardInput creates standard JSON input for the given sources and
+// settings, selecting the outputs required for verification
+func NewStandardInput(sources map[string]*Source, settings *Settings) *StandardInput {
+	if settings.OutputSelection == nil {
+		settings.OutputSelection = map[string]map[string][]string{
+			"*": {
+				"*": {"abi", "evm.bytecode", "evm.deployedBytecode", "evm.methodIdentifiers", "metadata"},
+				"":  {"ast"},
+			},
+		}
+	}
+	return &StandardInput{
+		Language: "Solidity",
+		Sources:  sources,
+		Settings: settings,
+	}
+}
+
+var importRegexp = regexp.MustCompile(`\bimport\s+(?:[^;"']*?\s+from\s+)?["']([^"']+)["'][^;]*;`)
+
+// Imports provides the paths imported by Solidity source, in the order in
+// which they are imported
+func Imports(source string) []string {
+	matches := importRegexp.FindAllStringSubmatch(stripComments(source), -1)
+	imports := make([]string, len(matches))
+	for i, match := range matches {
+		imports[i] = match[1]
+	}
+	return imports
+}
+
+// stripComments removes comments from Solidity source, leaving string
+// literals intact
+func stripComments(source string) string {
+	var res bytes.Buffer
+	for i := 0; i < len(source); i++ {
+		switch {
+		case source[i] == '"' || source[i] == '\'':
+			// Copy the string literal
+			quote := source[i]
+			res.WriteByte(source[i])
+			for i++; i < len(source) && source[i] != quote && source[i] != '\n'; i++ {
+				if source[i] == '\\' && i+1 < len(source) {
+					res.WriteByte(source[i])
+					i++
+				}
+				res.WriteByte(source[i])
+			}
+			if i < len(source) {
+				res.WriteByte(source[i])
+			}
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end == -1 {
+				return res.String()
+			}
+			i += end - 1
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end == -1 {
+				return res.String()
+			}
+			i += end + 3
+			res.WriteByte(' ')
+		default:
+			res.WriteByte(source[i])
+		}
+	}
+	return res.String()
+}
+
+// ResolveImport provides the name of the source imported with the given path
+// from the named source.  Paths starting with "./" or "../" are relative to
+// the importing source; other paths have the longest matching remapping, of
+// the form "prefix=target", applied.
+func ResolveImport(from string, importPath string, remappings []string) (string, error) {
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		importPath = path.Join(path.Dir(from), importPath)
+	} else {
+		prefix := ""
+		target := ""
+		for _, remapping := range remappings {
+			parts := strings.SplitN(remapping, "=", 2)
+			if len(parts) != 2 || parts[0] == "" || strings.Contains(parts[0], ":") {
+				return "", fmt.Errorf("invalid remapping %q; must be of the form prefix=target", remapping)
+			}
+			if strings.HasPrefix(importPath, parts[0]) && len(parts[0]) > len(prefix) {
+				prefix = parts[0]
+				target = parts[1]
+			}
+		}
+		if prefix != "" {
+			importPath = target + strings.TrimPrefix(importPath, prefix)
+		}
+		importPath = path.Clean(importPath)
+	}
+	if importPath == ".." || strings.HasPrefix(importPath, "../") || path.IsAbs(importPath) {
+		return "", fmt.Errorf("import %s from %s is outside of the sources", importPath, from)
+	}
+	return importPath, nil
+}
+
+// Sources reads the main source file from a directory, along with all of the
+// source files that it imports directly or indirectly.  Sources are keyed by
+// their path relative to the directory.
+func Sources(dir string, main string, remappings []string) (map[string]*Source, error) {
+	mainName, err := ResolveImport("", filepath.ToSlash(main), nil)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]*Source)
+	pending := []string{mainName}
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if _, exists := sources[name]; exists {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		sources[name] = &Source{Content: string(content)}
+		for _, importPath := range Imports(string(content)) {
+			imported, err := ResolveImport(name, importPath, remappings)
+			if err != nil {
+				return nil, err
+			}
+			pending = append(pending, imported)
+		}
+	}
+	return sources, nil
+}
+
+// SourceNames provides the names of sources in alphabetical order
+func SourceNames(sources map[string]*Source) []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
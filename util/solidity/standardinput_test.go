@@ -0,0 +1,160 @@
+// Copyright © 2017 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solidity
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImports(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected []string
+	}{
+		{
+			name:     "None",
+			source:   "pragma solidity ^0.8.0;\ncontract A {}",
+			expected: []string{},
+		},
+		{
+			name: "Forms",
+			source: `import "./A.sol";
+import './B.sol' as B;
+import * as C from "../C.sol";
+import {D, E as F} from "@openzeppelin/contracts/D.sol";
+import
+  "G.sol";`,
+			expected: []string{"./A.sol", "./B.sol", "../C.sol", "@openzeppelin/contracts/D.sol", "G.sol"},
+		},
+		{
+			name: "Comments",
+			source: `// import "./A.sol";
+/* import "./B.sol";
+   import "./C.sol"; */
+import "./D.sol"; // import "./E.sol";
+string constant url = "http://example.com/*"; import "./F.sol";`,
+			expected: []string{"./D.sol", "./F.sol"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Imports(test.source))
+		})
+	}
+}
+
+func TestResolveImport(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       string
+		path       string
+		remappings []string
+		expected   string
+		err        string
+	}{
+		{
+			name:     "Sibling",
+			from:     "contracts/A.sol",
+			path:     "./B.sol",
+			expected: "contracts/B.sol",
+		},
+		{
+			name:     "Parent",
+			from:     "contracts/token/A.sol",
+			path:     "../B.sol",
+			expected: "contracts/B.sol",
+		},
+		{
+			name:     "Direct",
+			from:     "contracts/A.sol",
+			path:     "lib/B.sol",
+			expected: "lib/B.sol",
+		},
+		{
+			name:       "Remapped",
+			from:       "contracts/A.sol",
+			path:       "@openzeppelin/contracts/token/ERC20.sol",
+			remappings: []string{"@openzeppelin/=lib/oz/", "@openzeppelin/contracts/=lib/openzeppelin-contracts/contracts/"},
+			expected:   "lib/openzeppelin-contracts/contracts/token/ERC20.sol",
+		},
+		{
+			name:       "RemappingInvalid",
+			from:       "contracts/A.sol",
+			path:       "@openzeppelin/contracts/token/ERC20.sol",
+			remappings: []string{"@openzeppelin"},
+			err:        `invalid remapping "@openzeppelin"; must be of the form prefix=target`,
+		},
+		{
+			name: "Outside",
+			from: "A.sol",
+			path: "../B.sol",
+			err:  "import ../B.sol from A.sol is outside of the sources",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := ResolveImport(test.from, test.path, test.remappings)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, res)
+			}
+		})
+	}
+}
+
+func TestSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solidity")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"contracts/Token.sol":           "import \"./Base.sol\";\nimport \"@lib/Math.sol\";\ncontract Token is Base {}",
+		"contracts/Base.sol":            "import \"../lib/math/Math.sol\";\ncontract Base {}",
+		"contracts/Unused.sol":          "contract Unused {}",
+		"lib/math/Math.sol":             "library Math {}",
+		"contracts/interfaces/IFoo.sol": "interface IFoo {}",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0700))
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+
+	sources, err := Sources(dir, "contracts/Token.sol", []string{"@lib/=lib/math/"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"contracts/Base.sol", "contracts/Token.sol", "lib/math/Math.sol"}, SourceNames(sources))
+	assert.Equal(t, "library Math {}", sources["lib/math/Math.sol"].Content)
+
+	_, err = Sources(dir, "contracts/Token.sol", nil)
+	assert.NotNil(t, err)
+}
+
+func TestNewStandardInput(t *testing.T) {
+	input := NewStandardInput(map[string]*Source{"A.sol": {Content: "contract A {}"}}, &Settings{
+		Optimizer: &Optimizer{Enabled: true, Runs: 200},
+	})
+	data, err := json.Marshal(input)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"language":"Solidity","sources":{"A.sol":{"content":"contract A {}"}},"settings":{"optimizer":{"enabled":true,"runs":200},"outputSelection":{"*":{"":["ast"],"*":["abi","evm.bytecode","evm.deployedBytecode","evm.methodIdentifiers","metadata"]}}}}`, string(data))
+}
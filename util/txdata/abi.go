@@ -0,0 +1,78 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type abiEntry struct {
+	Type   string        `json:"type"`
+	Name   string        `json:"name"`
+	Inputs []abiArgument `json:"inputs"`
+}
+
+type abiArgument struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Components []abiArgument `json:"components"`
+}
+
+// AddABIFunctions adds the functions in a JSON ABI to the translation list,
+// along with the names of their parameters.  Unlike signatures this allows
+// tuple (struct) parameters to be decoded.
+func AddABIFunctions(input []byte) error {
+	var entries []abiEntry
+	if err := json.Unmarshal(input, &entries); err != nil {
+		return fmt.Errorf("invalid ABI: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Type != "function" && entry.Type != "" {
+			continue
+		}
+		params := make([]string, len(entry.Inputs))
+		names := make([]string, len(entry.Inputs))
+		for i, input := range entry.Inputs {
+			params[i] = abiArgumentType(input)
+			names[i] = input.Name
+		}
+		addFunction(fmt.Sprintf("%s(%s)", entry.Name, strings.Join(params, ",")), names)
+	}
+	return nil
+}
+
+// abiArgumentType returns the canonical type of an ABI argument, expanding
+// tuples in to their component types
+func abiArgumentType(arg abiArgument) string {
+	if !strings.HasPrefix(arg.Type, "tuple") {
+		return arg.Type
+	}
+	components := make([]string, len(arg.Components))
+	for i, component := range arg.Components {
+		components[i] = abiArgumentType(component)
+	}
+	return "(" + strings.Join(components, ",") + ")" + strings.TrimPrefix(arg.Type, "tuple")
+}
+
+// ArgumentNames returns the names of the parameters of the function called by
+// a transaction's data, if they are known
+func ArgumentNames(input []byte) []string {
+	if len(input) < 4 {
+		return nil
+	}
+	var sig [4]byte
+	copy(sig[:], input[:4])
+	return functions[sig].names
+}
@@ -0,0 +1,329 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type argKind int
+
+const (
+	uintKind argKind = iota
+	intKind
+	boolKind
+	addressKind
+	fixedBytesKind
+	bytesKind
+	stringKind
+	functionKind
+	arrayKind
+	tupleKind
+)
+
+// argType is the decoded form of an ABI type such as "uint256[2][]" or
+// "(address,bytes)"
+type argType struct {
+	kind argKind
+	// size is the size in bytes of fixed bytes types
+	size int
+	// elem is the element type of arrays
+	elem *argType
+	// length is the length of fixed arrays, or -1 for dynamic arrays
+	length int
+	// components are the component types of tuples
+	components []*argType
+}
+
+var twoTo256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// parseType parses an ABI type string
+func parseType(input string) (*argType, error) {
+	input = strings.TrimSpace(input)
+	if strings.HasSuffix(input, "]") {
+		start := strings.LastIndex(input, "[")
+		if start == -1 {
+			return nil, fmt.Errorf("invalid type %s", input)
+		}
+		elem, err := parseType(input[:start])
+		if err != nil {
+			return nil, err
+		}
+		length := -1
+		if start+1 < len(input)-1 {
+			length, err = strconv.Atoi(input[start+1 : len(input)-1])
+			if err != nil || length <= 0 {
+				return nil, fmt.Errorf("invalid array length in %s", input)
+			}
+		}
+		return &argType{kind: arrayKind, elem: elem, length: length}, nil
+	}
+	if strings.HasPrefix(input, "(") && strings.HasSuffix(input, ")") {
+		parts, err := splitTypes(input[1 : len(input)-1])
+		if err != nil {
+			return nil, err
+		}
+		components := make([]*argType, len(parts))
+		for i, part := range parts {
+			components[i], err = parseType(part)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &argType{kind: tupleKind, components: components}, nil
+	}
+	switch {
+	case input == "bool":
+		return &argType{kind: boolKind}, nil
+	case input == "address":
+		return &argType{kind: addressKind}, nil
+	case input == "string":
+		return &argType{kind: stringKind}, nil
+	case input == "bytes":
+		return &argType{kind: bytesKind}, nil
+	case input == "function":
+		return &argType{kind: functionKind}, nil
+	case strings.HasPrefix(input, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(input, "bytes"))
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type %s", input)
+		}
+		return &argType{kind: fixedBytesKind, size: size}, nil
+	case strings.HasPrefix(input, "uint"):
+		if err := checkIntSize(strings.TrimPrefix(input, "uint")); err != nil {
+			return nil, fmt.Errorf("invalid type %s", input)
+		}
+		return &argType{kind: uintKind}, nil
+	case strings.HasPrefix(input, "int"):
+		if err := checkIntSize(strings.TrimPrefix(input, "int")); err != nil {
+			return nil, fmt.Errorf("invalid type %s", input)
+		}
+		return &argType{kind: intKind}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", input)
+	}
+}
+
+// checkIntSize checks the size suffix of an integer type
+func checkIntSize(input string) error {
+	if input == "" {
+		return nil
+	}
+	size, err := strconv.Atoi(input)
+	if err != nil || size < 8 || size > 256 || size%8 != 0 {
+		return fmt.Errorf("invalid integer size %s", input)
+	}
+	return nil
+}
+
+// splitTypes splits a comma-separated list of types, ignoring commas that
+// are inside tuples
+func splitTypes(input string) ([]string, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	res := make([]string, 0)
+	depth := 0
+	start := 0
+	for i, c := range input {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %s", input)
+			}
+		case ',':
+			if depth == 0 {
+				res = append(res, input[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in %s", input)
+	}
+	return append(res, input[start:]), nil
+}
+
+// isDynamic returns true if the type is encoded in the tail of its
+// enclosing data rather than in place
+func (t *argType) isDynamic() bool {
+	switch t.kind {
+	case bytesKind, stringKind:
+		return true
+	case arrayKind:
+		return t.length == -1 || t.elem.isDynamic()
+	case tupleKind:
+		for _, component := range t.components {
+			if component.isDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize returns the number of bytes the type occupies in the head of its
+// enclosing data
+func (t *argType) headSize() int {
+	if t.isDynamic() {
+		return 32
+	}
+	switch t.kind {
+	case arrayKind:
+		return t.length * t.elem.headSize()
+	case tupleKind:
+		size := 0
+		for _, component := range t.components {
+			size += component.headSize()
+		}
+		return size
+	default:
+		return 32
+	}
+}
+
+// readWord reads the 32-byte word at the given position
+func readWord(data []byte, pos int) ([]byte, error) {
+	if pos < 0 || pos+32 > len(data) {
+		return nil, fmt.Errorf("data too short")
+	}
+	return data[pos : pos+32], nil
+}
+
+// readUint reads the word at the given position as an offset or length,
+// ensuring that it is no larger than the data that contains it
+func readUint(data []byte, pos int) (int, error) {
+	word, err := readWord(data, pos)
+	if err != nil {
+		return 0, err
+	}
+	value := new(big.Int).SetBytes(word)
+	if !value.IsInt64() || value.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("invalid offset or length %v", value)
+	}
+	return int(value.Int64()), nil
+}
+
+// decodeElement decodes the element of the given type whose head is at the
+// given position
+func decodeElement(t *argType, data []byte, pos int) (string, error) {
+	if !t.isDynamic() {
+		if pos < 0 || pos > len(data) {
+			return "", fmt.Errorf("data too short")
+		}
+		return decodeValue(t, data[pos:])
+	}
+	offset, err := readUint(data, pos)
+	if err != nil {
+		return "", err
+	}
+	return decodeValue(t, data[offset:])
+}
+
+// decodeSequence decodes a sequence of elements, as found in tuples and
+// arrays
+func decodeSequence(types []*argType, data []byte) ([]string, error) {
+	values := make([]string, len(types))
+	pos := 0
+	for i, t := range types {
+		value, err := decodeElement(t, data, pos)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		pos += t.headSize()
+	}
+	return values, nil
+}
+
+// decodeValue decodes a single value of the given type from the start of the
+// data
+func decodeValue(t *argType, data []byte) (string, error) {
+	switch t.kind {
+	case tupleKind:
+		values, err := decodeSequence(t.components, data)
+		if err != nil {
+			return "", err
+		}
+		return "(" + strings.Join(values, ",") + ")", nil
+	case arrayKind:
+		length := t.length
+		if length == -1 {
+			var err error
+			length, err = readUint(data, 0)
+			if err != nil {
+				return "", err
+			}
+			data = data[32:]
+		}
+		// Every element occupies at least one word of the head
+		if length > len(data)/32 {
+			return "", fmt.Errorf("invalid array length %d", length)
+		}
+		elems := make([]*argType, length)
+		for i := range elems {
+			elems[i] = t.elem
+		}
+		values, err := decodeSequence(elems, data)
+		if err != nil {
+			return "", err
+		}
+		return "[" + strings.Join(values, ",") + "]", nil
+	case bytesKind, stringKind:
+		length, err := readUint(data, 0)
+		if err != nil {
+			return "", err
+		}
+		if 32+length > len(data) {
+			return "", fmt.Errorf("data too short")
+		}
+		if t.kind == stringKind {
+			return fmt.Sprintf("\"%s\"", string(data[32:32+length])), nil
+		}
+		return fmt.Sprintf("0x%x", data[32:32+length]), nil
+	}
+
+	word, err := readWord(data, 0)
+	if err != nil {
+		return "", err
+	}
+	switch t.kind {
+	case uintKind:
+		return new(big.Int).SetBytes(word).String(), nil
+	case intKind:
+		value := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			value.Sub(value, twoTo256)
+		}
+		return value.String(), nil
+	case boolKind:
+		if word[31] == 0x01 {
+			return "true", nil
+		}
+		return "false", nil
+	case addressKind:
+		return fmt.Sprintf("0x%x", word[12:]), nil
+	case fixedBytesKind:
+		return fmt.Sprintf("0x%x", word[:t.size]), nil
+	case functionKind:
+		return fmt.Sprintf("0x%x", word[:24]), nil
+	default:
+		return "", fmt.Errorf("unknown type")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature string
+		data      string
+		values    []string
+	}{
+		{
+			name:      "MultidimensionalFixed",
+			signature: "f(uint256[2][3])",
+			data:      "000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000050000000000000000000000000000000000000000000000000000000000000006",
+			values:    []string{"[[1,2],[3,4],[5,6]]"},
+		},
+		{
+			name:      "MultidimensionalDynamic",
+			signature: "f(uint256[][])",
+			data:      "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000003",
+			values:    []string{"[[1,2],[3]]"},
+		},
+		{
+			name:      "FixedArrayOfStrings",
+			signature: "f(string[2],bytes)",
+			data:      "0000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000080000000000000000000000000000000000000000000000000000000000000000161000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002626300000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000020102000000000000000000000000000000000000000000000000000000000000",
+			values:    []string{`["a","bc"]`, "0x0102"},
+		},
+		{
+			name:      "TupleWithDynamicFields",
+			signature: "f((uint256,string,bytes))",
+			data:      "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000007000000000000000000000000000000000000000000000000000000000000006000000000000000000000000000000000000000000000000000000000000000a0000000000000000000000000000000000000000000000000000000000000000568656c6c6f0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002dead000000000000000000000000000000000000000000000000000000000000",
+			values:    []string{`(7,"hello",0xdead)`},
+		},
+		{
+			name:      "ArrayOfTuplesWithDynamicFields",
+			signature: "f((address,uint256[])[],bool)",
+			data:      "000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000e00000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc400000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000020000000000000000000000002c7536e3605d9c16a7a3d7b1898e529396a65c2300000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000000",
+			values:    []string{"[(0x5ffc014343cd971b7eb70732021e26c35b744cc4,[1,2]),(0x2c7536e3605d9c16a7a3d7b1898e529396a65c23,[])]", "true"},
+		},
+		{
+			name:      "SignedIntegers",
+			signature: "f(int256,int8)",
+			data:      "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80",
+			values:    []string{"-1", "-128"},
+		},
+		{
+			name:      "StaticArrayBeforeString",
+			signature: "f(uint256[3],string)",
+			data:      "000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000056166746572000000000000000000000000000000000000000000000000000000",
+			values:    []string{"[1,2,3]", `"after"`},
+		},
+		{
+			name:      "Truncated",
+			signature: "f(uint256,string)",
+			data:      "00000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000040",
+			values:    []string{"1", "data too short"},
+		},
+		{
+			name:      "BadOffset",
+			signature: "f(bytes)",
+			data:      "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			values:    []string{"invalid offset or length 115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			InitFunctionMap()
+			AddFunctionSignature(test.signature)
+			selector := functionSelector(test.signature)
+			params, err := hex.DecodeString(test.data)
+			assert.Nil(t, err)
+			_, _, values, _, exists := DataToFunction(append(selector[:], params...))
+			assert.True(t, exists)
+			assert.Equal(t, test.values, values)
+		})
+	}
+}
+
+func TestDecodeABITuple(t *testing.T) {
+	InitFunctionMap()
+	abi := `[{"type":"function","name":"submit","inputs":[{"name":"order","type":"tuple","components":[{"name":"amount","type":"uint256"},{"name":"memo","type":"string"},{"name":"data","type":"bytes"}]}]},{"type":"event","name":"Submitted","inputs":[]}]`
+	assert.Nil(t, AddABIFunctions([]byte(abi)))
+
+	selector := functionSelector("submit((uint256,string,bytes))")
+	params, err := hex.DecodeString("00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000007000000000000000000000000000000000000000000000000000000000000006000000000000000000000000000000000000000000000000000000000000000a0000000000000000000000000000000000000000000000000000000000000000568656c6c6f0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002dead000000000000000000000000000000000000000000000000000000000000")
+	assert.Nil(t, err)
+	input := append(selector[:], params...)
+
+	name, types, values, _, exists := DataToFunction(input)
+	assert.True(t, exists)
+	assert.Equal(t, "submit", name)
+	assert.Equal(t, []string{"(uint256,string,bytes)"}, types)
+	assert.Equal(t, []string{`(7,"hello",0xdead)`}, values)
+	assert.Equal(t, []string{"order"}, ArgumentNames(input))
+}
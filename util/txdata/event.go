@@ -0,0 +1,204 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+)
+
+// events are keyed by topic; there can be more than one event for a topic
+// as events with the same signature can index different parameters (for
+// example ERC-20 and ERC-721 Transfer events)
+var events map[common.Hash][]event
+
+type event struct {
+	name    string
+	params  []string
+	names   []string
+	indexed []bool
+}
+
+// indexedCount returns the number of indexed parameters of the event
+func (e event) indexedCount() int {
+	count := 0
+	for _, indexed := range e.indexed {
+		if indexed {
+			count++
+		}
+	}
+	return count
+}
+
+// AddEventSignature adds an event signature to the translation list.  The
+// signature is in the form used by Solidity, where each parameter is a type
+// optionally followed by "indexed" and a name, for example
+// "Transfer(address indexed from,address indexed to,uint256 value)".
+func AddEventSignature(signature string) error {
+	start := strings.Index(signature, "(")
+	if start < 1 || !strings.HasSuffix(signature, ")") {
+		return fmt.Errorf("invalid event signature %s", signature)
+	}
+	params, err := splitTypes(signature[start+1 : len(signature)-1])
+	if err != nil {
+		return fmt.Errorf("invalid event signature %s: %v", signature, err)
+	}
+	e := event{
+		name:    signature[:start],
+		params:  make([]string, len(params)),
+		names:   make([]string, len(params)),
+		indexed: make([]bool, len(params)),
+	}
+	for i, param := range params {
+		// The type can be a tuple containing spaces so split from the end
+		fields := strings.Fields(param)
+		if len(fields) == 0 {
+			return fmt.Errorf("invalid event signature %s", signature)
+		}
+		if len(fields) > 1 && fields[len(fields)-1] != "indexed" {
+			e.names[i] = fields[len(fields)-1]
+			fields = fields[:len(fields)-1]
+		}
+		if len(fields) > 1 && fields[len(fields)-1] == "indexed" {
+			e.indexed[i] = true
+			fields = fields[:len(fields)-1]
+		}
+		e.params[i] = strings.Join(fields, "")
+		if _, err := parseType(e.params[i]); err != nil {
+			return fmt.Errorf("invalid event signature %s: %v", signature, err)
+		}
+	}
+	topic := eventTopic(fmt.Sprintf("%s(%s)", e.name, strings.Join(e.params, ",")))
+	for i, existing := range events[topic] {
+		if existing.indexedCount() == e.indexedCount() {
+			events[topic][i] = e
+			return nil
+		}
+	}
+	events[topic] = append(events[topic], e)
+	return nil
+}
+
+// eventTopic calculates the topic for an event signature
+func eventTopic(signature string) (topic common.Hash) {
+	sha := sha3.NewKeccak256()
+	sha.Write([]byte(signature))
+	sha.Sum(topic[:0])
+	return
+}
+
+// LogToEvent takes a log's topics and data and decodes it in to the name of
+// the event along with the name, type and value of each of its parameters.
+// Indexed parameters with dynamic types are stored in the log as the hash of
+// their value, so it is the hash that is returned for these parameters.
+func LogToEvent(topics []common.Hash, data []byte) (name string, names []string, types []string, values []string, exists bool) {
+	if len(topics) == 0 {
+		return
+	}
+	var e event
+	for _, candidate := range events[topics[0]] {
+		if candidate.indexedCount() == len(topics)-1 {
+			e = candidate
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return
+	}
+
+	name = e.name
+	topic := 1
+	pos := 0
+	for i, param := range e.params {
+		names = append(names, e.names[i])
+		types = append(types, param)
+		t, err := parseType(param)
+		if err != nil {
+			values = append(values, err.Error())
+			continue
+		}
+		var res string
+		if e.indexed[i] {
+			if t.isDynamic() || t.kind == arrayKind || t.kind == tupleKind {
+				res = topics[topic].Hex()
+			} else {
+				res, err = decodeValue(t, topics[topic].Bytes())
+			}
+			topic++
+		} else {
+			res, err = decodeElement(t, data, pos)
+			pos += t.headSize()
+		}
+		if err != nil {
+			res = err.Error()
+		}
+		values = append(values, res)
+	}
+	return
+}
+
+// InitEventMap initialises the event map with commonly-used events
+func InitEventMap() {
+	events = make(map[common.Hash][]event)
+	for _, signature := range []string{
+		// ERC-20
+		"Transfer(address indexed from,address indexed to,uint256 value)",
+		"Approval(address indexed owner,address indexed spender,uint256 value)",
+		// ERC-721
+		"Transfer(address indexed from,address indexed to,uint256 indexed tokenId)",
+		"Approval(address indexed owner,address indexed approved,uint256 indexed tokenId)",
+		"ApprovalForAll(address indexed owner,address indexed operator,bool approved)",
+		// ERC-777
+		"Sent(address indexed operator,address indexed from,address indexed to,uint256 amount,bytes data,bytes operatorData)",
+		"Minted(address indexed operator,address indexed to,uint256 amount,bytes data,bytes operatorData)",
+		"Burned(address indexed operator,address indexed from,uint256 amount,bytes data,bytes operatorData)",
+		// ERC-1155
+		"TransferSingle(address indexed operator,address indexed from,address indexed to,uint256 id,uint256 value)",
+		"TransferBatch(address indexed operator,address indexed from,address indexed to,uint256[] ids,uint256[] values)",
+		"URI(string value,uint256 indexed id)",
+		// Wrapped Ether
+		"Deposit(address indexed dst,uint256 wad)",
+		"Withdrawal(address indexed src,uint256 wad)",
+		// Ownership, access control and upgrades
+		"OwnershipTransferred(address indexed previousOwner,address indexed newOwner)",
+		"RoleGranted(bytes32 indexed role,address indexed account,address indexed sender)",
+		"RoleRevoked(bytes32 indexed role,address indexed account,address indexed sender)",
+		"Paused(address account)",
+		"Unpaused(address account)",
+		"Upgraded(address indexed implementation)",
+		"AdminChanged(address previousAdmin,address newAdmin)",
+		// Uniswap
+		"Swap(address indexed sender,uint256 amount0In,uint256 amount1In,uint256 amount0Out,uint256 amount1Out,address indexed to)",
+		"Sync(uint112 reserve0,uint112 reserve1)",
+		"Mint(address indexed sender,uint256 amount0,uint256 amount1)",
+		"Burn(address indexed sender,uint256 amount0,uint256 amount1,address indexed to)",
+		// ENS
+		"NewOwner(bytes32 indexed node,bytes32 indexed label,address owner)",
+		"Transfer(bytes32 indexed node,address owner)",
+		"NewResolver(bytes32 indexed node,address resolver)",
+		"NewTTL(bytes32 indexed node,uint64 ttl)",
+		"AddrChanged(bytes32 indexed node,address a)",
+		"AddressChanged(bytes32 indexed node,uint256 coinType,bytes newAddress)",
+		"NameChanged(bytes32 indexed node,string name)",
+		"ContenthashChanged(bytes32 indexed node,bytes hash)",
+		"TextChanged(bytes32 indexed node,string indexed indexedKey,string key)",
+		"NameRegistered(string name,bytes32 indexed label,address indexed owner,uint256 cost,uint256 expires)",
+		"NameRenewed(string name,bytes32 indexed label,uint256 cost,uint256 expires)",
+	} {
+		AddEventSignature(signature)
+	}
+}
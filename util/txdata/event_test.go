@@ -0,0 +1,136 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogToEvent(t *testing.T) {
+	from := common.HexToHash("0x5ffc014343cd971b7eb70732021e26c35b744cc4")
+	to := common.HexToHash("0x2c7536e3605d9c16a7a3d7b1898e529396a65c23")
+	tests := []struct {
+		name      string
+		signature string
+		topics    []common.Hash
+		data      string
+		event     string
+		names     []string
+		types     []string
+		values    []string
+		exists    bool
+	}{
+		{
+			name:   "NoTopics",
+			exists: false,
+		},
+		{
+			name:   "Unknown",
+			topics: []common.Hash{eventTopic("Unknown()")},
+			exists: false,
+		},
+		{
+			name:   "ERC20Transfer",
+			topics: []common.Hash{eventTopic("Transfer(address,address,uint256)"), from, to},
+			data:   "000000000000000000000000000000000000000000000000016345785d8a0000",
+			event:  "Transfer",
+			names:  []string{"from", "to", "value"},
+			types:  []string{"address", "address", "uint256"},
+			values: []string{"0x5ffc014343cd971b7eb70732021e26c35b744cc4", "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23", "100000000000000000"},
+			exists: true,
+		},
+		{
+			name:   "ERC721Transfer",
+			topics: []common.Hash{eventTopic("Transfer(address,address,uint256)"), from, to, common.BigToHash(common.Big3)},
+			event:  "Transfer",
+			names:  []string{"from", "to", "tokenId"},
+			types:  []string{"address", "address", "uint256"},
+			values: []string{"0x5ffc014343cd971b7eb70732021e26c35b744cc4", "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23", "3"},
+			exists: true,
+		},
+		{
+			name:   "WrongTopicCount",
+			topics: []common.Hash{eventTopic("Transfer(address,address,uint256)"), from},
+			exists: false,
+		},
+		{
+			name:   "IndexedAfterData",
+			topics: []common.Hash{eventTopic("URI(string,uint256)"), common.BigToHash(common.Big2)},
+			data:   "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000008697066733a2f2f78000000000000000000000000000000000000000000000000",
+			event:  "URI",
+			names:  []string{"value", "id"},
+			types:  []string{"string", "uint256"},
+			values: []string{`"ipfs://x"`, "2"},
+			exists: true,
+		},
+		{
+			name:   "IndexedDynamic",
+			topics: []common.Hash{eventTopic("TextChanged(bytes32,string,string)"), from, eventTopic("url")},
+			data:   "0000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000375726c0000000000000000000000000000000000000000000000000000000000",
+			event:  "TextChanged",
+			names:  []string{"node", "indexedKey", "key"},
+			types:  []string{"bytes32", "string", "string"},
+			values: []string{from.Hex(), eventTopic("url").Hex(), `"url"`},
+			exists: true,
+		},
+		{
+			name:      "Custom",
+			signature: "Moved(int8 indexed delta,address)",
+			topics:    []common.Hash{eventTopic("Moved(int8,address)"), common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")},
+			data:      "0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4",
+			event:     "Moved",
+			names:     []string{"delta", ""},
+			types:     []string{"int8", "address"},
+			values:    []string{"-1", "0x5ffc014343cd971b7eb70732021e26c35b744cc4"},
+			exists:    true,
+		},
+		{
+			name:   "ShortData",
+			topics: []common.Hash{eventTopic("Transfer(address,address,uint256)"), from, to},
+			event:  "Transfer",
+			names:  []string{"from", "to", "value"},
+			types:  []string{"address", "address", "uint256"},
+			values: []string{"0x5ffc014343cd971b7eb70732021e26c35b744cc4", "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23", "data too short"},
+			exists: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			InitEventMap()
+			if test.signature != "" {
+				assert.Nil(t, AddEventSignature(test.signature))
+			}
+			data, err := hex.DecodeString(test.data)
+			assert.Nil(t, err)
+			event, names, types, values, exists := LogToEvent(test.topics, data)
+			assert.Equal(t, test.exists, exists)
+			if exists {
+				assert.Equal(t, test.event, event)
+				assert.Equal(t, test.names, names)
+				assert.Equal(t, test.types, types)
+				assert.Equal(t, test.values, values)
+			}
+		})
+	}
+}
+
+func TestAddEventSignatureInvalid(t *testing.T) {
+	InitEventMap()
+	assert.EqualError(t, AddEventSignature("Moved"), "invalid event signature Moved")
+	assert.EqualError(t, AddEventSignature("Moved(uint7 indexed)"), "invalid event signature Moved(uint7 indexed): invalid type uint7")
+}
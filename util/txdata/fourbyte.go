@@ -0,0 +1,90 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// fourByteURL is the endpoint used to look up unknown function selectors
+var fourByteURL = "https://www.4byte.directory/api/v1/signatures/?hex_signature="
+
+var onlineLookup bool
+
+// lookedUp records the selectors already queried online, to avoid repeated lookups
+var lookedUp map[[4]byte]bool
+
+type fourByteResponse struct {
+	Count   int              `json:"count"`
+	Results []fourByteResult `json:"results"`
+}
+
+type fourByteResult struct {
+	ID            int    `json:"id"`
+	TextSignature string `json:"text_signature"`
+}
+
+// EnableOnlineLookup allows DataToString to look up function selectors that
+// are not in the function map using 4byte.directory
+func EnableOnlineLookup() {
+	onlineLookup = true
+	lookedUp = make(map[[4]byte]bool)
+}
+
+// lookupFunction fetches a function signature from 4byte.directory and adds
+// it to the function map.  If there are multiple candidate signatures then
+// the oldest (lowest ID) is used and the function is marked as ambiguous.
+func lookupFunction(sig [4]byte) (found bool) {
+	if lookedUp[sig] {
+		_, found = functions[sig]
+		return
+	}
+	lookedUp[sig] = true
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Get(fmt.Sprintf("%s0x%x", fourByteURL, sig))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	var response fourByteResponse
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return false
+	}
+	if len(response.Results) == 0 {
+		return false
+	}
+
+	best := response.Results[0]
+	for _, result := range response.Results[1:] {
+		if result.ID < best.ID {
+			best = result
+		}
+	}
+	if !addFunctionSignature(best.TextSignature, sig) {
+		return false
+	}
+	if response.Count > 1 {
+		function := functions[sig]
+		function.ambiguous = true
+		functions[sig] = function
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnlineLookup(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("hex_signature") {
+		case "0x12345678":
+			fmt.Fprint(w, `{"count":2,"results":[{"id":20,"text_signature":"wrongSelector(uint256)"},{"id":10,"text_signature":"alsoWrong(uint256)"}]}`)
+		case "0x70a08231":
+			fmt.Fprint(w, `{"count":2,"results":[{"id":31781,"text_signature":"passphrase_calculate_transfer(uint64,address)"},{"id":166,"text_signature":"balanceOf(address)"}]}`)
+		default:
+			fmt.Fprint(w, `{"count":0,"results":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	// Restore the package state for later tests
+	savedURL, savedFunctions, savedOnlineLookup, savedLookedUp := fourByteURL, functions, onlineLookup, lookedUp
+	defer func() {
+		fourByteURL, functions, onlineLookup, lookedUp = savedURL, savedFunctions, savedOnlineLookup, savedLookedUp
+	}()
+
+	fourByteURL = server.URL + "/?hex_signature="
+
+	functions = make(map[[4]byte]function)
+	EnableOnlineLookup()
+
+	data, _ := hex.DecodeString("70a082310000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4")
+	assert.Equal(t, "balanceOf(0x5ffc014343cd971b7eb70732021e26c35b744cc4) (ambiguous signature)", DataToString(data))
+	assert.Equal(t, "balanceOf(0x5ffc014343cd971b7eb70732021e26c35b744cc4) (ambiguous signature)", DataToString(data))
+	assert.Equal(t, 1, requests)
+
+	// Signatures that do not match the selector are ignored
+	data, _ = hex.DecodeString("12345678")
+	assert.Equal(t, "12345678", DataToString(data))
+	assert.Equal(t, "12345678", DataToString(data))
+	assert.Equal(t, 2, requests)
+}
@@ -14,20 +14,24 @@ package txdata
 
 import (
 	"bytes"
-	"encoding/binary"
 	"fmt"
-	"math/big"
 	"strings"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
 )
 
 var functions map[[4]byte]function
 
 type function struct {
-	name   string
-	params []string
+	name      string
+	params    []string
+	names     []string
+	ambiguous bool
+}
+
+// signature returns the signature of the function
+func (f function) signature() string {
+	return fmt.Sprintf("%s(%s)", f.name, strings.Join(f.params, ","))
 }
 
 // DataToString takes a transaction's data bytes and converts it in to a useful representation if one exists
@@ -35,92 +39,95 @@ func DataToString(input []byte) string {
 	if len(input) == 0 {
 		return ""
 	}
-	var sig [4]byte
-	copy(sig[:], input[:4])
-	function, exists := functions[sig]
-	if exists {
-		var buffer bytes.Buffer
-		buffer.WriteString(fmt.Sprintf("%s(", function.name))
-		for i, param := range function.params {
-			t, err := abi.NewType(param)
-			if err == nil {
-				res, err := contractValueToString(t, uint32(i), input)
-				if err != nil {
-					res = err.Error()
-				}
-				buffer.WriteString(fmt.Sprintf("%s", res))
-				if i < len(function.params)-1 {
-					buffer.WriteString(fmt.Sprintf(","))
-				}
-			}
-		}
-		buffer.WriteString(")")
-		return buffer.String()
-	} else {
+	name, _, values, ambiguous, exists := DataToFunction(input)
+	if !exists {
 		return fmt.Sprintf("%x", input)
 	}
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s(%s)", name, strings.Join(values, ",")))
+	if ambiguous {
+		buffer.WriteString(" (ambiguous signature)")
+	}
+	return buffer.String()
 }
 
-func contractValueToString(argType abi.Type, index uint32, data []byte) (string, error) {
-	switch argType.T {
-	case abi.IntTy:
-		return big.NewInt(0).SetBytes(data[4+index*32 : 36+index*32]).String(), nil
-	case abi.UintTy:
-		return big.NewInt(0).SetBytes(data[4+index*32 : 36+index*32]).String(), nil
-	case abi.BoolTy:
-		if data[35+index*32] == 0x01 {
-			return "true", nil
-		} else {
-			return "false", nil
+// DataToFunction takes a transaction's data bytes and decodes it in to the
+// name of the function called along with the type and value of each of its
+// parameters.  Ambiguous is set if the function signature was not the only
+// candidate for the data's selector.
+func DataToFunction(input []byte) (name string, types []string, values []string, ambiguous bool, exists bool) {
+	if len(input) < 4 {
+		return
+	}
+	var sig [4]byte
+	copy(sig[:], input[:4])
+	function, exists := functions[sig]
+	if !exists && onlineLookup && lookupFunction(sig) {
+		function, exists = functions[sig]
+	}
+	if !exists {
+		return
+	}
+	name = function.name
+	ambiguous = function.ambiguous
+	data := input[4:]
+	pos := 0
+	for _, param := range function.params {
+		t, err := parseType(param)
+		if err != nil {
+			types = append(types, param)
+			values = append(values, err.Error())
+			pos += 32
+			continue
 		}
-	case abi.StringTy:
-		offset := binary.BigEndian.Uint32(data[32+index*32 : 36+index*32])
-		len := binary.BigEndian.Uint32(data[32+offset : 36+offset])
-		return fmt.Sprintf("\"%s\"", string(data[36+offset:36+offset+len])), nil
-	case abi.SliceTy, abi.ArrayTy:
-		res := make([]string, 0)
-		offset := binary.BigEndian.Uint32(data[32+index*32 : 36+index*32])
-		entries := binary.BigEndian.Uint32(data[32+offset : 36+offset])
-		for i := uint32(0); i < entries; i++ {
-			elemRes, err := contractValueToString(*argType.Elem, 1+offset/32+i, data)
-			if err != nil {
-				return "", err
-			}
-			res = append(res, elemRes)
+		res, err := decodeElement(t, data, pos)
+		if err != nil {
+			res = err.Error()
 		}
-		return "[" + strings.Join(res, ",") + "]", nil
-	case abi.AddressTy:
-		return fmt.Sprintf("0x%x", data[16+index*32:36+index*32]), nil
-	case abi.FixedBytesTy:
-		return fmt.Sprintf("0x%x", data[36-uint32(argType.Size)+index*32:36+index*32]), nil
-	case abi.BytesTy:
-		offset := binary.BigEndian.Uint32(data[32+index*32 : 36+index*32])
-		len := binary.BigEndian.Uint32(data[32+offset : 36+offset])
-		return fmt.Sprintf("0x%x", data[36+offset:36+offset+len]), nil
-	case abi.HashTy:
-		return fmt.Sprintf("0x%x", data[4+index*32:36+index*32]), nil
-	case abi.FixedPointTy:
-		return "", fmt.Errorf("unhandled type %v", argType)
-	case abi.FunctionTy:
-		return "", fmt.Errorf("unhandled type %v", argType)
-	default:
-		return "", fmt.Errorf("unknown type %v", argType)
+		types = append(types, param)
+		values = append(values, res)
+		pos += t.headSize()
 	}
+	return
 }
 
 // AddFunctionSignature adds a function signature to the translation list
 func AddFunctionSignature(signature string) {
+	addFunction(signature, nil)
+}
+
+// addFunction adds a function signature, along with the names of its
+// parameters if known, to the translation list
+func addFunction(signature string, names []string) {
+	start := strings.Index(signature, "(")
+	if start == -1 || !strings.HasSuffix(signature, ")") {
+		return
+	}
+	params, err := splitTypes(signature[start+1 : len(signature)-1])
+	if err != nil {
+		return
+	}
+	functions[functionSelector(signature)] = function{name: signature[:start], params: params, names: names}
+}
+
+// addFunctionSignature adds a function signature to the translation list if
+// it matches the expected selector
+func addFunctionSignature(signature string, sig [4]byte) bool {
+	if !strings.Contains(signature, "(") || functionSelector(signature) != sig {
+		return false
+	}
+	AddFunctionSignature(signature)
+	return true
+}
+
+// functionSelector calculates the 4-byte selector for a function signature
+func functionSelector(signature string) (sig [4]byte) {
 	var hash [32]byte
 	sha := sha3.NewKeccak256()
 	sha.Write([]byte(signature))
 	sha.Sum(hash[:0])
-	var sig [4]byte
 	copy(sig[:], hash[:4])
-
-	sigBits := strings.Split(strings.TrimSuffix(signature, ")"), "(")
-	name := sigBits[0]
-	params := strings.Split(sigBits[1], ",")
-	functions[sig] = function{name: name, params: params}
+	return
 }
 
 func InitFunctionMap() {
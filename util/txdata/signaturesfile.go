@@ -0,0 +1,122 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// LoadSignaturesFile adds the function signatures in a file to the
+// translation list.  The file can either contain one signature per line, or
+// be JSON containing either an array of signatures or an object mapping
+// selectors to a signature or array of signatures as used by 4byte.directory.
+// If a signature's selector is already known then the existing signature is
+// kept and a warning is returned.
+func LoadSignaturesFile(path string) (warnings []string, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := parseSignatures(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, signature := range signatures {
+		sig := functionSelector(signature)
+		if existing, exists := functions[sig]; exists {
+			if existing.signature() != signature {
+				warnings = append(warnings, fmt.Sprintf("selector 0x%x for %s is already used by %s; ignoring", sig, signature, existing.signature()))
+			}
+			continue
+		}
+		AddFunctionSignature(signature)
+	}
+	return warnings, nil
+}
+
+// parseSignatures parses the contents of a signatures file
+func parseSignatures(data []byte) ([]string, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var signatures []string
+	switch data[0] {
+	case '[':
+		if err := json.Unmarshal(data, &signatures); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+	case '{':
+		entries := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		selectors := make([]string, 0, len(entries))
+		for selector := range entries {
+			selectors = append(selectors, selector)
+		}
+		sort.Strings(selectors)
+		for _, selector := range selectors {
+			var entrySignatures []string
+			var entrySignature string
+			if err := json.Unmarshal(entries[selector], &entrySignature); err == nil {
+				entrySignatures = []string{entrySignature}
+			} else if err := json.Unmarshal(entries[selector], &entrySignatures); err != nil {
+				return nil, fmt.Errorf("invalid signatures for selector %s", selector)
+			}
+			for _, signature := range entrySignatures {
+				if fmt.Sprintf("0x%x", functionSelector(signature)) != strings.ToLower(selector) {
+					return nil, fmt.Errorf("signature %s does not match selector %s", signature, selector)
+				}
+			}
+			signatures = append(signatures, entrySignatures...)
+		}
+	default:
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			signatures = append(signatures, line)
+		}
+	}
+	for _, signature := range signatures {
+		if err := checkSignature(signature); err != nil {
+			return nil, err
+		}
+	}
+	return signatures, nil
+}
+
+// checkSignature checks that a function signature is well-formed
+func checkSignature(signature string) error {
+	start := strings.Index(signature, "(")
+	if start < 1 || !strings.HasSuffix(signature, ")") {
+		return fmt.Errorf("invalid signature %s", signature)
+	}
+	params, err := splitTypes(signature[start+1 : len(signature)-1])
+	if err != nil {
+		return fmt.Errorf("invalid signature %s: %v", signature, err)
+	}
+	for _, param := range params {
+		if _, err := parseType(param); err != nil {
+			return fmt.Errorf("invalid signature %s: %v", signature, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignatures(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		signatures []string
+		err        string
+	}{
+		{
+			name: "Empty",
+			data: "",
+		},
+		{
+			name:       "Plaintext",
+			data:       "# Project signatures\nmyFunc(address,bytes32)\n\n  myFunc2(bool)  \n",
+			signatures: []string{"myFunc(address,bytes32)", "myFunc2(bool)"},
+		},
+		{
+			name:       "JSONArray",
+			data:       `["myFunc(address,bytes32)","submit((uint256,string)[])"]`,
+			signatures: []string{"myFunc(address,bytes32)", "submit((uint256,string)[])"},
+		},
+		{
+			name:       "JSONObject",
+			data:       `{"0xa9059cbb":"transfer(address,uint256)","0x70a08231":["balanceOf(address)","passphrase_calculate_transfer(uint64,address)"]}`,
+			signatures: []string{"balanceOf(address)", "passphrase_calculate_transfer(uint64,address)", "transfer(address,uint256)"},
+		},
+		{
+			name: "JSONObjectBadSelector",
+			data: `{"0x12345678":"transfer(address,uint256)"}`,
+			err:  "signature transfer(address,uint256) does not match selector 0x12345678",
+		},
+		{
+			name: "BadJSON",
+			data: `["myFunc(address,bytes32)"`,
+			err:  "invalid JSON: unexpected end of JSON input",
+		},
+		{
+			name: "BadSignature",
+			data: "myFunc\n",
+			err:  "invalid signature myFunc",
+		},
+		{
+			name: "BadType",
+			data: "myFunc(uint7)\n",
+			err:  "invalid signature myFunc(uint7): invalid type uint7",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signatures, err := parseSignatures([]byte(test.data))
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.signatures, signatures)
+			}
+		})
+	}
+}
+
+func TestLoadSignaturesFileDuplicates(t *testing.T) {
+	file, err := ioutil.TempFile("", "signatures")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("passphrase_calculate_transfer(uint64,address)\nbalanceOf(address)\nmyFunc(address,bytes32)\n")
+	assert.Nil(t, err)
+	file.Close()
+
+	InitFunctionMap()
+	warnings, err := LoadSignaturesFile(file.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"selector 0x70a08231 for passphrase_calculate_transfer(uint64,address) is already used by balanceOf(address); ignoring"}, warnings)
+	assert.Equal(t, "balanceOf(address)", functions[functionSelector("balanceOf(address)")].signature())
+	assert.Equal(t, "myFunc(address,bytes32)", functions[functionSelector("myFunc(address,bytes32)")].signature())
+}
@@ -0,0 +1,63 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseTypes parses a comma-separated list of ABI types such as
+// "address,uint256[],(bool,bytes)", checking that each is valid
+func ParseTypes(input string) ([]string, error) {
+	types, err := splitTypes(input)
+	if err != nil {
+		return nil, err
+	}
+	for i := range types {
+		types[i] = strings.TrimSpace(types[i])
+		if _, err := parseType(types[i]); err != nil {
+			return nil, err
+		}
+	}
+	return types, nil
+}
+
+// SignatureTypes obtains the name and parameter types of a function
+// signature such as "transfer(address,uint256)"
+func SignatureTypes(signature string) (name string, types []string, err error) {
+	if err = checkSignature(signature); err != nil {
+		return
+	}
+	start := strings.Index(signature, "(")
+	types, err = ParseTypes(signature[start+1 : len(signature)-1])
+	return signature[:start], types, err
+}
+
+// DecodeValues decodes ABI-encoded data as a sequence of values of the given
+// types
+func DecodeValues(types []string, data []byte) ([]string, error) {
+	argTypes := make([]*argType, len(types))
+	for i, input := range types {
+		t, err := parseType(input)
+		if err != nil {
+			return nil, err
+		}
+		argTypes[i] = t
+	}
+	values, err := decodeSequence(argTypes, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data: %v", err)
+	}
+	return values, nil
+}
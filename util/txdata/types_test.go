@@ -0,0 +1,67 @@
+// Copyright © 2018 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package txdata
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTypes(t *testing.T) {
+	tests := []struct {
+		input  string
+		output []string
+		err    bool
+	}{
+		{"", nil, false},
+		{"address", []string{"address"}, false},
+		{"address, uint256", []string{"address", "uint256"}, false},
+		{"uint8[2],(bool,bytes)[]", []string{"uint8[2]", "(bool,bytes)[]"}, false},
+		{"uint7", nil, true},
+		{"(bool,bytes", nil, true},
+		{"address,foo", nil, true},
+	}
+
+	for _, tt := range tests {
+		result, err := ParseTypes(tt.input)
+		if tt.err {
+			assert.NotNil(t, err, "Expected error for %s", tt.input)
+		} else {
+			assert.Nil(t, err, "Unexpected error for %s", tt.input)
+			assert.Equal(t, tt.output, result, "Incorrect result for %s", tt.input)
+		}
+	}
+}
+
+func TestSignatureTypes(t *testing.T) {
+	name, types, err := SignatureTypes("transfer(address,uint256)")
+	assert.Nil(t, err)
+	assert.Equal(t, "transfer", name)
+	assert.Equal(t, []string{"address", "uint256"}, types)
+
+	_, _, err = SignatureTypes("transfer")
+	assert.NotNil(t, err)
+}
+
+func TestDecodeValuesTypes(t *testing.T) {
+	data, err := hex.DecodeString("0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc40000000000000000000000000000000000000000000000000000000000000064")
+	assert.Nil(t, err)
+	values, err := DecodeValues([]string{"address", "uint256"}, data)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"0x5ffc014343cd971b7eb70732021e26c35b744cc4", "100"}, values)
+
+	_, err = DecodeValues([]string{"address", "uint256"}, data[:40])
+	assert.NotNil(t, err)
+}